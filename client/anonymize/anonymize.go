@@ -0,0 +1,182 @@
+package anonymize
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	ipv4Regex   = regexp.MustCompile(`\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}\b`)
+	ipv6Regex   = regexp.MustCompile(`(?:[0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}`)
+	domainRegex = regexp.MustCompile(`\b(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63}\b`)
+
+	// cgnatRange is the shared address space NetBird assigns peer addresses from
+	cgnatRange = netip.MustParsePrefix("100.64.0.0/10")
+
+	// anonIPv4Range and anonIPv6Range are documentation ranges the anonymized addresses are taken from
+	anonIPv4Range = netip.MustParsePrefix("198.51.100.0/24")
+	anonIPv6Range = netip.MustParsePrefix("2001:db8::/32")
+
+	// anonDomainSuffix is the top level domain of the anonymized domains
+	anonDomainSuffix = "domain"
+)
+
+// keptDomains are domains of the NetBird infrastructure, they and their subdomains are not anonymized
+var keptDomains = []string{"netbird.io", "netbird.cloud", "wiretrustee.com", "wiretrustee.io"}
+
+// fileExtensions are suffixes matched by the domain expression that are file names rather than domains
+var fileExtensions = map[string]struct{}{
+	"go": {}, "log": {}, "json": {}, "txt": {}, "yaml": {}, "yml": {}, "conf": {}, "zip": {}, "pem": {},
+	"key": {}, "sock": {}, "exe": {}, "dll": {}, "sh": {}, "service": {},
+}
+
+// Anonymizer replaces public IP addresses and domains with documentation addresses and placeholder domains.
+// Replacements are consistent: the same address or domain is always replaced with the same value.
+// It is not safe for concurrent use
+type Anonymizer struct {
+	ips         map[netip.Addr]netip.Addr
+	domains     map[string]string
+	nextAnonIP4 netip.Addr
+	nextAnonIP6 netip.Addr
+}
+
+// NewAnonymizer returns a new Anonymizer instance
+func NewAnonymizer() *Anonymizer {
+	return &Anonymizer{
+		ips:         make(map[netip.Addr]netip.Addr),
+		domains:     make(map[string]string),
+		nextAnonIP4: anonIPv4Range.Addr().Next(),
+		nextAnonIP6: anonIPv6Range.Addr().Next(),
+	}
+}
+
+// AnonymizeIP returns the replacement of a public IP address.
+// Private, loopback, link-local, multicast and NetBird addresses are returned unchanged
+func (a *Anonymizer) AnonymizeIP(ip netip.Addr) netip.Addr {
+	ip = ip.Unmap()
+	if !isPublic(ip) {
+		return ip
+	}
+
+	anonIP, ok := a.ips[ip]
+	if ok {
+		return anonIP
+	}
+
+	if ip.Is4() {
+		anonIP = a.nextAnonIP4
+		a.nextAnonIP4 = a.nextAnonIP4.Next()
+		// the documentation range is small, wrap around rather than leaking addresses
+		if !anonIPv4Range.Contains(a.nextAnonIP4) {
+			a.nextAnonIP4 = anonIPv4Range.Addr().Next()
+		}
+	} else {
+		anonIP = a.nextAnonIP6
+		a.nextAnonIP6 = a.nextAnonIP6.Next()
+	}
+
+	a.ips[ip] = anonIP
+	return anonIP
+}
+
+// AnonymizeDomain returns the replacement of a domain. NetBird domains are returned unchanged
+func (a *Anonymizer) AnonymizeDomain(domain string) string {
+	if !shouldAnonymizeDomain(domain) {
+		return domain
+	}
+
+	key := strings.ToLower(strings.TrimSuffix(domain, "."))
+	anonDomain, ok := a.domains[key]
+	if ok {
+		return anonDomain
+	}
+
+	anonDomain = fmt.Sprintf("anon-%d.%s", len(a.domains)+1, anonDomainSuffix)
+	a.domains[key] = anonDomain
+	return anonDomain
+}
+
+// AnonymizeURI returns a URI with its host anonymized, the rest of the URI is kept
+func (a *Anonymizer) AnonymizeURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Host == "" {
+		return a.AnonymizeString(uri)
+	}
+
+	host := u.Hostname()
+	port := u.Port()
+
+	var anonHost string
+	if ip, err := netip.ParseAddr(host); err == nil {
+		anonHost = a.AnonymizeIP(ip).String()
+		if ip.Is6() && !ip.Is4In6() {
+			anonHost = "[" + anonHost + "]"
+		}
+	} else {
+		anonHost = a.AnonymizeDomain(host)
+	}
+
+	if port != "" {
+		anonHost = anonHost + ":" + port
+	}
+	u.Host = anonHost
+	u.User = nil
+
+	return u.String()
+}
+
+// AnonymizeString replaces all public IP addresses and domains found in a text
+func (a *Anonymizer) AnonymizeString(str string) string {
+	str = ipv4Regex.ReplaceAllStringFunc(str, a.replaceIP)
+	str = ipv6Regex.ReplaceAllStringFunc(str, a.replaceIP)
+	return domainRegex.ReplaceAllStringFunc(str, a.AnonymizeDomain)
+}
+
+// replaceIP anonymizes a match of the IP expressions, matches that are not addresses are kept
+func (a *Anonymizer) replaceIP(match string) string {
+	ip, err := netip.ParseAddr(match)
+	if err != nil {
+		return match
+	}
+	return a.AnonymizeIP(ip).String()
+}
+
+func isPublic(ip netip.Addr) bool {
+	return ip.IsValid() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!cgnatRange.Contains(ip) &&
+		!anonIPv4Range.Contains(ip) &&
+		!anonIPv6Range.Contains(ip)
+}
+
+func shouldAnonymizeDomain(domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	lastDot := strings.LastIndex(domain, ".")
+	if lastDot < 0 {
+		return false
+	}
+	tld := domain[lastDot+1:]
+	if tld == anonDomainSuffix {
+		return false
+	}
+	if _, ok := fileExtensions[tld]; ok {
+		return false
+	}
+
+	for _, kept := range keptDomains {
+		if domain == kept || strings.HasSuffix(domain, "."+kept) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change the client configuration",
+	Long:  "Commands to show or change the client configuration. Changes are applied by restarting the engine if it is running",
+}
+
+var configGetCmd = &cobra.Command{
+	Use:     "get [setting]",
+	Short:   "Show the value of a setting or of all settings",
+	Example: "  netbird config get\n  netbird config get wireguard-port",
+	Args:    cobra.MaximumNArgs(1),
+	RunE:    configGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:     "set <setting> <value> [<setting> <value>...]",
+	Short:   "Change the value of one or more settings",
+	Example: "  netbird config set wireguard-port 51821\n  netbird config set lazy-connection true route-overlap-policy skip",
+	RunE:    configSet,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd, configSetCmd)
+}
+
+func configGet(cmd *cobra.Command, args []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.GetConfig(cmd.Context(), &proto.GetConfigRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get config: %v", status.Convert(err).Message())
+	}
+
+	settings := resp.GetSettings()
+	if len(settings) == 0 {
+		return fmt.Errorf("configuration is not loaded, please run login first")
+	}
+
+	if len(args) == 1 {
+		value, ok := settings[args[0]]
+		if !ok {
+			return fmt.Errorf("unknown setting %q", args[0])
+		}
+		cmd.Println(value)
+		return nil
+	}
+
+	cmd.Print(parseConfigSettings(settings))
+	return nil
+}
+
+func parseConfigSettings(settings map[string]string) string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	output := ""
+	for _, key := range keys {
+		output += fmt.Sprintf("%s: %s\n", key, settings[key])
+	}
+	return output
+}
+
+func configSet(cmd *cobra.Command, args []string) error {
+	settings, err := parseConfigSetArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.SetConfig(cmd.Context(), &proto.SetConfigRequest{Settings: settings})
+	if err != nil {
+		return fmt.Errorf("failed to set config: %v", status.Convert(err).Message())
+	}
+
+	if resp.GetEngineRestarted() {
+		cmd.Println("Configuration updated, the engine has been restarted to apply the changes")
+	} else {
+		cmd.Println("Configuration updated")
+	}
+	return nil
+}
+
+// parseConfigSetArgs returns the settings of setting and value argument pairs
+func parseConfigSetArgs(args []string) (map[string]string, error) {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return nil, fmt.Errorf("provide settings as <setting> <value> pairs")
+	}
+
+	settings := make(map[string]string, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		if _, ok := settings[args[i]]; ok {
+			return nil, fmt.Errorf("setting %q provided more than once", args[i])
+		}
+		settings[args[i]] = args[i+1]
+	}
+	return settings, nil
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigSetArgs(t *testing.T) {
+	settings, err := parseConfigSetArgs([]string{"wireguard-port", "51821", "lazy-connection", "true"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"wireguard-port": "51821", "lazy-connection": "true"}, settings)
+
+	_, err = parseConfigSetArgs([]string{"wireguard-port"})
+	assert.Error(t, err)
+
+	_, err = parseConfigSetArgs([]string{"wireguard-port", "1", "wireguard-port", "2"})
+	assert.Error(t, err)
+}
+
+func TestParseConfigSettings(t *testing.T) {
+	output := parseConfigSettings(map[string]string{"wireguard-port": "51820", "interface-name": "wt0"})
+	assert.Equal(t, "interface-name: wt0\nwireguard-port: 51820\n", output)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var (
+	anonymizeFlag    bool
+	logLevelDuration time.Duration
+	followLogsFlag   bool
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Debugging commands",
+	Long:  "Commands to collect information for troubleshooting and support requests",
+}
+
+var debugBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create a debug bundle",
+	Long: "Asks the daemon to create an archive with recent logs, the client configuration with its keys redacted, " +
+		"the full status, the WireGuard peers, the routing table, the NetBird firewall rules and system information",
+	Example: "  netbird debug bundle\n  netbird debug bundle --anonymize",
+	RunE:    debugBundle,
+}
+
+var debugLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Manage the daemon logging",
+}
+
+var debugLogLevelCmd = &cobra.Command{
+	Use:   "level <level>",
+	Short: "Change the log level of the daemon without restarting it",
+	Long: "Changes the log level of the running daemon. With --for the previous level is restored once the duration expires, " +
+		"otherwise the level is kept until the daemon restarts. Levels: panic, fatal, error, warn, info, debug, trace",
+	Example: "  netbird debug log level debug --for 10m\n  netbird debug log level info",
+	Args:    cobra.ExactArgs(1),
+	RunE:    setLogLevel,
+}
+
+var debugLogsCmd = &cobra.Command{
+	Use:     "logs",
+	Short:   "Print the recent log lines of the daemon",
+	Example: "  netbird debug logs\n  netbird debug logs --follow",
+	RunE:    followLogs,
+}
+
+func init() {
+	debugBundleCmd.Flags().BoolVarP(&anonymizeFlag, "anonymize", "A", false, "replace public IP addresses and domains consistently in the bundle")
+	debugLogLevelCmd.Flags().DurationVar(&logLevelDuration, "for", 0, "restore the previous log level after this duration, e.g. --for 10m")
+	debugLogsCmd.Flags().BoolVarP(&followLogsFlag, "follow", "f", false, "keep printing new log lines as they are written")
+	debugLogCmd.AddCommand(debugLogLevelCmd)
+	debugCmd.AddCommand(debugBundleCmd, debugLogCmd, debugLogsCmd)
+}
+
+func debugBundle(cmd *cobra.Command, _ []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	statusOutput := ""
+	statusResp, err := client.Status(cmd.Context(), &proto.StatusRequest{GetFullPeerStatus: true})
+	if err != nil {
+		statusOutput = fmt.Sprintf("unable to get status: %v\n", status.Convert(err).Message())
+	} else {
+		daemonStatus := fmt.Sprintf("Daemon status: %s\n", statusResp.GetStatus())
+		statusOutput = parseFullStatus(fromProtoFullStatus(statusResp.GetFullStatus()), true, daemonStatus, statusResp.GetDaemonVersion(), false)
+		if statusOutput == "" {
+			statusOutput = daemonStatus
+		}
+	}
+
+	resp, err := client.DebugBundle(cmd.Context(), &proto.DebugBundleRequest{
+		Anonymize: anonymizeFlag,
+		Status:    statusOutput,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create debug bundle: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Debug bundle created on the daemon host: %s\n", resp.GetPath())
+	return nil
+}
+
+func setLogLevel(cmd *cobra.Command, args []string) error {
+	if logLevelDuration < 0 {
+		return fmt.Errorf("duration can't be negative, got %s", logLevelDuration)
+	}
+
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.SetLogLevel(cmd.Context(), &proto.SetLogLevelRequest{
+		Level:    args[0],
+		Duration: durationpb.New(logLevelDuration),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set log level: %v", status.Convert(err).Message())
+	}
+
+	if resp.GetRevertAt() == nil {
+		cmd.Printf("Log level set to %s\n", args[0])
+		return nil
+	}
+
+	cmd.Printf("Log level set to %s, %s will be restored at %s\n", args[0], resp.GetPreviousLevel(),
+		resp.GetRevertAt().AsTime().Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+func followLogs(cmd *cobra.Command, _ []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	stream, err := client.FollowLogs(ctx, &proto.FollowLogsRequest{Follow: followLogsFlag})
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %v", status.Convert(err).Message())
+	}
+
+	for {
+		line, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("receiving logs failed: %v", status.Convert(err).Message())
+		}
+		cmd.Println(line.GetLine())
+	}
+}
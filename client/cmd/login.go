@@ -3,9 +3,11 @@ package cmd
 import (
 	"context"
 	"fmt"
+	log "github.com/sirupsen/logrus"
 	"github.com/skratchdot/open-golang/open"
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
+	"strings"
 	"time"
 
 	"github.com/netbirdio/netbird/util"
@@ -63,10 +65,16 @@ var loginCmd = &cobra.Command{
 
 		client := proto.NewDaemonServiceClient(conn)
 
+		flow, err := parseSSOFlow(ssoFlow)
+		if err != nil {
+			return err
+		}
+
 		loginRequest := proto.LoginRequest{
 			SetupKey:      setupKey,
 			PreSharedKey:  preSharedKey,
 			ManagementUrl: managementURL,
+			SsoFlow:       flow,
 		}
 
 		var loginErr error
@@ -124,36 +132,52 @@ func foregroundLogin(ctx context.Context, cmd *cobra.Command, config *internal.C
 	}
 
 	jwtToken := ""
+	refreshToken := ""
 	if setupKey == "" && needsLogin {
 		tokenInfo, err := foregroundGetTokenInfo(ctx, cmd, config)
 		if err != nil {
 			return fmt.Errorf("interactive sso login failed: %v", err)
 		}
 		jwtToken = tokenInfo.AccessToken
+		refreshToken = tokenInfo.RefreshToken
 	}
 
+	loggedIn := false
 	err = WithBackOff(func() error {
 		err := internal.Login(ctx, config, setupKey, jwtToken)
 		if s, ok := gstatus.FromError(err); ok && (s.Code() == codes.InvalidArgument || s.Code() == codes.PermissionDenied) {
 			return nil
 		}
+		loggedIn = err == nil
 		return err
 	})
 	if err != nil {
 		return fmt.Errorf("backoff cycle failed: %v", err)
 	}
 
+	if loggedIn && jwtToken != "" {
+		err = internal.UpdateSSORefreshToken(config, refreshToken)
+		if err != nil {
+			log.Warnf("failed storing the SSO refresh token: %v", err)
+		}
+	}
+
 	return nil
 }
 
 func foregroundGetTokenInfo(ctx context.Context, cmd *cobra.Command, config *internal.Config) (*internal.TokenInfo, error) {
-	providerConfig, err := internal.GetDeviceAuthorizationFlowInfo(ctx, config)
+	flow, err := parseSSOFlow(ssoFlow)
+	if err != nil {
+		return nil, err
+	}
+
+	oAuthClient, err := foregroundOAuthClient(ctx, config, flow)
 	if err != nil {
 		s, ok := gstatus.FromError(err)
 		if ok && s.Code() == codes.NotFound {
-			return nil, fmt.Errorf("no SSO provider returned from management. " +
-				"If you are using hosting Netbird see documentation at " +
-				"https://github.com/netbirdio/netbird/tree/main/management for details")
+			return nil, fmt.Errorf("no SSO provider for the %s flow returned from management. "+
+				"If you are using hosting Netbird see documentation at "+
+				"https://github.com/netbirdio/netbird/tree/main/management for details", ssoFlow)
 		} else if ok && s.Code() == codes.Unimplemented {
 			mgmtURL := managementURL
 			if mgmtURL == "" {
@@ -162,18 +186,19 @@ func foregroundGetTokenInfo(ctx context.Context, cmd *cobra.Command, config *int
 			return nil, fmt.Errorf("the management server, %s, does not support SSO providers, "+
 				"please update your servver or use Setup Keys to login", mgmtURL)
 		} else {
-			return nil, fmt.Errorf("getting device authorization flow info failed with error: %v", err)
+			return nil, fmt.Errorf("getting %s authorization flow info failed with error: %v", ssoFlow, err)
 		}
 	}
 
-	hostedClient := internal.NewHostedDeviceFlow(
-		providerConfig.ProviderConfig.Audience,
-		providerConfig.ProviderConfig.ClientID,
-		providerConfig.ProviderConfig.TokenEndpoint,
-		providerConfig.ProviderConfig.DeviceAuthEndpoint,
-	)
+	if config.SSORefreshToken != "" {
+		tokenInfo, err := oAuthClient.RefreshToken(ctx, config.SSORefreshToken)
+		if err == nil {
+			return &tokenInfo, nil
+		}
+		log.Infof("the stored SSO refresh token can't be used, an interactive login is required: %v", err)
+	}
 
-	flowInfo, err := hostedClient.RequestDeviceCode(context.TODO())
+	flowInfo, err := oAuthClient.RequestDeviceCode(context.TODO())
 	if err != nil {
 		return nil, fmt.Errorf("getting a request device code failed: %v", err)
 	}
@@ -184,7 +209,7 @@ func foregroundGetTokenInfo(ctx context.Context, cmd *cobra.Command, config *int
 	waitCTX, c := context.WithTimeout(context.TODO(), waitTimeout*time.Second)
 	defer c()
 
-	tokenInfo, err := hostedClient.WaitToken(waitCTX, flowInfo)
+	tokenInfo, err := oAuthClient.WaitToken(waitCTX, flowInfo)
 	if err != nil {
 		return nil, fmt.Errorf("waiting for browser login failed: %v", err)
 	}
@@ -192,6 +217,40 @@ func foregroundGetTokenInfo(ctx context.Context, cmd *cobra.Command, config *int
 	return &tokenInfo, nil
 }
 
+// foregroundOAuthClient returns a client of the SSO flow configured with the provider received from management
+func foregroundOAuthClient(ctx context.Context, config *internal.Config, flow proto.SSOFlow) (internal.OAuthClient, error) {
+	if flow == proto.SSOFlow_PKCE {
+		pkceFlow, err := internal.GetPKCEAuthorizationFlowInfo(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		return internal.NewPKCEFlow(pkceFlow.ProviderConfig), nil
+	}
+
+	providerConfig, err := internal.GetDeviceAuthorizationFlowInfo(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return internal.NewHostedDeviceFlow(providerConfig.ProviderConfig), nil
+}
+
+const (
+	ssoFlowDevice = "device"
+	ssoFlowPKCE   = "pkce"
+)
+
+// parseSSOFlow converts the sso-flow flag value to the SSO flow of the daemon Login request
+func parseSSOFlow(value string) (proto.SSOFlow, error) {
+	switch strings.ToLower(value) {
+	case ssoFlowDevice, "":
+		return proto.SSOFlow_DEVICE_CODE, nil
+	case ssoFlowPKCE:
+		return proto.SSOFlow_PKCE, nil
+	default:
+		return 0, fmt.Errorf("invalid SSO flow %q, use %s or %s", value, ssoFlowDevice, ssoFlowPKCE)
+	}
+}
+
 func openURL(cmd *cobra.Command, verificationURIComplete string) {
 	err := open.Run(verificationURIComplete)
 	cmd.Printf("Please do the SSO login in your browser. \n" +
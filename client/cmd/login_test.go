@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/iface"
 	"github.com/netbirdio/netbird/util"
 )
@@ -51,3 +52,20 @@ func TestLogin(t *testing.T) {
 		t.Errorf("expected non empty Private key, got empty")
 	}
 }
+
+func TestParseSSOFlow(t *testing.T) {
+	flow, err := parseSSOFlow("device")
+	if err != nil || flow != proto.SSOFlow_DEVICE_CODE {
+		t.Errorf("expected the device code flow, got %s, %v", flow, err)
+	}
+
+	flow, err = parseSSOFlow("PKCE")
+	if err != nil || flow != proto.SSOFlow_PKCE {
+		t.Errorf("expected the PKCE flow, got %s, %v", flow, err)
+	}
+
+	_, err = parseSSOFlow("implicit")
+	if err == nil {
+		t.Error("expected an error for an unknown flow")
+	}
+}
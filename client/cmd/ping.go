@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var (
+	pingCount uint32
+	traceFlag bool
+)
+
+var pingCmd = &cobra.Command{
+	Use:   "ping <peer name|peer IP|peer public key>",
+	Short: "Diagnose the connection to a peer and measure the round-trip time over the tunnel",
+	Long: "Reports whether the peer is in the network map received from management, its connection state, " +
+		"the selected ICE candidate pair and relay usage, and measures the round-trip time over the tunnel. " +
+		"With --trace it keeps running and prints the signal and ICE negotiation with the peer as it happens.",
+	Example: "  netbird ping 100.64.0.10\n  netbird ping my-laptop\n  netbird ping 100.64.0.10 --count 10\n  netbird ping 100.64.0.10 --trace",
+	Args:    cobra.ExactArgs(1),
+	RunE:    pingPeer,
+}
+
+func init() {
+	pingCmd.Flags().Uint32VarP(&pingCount, "count", "c", 4, "number of round-trip time probes to send")
+	pingCmd.Flags().BoolVar(&traceFlag, "trace", false, "keep running and print the signal offer/answer and ICE candidate exchange with the peer")
+}
+
+func pingPeer(cmd *cobra.Command, args []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	target := args[0]
+
+	traceDone := make(chan error, 1)
+	if traceFlag {
+		stream, err := client.TracePeer(ctx, &proto.TracePeerRequest{Peer: target})
+		if err != nil {
+			return fmt.Errorf("failed to trace peer: %v", status.Convert(err).Message())
+		}
+		go func() {
+			traceDone <- printTrace(ctx, cmd, stream)
+		}()
+	}
+
+	resp, err := client.Ping(ctx, &proto.PingRequest{Peer: target, Count: pingCount})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to ping peer: %v", status.Convert(err).Message())
+	}
+
+	cmd.Print(parsePingResponse(target, resp))
+
+	if !traceFlag {
+		return nil
+	}
+
+	cmd.Println("\nTracing the connection negotiation, press Ctrl+C to stop")
+	return <-traceDone
+}
+
+func printTrace(ctx context.Context, cmd *cobra.Command, stream proto.DaemonService_TracePeerClient) error {
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("receiving trace failed: %v", status.Convert(err).Message())
+		}
+		cmd.Printf("[trace] %s %s\n", event.GetTime().AsTime().Local().Format("15:04:05.000"), event.GetMessage())
+	}
+}
+
+func parsePingResponse(target string, resp *proto.PingResponse) string {
+	if !resp.GetInNetworkMap() {
+		return fmt.Sprintf("Peer %s is not in the network map of this peer.\n"+
+			"Check that it is registered and that the access control rules allow the connection.\n", target)
+	}
+
+	peerState := resp.GetPeerState()
+	connected := peerState.GetConnStatus() == peer.StatusConnected.String()
+
+	connType := "-"
+	candidatePair := "-"
+	relayServer := "-"
+	if connected {
+		connType = "P2P"
+		if peerState.GetRelayed() {
+			connType = "Relayed"
+		}
+		candidatePair = fmt.Sprintf("%s %s <-> %s %s",
+			peerState.GetLocalIceCandidateType(), valueOrDash(peerState.GetLocalIceCandidateEndpoint()),
+			peerState.GetRemoteIceCandidateType(), valueOrDash(peerState.GetRemoteIceCandidateEndpoint()))
+		relayServer = valueOrDash(peerState.GetRelayServerAddress())
+	}
+
+	output := fmt.Sprintf(
+		"Peer %s (%s):\n"+
+			" In network map: yes\n"+
+			" Status: %s\n"+
+			" Connection type: %s\n"+
+			" Direct: %t\n"+
+			" ICE candidate pair (Local <-> Remote): %s\n"+
+			" Relay server address: %s\n",
+		peerState.GetIP(),
+		peerState.GetPubKey(),
+		peerState.GetConnStatus(),
+		connType,
+		peerState.GetDirect(),
+		candidatePair,
+		relayServer,
+	)
+
+	var (
+		received       int
+		minRTT, maxRTT time.Duration
+		totalRTT       time.Duration
+	)
+	for i, result := range resp.GetResults() {
+		if result.GetError() != "" {
+			output += fmt.Sprintf(" probe %d: %s\n", i+1, result.GetError())
+			continue
+		}
+		rtt := result.GetRtt().AsDuration()
+		output += fmt.Sprintf(" probe %d: rtt=%s\n", i+1, rtt)
+		if received == 0 || rtt < minRTT {
+			minRTT = rtt
+		}
+		if rtt > maxRTT {
+			maxRTT = rtt
+		}
+		totalRTT += rtt
+		received++
+	}
+
+	sent := len(resp.GetResults())
+	if sent == 0 {
+		return output
+	}
+
+	output += fmt.Sprintf(" %d probes sent, %d received, %.0f%% loss\n", sent, received, float64(sent-received)*100/float64(sent))
+	if received > 0 {
+		output += fmt.Sprintf(" rtt min/avg/max: %s/%s/%s\n", minRTT, totalRTT/time.Duration(received), maxRTT)
+	}
+	return output
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
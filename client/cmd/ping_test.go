@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestParsePingResponse(t *testing.T) {
+	output := parsePingResponse("100.64.0.99", &proto.PingResponse{InNetworkMap: false})
+	assert.Contains(t, output, "is not in the network map")
+
+	resp := &proto.PingResponse{
+		InNetworkMap: true,
+		PeerState: &proto.PeerState{
+			IP:                         "100.64.0.10",
+			PubKey:                     "peerA",
+			ConnStatus:                 "Connected",
+			Relayed:                    true,
+			LocalIceCandidateType:      "relay",
+			RemoteIceCandidateType:     "srflx",
+			LocalIceCandidateEndpoint:  "10.0.0.1:3478",
+			RemoteIceCandidateEndpoint: "1.1.1.1:51820",
+			RelayServerAddress:         "10.0.0.1:3478",
+		},
+		Results: []*proto.PingResult{
+			{Rtt: durationpb.New(10 * time.Millisecond)},
+			{Error: "i/o timeout"},
+			{Rtt: durationpb.New(30 * time.Millisecond)},
+			{Rtt: durationpb.New(20 * time.Millisecond)},
+		},
+	}
+
+	output = parsePingResponse("100.64.0.10", resp)
+	assert.Contains(t, output, "Connection type: Relayed")
+	assert.Contains(t, output, "ICE candidate pair (Local <-> Remote): relay 10.0.0.1:3478 <-> srflx 1.1.1.1:51820")
+	assert.Contains(t, output, "Relay server address: 10.0.0.1:3478")
+	assert.Contains(t, output, "probe 2: i/o timeout")
+	assert.Contains(t, output, "4 probes sent, 3 received, 25% loss")
+	assert.Contains(t, output, "rtt min/avg/max: 10ms/20ms/30ms")
+}
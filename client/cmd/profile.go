@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage client profiles",
+	Long: "Commands to manage client profiles. Each profile has its own keys, Management service and interface, " +
+		"only the selected profile is connected",
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:     "add <name>",
+	Short:   "Add a profile, it has to be logged in after it is selected",
+	Example: "  netbird profile add customer --management-url https://netbird.customer.example:443",
+	Args:    cobra.ExactArgs(1),
+	RunE:    profileAdd,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the profiles",
+	Example: "  netbird profile list",
+	Args:    cobra.NoArgs,
+	RunE:    profileList,
+}
+
+var profileSelectCmd = &cobra.Command{
+	Use:     "select <name>",
+	Short:   "Select the active profile, a running connection is restarted with it",
+	Example: "  netbird profile select customer",
+	Args:    cobra.ExactArgs(1),
+	RunE:    profileSelect,
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a profile and its keys",
+	Example: "  netbird profile remove customer",
+	Args:    cobra.ExactArgs(1),
+	RunE:    profileRemove,
+}
+
+func init() {
+	profileCmd.AddCommand(profileAddCmd, profileListCmd, profileSelectCmd, profileRemoveCmd)
+}
+
+func profileAdd(cmd *cobra.Command, args []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.AddProfile(cmd.Context(), &proto.AddProfileRequest{Name: args[0], ManagementUrl: managementURL})
+	if err != nil {
+		return fmt.Errorf("failed to add profile: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Profile %s added for %s\n", resp.GetProfile().GetName(), resp.GetProfile().GetManagementUrl())
+	return nil
+}
+
+func profileList(cmd *cobra.Command, _ []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.ListProfiles(cmd.Context(), &proto.ListProfilesRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list profiles: %v", status.Convert(err).Message())
+	}
+
+	cmd.Print(parseProfiles(resp.GetProfiles()))
+	return nil
+}
+
+func parseProfiles(profiles []*proto.Profile) string {
+	output := ""
+	for _, profile := range profiles {
+		marker := " "
+		if profile.GetActive() {
+			marker = "*"
+		}
+		output += fmt.Sprintf("%s %s\t%s\n", marker, profile.GetName(), valueOrDash(profile.GetManagementUrl()))
+	}
+	return output
+}
+
+func profileSelect(cmd *cobra.Command, args []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.SelectProfile(cmd.Context(), &proto.SelectProfileRequest{Name: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to select profile: %v", status.Convert(err).Message())
+	}
+
+	if resp.GetEngineRestarted() {
+		cmd.Printf("Profile %s selected, the connection has been restarted with it\n", args[0])
+	} else {
+		cmd.Printf("Profile %s selected\n", args[0])
+	}
+	return nil
+}
+
+func profileRemove(cmd *cobra.Command, args []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	_, err = client.RemoveProfile(cmd.Context(), &proto.RemoveProfileRequest{Name: args[0]})
+	if err != nil {
+		return fmt.Errorf("failed to remove profile: %v", status.Convert(err).Message())
+	}
+
+	cmd.Printf("Profile %s removed\n", args[0])
+	return nil
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestParseProfiles(t *testing.T) {
+	output := parseProfiles([]*proto.Profile{
+		{Name: "default", ManagementUrl: "https://api.netbird.io:443"},
+		{Name: "customer", Active: true},
+	})
+	assert.Equal(t, "  default\thttps://api.netbird.io:443\n* customer\t-\n", output)
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/netbirdio/netbird/util"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+var allRoutesFlag bool
+
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "Manage the networks routed through routing peers",
+	Long:  "Commands to list, select or deselect the networks routed through routing peers",
+}
+
+var routesListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List networks available through routing peers",
+	Example: "  netbird routes list",
+	RunE:    routesList,
+}
+
+var routesSelectCmd = &cobra.Command{
+	Use:     "select <network ID> [network ID...]",
+	Short:   "Select networks to be routed through routing peers",
+	Example: "  netbird routes select office-net\n  netbird routes select --all",
+	RunE:    routesSelect,
+}
+
+var routesDeselectCmd = &cobra.Command{
+	Use:     "deselect <network ID> [network ID...]",
+	Short:   "Deselect networks so they are no longer routed through routing peers",
+	Example: "  netbird routes deselect office-net\n  netbird routes deselect --all",
+	RunE:    routesDeselect,
+}
+
+func init() {
+	routesSelectCmd.Flags().BoolVar(&allRoutesFlag, "all", false, "select all networks")
+	routesDeselectCmd.Flags().BoolVar(&allRoutesFlag, "all", false, "deselect all networks")
+	routesCmd.AddCommand(routesListCmd, routesSelectCmd, routesDeselectCmd)
+}
+
+func routesList(cmd *cobra.Command, args []string) error {
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.ListRoutes(cmd.Context(), &proto.ListRoutesRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list routes: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetRoutes()) == 0 {
+		cmd.Println("No networks available.")
+		return nil
+	}
+
+	peerIPs := make(map[string]string)
+	statusResp, err := client.Status(cmd.Context(), &proto.StatusRequest{GetFullPeerStatus: true})
+	if err == nil {
+		for _, peerState := range statusResp.GetFullStatus().GetPeers() {
+			peerIPs[peerState.GetPubKey()] = peerState.GetIP()
+		}
+	}
+
+	cmd.Println("Available networks:")
+	for _, route := range resp.GetRoutes() {
+		cmd.Print(parseRoute(route, peerIPs))
+	}
+	return nil
+}
+
+func parseRoute(route *proto.Route, peerIPs map[string]string) string {
+	routeStatus := "Deselected"
+	routingPeer := "-"
+	if route.GetSelected() {
+		routeStatus = "Inactive"
+		if route.GetPeer() != "" {
+			routeStatus = "Active"
+			routingPeer = route.GetPeer()
+			if ip, found := peerIPs[route.GetPeer()]; found {
+				routingPeer = fmt.Sprintf("%s (%s)", ip, route.GetPeer())
+			}
+		}
+	}
+
+	return fmt.Sprintf(
+		"\n - ID: %s\n"+
+			"   Network: %s\n"+
+			"   Status: %s\n"+
+			"   Routing peer: %s\n",
+		route.GetNetID(),
+		route.GetNetwork(),
+		routeStatus,
+		routingPeer,
+	)
+}
+
+func routesSelect(cmd *cobra.Command, args []string) error {
+	if !allRoutesFlag && len(args) == 0 {
+		return fmt.Errorf("provide at least one network ID or use --all")
+	}
+
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	_, err = client.SelectRoutes(cmd.Context(), &proto.SelectRoutesRequest{NetIDs: args, All: allRoutesFlag})
+	if err != nil {
+		return fmt.Errorf("failed to select routes: %v", status.Convert(err).Message())
+	}
+
+	cmd.Println("Networks selected successfully.")
+	return nil
+}
+
+func routesDeselect(cmd *cobra.Command, args []string) error {
+	if !allRoutesFlag && len(args) == 0 {
+		return fmt.Errorf("provide at least one network ID or use --all")
+	}
+
+	client, closeConn, err := newDaemonClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	_, err = client.DeselectRoutes(cmd.Context(), &proto.SelectRoutesRequest{NetIDs: args, All: allRoutesFlag})
+	if err != nil {
+		return fmt.Errorf("failed to deselect routes: %v", status.Convert(err).Message())
+	}
+
+	cmd.Println("Networks deselected successfully.")
+	return nil
+}
+
+func newDaemonClient(cmd *cobra.Command) (proto.DaemonServiceClient, func(), error) {
+	SetFlagsFromEnvVars()
+
+	cmd.SetOut(cmd.OutOrStdout())
+
+	err := util.InitLog(logLevel, "console")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed initializing log %v", err)
+	}
+
+	conn, err := DialClientGRPCServer(cmd.Context(), daemonAddr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to daemon error: %v\n"+
+			"If the daemon is not running please run: "+
+			"\nnetbird service install \nnetbird service start\n", err)
+	}
+
+	return proto.NewDaemonServiceClient(conn), func() { _ = conn.Close() }, nil
+}
@@ -22,11 +22,17 @@ func (p *program) Start(svc service.Service) error {
 	// Start should not block. Do the actual work async.
 	log.Info("starting Netbird service") //nolint
 	// in any case, even if configuration does not exists we run daemon to serve CLI gRPC API.
-	p.serv = grpc.NewServer()
-
 	split := strings.Split(daemonAddr, "://")
 	switch split[0] {
 	case "unix":
+		// callers are authorized by the credentials of their process, see server.Authorizer
+		authorizer := server.NewAuthorizer(daemonGroup)
+		p.serv = grpc.NewServer(
+			grpc.Creds(server.NewPeerCredentialsTransport()),
+			grpc.UnaryInterceptor(authorizer.UnaryInterceptor),
+			grpc.StreamInterceptor(authorizer.StreamInterceptor),
+		)
+
 		// cleanup failed close
 		stat, err := os.Stat(split[1])
 		if err == nil && !stat.IsDir() {
@@ -35,6 +41,8 @@ func (p *program) Start(svc service.Service) error {
 			}
 		}
 	case "tcp":
+		log.Warnf("authorization disabled: %s", server.TCPAuthorizationWarning)
+		p.serv = grpc.NewServer()
 	default:
 		return fmt.Errorf("unsupported daemon address protocol: %v", split[0])
 	}
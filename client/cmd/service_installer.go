@@ -29,6 +29,8 @@ var installCmd = &cobra.Command{
 			configPath,
 			"--log-level",
 			logLevel,
+			"--daemon-group",
+			daemonGroup,
 		}
 
 		if managementURL != "" {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/netbirdio/netbird/client/internal"
 	"github.com/netbirdio/netbird/client/internal/peer"
@@ -11,10 +12,15 @@ import (
 	"github.com/netbirdio/netbird/util"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
 	"net"
 	"net/netip"
+	"os"
+	"os/signal"
 	"sort"
 	"strings"
+	"syscall"
+	"time"
 )
 
 var (
@@ -23,8 +29,92 @@ var (
 	ipsFilter    []string
 	statusFilter string
 	ipsFilterMap map[string]struct{}
+	jsonFlag     bool
+	yamlFlag     bool
+	watchFlag    bool
 )
 
+type peerStateDetailOutput struct {
+	IP                         string        `json:"netbirdIp" yaml:"netbirdIp"`
+	PubKey                     string        `json:"publicKey" yaml:"publicKey"`
+	Status                     string        `json:"status" yaml:"status"`
+	LastStatusUpdate           time.Time     `json:"lastStatusUpdate" yaml:"lastStatusUpdate"`
+	ConnType                   string        `json:"connectionType" yaml:"connectionType"`
+	ConnectionPolicy           string        `json:"connectionPolicy" yaml:"connectionPolicy"`
+	Direct                     bool          `json:"direct" yaml:"direct"`
+	LocalIceCandidateType      string        `json:"localIceCandidateType" yaml:"localIceCandidateType"`
+	RemoteIceCandidateType     string        `json:"remoteIceCandidateType" yaml:"remoteIceCandidateType"`
+	LocalIceCandidateEndpoint  string        `json:"localIceCandidateEndpoint" yaml:"localIceCandidateEndpoint"`
+	RemoteIceCandidateEndpoint string        `json:"remoteIceCandidateEndpoint" yaml:"remoteIceCandidateEndpoint"`
+	RelayServerAddress         string        `json:"relayServerAddress" yaml:"relayServerAddress"`
+	Latency                    time.Duration `json:"latency" yaml:"latency"`
+	LastWireguardHandshake     time.Time     `json:"lastWireguardHandshake" yaml:"lastWireguardHandshake"`
+	TransferReceived           int64         `json:"transferReceived" yaml:"transferReceived"`
+	TransferSent               int64         `json:"transferSent" yaml:"transferSent"`
+}
+
+type peersStateOutput struct {
+	Total     int                     `json:"total" yaml:"total"`
+	Connected int                     `json:"connected" yaml:"connected"`
+	Details   []peerStateDetailOutput `json:"details" yaml:"details"`
+}
+
+type connectionStateOutput struct {
+	URL       string `json:"url" yaml:"url"`
+	Connected bool   `json:"connected" yaml:"connected"`
+}
+
+type routeConflictOutput struct {
+	NetID        string `json:"netId" yaml:"netId"`
+	Network      string `json:"network" yaml:"network"`
+	LocalNetwork string `json:"localNetwork" yaml:"localNetwork"`
+	Interface    string `json:"interface" yaml:"interface"`
+	Action       string `json:"action" yaml:"action"`
+}
+
+type routeTrafficOutput struct {
+	ID        string `json:"id" yaml:"id"`
+	NetID     string `json:"netId" yaml:"netId"`
+	Network   string `json:"network" yaml:"network"`
+	TxBytes   uint64 `json:"txBytes" yaml:"txBytes"`
+	TxPackets uint64 `json:"txPackets" yaml:"txPackets"`
+	RxBytes   uint64 `json:"rxBytes" yaml:"rxBytes"`
+	RxPackets uint64 `json:"rxPackets" yaml:"rxPackets"`
+}
+
+type connSwitchOutput struct {
+	PubKey                     string    `json:"publicKey" yaml:"publicKey"`
+	Time                       time.Time `json:"time" yaml:"time"`
+	Direct                     bool      `json:"direct" yaml:"direct"`
+	LocalIceCandidateType      string    `json:"localIceCandidateType" yaml:"localIceCandidateType"`
+	RemoteIceCandidateType     string    `json:"remoteIceCandidateType" yaml:"remoteIceCandidateType"`
+	LocalIceCandidateEndpoint  string    `json:"localIceCandidateEndpoint" yaml:"localIceCandidateEndpoint"`
+	RemoteIceCandidateEndpoint string    `json:"remoteIceCandidateEndpoint" yaml:"remoteIceCandidateEndpoint"`
+}
+
+// statusOutputOverview is the machine-readable representation of the status command output
+type statusOutputOverview struct {
+	DaemonVersion   string                `json:"daemonVersion" yaml:"daemonVersion"`
+	CliVersion      string                `json:"cliVersion" yaml:"cliVersion"`
+	DaemonStatus    string                `json:"daemonStatus" yaml:"daemonStatus"`
+	Management      connectionStateOutput `json:"management" yaml:"management"`
+	Signal          connectionStateOutput `json:"signal" yaml:"signal"`
+	IP              string                `json:"netbirdIp" yaml:"netbirdIp"`
+	PubKey          string                `json:"publicKey" yaml:"publicKey"`
+	KernelInterface bool                  `json:"usesKernelInterface" yaml:"usesKernelInterface"`
+	Peers           peersStateOutput      `json:"peers" yaml:"peers"`
+	RouteConflicts  []routeConflictOutput `json:"routeConflicts" yaml:"routeConflicts"`
+	RoutesTraffic   []routeTrafficOutput  `json:"routesTraffic" yaml:"routesTraffic"`
+	ConnSwitches    []connSwitchOutput    `json:"connSwitches" yaml:"connSwitches"`
+}
+
+type eventOutput struct {
+	Type     string            `json:"type" yaml:"type"`
+	Time     time.Time         `json:"time" yaml:"time"`
+	Message  string            `json:"message" yaml:"message"`
+	Metadata map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "status of the Netbird Service",
@@ -38,6 +128,10 @@ var statusCmd = &cobra.Command{
 			return err
 		}
 
+		if jsonFlag && yamlFlag {
+			return fmt.Errorf("only one output format can be used, got both --json and --yaml")
+		}
+
 		err = util.InitLog(logLevel, "console")
 		if err != nil {
 			return fmt.Errorf("failed initializing log %v", err)
@@ -53,11 +147,26 @@ var statusCmd = &cobra.Command{
 		}
 		defer conn.Close()
 
-		resp, err := proto.NewDaemonServiceClient(conn).Status(cmd.Context(), &proto.StatusRequest{GetFullPeerStatus: true})
+		client := proto.NewDaemonServiceClient(conn)
+
+		resp, err := client.Status(cmd.Context(), &proto.StatusRequest{GetFullPeerStatus: true})
 		if err != nil {
 			return fmt.Errorf("status failed: %v", status.Convert(err).Message())
 		}
 
+		if jsonFlag || yamlFlag {
+			overview := toStatusOutputOverview(fromProtoFullStatus(resp.GetFullStatus()), resp.GetStatus(), resp.GetDaemonVersion())
+			output, err := marshalOutput(overview)
+			if err != nil {
+				return err
+			}
+			cmd.Print(output)
+			if watchFlag {
+				return watchEvents(cmd, client)
+			}
+			return nil
+		}
+
 		daemonStatus := fmt.Sprintf("Daemon status: %s\n", resp.GetStatus())
 		if resp.GetStatus() == string(internal.StatusNeedsLogin) || resp.GetStatus() == string(internal.StatusLoginFailed) {
 
@@ -69,6 +178,9 @@ var statusCmd = &cobra.Command{
 				"More info: https://www.netbird.io/docs/overview/setup-keys\n\n",
 				daemonStatus,
 			)
+			if watchFlag {
+				return watchEvents(cmd, client)
+			}
 			return nil
 		}
 
@@ -77,6 +189,10 @@ var statusCmd = &cobra.Command{
 
 		cmd.Print(parseFullStatus(fullStatus, detailFlag, daemonStatus, resp.GetDaemonVersion(), ipv4Flag))
 
+		if watchFlag {
+			return watchEvents(cmd, client)
+		}
+
 		return nil
 	},
 }
@@ -87,6 +203,171 @@ func init() {
 	statusCmd.PersistentFlags().BoolVar(&ipv4Flag, "ipv4", false, "display only NetBird IPv4 of this peer, e.g., --ipv4 will output 100.64.0.33")
 	statusCmd.PersistentFlags().StringSliceVar(&ipsFilter, "filter-by-ips", []string{}, "filters the detailed output by a list of one or more IPs, e.g., --filter-by-ips 100.64.0.100,100.64.0.200")
 	statusCmd.PersistentFlags().StringVar(&statusFilter, "filter-by-status", "", "filters the detailed output by connection status(connected|disconnected), e.g., --filter-by-status connected")
+	statusCmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "display the full status in JSON format")
+	statusCmd.PersistentFlags().BoolVar(&yamlFlag, "yaml", false, "display the full status in YAML format")
+	statusCmd.PersistentFlags().BoolVarP(&watchFlag, "watch", "w", false, "keep running and print status change events as they happen, e.g. peer connections and route changes")
+}
+
+// watchEvents prints the events streamed by the daemon until the command is interrupted or the daemon goes away
+func watchEvents(cmd *cobra.Command, client proto.DaemonServiceClient) error {
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	stream, err := client.SubscribeEvents(ctx, &proto.SubscribeEventsRequest{})
+	if err != nil {
+		return fmt.Errorf("subscribe to events failed: %v", status.Convert(err).Message())
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("receiving events failed: %v", status.Convert(err).Message())
+		}
+
+		output, err := parseEvent(event)
+		if err != nil {
+			return err
+		}
+		cmd.Print(output)
+	}
+}
+
+// parseEvent formats an event in the selected output format, JSON events are printed one per line
+func parseEvent(event *proto.SystemEvent) (string, error) {
+	eventTime := event.GetTime().AsTime().Local()
+
+	if !jsonFlag && !yamlFlag {
+		return fmt.Sprintf("%s %s: %s\n", eventTime.Format("2006-01-02 15:04:05"), event.GetType(), event.GetMessage()), nil
+	}
+
+	output := eventOutput{
+		Type:     event.GetType(),
+		Time:     eventTime,
+		Message:  event.GetMessage(),
+		Metadata: event.GetMetadata(),
+	}
+
+	if jsonFlag {
+		jsonBytes, err := json.Marshal(output)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal event to json: %v", err)
+		}
+		return string(jsonBytes) + "\n", nil
+	}
+
+	yamlBytes, err := yaml.Marshal(output)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal event to yaml: %v", err)
+	}
+	return "---\n" + string(yamlBytes), nil
+}
+
+// marshalOutput formats the status overview in the selected machine-readable format
+func marshalOutput(overview statusOutputOverview) (string, error) {
+	if jsonFlag {
+		jsonBytes, err := json.MarshalIndent(overview, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal status to json: %v", err)
+		}
+		return string(jsonBytes) + "\n", nil
+	}
+
+	yamlBytes, err := yaml.Marshal(overview)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal status to yaml: %v", err)
+	}
+	return string(yamlBytes), nil
+}
+
+// toStatusOutputOverview converts the full status to its machine-readable representation, peer filters are applied
+func toStatusOutputOverview(fullStatus nbStatus.FullStatus, daemonStatus, daemonVersion string) statusOutputOverview {
+	overview := statusOutputOverview{
+		DaemonVersion: daemonVersion,
+		CliVersion:    system.NetbirdVersion(),
+		DaemonStatus:  daemonStatus,
+		Management: connectionStateOutput{
+			URL:       fullStatus.ManagementState.URL,
+			Connected: fullStatus.ManagementState.Connected,
+		},
+		Signal: connectionStateOutput{
+			URL:       fullStatus.SignalState.URL,
+			Connected: fullStatus.SignalState.Connected,
+		},
+		IP:              fullStatus.LocalPeerState.IP,
+		PubKey:          fullStatus.LocalPeerState.PubKey,
+		KernelInterface: fullStatus.LocalPeerState.KernelInterface,
+		Peers: peersStateOutput{
+			Total:   len(fullStatus.Peers),
+			Details: []peerStateDetailOutput{},
+		},
+		RouteConflicts: []routeConflictOutput{},
+		RoutesTraffic:  []routeTrafficOutput{},
+		ConnSwitches:   []connSwitchOutput{},
+	}
+
+	peers := fullStatus.Peers
+	sort.SliceStable(peers, func(i, j int) bool {
+		iAddr, _ := netip.ParseAddr(peers[i].IP)
+		jAddr, _ := netip.ParseAddr(peers[j].IP)
+		return iAddr.Compare(jAddr) == -1
+	})
+
+	connectedStatusString := peer.StatusConnected.String()
+
+	for _, peerState := range peers {
+		isConnected := peerState.ConnStatus == connectedStatusString
+		if isConnected {
+			overview.Peers.Connected++
+		}
+
+		if skipDetailByFilters(peerState, isConnected) {
+			continue
+		}
+
+		connType := ""
+		if isConnected {
+			connType = "P2P"
+			if peerState.Relayed {
+				connType = "Relayed"
+			}
+		}
+
+		overview.Peers.Details = append(overview.Peers.Details, peerStateDetailOutput{
+			IP:                         peerState.IP,
+			PubKey:                     peerState.PubKey,
+			Status:                     peerState.ConnStatus,
+			LastStatusUpdate:           peerState.ConnStatusUpdate,
+			ConnType:                   connType,
+			ConnectionPolicy:           peerState.ConnectionPolicy,
+			Direct:                     peerState.Direct,
+			LocalIceCandidateType:      peerState.LocalIceCandidateType,
+			RemoteIceCandidateType:     peerState.RemoteIceCandidateType,
+			LocalIceCandidateEndpoint:  peerState.LocalIceCandidateEndpoint,
+			RemoteIceCandidateEndpoint: peerState.RemoteIceCandidateEndpoint,
+			RelayServerAddress:         peerState.RelayServerAddress,
+			Latency:                    peerState.Latency,
+			LastWireguardHandshake:     peerState.LastWireguardHandshake,
+			TransferReceived:           peerState.BytesRx,
+			TransferSent:               peerState.BytesTx,
+		})
+	}
+
+	for _, conflict := range fullStatus.RouteConflicts {
+		overview.RouteConflicts = append(overview.RouteConflicts, routeConflictOutput(conflict))
+	}
+
+	for _, traffic := range fullStatus.RoutesTraffic {
+		overview.RoutesTraffic = append(overview.RoutesTraffic, routeTrafficOutput(traffic))
+	}
+
+	for _, connSwitch := range fullStatus.ConnSwitches {
+		overview.ConnSwitches = append(overview.ConnSwitches, connSwitchOutput(connSwitch))
+	}
+
+	return overview
 }
 
 func parseFilters() error {
@@ -128,20 +409,64 @@ func fromProtoFullStatus(pbFullStatus *proto.FullStatus) nbStatus.FullStatus {
 	for _, pbPeerState := range pbFullStatus.GetPeers() {
 		timeLocal := pbPeerState.GetConnStatusUpdate().AsTime().Local()
 		peerState := nbStatus.PeerState{
-			IP:                     pbPeerState.GetIP(),
-			PubKey:                 pbPeerState.GetPubKey(),
-			ConnStatus:             pbPeerState.GetConnStatus(),
-			ConnStatusUpdate:       timeLocal,
-			Relayed:                pbPeerState.GetRelayed(),
-			Direct:                 pbPeerState.GetDirect(),
-			LocalIceCandidateType:  pbPeerState.GetLocalIceCandidateType(),
-			RemoteIceCandidateType: pbPeerState.GetRemoteIceCandidateType(),
+			IP:                         pbPeerState.GetIP(),
+			PubKey:                     pbPeerState.GetPubKey(),
+			ConnStatus:                 pbPeerState.GetConnStatus(),
+			ConnStatusUpdate:           timeLocal,
+			Relayed:                    pbPeerState.GetRelayed(),
+			Direct:                     pbPeerState.GetDirect(),
+			LocalIceCandidateType:      pbPeerState.GetLocalIceCandidateType(),
+			RemoteIceCandidateType:     pbPeerState.GetRemoteIceCandidateType(),
+			Latency:                    pbPeerState.GetLatency().AsDuration(),
+			BytesRx:                    pbPeerState.GetBytesRx(),
+			BytesTx:                    pbPeerState.GetBytesTx(),
+			RelayServerAddress:         pbPeerState.GetRelayServerAddress(),
+			LocalIceCandidateEndpoint:  pbPeerState.GetLocalIceCandidateEndpoint(),
+			RemoteIceCandidateEndpoint: pbPeerState.GetRemoteIceCandidateEndpoint(),
+			ConnectionPolicy:           pbPeerState.GetConnectionPolicy(),
+		}
+		if handshake := pbPeerState.GetLastWireguardHandshake(); handshake != nil && handshake.AsTime().Unix() > 0 {
+			peerState.LastWireguardHandshake = handshake.AsTime().Local()
 		}
 		peersState = append(peersState, peerState)
 	}
 
 	fullStatus.Peers = peersState
 
+	for _, pbConflict := range pbFullStatus.GetRouteConflicts() {
+		fullStatus.RouteConflicts = append(fullStatus.RouteConflicts, nbStatus.RouteConflict{
+			NetID:        pbConflict.GetNetID(),
+			Network:      pbConflict.GetNetwork(),
+			LocalNetwork: pbConflict.GetLocalNetwork(),
+			Interface:    pbConflict.GetInterface(),
+			Action:       pbConflict.GetAction(),
+		})
+	}
+
+	for _, pbConnSwitch := range pbFullStatus.GetConnSwitches() {
+		fullStatus.ConnSwitches = append(fullStatus.ConnSwitches, nbStatus.ConnSwitch{
+			PubKey:                     pbConnSwitch.GetPubKey(),
+			Time:                       pbConnSwitch.GetTime().AsTime().Local(),
+			Direct:                     pbConnSwitch.GetDirect(),
+			LocalIceCandidateType:      pbConnSwitch.GetLocalIceCandidateType(),
+			RemoteIceCandidateType:     pbConnSwitch.GetRemoteIceCandidateType(),
+			LocalIceCandidateEndpoint:  pbConnSwitch.GetLocalIceCandidateEndpoint(),
+			RemoteIceCandidateEndpoint: pbConnSwitch.GetRemoteIceCandidateEndpoint(),
+		})
+	}
+
+	for _, pbTraffic := range pbFullStatus.GetRoutesTraffic() {
+		fullStatus.RoutesTraffic = append(fullStatus.RoutesTraffic, nbStatus.RouteTraffic{
+			ID:        pbTraffic.GetID(),
+			NetID:     pbTraffic.GetNetID(),
+			Network:   pbTraffic.GetNetwork(),
+			TxBytes:   pbTraffic.GetTxBytes(),
+			TxPackets: pbTraffic.GetTxPackets(),
+			RxBytes:   pbTraffic.GetRxBytes(),
+			RxPackets: pbTraffic.GetRxPackets(),
+		})
+	}
+
 	return fullStatus
 }
 
@@ -211,18 +536,71 @@ func parseFullStatus(fullStatus nbStatus.FullStatus, printDetail bool, daemonSta
 		peersCountString,
 	)
 
+	if len(fullStatus.RouteConflicts) > 0 {
+		summary += fmt.Sprintf("Route conflicts: %d\n", len(fullStatus.RouteConflicts))
+	}
+
 	if printDetail {
 		return fmt.Sprintf(
 			"Peers detail:"+
 				"%s\n"+
+				"%s"+
+				"%s"+
 				"%s",
 			parsedPeersString,
+			parseRouteConflicts(fullStatus.RouteConflicts),
+			parseRoutesTraffic(fullStatus.RoutesTraffic),
 			summary,
 		)
 	}
 	return summary
 }
 
+func parseRouteConflicts(conflicts []nbStatus.RouteConflict) string {
+	if len(conflicts) == 0 {
+		return ""
+	}
+
+	conflictsString := "Route conflicts:"
+	for _, conflict := range conflicts {
+		conflictsString += fmt.Sprintf(
+			"\n Route: %s\n"+
+				"  Network: %s\n"+
+				"  Local network: %s on %s\n"+
+				"  Action: %s\n",
+			conflict.NetID,
+			conflict.Network,
+			conflict.LocalNetwork,
+			conflict.Interface,
+			conflict.Action,
+		)
+	}
+	return conflictsString + "\n"
+}
+
+func parseRoutesTraffic(routesTraffic []nbStatus.RouteTraffic) string {
+	if len(routesTraffic) == 0 {
+		return ""
+	}
+
+	trafficString := "Routed traffic:"
+	for _, traffic := range routesTraffic {
+		trafficString += fmt.Sprintf(
+			"\n Route: %s\n"+
+				"  Network: %s\n"+
+				"  Forwarded to network: %d bytes, %d packets\n"+
+				"  Forwarded from network: %d bytes, %d packets\n",
+			traffic.NetID,
+			traffic.Network,
+			traffic.TxBytes,
+			traffic.TxPackets,
+			traffic.RxBytes,
+			traffic.RxPackets,
+		)
+	}
+	return trafficString + "\n"
+}
+
 func parsePeers(peers []nbStatus.PeerState, printDetail bool) (string, int) {
 	var (
 		peersString    = ""
@@ -254,15 +632,31 @@ func parsePeers(peers []nbStatus.PeerState, printDetail bool) (string, int) {
 
 			localICE := "-"
 			remoteICE := "-"
+			localICEEndpoint := "-"
+			remoteICEEndpoint := "-"
 			connType := "-"
+			latency := "-"
+			lastHandshake := "-"
+			relayServer := "-"
 
 			if peerConnectionStatus {
 				localICE = peerState.LocalIceCandidateType
 				remoteICE = peerState.RemoteIceCandidateType
+				localICEEndpoint = valueOrDash(peerState.LocalIceCandidateEndpoint)
+				remoteICEEndpoint = valueOrDash(peerState.RemoteIceCandidateEndpoint)
 				connType = "P2P"
 				if peerState.Relayed {
 					connType = "Relayed"
 				}
+				if peerState.Latency > 0 {
+					latency = peerState.Latency.String()
+				}
+				if !peerState.LastWireguardHandshake.IsZero() {
+					lastHandshake = peerState.LastWireguardHandshake.Format("2006-01-02 15:04:05")
+				}
+				if peerState.RelayServerAddress != "" {
+					relayServer = peerState.RelayServerAddress
+				}
 			}
 
 			peerString := fmt.Sprintf(
@@ -272,16 +666,30 @@ func parsePeers(peers []nbStatus.PeerState, printDetail bool) (string, int) {
 					"  Status: %s\n"+
 					"  -- detail --\n"+
 					"  Connection type: %s\n"+
+					"  Connection policy: %s\n"+
 					"  Direct: %t\n"+
 					"  ICE candidate (Local/Remote): %s/%s\n"+
+					"  ICE candidate endpoints (Local/Remote): %s/%s\n"+
+					"  Relay server address: %s\n"+
+					"  Latency: %s\n"+
+					"  Last WireGuard handshake: %s\n"+
+					"  Transfer status (received/sent): %s/%s\n"+
 					"  Last connection update: %s\n",
 				peerState.IP,
 				peerState.PubKey,
 				peerState.ConnStatus,
 				connType,
+				valueOrDash(peerState.ConnectionPolicy),
 				peerState.Direct,
 				localICE,
 				remoteICE,
+				localICEEndpoint,
+				remoteICEEndpoint,
+				relayServer,
+				latency,
+				lastHandshake,
+				toIEC(peerState.BytesRx),
+				toIEC(peerState.BytesTx),
 				peerState.ConnStatusUpdate.Format("2006-01-02 15:04:05"),
 			)
 
@@ -291,6 +699,20 @@ func parsePeers(peers []nbStatus.PeerState, printDetail bool) (string, int) {
 	return peersString, peersConnected
 }
 
+// toIEC formats a number of bytes with binary prefixes, e.g. 1.5 KiB
+func toIEC(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
 func skipDetailByFilters(peerState nbStatus.PeerState, isConnected bool) bool {
 	statusEval := false
 	ipEval := false
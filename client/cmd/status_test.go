@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/netbirdio/netbird/client/proto"
+	nbStatus "github.com/netbirdio/netbird/client/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestToIEC(t *testing.T) {
+	testCases := []struct {
+		bytes    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, toIEC(testCase.bytes))
+	}
+}
+
+func TestToStatusOutputOverview(t *testing.T) {
+	ipsFilter = nil
+	statusFilter = "connected"
+	defer func() { statusFilter = "" }()
+
+	handshake := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	fullStatus := nbStatus.FullStatus{
+		ManagementState: nbStatus.ManagementState{URL: "https://mgm:33073", Connected: true},
+		SignalState:     nbStatus.SignalState{URL: "https://signal:10000", Connected: false},
+		LocalPeerState:  nbStatus.LocalPeerState{IP: "100.64.0.1/16", PubKey: "localKey", KernelInterface: true},
+		Peers: []nbStatus.PeerState{
+			{IP: "100.64.0.20", PubKey: "peerB", ConnStatus: "Disconnected"},
+			{
+				IP:                     "100.64.0.10",
+				PubKey:                 "peerA",
+				ConnStatus:             "Connected",
+				Relayed:                true,
+				LocalIceCandidateType:  "relay",
+				RemoteIceCandidateType: "host",
+				RelayServerAddress:     "turn.netbird.io:443",
+				ConnectionPolicy:       "relay",
+				Latency:                10 * time.Millisecond,
+				LastWireguardHandshake: handshake,
+				BytesRx:                100,
+				BytesTx:                200,
+			},
+		},
+		RoutesTraffic: []nbStatus.RouteTraffic{{ID: "r1", NetID: "net1", Network: "10.0.0.0/24", TxBytes: 5}},
+	}
+
+	overview := toStatusOutputOverview(fullStatus, "Connected", "0.10.0")
+
+	assert.Equal(t, "0.10.0", overview.DaemonVersion)
+	assert.Equal(t, "Connected", overview.DaemonStatus)
+	assert.True(t, overview.Management.Connected)
+	assert.False(t, overview.Signal.Connected)
+	assert.Equal(t, 2, overview.Peers.Total)
+	assert.Equal(t, 1, overview.Peers.Connected)
+	require.Len(t, overview.Peers.Details, 1, "status filter should be applied")
+	assert.Equal(t, "Relayed", overview.Peers.Details[0].ConnType)
+	assert.Equal(t, "relay", overview.Peers.Details[0].ConnectionPolicy)
+	assert.Equal(t, int64(100), overview.Peers.Details[0].TransferReceived)
+	require.Len(t, overview.RoutesTraffic, 1)
+	assert.Equal(t, uint64(5), overview.RoutesTraffic[0].TxBytes)
+	assert.Empty(t, overview.ConnSwitches)
+
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+	jsonOutput, err := marshalOutput(overview)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(jsonOutput), &decoded))
+	assert.Equal(t, "100.64.0.1/16", decoded["netbirdIp"])
+	assert.Equal(t, "turn.netbird.io:443", decoded["peers"].(map[string]interface{})["details"].([]interface{})[0].(map[string]interface{})["relayServerAddress"])
+
+	jsonFlag = false
+	yamlFlag = true
+	defer func() { yamlFlag = false }()
+	yamlOutput, err := marshalOutput(overview)
+	require.NoError(t, err)
+	assert.Contains(t, yamlOutput, "relayServerAddress: turn.netbird.io:443")
+	assert.Contains(t, yamlOutput, "latency: 10ms")
+	assert.Contains(t, yamlOutput, "connectionPolicy: relay")
+}
+
+func TestParseEvent(t *testing.T) {
+	event := &proto.SystemEvent{
+		Type:     "PeerConnected",
+		Time:     timestamppb.Now(),
+		Message:  "peer 100.64.0.10 connected (P2P)",
+		Metadata: map[string]string{"pubKey": "peerA"},
+	}
+
+	output, err := parseEvent(event)
+	require.NoError(t, err)
+	assert.Contains(t, output, "PeerConnected: peer 100.64.0.10 connected (P2P)")
+
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+	output, err = parseEvent(event)
+	require.NoError(t, err)
+
+	var decoded eventOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+	assert.Equal(t, "peerA", decoded.Metadata["pubKey"])
+}
@@ -13,6 +13,11 @@ import (
 	gstatus "google.golang.org/grpc/status"
 )
 
+var (
+	netstackFlag         bool
+	netstackProxyAddress string
+)
+
 var upCmd = &cobra.Command{
 	Use:   "up",
 	Short: "install, login and start Netbird client",
@@ -42,6 +47,13 @@ var upCmd = &cobra.Command{
 
 			config, _ = internal.UpdateOldManagementPort(ctx, config, configPath)
 
+			if cmd.Flag("netstack").Changed {
+				config.Netstack = netstackFlag
+			}
+			if cmd.Flag("netstack-proxy-address").Changed {
+				config.NetstackProxyAddress = netstackProxyAddress
+			}
+
 			err = foregroundLogin(ctx, cmd, config, setupKey)
 			if err != nil {
 				return fmt.Errorf("foreground login failed: %v", err)
@@ -50,7 +62,17 @@ var upCmd = &cobra.Command{
 			var cancel context.CancelFunc
 			ctx, cancel = context.WithCancel(ctx)
 			SetupCloseHandler(ctx, cancel)
-			return internal.RunClient(ctx, config, nbStatus.NewRecorder())
+			err = internal.RunClient(ctx, config, nbStatus.NewRecorder(), nil)
+			// there is no daemon to take netbird down in the foreground mode, stopping the client lifts the kill-switch
+			if killSwitchErr := internal.DisableKillSwitch(); killSwitchErr != nil {
+				log.Errorf("failed disabling the kill-switch: %v", killSwitchErr)
+			}
+			return err
+		}
+
+		if cmd.Flag("netstack").Changed || cmd.Flag("netstack-proxy-address").Changed {
+			return fmt.Errorf("the netstack flags only apply to the foreground mode, " +
+				"configure the daemon with: netbird config set netstack true")
 		}
 
 		conn, err := DialClientGRPCServer(ctx, daemonAddr)
@@ -79,10 +101,16 @@ var upCmd = &cobra.Command{
 			return nil
 		}
 
+		flow, err := parseSSOFlow(ssoFlow)
+		if err != nil {
+			return err
+		}
+
 		loginRequest := proto.LoginRequest{
 			SetupKey:      setupKey,
 			PreSharedKey:  preSharedKey,
 			ManagementUrl: managementURL,
+			SsoFlow:       flow,
 		}
 
 		var loginErr error
@@ -126,3 +154,9 @@ var upCmd = &cobra.Command{
 		return nil
 	},
 }
+
+func init() {
+	upCmd.Flags().BoolVar(&netstackFlag, "netstack", false, "run WireGuard on an in-process network stack without root privileges, "+
+		"peers are reached through a local SOCKS5 and HTTP CONNECT proxy (foreground mode only)")
+	upCmd.Flags().StringVar(&netstackProxyAddress, "netstack-proxy-address", "", "local address of the proxy to the mesh in netstack mode (default \"127.0.0.1:1080\")")
+}
@@ -0,0 +1,330 @@
+// Package embed runs a NetBird client inside a Go program, without a separate daemon.
+//
+// By default the client creates the same WireGuard interface as the daemon does, so the program needs the privileges
+// to create network interfaces. With Options.Netstack the client runs on an in-process network stack instead and needs
+// no privileges, the network is then only reachable through the client. Connections to peers are made with
+// Client.Dial and accepted with Client.Listen.
+package embed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal"
+	nbStatus "github.com/netbirdio/netbird/client/status"
+)
+
+// statusCheckInterval is the interval between checks whether a starting client is connected
+const statusCheckInterval = 100 * time.Millisecond
+
+var (
+	// ErrClientStarted is returned by Start if the client is already running
+	ErrClientStarted = errors.New("client is already started")
+	// ErrClientNotStarted is returned if the client needs to be started for the operation
+	ErrClientNotStarted = errors.New("client is not started")
+	// ErrPeerNotFound is returned by ResolvePeer if no peer matches
+	ErrPeerNotFound = errors.New("peer not found")
+)
+
+// Options configure an embedded client
+type Options struct {
+	// ManagementURL is the URL of the Management service. The NetBird cloud is used when empty
+	ManagementURL string
+	// SetupKey registers the client as a new peer. Either a setup key or a JWT token is needed unless the peer
+	// stored in the config is already registered
+	SetupKey string
+	// JWTToken registers the client as a peer of the user the token was issued to
+	JWTToken string
+	// PreSharedKey is the WireGuard pre-shared key of the network, if any
+	PreSharedKey string
+	// ConfigPath is the file the client keys and settings are stored in.
+	// Reusing it keeps the peer identity between runs, a missing file is created
+	ConfigPath string
+	// InterfaceName is the name of the WireGuard interface, the platform default is used when empty
+	InterfaceName string
+	// WireguardPort is the port WireGuard listens on, the default port is used when zero
+	WireguardPort int
+	// Netstack runs WireGuard on an in-process network stack instead of an interface of the host.
+	// Dial and Listen connect through the stack, no SOCKS5 proxy is started
+	Netstack bool
+}
+
+// Client is a NetBird client running in the current process
+type Client struct {
+	mu       sync.Mutex
+	config   *internal.Config
+	setupKey string
+	jwtToken string
+
+	recorder *nbStatus.Status
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	engineMu sync.Mutex
+	// engine is the running engine, Dial and Listen connect through its network stack in netstack mode
+	engine *internal.Engine
+}
+
+// New reads or creates the client config and returns a client ready to be started
+func New(opts Options) (*Client, error) {
+	if opts.ConfigPath == "" {
+		return nil, fmt.Errorf("config path is required")
+	}
+
+	config, err := internal.GetConfig(opts.ManagementURL, "", opts.ConfigPath, opts.PreSharedKey)
+	if err != nil {
+		return nil, fmt.Errorf("get config: %v", err)
+	}
+
+	settings := map[string]string{}
+	if opts.InterfaceName != "" {
+		settings["interface-name"] = opts.InterfaceName
+	}
+	if opts.WireguardPort != 0 {
+		settings["wireguard-port"] = strconv.Itoa(opts.WireguardPort)
+	}
+	settings["netstack"] = strconv.FormatBool(opts.Netstack)
+	if opts.Netstack {
+		settings["netstack-proxy-address"] = internal.NetstackProxyDisabled
+	}
+
+	config, changed, err := internal.UpdateConfigSettings(config, settings)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		err = internal.WriteOutConfig(opts.ConfigPath, config)
+		if err != nil {
+			return nil, fmt.Errorf("write config: %v", err)
+		}
+	}
+
+	return &Client{
+		config:   config,
+		setupKey: opts.SetupKey,
+		jwtToken: opts.JWTToken,
+		recorder: nbStatus.NewRecorder(),
+	}, nil
+}
+
+// Start logs the client in and brings up the connection to the network.
+// If the Management service is unreachable, the client is brought up from the last network map it has received.
+// It returns once the client is connected, or stops the client and returns an error if ctx is done first.
+// The client keeps reconnecting in the background until Stop is called
+func (c *Client) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		return ErrClientStarted
+	}
+
+	err := internal.Login(ctx, c.config, c.setupKey, c.jwtToken)
+	if err != nil {
+		// a Management service answering with an error other than Unavailable is reachable and has rejected the login
+		if s, ok := gstatus.FromError(err); (ok && s.Code() != codes.Unavailable) || !internal.HasNetworkSnapshot(c.config) {
+			return fmt.Errorf("login: %v", err)
+		}
+		log.Warnf("the Management service is unreachable, starting from the network snapshot: %v", err)
+	}
+
+	runCtx, cancel := context.WithCancel(internal.CtxInitState(context.Background()))
+	done := make(chan struct{})
+	var runErr error
+	go func() {
+		defer close(done)
+		runErr = internal.RunClientWithEngineListener(runCtx, c.config, c.recorder, nil, c.setEngine)
+		if runErr != nil {
+			log.Errorf("run client connection: %v", runErr)
+		}
+	}()
+
+	state := internal.CtxGetState(runCtx)
+	ticker := time.NewTicker(statusCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return ctx.Err()
+		case <-done:
+			cancel()
+			if runErr != nil {
+				return runErr
+			}
+			return fmt.Errorf("client stopped while connecting")
+		case <-ticker.C:
+			status, err := state.Status()
+			if err != nil {
+				continue
+			}
+			if status == internal.StatusConnected {
+				c.cancel = cancel
+				c.done = done
+				return nil
+			}
+		}
+	}
+}
+
+// setEngine keeps the started engine until its context is done
+func (c *Client) setEngine(ctx context.Context, engine *internal.Engine) {
+	c.engineMu.Lock()
+	c.engine = engine
+	c.engineMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		c.engineMu.Lock()
+		if c.engine == engine {
+			c.engine = nil
+		}
+		c.engineMu.Unlock()
+	}()
+}
+
+// netstackEngine returns the running engine if the client runs on an in-process network stack
+func (c *Client) netstackEngine() (*internal.Engine, error) {
+	if !c.config.Netstack {
+		return nil, nil
+	}
+
+	c.engineMu.Lock()
+	defer c.engineMu.Unlock()
+	if c.engine == nil {
+		return nil, fmt.Errorf("client is not connected")
+	}
+	return c.engine, nil
+}
+
+// Stop disconnects the client from the network and removes its interface.
+// It waits for the client to stop until ctx is done
+func (c *Client) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel == nil {
+		return ErrClientNotStarted
+	}
+
+	c.cancel()
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	c.cancel = nil
+	c.done = nil
+	return nil
+}
+
+// Status returns the state of the connections to the Management and Signal services and to the peers
+func (c *Client) Status() nbStatus.FullStatus {
+	return c.recorder.GetFullStatus()
+}
+
+// ResolvePeer returns the state of a peer, looked up by its NetBird IP or WireGuard public key
+func (c *Client) ResolvePeer(target string) (nbStatus.PeerState, error) {
+	peerState, err := c.recorder.GetPeer(target)
+	if err == nil {
+		return peerState, nil
+	}
+
+	for _, peerState := range c.recorder.GetFullStatus().Peers {
+		if peerState.IP == target {
+			return peerState, nil
+		}
+	}
+	return nbStatus.PeerState{}, ErrPeerNotFound
+}
+
+// Dial connects to an address in the network, the local end of the connection is the NetBird IP of the client.
+// See net.Dial for the supported networks and address formats, in netstack mode the host has to be an IP address
+func (c *Client) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	localIP, err := c.localIP()
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := c.netstackEngine()
+	if err != nil {
+		return nil, err
+	}
+	if engine != nil {
+		return engine.DialContext(ctx, network, address)
+	}
+
+	dialer := &net.Dialer{}
+	switch network {
+	case "tcp", "tcp4":
+		dialer.LocalAddr = &net.TCPAddr{IP: localIP.AsSlice()}
+	case "udp", "udp4":
+		dialer.LocalAddr = &net.UDPAddr{IP: localIP.AsSlice()}
+	default:
+		return nil, fmt.Errorf("unsupported network %s", network)
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
+
+// Listen announces on the NetBird IP of the client, address is the port to listen on, e.g. ":8080".
+// Only TCP networks are supported
+func (c *Client) Listen(network, address string) (net.Listener, error) {
+	if network != "tcp" && network != "tcp4" {
+		return nil, fmt.Errorf("unsupported network %s", network)
+	}
+
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	localIP, err := c.localIP()
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := c.netstackEngine()
+	if err != nil {
+		return nil, err
+	}
+	if engine != nil {
+		return engine.Listen(network, net.JoinHostPort(localIP.String(), port))
+	}
+
+	return net.Listen(network, net.JoinHostPort(localIP.String(), port))
+}
+
+// localIP returns the NetBird IP of the client once it is connected
+func (c *Client) localIP() (netip.Addr, error) {
+	c.mu.Lock()
+	started := c.cancel != nil
+	c.mu.Unlock()
+	if !started {
+		return netip.Addr{}, ErrClientNotStarted
+	}
+
+	address := c.recorder.GetFullStatus().LocalPeerState.IP
+	if address == "" {
+		return netip.Addr{}, fmt.Errorf("client is not connected")
+	}
+
+	prefix, err := netip.ParsePrefix(address)
+	if err == nil {
+		return prefix.Addr(), nil
+	}
+	return netip.ParseAddr(address)
+}
@@ -0,0 +1,203 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	mgmtProto "github.com/netbirdio/netbird/management/proto"
+	mgmt "github.com/netbirdio/netbird/management/server"
+	sigProto "github.com/netbirdio/netbird/signal/proto"
+	sig "github.com/netbirdio/netbird/signal/server"
+	"github.com/netbirdio/netbird/util"
+)
+
+const testSetupKey = "A2C8E62B-38F5-4553-B31E-DD66C696CEBB"
+
+func TestClient(t *testing.T) {
+	managementURL, _ := startTestingServices(t)
+
+	// on in-process network stacks the NetBird IPs aren't addresses of the host, the clients can only reach
+	// each other through WireGuard
+	clientA := newTestClient(t, managementURL, filepath.Join(t.TempDir(), "config.json"), 0, true)
+	clientB := newTestClient(t, managementURL, filepath.Join(t.TempDir(), "config.json"), 1, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, clientA.Start(ctx), "client A should start")
+	defer clientA.Stop(context.Background()) //nolint
+	require.NoError(t, clientB.Start(ctx), "client B should start")
+	defer clientB.Stop(context.Background()) //nolint
+
+	assert.ErrorIs(t, clientA.Start(ctx), ErrClientStarted)
+
+	ipB, err := clientB.localIP()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		peerState, err := clientA.ResolvePeer(ipB.String())
+		return err == nil && peerState.ConnStatus == "Connected"
+	}, 30*time.Second, 100*time.Millisecond, "client A should connect to client B")
+
+	peerState, err := clientA.ResolvePeer(clientB.Status().LocalPeerState.PubKey)
+	require.NoError(t, err, "peers should be resolved by public key")
+	assert.Equal(t, ipB.String(), peerState.IP)
+
+	_, err = clientA.ResolvePeer("100.64.255.254")
+	assert.ErrorIs(t, err, ErrPeerNotFound)
+
+	listener, err := clientB.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	ipA, err := clientA.localIP()
+	require.NoError(t, err)
+	hostAddrs, err := net.InterfaceAddrs()
+	require.NoError(t, err)
+	for _, addr := range hostAddrs {
+		hostIP := addr.(*net.IPNet).IP.String()
+		require.NotContains(t, []string{ipA.String(), ipB.String()}, hostIP, "the NetBird IPs should not be assigned on the host")
+	}
+
+	conn, err := clientA.Dial(ctx, "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, ipA.String(), conn.LocalAddr().(*net.TCPAddr).IP.String())
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+
+	require.Eventually(t, func() bool {
+		peerState, err := clientA.ResolvePeer(ipB.String())
+		return err == nil && peerState.BytesRx > 0 && !peerState.LastWireguardHandshake.IsZero()
+	}, 30*time.Second, 500*time.Millisecond, "the traffic should have been received through WireGuard")
+
+	require.NoError(t, clientA.Stop(ctx))
+	assert.ErrorIs(t, clientA.Stop(ctx), ErrClientNotStarted)
+	_, err = clientA.Dial(ctx, "tcp", listener.Addr().String())
+	assert.ErrorIs(t, err, ErrClientNotStarted)
+}
+
+func newTestClient(t *testing.T, managementURL, configPath string, i int, netstack bool) *Client {
+	t.Helper()
+
+	ifaceName := fmt.Sprintf("wt%d", 50+i)
+	if runtime.GOOS == "darwin" {
+		ifaceName = fmt.Sprintf("utun15%d", i)
+	}
+
+	client, err := New(Options{
+		ManagementURL: managementURL,
+		SetupKey:      testSetupKey,
+		ConfigPath:    configPath,
+		InterfaceName: ifaceName,
+		WireguardPort: 33150 + i,
+		Netstack:      netstack,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_StartFromNetworkSnapshot(t *testing.T) {
+	managementURL, stopManagement := startTestingServices(t)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	client := newTestClient(t, managementURL, configPath, 2, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, client.Start(ctx))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(configPath + ".snapshot")
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond, "network snapshot should be persisted")
+	ip, err := client.localIP()
+	require.NoError(t, err)
+	require.NoError(t, client.Stop(ctx))
+
+	stopManagement()
+
+	// another interface avoids waiting for the previous one to be released
+	client = newTestClient(t, managementURL, configPath, 3, false)
+	offlineCtx, offlineCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer offlineCancel()
+	require.NoError(t, client.Start(offlineCtx), "client should start from the network snapshot")
+	defer client.Stop(context.Background()) //nolint
+
+	status := client.Status()
+	assert.False(t, status.ManagementState.Connected)
+	assert.Equal(t, ip.String(), strings.Split(status.LocalPeerState.IP, "/")[0])
+}
+
+func startTestingServices(t *testing.T) (string, func()) {
+	t.Helper()
+
+	dataDir := t.TempDir()
+	err := util.CopyFileContents("../testdata/store.json", filepath.Join(dataDir, "store.json"))
+	require.NoError(t, err)
+
+	signalLis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	signalServer := grpc.NewServer()
+	sigProto.RegisterSignalExchangeServer(signalServer, sig.NewServer())
+	go func() {
+		_ = signalServer.Serve(signalLis)
+	}()
+	t.Cleanup(signalServer.Stop)
+
+	config := &mgmt.Config{
+		Stuns:      []*mgmt.Host{},
+		TURNConfig: &mgmt.TURNConfig{},
+		Signal: &mgmt.Host{
+			Proto: "http",
+			URI:   signalLis.Addr().String(),
+		},
+		Datadir: dataDir,
+	}
+
+	mgmtLis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	store, err := mgmt.NewStore(config.Datadir)
+	require.NoError(t, err)
+	peersUpdateManager := mgmt.NewPeersUpdateManager()
+	accountManager, err := mgmt.BuildManager(store, peersUpdateManager, nil, "")
+	require.NoError(t, err)
+	turnManager := mgmt.NewTimeBasedAuthSecretsManager(peersUpdateManager, config.TURNConfig)
+	mgmtServer, err := mgmt.NewServer(config, accountManager, peersUpdateManager, turnManager, nil)
+	require.NoError(t, err)
+
+	managementServer := grpc.NewServer()
+	mgmtProto.RegisterManagementServiceServer(managementServer, mgmtServer)
+	go func() {
+		_ = managementServer.Serve(mgmtLis)
+	}()
+	t.Cleanup(managementServer.Stop)
+
+	return "http://" + mgmtLis.Addr().String(), managementServer.Stop
+}
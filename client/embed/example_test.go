@@ -0,0 +1,46 @@
+package embed_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/netbirdio/netbird/client/embed"
+)
+
+// This example joins a network with a setup key and fetches a page served by a peer
+func Example() {
+	client, err := embed.New(embed.Options{
+		ManagementURL: "https://api.netbird.io:443",
+		SetupKey:      "A2C8E62B-38F5-4553-B31E-DD66C696CEBB",
+		ConfigPath:    "/var/lib/myservice/netbird.json",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err = client.Start(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Stop(context.Background()) //nolint
+
+	httpClient := &http.Client{Transport: &http.Transport{DialContext: client.Dial}}
+	resp, err := httpClient.Get("http://100.64.0.10:8080/")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(body))
+}
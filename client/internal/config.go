@@ -41,10 +41,59 @@ type Config struct {
 	IFaceBlackList []string
 	// SSHKey is a private SSH key in a PEM format
 	SSHKey string
+	// ManagementFailoverURLs are further endpoints of a highly available Management service.
+	// They are tried in order when the endpoint at ManagementURL isn't reachable
+	ManagementFailoverURLs []*url.URL
+	// RouteLatencyThresholdMs is the minimum latency improvement in milliseconds required to switch between
+	// routing peers of the same network. Zero means the default threshold is used
+	RouteLatencyThresholdMs int
+	// RouteOverlapPolicy defines how routes overlapping with local networks are handled:
+	// skip, prefer-local or prefer-netbird. Empty means prefer-local
+	RouteOverlapPolicy string
+	// DeselectedRoutes is a list of network IDs the user opted out of. Networks are selected by default
+	DeselectedRoutes []string
+	// LazyConnectionEnabled connects to remote peers only when there is traffic for them or they request a connection
+	LazyConnectionEnabled bool
+	// LazyConnectionInactivityThresholdSec is the period in seconds without traffic after which a lazy connection is
+	// torn down. Zero means the default threshold is used
+	LazyConnectionInactivityThresholdSec int
+	// KillSwitch blocks the traffic of the host that doesn't go through NetBird while the client is up.
+	// The block stays in place when the engine restarts and is lifted only by netbird down
+	KillSwitch bool
+	// Netstack runs WireGuard on an in-process network stack instead of an interface of the host, so the client
+	// doesn't need root privileges. The processes of the host reach the peers through a SOCKS5 and HTTP CONNECT proxy
+	Netstack bool
+	// NetstackProxyAddress is the local address of the proxy in netstack mode. Empty means 127.0.0.1:1080,
+	// NetstackProxyDisabled doesn't start the proxy, e.g. for programs embedding the client
+	NetstackProxyAddress string
+	// SSORefreshToken is the refresh token of the last SSO login, it is used to log in again without user interaction
+	SSORefreshToken string
+	// AllowUnverifiedAccessTokens accepts SSO access tokens without verifying their signature if the Management
+	// service provides no JWKS endpoint of the identity provider, e.g. when it runs an older version
+	AllowUnverifiedAccessTokens bool
+
+	// path is the file the config has been read from, the network snapshot is stored next to it
+	path string
 }
 
 // createNewConfig creates a new config generating a new Wireguard key and saving to file
 func createNewConfig(managementURL, adminURL, configPath, preSharedKey string) (*Config, error) {
+	config, err := newConfig(managementURL, adminURL, preSharedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.WriteJson(configPath, config)
+	if err != nil {
+		return nil, err
+	}
+	config.path = configPath
+
+	return config, nil
+}
+
+// newConfig returns a new config with a new Wireguard key, the default interface and the default port
+func newConfig(managementURL, adminURL, preSharedKey string) (*Config, error) {
 	wgKey := generateKey()
 	pem, err := ssh.GeneratePrivateKey(ssh.ED25519)
 	if err != nil {
@@ -82,11 +131,6 @@ func createNewConfig(managementURL, adminURL, configPath, preSharedKey string) (
 	config.IFaceBlackList = []string{iface.WgInterfaceDefault, "wt", "utun", "tun0", "zt", "ZeroTier", "utun", "wg", "ts",
 		"Tailscale", "tailscale", "docker", "vet"}
 
-	err = util.WriteJson(configPath, config)
-	if err != nil {
-		return nil, err
-	}
-
 	return config, nil
 }
 
@@ -117,6 +161,7 @@ func ReadConfig(managementURL, adminURL, configPath string, preSharedKey *string
 	if _, err := util.ReadJson(configPath, config); err != nil {
 		return nil, err
 	}
+	config.path = configPath
 
 	refresh := false
 
@@ -172,6 +217,49 @@ func ReadConfig(managementURL, adminURL, configPath string, preSharedKey *string
 	return config, nil
 }
 
+// managementEndpoints returns the Management service endpoints of the config, ManagementURL comes first
+func managementEndpoints(config *Config) []mgm.Endpoint {
+	urls := append([]*url.URL{config.ManagementURL}, config.ManagementFailoverURLs...)
+	endpoints := make([]mgm.Endpoint, 0, len(urls))
+	for _, u := range urls {
+		endpoints = append(endpoints, mgm.Endpoint{Addr: u.Host, TLSEnabled: u.Scheme == "https"})
+	}
+	return endpoints
+}
+
+// managementEndpointURL returns the URL of the config a Management service endpoint was created from
+func managementEndpointURL(config *Config, endpoint mgm.Endpoint) string {
+	for _, u := range append([]*url.URL{config.ManagementURL}, config.ManagementFailoverURLs...) {
+		if u.Host == endpoint.Addr {
+			return u.String()
+		}
+	}
+	return config.ManagementURL.String()
+}
+
+// newManagementClient connects to the first healthy Management service endpoint of the config
+func newManagementClient(ctx context.Context, config *Config, privateKey wgtypes.Key) (*mgm.GrpcClient, error) {
+	return mgm.NewFailoverClient(ctx, managementEndpoints(config), privateKey)
+}
+
+// WriteOutConfig writes the config to the config file
+func WriteOutConfig(configPath string, config *Config) error {
+	return util.WriteJson(configPath, config)
+}
+
+// UpdateSSORefreshToken stores the refresh token of an SSO login in the config file.
+// An empty token removes the stored one
+func UpdateSSORefreshToken(config *Config, refreshToken string) error {
+	if config.SSORefreshToken == refreshToken {
+		return nil
+	}
+	config.SSORefreshToken = refreshToken
+	if config.path == "" {
+		return nil
+	}
+	return util.WriteJson(config.path, config)
+}
+
 // GetConfig reads existing config or generates a new one
 func GetConfig(managementURL, adminURL, configPath, preSharedKey string) (*Config, error) {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -217,6 +305,14 @@ type ProviderConfig struct {
 	TokenEndpoint string
 	// DeviceAuthEndpoint is the endpoint of an IDP manager where clients can obtain device authorization code
 	DeviceAuthEndpoint string
+	// Scope requested with the device code
+	Scope string
+	// Issuer of the access tokens, it isn't verified if empty
+	Issuer string
+	// JwksURI to fetch the keys verifying the signature of access tokens from
+	JwksURI string
+	// AllowUnverifiedTokens accepts access tokens without verifying their signature if JwksURI is empty
+	AllowUnverifiedTokens bool
 }
 
 func GetDeviceAuthorizationFlowInfo(ctx context.Context, config *Config) (DeviceAuthorizationFlow, error) {
@@ -227,13 +323,8 @@ func GetDeviceAuthorizationFlowInfo(ctx context.Context, config *Config) (Device
 		return DeviceAuthorizationFlow{}, err
 	}
 
-	var mgmTlsEnabled bool
-	if config.ManagementURL.Scheme == "https" {
-		mgmTlsEnabled = true
-	}
-
 	log.Debugf("connecting to Management Service %s", config.ManagementURL.String())
-	mgmClient, err := mgm.NewClient(ctx, config.ManagementURL.Host, myPrivateKey, mgmTlsEnabled)
+	mgmClient, err := newManagementClient(ctx, config, myPrivateKey)
 	if err != nil {
 		log.Errorf("failed connecting to Management Service %s %v", config.ManagementURL.String(), err)
 		return DeviceAuthorizationFlow{}, err
@@ -273,6 +364,11 @@ func GetDeviceAuthorizationFlowInfo(ctx context.Context, config *Config) (Device
 			Domain:             protoDeviceAuthorizationFlow.GetProviderConfig().Domain,
 			TokenEndpoint:      protoDeviceAuthorizationFlow.GetProviderConfig().GetTokenEndpoint(),
 			DeviceAuthEndpoint: protoDeviceAuthorizationFlow.GetProviderConfig().GetDeviceAuthEndpoint(),
+			Scope:              protoDeviceAuthorizationFlow.GetProviderConfig().GetScope(),
+			Issuer:             protoDeviceAuthorizationFlow.GetProviderConfig().GetIssuer(),
+			JwksURI:            protoDeviceAuthorizationFlow.GetProviderConfig().GetJwksURI(),
+
+			AllowUnverifiedTokens: config.AllowUnverifiedAccessTokens,
 		},
 	}
 
@@ -298,5 +394,8 @@ func isProviderConfigValid(config ProviderConfig) error {
 	if config.DeviceAuthEndpoint == "" {
 		return fmt.Errorf(errorMSGFormat, "Device Auth Endpoint")
 	}
+	if config.JwksURI == "" && !config.AllowUnverifiedTokens {
+		return errNoJWKS
+	}
 	return nil
 }
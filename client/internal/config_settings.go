@@ -0,0 +1,287 @@
+package internal
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/client/internal/routemanager"
+)
+
+// maskedPreSharedKey is returned instead of the pre-shared key so that it never leaves the daemon
+const maskedPreSharedKey = "**********"
+
+// NetstackProxyDisabled is the netstack-proxy-address that doesn't start the proxy to the mesh
+const NetstackProxyDisabled = "off"
+
+// maxInterfaceNameLength is the maximum length of a network interface name (IFNAMSIZ without the terminating null)
+const maxInterfaceNameLength = 15
+
+// configSetting is a Config value that can be read and changed by name
+type configSetting struct {
+	get func(config *Config) string
+	// set validates the value and applies it to the config, it returns an error and leaves the config unchanged
+	// if the value is invalid
+	set func(config *Config, value string) error
+	// restart tells whether a running engine has to be restarted to apply a change, the other settings are applied
+	// to the running engine with Engine.UpdateSettings
+	restart bool
+}
+
+var configSettings = map[string]configSetting{
+	"interface-name": {
+		get: func(config *Config) string { return config.WgIface },
+		set: func(config *Config, value string) error {
+			if value == "" || len(value) > maxInterfaceNameLength {
+				return fmt.Errorf("interface name must be between 1 and %d characters long", maxInterfaceNameLength)
+			}
+			if strings.ContainsAny(value, " /:") {
+				return fmt.Errorf("interface name %q contains invalid characters", value)
+			}
+			config.WgIface = value
+			return nil
+		},
+		restart: true,
+	},
+	"wireguard-port": {
+		get: func(config *Config) string { return strconv.Itoa(config.WgPort) },
+		set: func(config *Config, value string) error {
+			port, err := strconv.Atoi(value)
+			if err != nil || port < 1 || port > 65535 {
+				return fmt.Errorf("invalid port %q, must be a number between 1 and 65535", value)
+			}
+			config.WgPort = port
+			return nil
+		},
+		restart: true,
+	},
+	"interface-blacklist": {
+		get: func(config *Config) string { return strings.Join(config.IFaceBlackList, ",") },
+		set: func(config *Config, value string) error {
+			blackList := []string{}
+			for _, name := range strings.Split(value, ",") {
+				name = strings.TrimSpace(name)
+				if name != "" {
+					blackList = append(blackList, name)
+				}
+			}
+			config.IFaceBlackList = blackList
+			return nil
+		},
+	},
+	"management-failover-urls": {
+		get: func(config *Config) string {
+			urls := make([]string, 0, len(config.ManagementFailoverURLs))
+			for _, u := range config.ManagementFailoverURLs {
+				urls = append(urls, u.String())
+			}
+			return strings.Join(urls, ",")
+		},
+		set: func(config *Config, value string) error {
+			urls := []*url.URL{}
+			for _, rawURL := range strings.Split(value, ",") {
+				rawURL = strings.TrimSpace(rawURL)
+				if rawURL == "" {
+					continue
+				}
+				u, err := ParseURL("Management URL", rawURL)
+				if err != nil {
+					return err
+				}
+				urls = append(urls, u)
+			}
+			config.ManagementFailoverURLs = urls
+			return nil
+		},
+		restart: true,
+	},
+	"preshared-key": {
+		get: func(config *Config) string {
+			if config.PreSharedKey == "" {
+				return ""
+			}
+			return maskedPreSharedKey
+		},
+		set: func(config *Config, value string) error {
+			if value != "" {
+				if _, err := wgtypes.ParseKey(value); err != nil {
+					return fmt.Errorf("invalid pre-shared key: %v", err)
+				}
+			}
+			config.PreSharedKey = value
+			return nil
+		},
+		restart: true,
+	},
+	"route-latency-threshold-ms": {
+		get: func(config *Config) string { return strconv.Itoa(config.RouteLatencyThresholdMs) },
+		set: func(config *Config, value string) error {
+			threshold, err := parseNonNegativeInt(value)
+			if err != nil {
+				return err
+			}
+			config.RouteLatencyThresholdMs = threshold
+			return nil
+		},
+	},
+	"route-overlap-policy": {
+		get: func(config *Config) string { return config.RouteOverlapPolicy },
+		set: func(config *Config, value string) error {
+			if _, err := routemanager.ParseOverlapPolicy(value); err != nil {
+				return err
+			}
+			config.RouteOverlapPolicy = value
+			return nil
+		},
+	},
+	"lazy-connection": {
+		get: func(config *Config) string { return strconv.FormatBool(config.LazyConnectionEnabled) },
+		set: func(config *Config, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q, must be true or false", value)
+			}
+			config.LazyConnectionEnabled = enabled
+			return nil
+		},
+		restart: true,
+	},
+	"kill-switch": {
+		get: func(config *Config) string { return strconv.FormatBool(config.KillSwitch) },
+		set: func(config *Config, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q, must be true or false", value)
+			}
+			config.KillSwitch = enabled
+			return nil
+		},
+		restart: true,
+	},
+	"netstack": {
+		get: func(config *Config) string { return strconv.FormatBool(config.Netstack) },
+		set: func(config *Config, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q, must be true or false", value)
+			}
+			config.Netstack = enabled
+			return nil
+		},
+		restart: true,
+	},
+	"netstack-proxy-address": {
+		get: func(config *Config) string { return config.NetstackProxyAddress },
+		set: func(config *Config, value string) error {
+			if value != "" && value != NetstackProxyDisabled {
+				if _, _, err := net.SplitHostPort(value); err != nil {
+					return fmt.Errorf("invalid proxy address %q: %v", value, err)
+				}
+			}
+			config.NetstackProxyAddress = value
+			return nil
+		},
+		restart: true,
+	},
+	"allow-unverified-access-tokens": {
+		get: func(config *Config) string { return strconv.FormatBool(config.AllowUnverifiedAccessTokens) },
+		set: func(config *Config, value string) error {
+			allowed, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q, must be true or false", value)
+			}
+			config.AllowUnverifiedAccessTokens = allowed
+			return nil
+		},
+	},
+	"lazy-connection-inactivity-threshold-sec": {
+		get: func(config *Config) string { return strconv.Itoa(config.LazyConnectionInactivityThresholdSec) },
+		set: func(config *Config, value string) error {
+			threshold, err := parseNonNegativeInt(value)
+			if err != nil {
+				return err
+			}
+			config.LazyConnectionInactivityThresholdSec = threshold
+			return nil
+		},
+	},
+}
+
+// ConfigSettingKeys returns the sorted names of the settings that can be changed with UpdateConfigSettings
+func ConfigSettingKeys() []string {
+	keys := make([]string, 0, len(configSettings))
+	for key := range configSettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetConfigSettings returns the current values of all settings of a config. The pre-shared key is masked
+func GetConfigSettings(config *Config) map[string]string {
+	settings := make(map[string]string, len(configSettings))
+	for key, setting := range configSettings {
+		settings[key] = setting.get(config)
+	}
+	return settings
+}
+
+// UpdateConfigSettings validates the settings and applies them to a copy of the config.
+// The copy is returned together with a flag telling whether any value has changed.
+// The config is not modified and nothing is applied if any of the settings is invalid
+func UpdateConfigSettings(config *Config, settings map[string]string) (*Config, bool, error) {
+	updated := *config
+	changed := false
+
+	for key, value := range settings {
+		setting, ok := configSettings[key]
+		if !ok {
+			return nil, false, fmt.Errorf("unknown setting %q, supported settings are %s",
+				key, strings.Join(ConfigSettingKeys(), ", "))
+		}
+
+		// the masked pre-shared key is what GetConfigSettings returns, setting it back keeps the current key
+		if key == "preshared-key" && value == maskedPreSharedKey {
+			continue
+		}
+
+		oldValue := setting.get(&updated)
+		err := setting.set(&updated, value)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %v", key, err)
+		}
+
+		if setting.get(&updated) != oldValue || (key == "preshared-key" && updated.PreSharedKey != config.PreSharedKey) {
+			changed = true
+		}
+	}
+
+	return &updated, changed, nil
+}
+
+// SettingsRequireRestart returns true if a setting that can't be applied to a running engine differs between the
+// configs: the interface, the ports, the Management URLs, the keys and the modes the engine is built with
+func SettingsRequireRestart(config, updated *Config) bool {
+	if config.PreSharedKey != updated.PreSharedKey {
+		return true
+	}
+	for _, setting := range configSettings {
+		if setting.restart && setting.get(config) != setting.get(updated) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseNonNegativeInt(value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid value %q, must be a non-negative number", value)
+	}
+	return number, nil
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func TestUpdateConfigSettings(t *testing.T) {
+	key, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+
+	config := &Config{
+		WgIface:        "wt0",
+		WgPort:         51820,
+		IFaceBlackList: []string{"docker"},
+		PreSharedKey:   key.String(),
+	}
+
+	updated, changed, err := UpdateConfigSettings(config, map[string]string{
+		"wireguard-port":      "51821",
+		"interface-blacklist": "docker, veth ,",
+		"lazy-connection":     "true",
+		"kill-switch":         "true",
+		"netstack":            "true",
+	})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 51821, updated.WgPort)
+	assert.Equal(t, []string{"docker", "veth"}, updated.IFaceBlackList)
+	assert.True(t, updated.LazyConnectionEnabled)
+	assert.True(t, updated.KillSwitch)
+	assert.True(t, updated.Netstack)
+	assert.Equal(t, 51820, config.WgPort, "the original config must not be modified")
+
+	_, changed, err = UpdateConfigSettings(updated, map[string]string{
+		"wireguard-port": "51821",
+		"preshared-key":  maskedPreSharedKey,
+	})
+	require.NoError(t, err)
+	assert.False(t, changed, "setting the current values is not a change")
+
+	newKey, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+	updated, changed, err = UpdateConfigSettings(config, map[string]string{"preshared-key": newKey.String()})
+	require.NoError(t, err)
+	assert.True(t, changed, "replacing the pre-shared key is a change even though its masked value is the same")
+	assert.Equal(t, newKey.String(), updated.PreSharedKey)
+
+	invalid := []map[string]string{
+		{"unknown": "value"},
+		{"wireguard-port": "0"},
+		{"wireguard-port": "port"},
+		{"interface-name": ""},
+		{"interface-name": "an-interface-name-too-long"},
+		{"preshared-key": "not a key"},
+		{"route-overlap-policy": "unknown"},
+		{"route-latency-threshold-ms": "-1"},
+		{"lazy-connection": "maybe"},
+		{"kill-switch": "on"},
+		{"netstack-proxy-address": "1080"},
+	}
+	for _, settings := range invalid {
+		_, _, err = UpdateConfigSettings(config, settings)
+		assert.Error(t, err, "settings %v should be rejected", settings)
+	}
+}
+
+func TestGetConfigSettings(t *testing.T) {
+	settings := GetConfigSettings(&Config{WgIface: "wt0", PreSharedKey: "secret"})
+	assert.Len(t, settings, len(ConfigSettingKeys()))
+	assert.Equal(t, "wt0", settings["interface-name"])
+	assert.Equal(t, maskedPreSharedKey, settings["preshared-key"])
+}
+
+func TestManagementFailoverURLsSetting(t *testing.T) {
+	config := &Config{ManagementURL: &url.URL{Scheme: "https", Host: "mgmt-1.example.com:443"}}
+
+	updated, changed, err := UpdateConfigSettings(config, map[string]string{
+		"management-failover-urls": "https://mgmt-2.example.com:443, http://mgmt-3.example.com:33073",
+	})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "https://mgmt-2.example.com:443,http://mgmt-3.example.com:33073",
+		GetConfigSettings(updated)["management-failover-urls"])
+
+	endpoints := managementEndpoints(updated)
+	require.Len(t, endpoints, 3)
+	assert.Equal(t, "mgmt-1.example.com:443", endpoints[0].Addr)
+	assert.True(t, endpoints[0].TLSEnabled)
+	assert.False(t, endpoints[2].TLSEnabled)
+	assert.Equal(t, "http://mgmt-3.example.com:33073", managementEndpointURL(updated, endpoints[2]))
+
+	_, _, err = UpdateConfigSettings(config, map[string]string{"management-failover-urls": "mgmt-2.example.com"})
+	assert.Error(t, err, "URLs without a scheme are rejected")
+}
+
+func TestSettingsRequireRestart(t *testing.T) {
+	config := &Config{WgIface: "wt0", WgPort: 51820, RouteLatencyThresholdMs: 20}
+
+	testCases := []struct {
+		name     string
+		settings map[string]string
+		restart  bool
+	}{
+		{name: "interface name", settings: map[string]string{"interface-name": "wt1"}, restart: true},
+		{name: "wireguard port", settings: map[string]string{"wireguard-port": "51821"}, restart: true},
+		{name: "pre-shared key", settings: map[string]string{"preshared-key": "ydHlM+ueE4fmpgK1FP+k5l8BhBE0yUhpkqS7A7l7pzY="}, restart: true},
+		{name: "route latency threshold", settings: map[string]string{"route-latency-threshold-ms": "50"}, restart: false},
+		{name: "route overlap policy", settings: map[string]string{"route-overlap-policy": "skip"}, restart: false},
+		{name: "interface blacklist", settings: map[string]string{"interface-blacklist": "docker"}, restart: false},
+		{name: "mixed", settings: map[string]string{"interface-blacklist": "docker", "wireguard-port": "51821"}, restart: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			updated, changed, err := UpdateConfigSettings(config, testCase.settings)
+			require.NoError(t, err)
+			require.True(t, changed)
+			assert.Equal(t, testCase.restart, SettingsRequireRestart(config, updated))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"fmt"
+	"github.com/netbirdio/netbird/client/internal/routemanager"
 	"github.com/netbirdio/netbird/client/ssh"
 	nbStatus "github.com/netbirdio/netbird/client/status"
 	"strings"
@@ -22,8 +23,16 @@ import (
 	gstatus "google.golang.org/grpc/status"
 )
 
-// RunClient with main logic.
-func RunClient(ctx context.Context, config *Config, statusRecorder *nbStatus.Status) error {
+// RunClient with main logic. The routeSelector holds the client networks selected by the user,
+// if it is nil the selection is read from the config
+func RunClient(ctx context.Context, config *Config, statusRecorder *nbStatus.Status, routeSelector *routemanager.RouteSelector) error {
+	return RunClientWithEngineListener(ctx, config, statusRecorder, routeSelector, nil)
+}
+
+// RunClientWithEngineListener runs the client like RunClient and calls engineStarted with every engine that has
+// been started, e.g. to reach the in-process network stack of the engine in netstack mode. The engine is stopped
+// when the context passed to engineStarted is done
+func RunClientWithEngineListener(ctx context.Context, config *Config, statusRecorder *nbStatus.Status, routeSelector *routemanager.RouteSelector, engineStarted func(ctx context.Context, engine *Engine)) error {
 	backOff := &backoff.ExponentialBackOff{
 		InitialInterval:     time.Second,
 		RandomizationFactor: 1,
@@ -34,6 +43,10 @@ func RunClient(ctx context.Context, config *Config, statusRecorder *nbStatus.Sta
 		Clock:               backoff.SystemClock,
 	}
 
+	if routeSelector == nil {
+		routeSelector = routemanager.NewRouteSelector(config.DeselectedRoutes)
+	}
+
 	state := CtxGetState(ctx)
 	defer func() {
 		s, err := state.Status()
@@ -49,16 +62,18 @@ func RunClient(ctx context.Context, config *Config, statusRecorder *nbStatus.Sta
 		return wrapErr(err)
 	}
 
-	var mgmTlsEnabled bool
-	if config.ManagementURL.Scheme == "https" {
-		mgmTlsEnabled = true
-	}
-
 	publicSSHKey, err := ssh.GeneratePublicKey([]byte(config.SSHKey))
 	if err != nil {
 		return err
 	}
 
+	if !config.KillSwitch && !config.Netstack {
+		// the kill-switch may still be enabled by a previous run with a different config
+		if err := DisableKillSwitch(); err != nil {
+			log.Warnf("failed disabling the kill-switch: %v", err)
+		}
+	}
+
 	managementURL := config.ManagementURL.String()
 	statusRecorder.MarkManagementDisconnected(managementURL)
 
@@ -79,30 +94,73 @@ func RunClient(ctx context.Context, config *Config, statusRecorder *nbStatus.Sta
 			cancel()
 		}()
 
-		log.Debugf("conecting to the Management service %s", config.ManagementURL.Host)
-		mgmClient, err := mgm.NewClient(engineCtx, config.ManagementURL.Host, myPrivateKey, mgmTlsEnabled)
-		if err != nil {
-			return wrapErr(gstatus.Errorf(codes.FailedPrecondition, "failed connecting to Management Service : %s", err))
-		}
-		log.Debugf("connected to the Management service %s", config.ManagementURL.Host)
-		defer func() {
-			err = mgmClient.Close()
+		snapshotPath := networkSnapshotPath(config)
+		connectManagement := func() (mgm.Client, *mgmProto.LoginResponse, error) {
+			log.Debugf("conecting to the Management service %s", config.ManagementURL.Host)
+			grpcClient, err := newManagementClient(engineCtx, config, myPrivateKey)
 			if err != nil {
-				log.Warnf("failed to close the Management service client %v", err)
+				return nil, nil, gstatus.Errorf(codes.FailedPrecondition, "failed connecting to Management Service : %s", err)
 			}
-		}()
+			log.Debugf("connected to the Management service %s", config.ManagementURL.Host)
+
+			// connect (just a connection, no stream yet) and login to Management Service to get an initial global Wiretrustee config
+			loginResp, err := loginToManagement(engineCtx, grpcClient, publicSSHKey)
+			if err != nil {
+				_ = grpcClient.Close()
+				if s, ok := gstatus.FromError(err); ok && (s.Code() == codes.PermissionDenied) {
+					// the peer has been removed or has expired, the network snapshot mustn't bring it up anymore
+					if err := removeNetworkSnapshot(snapshotPath); err != nil {
+						log.Warnf("failed removing network snapshot: %v", err)
+					}
+				}
+				return nil, nil, err
+			}
+
+			statusRecorder.MarkManagementConnected(managementEndpointURL(config, grpcClient.Endpoint()))
+			grpcClient.SetEndpointChangeListener(func(endpoint mgm.Endpoint) {
+				statusRecorder.MarkManagementConnected(managementEndpointURL(config, endpoint))
+			})
+			return grpcClient, loginResp, nil
+		}
+		reconnectManagement := func() (mgm.Client, error) {
+			client, _, err := connectManagement()
+			return client, err
+		}
 
-		// connect (just a connection, no stream yet) and login to Management Service to get an initial global Wiretrustee config
-		loginResp, err := loginToManagement(engineCtx, mgmClient, publicSSHKey)
+		var mgmClient *snapshotClient
+		client, loginResp, err := connectManagement()
 		if err != nil {
 			log.Debug(err)
 			if s, ok := gstatus.FromError(err); ok && (s.Code() == codes.PermissionDenied) {
 				state.Set(StatusNeedsLogin)
+				statusRecorder.PublishEvent(nbStatus.EventLoginRequired, "management rejected the peer, login required", nil)
 				return backoff.Permanent(wrapErr(err)) // unrecoverable error
 			}
-			return wrapErr(err)
+
+			if snapshotPath == "" {
+				return wrapErr(err)
+			}
+			snapshot, snapshotErr := loadNetworkSnapshot(snapshotPath, myPrivateKey)
+			if snapshotErr != nil {
+				log.Debugf("unable to start from the network snapshot: %v", snapshotErr)
+				return wrapErr(err)
+			}
+
+			log.Warnf("the Management service is unreachable, starting from the network snapshot: %v", err)
+			loginResp = &mgmProto.LoginResponse{
+				WiretrusteeConfig: snapshot.GetWiretrusteeConfig(),
+				PeerConfig:        snapshot.GetPeerConfig(),
+			}
+			mgmClient = newSnapshotClient(engineCtx, myPrivateKey, snapshotPath, nil, snapshot, reconnectManagement)
+		} else {
+			mgmClient = newSnapshotClient(engineCtx, myPrivateKey, snapshotPath, client, nil, reconnectManagement)
 		}
-		statusRecorder.MarkManagementConnected(managementURL)
+		defer func() {
+			err = mgmClient.Close()
+			if err != nil {
+				log.Warnf("failed to close the Management service client %v", err)
+			}
+		}()
 
 		localPeerState := nbStatus.LocalPeerState{
 			IP:              loginResp.GetPeerConfig().GetAddress(),
@@ -142,6 +200,7 @@ func RunClient(ctx context.Context, config *Config, statusRecorder *nbStatus.Sta
 			log.Error(err)
 			return wrapErr(err)
 		}
+		engineConfig.RouteSelector = routeSelector
 
 		engine := NewEngine(engineCtx, cancel, signalClient, mgmClient, engineConfig, statusRecorder)
 		err = engine.Start()
@@ -150,6 +209,24 @@ func RunClient(ctx context.Context, config *Config, statusRecorder *nbStatus.Sta
 			return wrapErr(err)
 		}
 
+		if config.KillSwitch && config.Netstack {
+			log.Warnf("the kill-switch isn't supported in netstack mode, the traffic of the host isn't blocked")
+		} else if config.KillSwitch {
+			// the rules are refreshed on every start and intentionally left in place when the engine stops.
+			// The client doesn't connect without them, the start is retried and the error reported in the status
+			if err := enableKillSwitch(engineCtx, config, loginResp.GetWiretrusteeConfig(), engine); err != nil {
+				log.Errorf("failed enabling the kill-switch: %v", err)
+				if stopErr := engine.Stop(); stopErr != nil {
+					log.Errorf("failed stopping engine %v", stopErr)
+				}
+				return wrapErr(fmt.Errorf("failed enabling the kill-switch: %v", err))
+			}
+		}
+
+		if engineStarted != nil {
+			engineStarted(engineCtx, engine)
+		}
+
 		log.Print("Netbird engine started, my IP is: ", peerConfig.Address)
 		state.Set(StatusConnected)
 
@@ -190,7 +267,21 @@ func createEngineConfig(key wgtypes.Key, config *Config, peerConfig *mgmProto.Pe
 		WgPrivateKey:   key,
 		WgPort:         config.WgPort,
 		SSHKey:         []byte(config.SSHKey),
+
+		RouteLatencyThreshold: time.Duration(config.RouteLatencyThresholdMs) * time.Millisecond,
+
+		LazyConnectionEnabled:             config.LazyConnectionEnabled,
+		LazyConnectionInactivityThreshold: time.Duration(config.LazyConnectionInactivityThresholdSec) * time.Second,
+
+		Netstack:             config.Netstack,
+		NetstackProxyAddress: config.NetstackProxyAddress,
+	}
+
+	overlapPolicy, err := routemanager.ParseOverlapPolicy(config.RouteOverlapPolicy)
+	if err != nil {
+		return nil, err
 	}
+	engineConf.RouteOverlapPolicy = overlapPolicy
 
 	if config.PreSharedKey != "" {
 		preSharedKey, err := wgtypes.ParseKey(config.PreSharedKey)
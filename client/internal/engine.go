@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/internal/lazyconn"
+	"github.com/netbirdio/netbird/client/internal/meshproxy"
+	"github.com/netbirdio/netbird/client/internal/networkmonitor"
+	"github.com/netbirdio/netbird/client/internal/portmap"
 	"github.com/netbirdio/netbird/client/internal/routemanager"
 	nbssh "github.com/netbirdio/netbird/client/ssh"
 	nbstatus "github.com/netbirdio/netbird/client/status"
 	"github.com/netbirdio/netbird/route"
 	"math/rand"
 	"net"
+	"net/netip"
 	"reflect"
 	"runtime"
 	"strings"
@@ -40,6 +45,18 @@ const (
 
 var ErrResetConnection = fmt.Errorf("reset connection")
 
+// routesTrafficReportInterval is the interval between two reports of the routes traffic to the Management service
+const routesTrafficReportInterval = time.Minute
+
+const (
+	// peerMetricsInterval is the interval between two collections of the connected peers metrics
+	peerMetricsInterval = 15 * time.Second
+	// peerLatencyTimeout is the maximum time to wait for a latency measurement of a peer
+	peerLatencyTimeout = 2 * time.Second
+	// maxConcurrentLatencyProbes limits the number of peers pinged at the same time
+	maxConcurrentLatencyProbes = 10
+)
+
 // EngineConfig is a config for the Engine
 type EngineConfig struct {
 	WgPort      int
@@ -64,6 +81,28 @@ type EngineConfig struct {
 
 	// SSHKey is a private SSH key in a PEM format
 	SSHKey []byte
+
+	// RouteLatencyThreshold is the minimum latency improvement required to switch between routing peers
+	RouteLatencyThreshold time.Duration
+
+	// RouteOverlapPolicy defines how routes overlapping with local networks are handled
+	RouteOverlapPolicy routemanager.OverlapPolicy
+
+	// RouteSelector holds the client networks selected by the user
+	RouteSelector *routemanager.RouteSelector
+
+	// LazyConnectionEnabled enables connecting to remote peers only when there is traffic for them
+	LazyConnectionEnabled bool
+
+	// LazyConnectionInactivityThreshold is the period without traffic after which a lazy connection is torn down
+	LazyConnectionInactivityThreshold time.Duration
+
+	// Netstack runs WireGuard on an in-process network stack instead of an interface of the host
+	Netstack bool
+
+	// NetstackProxyAddress is the local address of the SOCKS5 and HTTP CONNECT proxy to the mesh in netstack mode,
+	// NetstackProxyDisabled doesn't start the proxy
+	NetstackProxyAddress string
 }
 
 // Engine is a mechanism responsible for reacting on Signal and Management stream events and managing connections to the remote peers.
@@ -95,15 +134,29 @@ type Engine struct {
 	udpMuxConn      *net.UDPConn
 	udpMuxConnSrflx *net.UDPConn
 
+	// portMapper keeps the UDP mux port mapped on the gateway, nil if there is no default gateway
+	portMapper *portmap.Manager
+
 	// networkSerial is the latest CurrentSerial (state ID) of the network sent by the Management service
 	networkSerial uint64
 
 	sshServerFunc func(hostKeyPEM []byte, addr string) (nbssh.Server, error)
 	sshServer     nbssh.Server
 
+	// meshProxy lets the processes of the host reach the peers in netstack mode, nil otherwise
+	meshProxy *meshproxy.Proxy
+
 	statusRecorder *nbstatus.Status
 
 	routeManager routemanager.Manager
+	// reportedRouteConflicts are the route conflicts last reported to the Management service
+	reportedRouteConflicts []nbstatus.RouteConflict
+
+	// lazyConnManager handles the remote peers when lazy connections are enabled, nil otherwise
+	lazyConnManager *lazyconn.Manager
+	// routingPeers are the remote peers routing networks for this peer. They are always connected as routed traffic
+	// is only sent to a peer once its route has been chosen, which requires the peer to be connected
+	routingPeers map[string]struct{}
 
 	dnsServer *dns.Server
 }
@@ -159,6 +212,16 @@ func (e *Engine) Stop() error {
 		}
 	}
 
+	if e.portMapper != nil {
+		e.portMapper.Stop()
+	}
+
+	if e.meshProxy != nil {
+		if err := e.meshProxy.Close(); err != nil {
+			log.Debugf("close mesh proxy: %v", err)
+		}
+	}
+
 	if e.udpMux != nil {
 		if err := e.udpMux.Close(); err != nil {
 			log.Debugf("close udp mux: %v", err)
@@ -215,7 +278,11 @@ func (e *Engine) Start() error {
 	myPrivateKey := e.config.WgPrivateKey
 	var err error
 
-	e.wgInterface, err = iface.NewWGIFace(wgIfaceName, wgAddr, iface.DefaultMTU)
+	if e.config.Netstack {
+		e.wgInterface, err = iface.NewWGIFaceNetstack(wgIfaceName, wgAddr, iface.DefaultMTU)
+	} else {
+		e.wgInterface, err = iface.NewWGIFace(wgIfaceName, wgAddr, iface.DefaultMTU)
+	}
 	if err != nil {
 		log.Errorf("failed creating wireguard interface instance %s: [%s]", wgIfaceName, err.Error())
 		return err
@@ -236,6 +303,8 @@ func (e *Engine) Start() error {
 	e.udpMux = ice.NewUDPMuxDefault(ice.UDPMuxParams{UDPConn: e.udpMuxConn})
 	e.udpMuxSrflx = ice.NewUniversalUDPMuxDefault(ice.UniversalUDPMuxParams{UDPConn: e.udpMuxConnSrflx})
 
+	e.startPortMapping()
+
 	err = e.wgInterface.Create()
 	if err != nil {
 		log.Errorf("failed creating tunnel interface %s: [%s]", wgIfaceName, err.Error())
@@ -248,15 +317,249 @@ func (e *Engine) Start() error {
 		return err
 	}
 
-	e.routeManager = routemanager.NewManager(e.ctx, e.config.WgPrivateKey.PublicKey().String(), e.wgInterface, e.statusRecorder)
+	if e.config.Netstack {
+		err = e.setupNetstack()
+		if err != nil {
+			return err
+		}
+	}
+
+	e.routeManager = routemanager.NewManager(e.ctx, e.config.WgPrivateKey.PublicKey().String(), e.wgInterface, e.statusRecorder, e.config.RouteLatencyThreshold, e.config.RouteOverlapPolicy, e.config.RouteSelector)
+
+	if e.config.LazyConnectionEnabled {
+		log.Infof("lazy connections enabled, peers will be connected on demand")
+		e.lazyConnManager = lazyconn.NewManager(e.ctx, e.wgInterface, e.config.LazyConnectionInactivityThreshold, e.onPeerActivity, e.onPeerInactivity)
+	}
 
 	e.receiveSignalEvents()
 	e.receiveManagementEvents()
+	e.reportRoutesTraffic()
+	e.collectPeerMetrics()
+	e.startNetworkMonitor()
+
+	return nil
+}
+
+// UpdateSettings applies the settings of the config that don't require restarting the engine. The interface
+// blacklist is used for the next connection attempts, the route settings are applied to the client routes right away
+func (e *Engine) UpdateSettings(config *Config) error {
+	overlapPolicy, err := routemanager.ParseOverlapPolicy(config.RouteOverlapPolicy)
+	if err != nil {
+		return err
+	}
+	latencyThreshold := time.Duration(config.RouteLatencyThresholdMs) * time.Millisecond
+	inactivityThreshold := time.Duration(config.LazyConnectionInactivityThresholdSec) * time.Second
+
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	e.config.IFaceBlackList = config.IFaceBlackList
+	e.config.RouteLatencyThreshold = latencyThreshold
+	e.config.RouteOverlapPolicy = overlapPolicy
+	e.config.LazyConnectionInactivityThreshold = inactivityThreshold
+
+	if e.routeManager != nil {
+		e.routeManager.UpdateSettings(latencyThreshold, overlapPolicy)
+	}
+	if e.lazyConnManager != nil {
+		e.lazyConnManager.SetInactivityThreshold(inactivityThreshold)
+	}
+
+	return nil
+}
+
+// setupNetstack lets the SSH server listen on the in-process network stack and starts the SOCKS5 and HTTP CONNECT
+// proxy that dials into the mesh through the stack, unless the proxy is disabled
+func (e *Engine) setupNetstack() error {
+	e.sshServerFunc = func(hostKeyPEM []byte, addr string) (nbssh.Server, error) {
+		listener, err := e.wgInterface.Listen("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		return nbssh.DefaultSSHServerOnListener(hostKeyPEM, listener)
+	}
 
+	if e.config.NetstackProxyAddress == NetstackProxyDisabled {
+		return nil
+	}
+
+	meshProxy, err := meshproxy.New(e.config.NetstackProxyAddress, e.wgInterface)
+	if err != nil {
+		return fmt.Errorf("start mesh proxy: %v", err)
+	}
+	e.meshProxy = meshProxy
+
+	go func() {
+		if err := meshProxy.Serve(); err != nil {
+			log.Errorf("mesh proxy stopped: %v", err)
+		}
+	}()
 	return nil
 }
 
-// modifyPeers updates peers that have been modified (e.g. IP address has been changed).
+// DialContext connects to the address through the mesh, it is only available in netstack mode
+func (e *Engine) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return e.wgInterface.DialContext(ctx, network, address)
+}
+
+// Listen listens for TCP connections from the mesh, it is only available in netstack mode
+func (e *Engine) Listen(network, address string) (net.Listener, error) {
+	return e.wgInterface.Listen(network, address)
+}
+
+// collectPeerMetrics periodically stores the WireGuard statistics and the latency over the tunnel
+// of the connected peers in the status recorder
+func (e *Engine) collectPeerMetrics() {
+	go func() {
+		ticker := time.NewTicker(peerMetricsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-ticker.C:
+				e.updatePeerMetrics()
+			}
+		}
+	}()
+}
+
+// localUDPPorts returns the ports WireGuard and ICE send from
+func (e *Engine) localUDPPorts() []uint16 {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	ports := []uint16{uint16(e.config.WgPort)}
+	for _, conn := range []*net.UDPConn{e.udpMuxConn, e.udpMuxConnSrflx} {
+		if conn == nil {
+			continue
+		}
+		if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+			ports = append(ports, uint16(addr.Port))
+		}
+	}
+	return ports
+}
+
+// updatePeerMetrics stores the WireGuard statistics of the connected peers and measures their latency, which is also
+// used by the route manager to choose between routing peers. Lazy peers aren't pinged, as the probes would keep them
+// from becoming idle
+func (e *Engine) updatePeerMetrics() {
+	e.syncMsgMux.Lock()
+	peerKeys := make([]string, 0, len(e.peerConns))
+	lazyPeers := make(map[string]bool)
+	for peerKey := range e.peerConns {
+		peerKeys = append(peerKeys, peerKey)
+		lazyPeers[peerKey] = e.lazyConnManager != nil && !e.isRoutingPeer(peerKey)
+	}
+	e.syncMsgMux.Unlock()
+
+	var wg sync.WaitGroup
+	probes := make(chan struct{}, maxConcurrentLatencyProbes)
+	for _, peerKey := range peerKeys {
+		peerState, err := e.statusRecorder.GetPeer(peerKey)
+		if err != nil || peerState.ConnStatus != peer.StatusConnected.String() {
+			continue
+		}
+
+		stats, err := e.wgInterface.GetStats(peerKey)
+		if err != nil {
+			log.Debugf("unable to get WireGuard stats of peer %s: %v", peerKey, err)
+		} else {
+			err = e.statusRecorder.UpdateWireguardPeerStats(peerKey, stats.LastHandshake, stats.RxBytes, stats.TxBytes)
+			if err != nil {
+				log.Debugf("unable to update WireGuard stats of peer %s: %v", peerKey, err)
+			}
+		}
+
+		if lazyPeers[peerKey] {
+			continue
+		}
+
+		addr, err := netip.ParseAddr(peerState.IP)
+		if err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(peerKey string, addr netip.Addr) {
+			defer wg.Done()
+			probes <- struct{}{}
+			defer func() { <-probes }()
+
+			ctx, cancel := context.WithTimeout(e.ctx, peerLatencyTimeout)
+			defer cancel()
+
+			latency, err := e.ping(ctx, addr)
+			if err != nil {
+				log.Debugf("unable to measure latency to peer %s: %v", peerKey, err)
+			}
+			// a failed measurement resets the latency to unknown
+			err = e.statusRecorder.UpdatePeerLatency(peerKey, latency)
+			if err != nil {
+				log.Debugf("unable to update latency of peer %s: %v", peerKey, err)
+			}
+		}(peerKey, addr)
+	}
+	wg.Wait()
+
+	if e.routeManager != nil {
+		e.routeManager.LatencyUpdated()
+	}
+}
+
+// ping measures the round-trip time to the address over the tunnel
+func (e *Engine) ping(ctx context.Context, addr netip.Addr) (time.Duration, error) {
+	if e.wgInterface.IsNetstack() {
+		return e.wgInterface.Ping(ctx, addr)
+	}
+	return peer.Ping(ctx, addr)
+}
+
+// startPortMapping requests a mapping of the UDP mux port from the gateway with PCP, NAT-PMP or UPnP IGD.
+// The mapped address is offered to the remote peers as an additional candidate, allowing direct connections
+// to peers behind routers that would otherwise be relayed
+func (e *Engine) startPortMapping() {
+	gateway, localIP, err := portmap.DefaultGateway()
+	if err != nil {
+		log.Debugf("not mapping UDP port on the gateway: %v", err)
+		return
+	}
+
+	port := e.udpMuxConn.LocalAddr().(*net.UDPAddr).Port
+	e.portMapper = portmap.NewManager(gateway, localIP, port)
+	e.portMapper.Start()
+}
+
+// startNetworkMonitor watches the local network and resets the connection when it changes (e.g. switching from Wi-Fi
+// to Ethernet or resuming from sleep). The reset reconnects the Signal and Management streams and restarts ICE
+// for all peers at once instead of waiting for every broken connection to time out
+func (e *Engine) startNetworkMonitor() {
+	monitor := networkmonitor.New(e.config.WgIfaceName, func() {
+		select {
+		case <-e.ctx.Done():
+			return
+		default:
+		}
+		log.Infof("network change detected, reconnecting")
+		_ = CtxGetState(e.ctx).Wrap(ErrResetConnection)
+		e.cancel()
+	})
+
+	go func() {
+		err := monitor.Start(e.ctx)
+		if err == networkmonitor.ErrNotSupported {
+			log.Debugf("network monitor is not supported on %s", runtime.GOOS)
+			return
+		}
+		if err != nil {
+			log.Errorf("network monitor stopped: %v", err)
+		}
+	}()
+}
+
+// modifyPeers updates peers that have been modified (e.g. IP address or connection policy has been changed).
 // It closes the existing connection, removes it from the peerConns map, and creates a new one.
 func (e *Engine) modifyPeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
 
@@ -264,7 +567,9 @@ func (e *Engine) modifyPeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
 	var modified []*mgmProto.RemotePeerConfig
 	for _, p := range peersUpdate {
 		if peerConn, ok := e.peerConns[p.GetWgPubKey()]; ok {
-			if peerConn.GetConf().ProxyConfig.AllowedIps != strings.Join(p.AllowedIps, ",") {
+			conf := peerConn.GetConf()
+			if conf.ProxyConfig.AllowedIps != strings.Join(p.AllowedIps, ",") ||
+				conf.ConnectionPolicy != toConnectionPolicy(p.GetConnectionPolicy()) {
 				modified = append(modified, p)
 			}
 		}
@@ -338,6 +643,10 @@ func (e *Engine) removePeer(peerKey string) error {
 		}
 	}()
 
+	if e.lazyConnManager != nil {
+		e.lazyConnManager.RemovePeer(peerKey)
+	}
+
 	conn, exists := e.peerConns[peerKey]
 	if exists {
 		delete(e.peerConns, peerKey)
@@ -390,13 +699,14 @@ func (e *Engine) GetConnectedPeers() []string {
 	return peers
 }
 
-func signalCandidate(candidate ice.Candidate, myKey wgtypes.Key, remoteKey wgtypes.Key, s signal.Client) error {
+func signalCandidate(candidate ice.Candidate, myKey wgtypes.Key, remoteKey wgtypes.Key, s signal.Client, upgrade bool) error {
 	err := s.Send(&sProto.Message{
 		Key:       myKey.PublicKey().String(),
 		RemoteKey: remoteKey.String(),
 		Body: &sProto.Body{
 			Type:    sProto.Body_CANDIDATE,
 			Payload: candidate.Marshal(),
+			Upgrade: upgrade,
 		},
 	})
 	if err != nil {
@@ -422,6 +732,7 @@ func SignalOfferAnswer(offerAnswer peer.OfferAnswer, myKey wgtypes.Key, remoteKe
 	if err != nil {
 		return err
 	}
+	msg.Body.Upgrade = offerAnswer.Upgrade
 	err = s.Send(msg)
 	if err != nil {
 		return err
@@ -603,6 +914,8 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 
 	log.Debugf("got peers update from Management Service, total peers to connect to = %d", len(networkMap.GetRemotePeers()))
 
+	e.updateRoutingPeers(networkMap.GetRoutes())
+
 	// cleanup request, most likely our peer has been deleted
 	if networkMap.GetRemotePeersIsEmpty() {
 		err := e.removeAllPeers()
@@ -625,6 +938,13 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 			return err
 		}
 
+		for _, config := range networkMap.GetRemotePeers() {
+			err = e.statusRecorder.UpdatePeerName(config.GetWgPubKey(), config.GetName())
+			if err != nil {
+				log.Debugf("unable to update the name of peer %s: %v", config.GetWgPubKey(), err)
+			}
+		}
+
 		// update SSHServer by adding remote peer SSH keys
 		if !isNil(e.sshServer) {
 			for _, config := range networkMap.GetRemotePeers() {
@@ -645,11 +965,90 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 	if err != nil {
 		log.Errorf("failed to update routes, err: %v", err)
 	}
+	e.reportRouteConflicts()
+
+	err = e.routeManager.UpdatePortForwards(toPortForwards(networkMap.GetPortForwards()))
+	if err != nil {
+		log.Errorf("failed to update port forwards, err: %v", err)
+	}
 
 	e.networkSerial = serial
 	return nil
 }
 
+// reportRouteConflicts sends the route conflicts detected by the route manager to the Management service if they changed
+func (e *Engine) reportRouteConflicts() {
+	conflicts := e.statusRecorder.GetRouteConflicts()
+	if reflect.DeepEqual(conflicts, e.reportedRouteConflicts) {
+		return
+	}
+
+	protoConflicts := make([]*mgmProto.RouteConflict, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		protoConflicts = append(protoConflicts, &mgmProto.RouteConflict{
+			NetID:        conflict.NetID,
+			Network:      conflict.Network,
+			LocalNetwork: conflict.LocalNetwork,
+			Interface:    conflict.Interface,
+			Action:       conflict.Action,
+		})
+	}
+
+	go func() {
+		err := e.mgmClient.ReportRouteConflicts(protoConflicts)
+		if err != nil {
+			log.Warnf("failed to report route conflicts to the Management service: %v", err)
+			return
+		}
+
+		// only remember the conflicts once they were delivered so a failed report is retried on the next update
+		e.syncMsgMux.Lock()
+		e.reportedRouteConflicts = conflicts
+		e.syncMsgMux.Unlock()
+	}()
+}
+
+// reportRoutesTraffic periodically sends the traffic of the routes routed by the peer to the Management service
+// if it changed since the last report
+func (e *Engine) reportRoutesTraffic() {
+	go func() {
+		ticker := time.NewTicker(routesTrafficReportInterval)
+		defer ticker.Stop()
+
+		var reportedRoutesTraffic []nbstatus.RouteTraffic
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			routesTraffic := e.statusRecorder.GetRoutesTraffic()
+			if reflect.DeepEqual(routesTraffic, reportedRoutesTraffic) {
+				continue
+			}
+
+			protoRoutesTraffic := make([]*mgmProto.RouteTraffic, 0, len(routesTraffic))
+			for _, traffic := range routesTraffic {
+				protoRoutesTraffic = append(protoRoutesTraffic, &mgmProto.RouteTraffic{
+					ID:        traffic.ID,
+					TxBytes:   traffic.TxBytes,
+					TxPackets: traffic.TxPackets,
+					RxBytes:   traffic.RxBytes,
+					RxPackets: traffic.RxPackets,
+				})
+			}
+
+			err := e.mgmClient.ReportRouteTraffic(protoRoutesTraffic)
+			if err != nil {
+				log.Warnf("failed to report routes traffic to the Management service: %v", err)
+				continue
+			}
+			reportedRoutesTraffic = routesTraffic
+		}
+	}()
+}
+
 func toRoutes(protoRoutes []*mgmProto.Route) []*route.Route {
 	routes := make([]*route.Route, 0)
 	for _, protoRoute := range protoRoutes {
@@ -668,6 +1067,26 @@ func toRoutes(protoRoutes []*mgmProto.Route) []*route.Route {
 	return routes
 }
 
+func toPortForwards(protoPortForwards []*mgmProto.PortForward) []*route.PortForward {
+	portForwards := make([]*route.PortForward, 0)
+	for _, protoPortForward := range protoPortForwards {
+		targetIP, err := netip.ParseAddr(protoPortForward.TargetIP)
+		if err != nil {
+			log.Warnf("failed to parse target IP %s of port forward %s: %v", protoPortForward.TargetIP, protoPortForward.ID, err)
+			continue
+		}
+		portForwards = append(portForwards, &route.PortForward{
+			ID:           protoPortForward.ID,
+			Protocol:     protoPortForward.Protocol,
+			ExternalPort: int(protoPortForward.ExternalPort),
+			TargetIP:     targetIP,
+			TargetPort:   int(protoPortForward.TargetPort),
+			Enabled:      true,
+		})
+	}
+	return portForwards
+}
+
 // addNewPeers adds peers that were not know before but arrived from the Management service with the update
 func (e *Engine) addNewPeers(peersUpdate []*mgmProto.RemotePeerConfig) error {
 	for _, p := range peersUpdate {
@@ -684,7 +1103,7 @@ func (e *Engine) addNewPeer(peerConfig *mgmProto.RemotePeerConfig) error {
 	peerKey := peerConfig.GetWgPubKey()
 	peerIPs := peerConfig.GetAllowedIps()
 	if _, ok := e.peerConns[peerKey]; !ok {
-		conn, err := e.createPeerConn(peerKey, strings.Join(peerIPs, ","))
+		conn, err := e.createPeerConn(peerKey, strings.Join(peerIPs, ","), toConnectionPolicy(peerConfig.GetConnectionPolicy()))
 		if err != nil {
 			return err
 		}
@@ -695,11 +1114,81 @@ func (e *Engine) addNewPeer(peerConfig *mgmProto.RemotePeerConfig) error {
 			log.Warnf("error adding peer %s to status recorder, got error: %v", peerKey, err)
 		}
 
+		if e.lazyConnManager != nil && !e.isRoutingPeer(peerKey) {
+			err = e.lazyConnManager.AddPeer(peerKey, strings.Join(peerIPs, ","))
+			if err == nil {
+				return nil
+			}
+			log.Warnf("failed to add peer %s as a lazy connection, connecting to it right away: %v", peerKey, err)
+		}
+
 		go e.connWorker(conn, peerKey)
 	}
 	return nil
 }
 
+// updateRoutingPeers records the peers routing networks for this peer. With lazy connections, peers that became
+// routing peers are taken out of the lazy connection manager and connected right away. Peers that stop routing
+// networks stay connected
+func (e *Engine) updateRoutingPeers(routes []*mgmProto.Route) {
+	routingPeers := make(map[string]struct{})
+	for _, r := range routes {
+		routingPeers[r.GetPeer()] = struct{}{}
+	}
+	e.routingPeers = routingPeers
+
+	if e.lazyConnManager == nil {
+		return
+	}
+	for peerKey := range routingPeers {
+		conn, exists := e.peerConns[peerKey]
+		if !exists {
+			continue
+		}
+		// activating the peer releases its placeholder, so removing it doesn't touch the Wireguard peer
+		idle := e.lazyConnManager.ActivatePeer(peerKey)
+		e.lazyConnManager.RemovePeer(peerKey)
+		if idle {
+			log.Infof("peer %s routes networks, connecting", peerKey)
+			go e.connWorker(conn, peerKey)
+		}
+	}
+}
+
+// isRoutingPeer returns true if the peer routes networks for this peer
+func (e *Engine) isRoutingPeer(peerKey string) bool {
+	_, ok := e.routingPeers[peerKey]
+	return ok
+}
+
+// onPeerActivity starts connecting to a lazy peer on traffic for it
+func (e *Engine) onPeerActivity(peerKey string) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	conn, exists := e.peerConns[peerKey]
+	if !exists {
+		return
+	}
+	log.Infof("traffic detected for peer %s, connecting", peerKey)
+	go e.connWorker(conn, peerKey)
+}
+
+// onPeerInactivity closes the connection to an idle lazy peer, the connection worker sets the peer back to idle
+func (e *Engine) onPeerInactivity(peerKey string) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	conn, exists := e.peerConns[peerKey]
+	if !exists {
+		return
+	}
+	err := conn.Close()
+	if err != nil {
+		log.Debugf("closing connection to idle peer %s: %v", peerKey, err)
+	}
+}
+
 func (e *Engine) connWorker(conn *peer.Conn, peerKey string) {
 	for {
 
@@ -708,9 +1197,8 @@ func (e *Engine) connWorker(conn *peer.Conn, peerKey string) {
 		max := 2000
 		time.Sleep(time.Duration(rand.Intn(max-min)+min) * time.Millisecond)
 
-		// if peer has been removed -> give up
-		if !e.peerExists(peerKey) {
-			log.Debugf("peer %s doesn't exist anymore, won't retry connection", peerKey)
+		// if peer has been removed or is idle -> give up
+		if !e.shouldConnect(peerKey) {
 			return
 		}
 
@@ -731,22 +1219,35 @@ func (e *Engine) connWorker(conn *peer.Conn, peerKey string) {
 			log.Debugf("connection to peer %s failed: %v", peerKey, err)
 			switch err.(type) {
 			case *peer.ConnectionClosedError:
-				// conn has been forced to close, so we exit the loop
-				return
+				// conn has been forced to close, so we exit the loop unless it was closed because of inactivity
+				if e.lazyConnManager == nil {
+					return
+				}
 			default:
 			}
 		}
 	}
 }
 
-func (e Engine) peerExists(peerKey string) bool {
+// shouldConnect returns true if a connection attempt to the peer should be made.
+// With lazy connections, an idle peer is set back to wait for traffic and false is returned
+func (e *Engine) shouldConnect(peerKey string) bool {
 	e.syncMsgMux.Lock()
 	defer e.syncMsgMux.Unlock()
+
 	_, ok := e.peerConns[peerKey]
-	return ok
+	if !ok {
+		log.Debugf("peer %s doesn't exist anymore, won't retry connection", peerKey)
+		return false
+	}
+
+	if e.lazyConnManager == nil || e.isRoutingPeer(peerKey) {
+		return true
+	}
+	return e.lazyConnManager.ResumeOrArm(peerKey)
 }
 
-func (e Engine) createPeerConn(pubKey string, allowedIPs string) (*peer.Conn, error) {
+func (e Engine) createPeerConn(pubKey string, allowedIPs string, connectionPolicy peer.ConnectionPolicy) (*peer.Conn, error) {
 	log.Debugf("creating peer connection %s", pubKey)
 	var stunTurn []*ice.URL
 	stunTurn = append(stunTurn, e.STUNs...)
@@ -772,6 +1273,11 @@ func (e Engine) createPeerConn(pubKey string, allowedIPs string) (*peer.Conn, er
 		UDPMuxSrflx:        e.udpMuxSrflx,
 		ProxyConfig:        proxyConfig,
 		LocalWgPort:        e.config.WgPort,
+		ConnectionPolicy:   connectionPolicy,
+	}
+
+	if e.portMapper != nil {
+		config.MappedAddress = e.portMapper.ExternalAddress
 	}
 
 	peerConn, err := peer.NewConn(config, e.statusRecorder)
@@ -785,14 +1291,17 @@ func (e Engine) createPeerConn(pubKey string, allowedIPs string) (*peer.Conn, er
 	}
 
 	signalOffer := func(offerAnswer peer.OfferAnswer) error {
+		e.statusRecorder.TracePeer(pubKey, fmt.Sprintf("sending %s", describeOfferAnswer("offer", offerAnswer)))
 		return SignalOfferAnswer(offerAnswer, e.config.WgPrivateKey, wgPubKey, e.signal, false)
 	}
 
-	signalCandidate := func(candidate ice.Candidate) error {
-		return signalCandidate(candidate, e.config.WgPrivateKey, wgPubKey, e.signal)
+	signalCandidate := func(candidate ice.Candidate, upgrade bool) error {
+		e.statusRecorder.TracePeer(pubKey, fmt.Sprintf("sending %s", describeCandidate(candidate, upgrade)))
+		return signalCandidate(candidate, e.config.WgPrivateKey, wgPubKey, e.signal, upgrade)
 	}
 
 	signalAnswer := func(offerAnswer peer.OfferAnswer) error {
+		e.statusRecorder.TracePeer(pubKey, fmt.Sprintf("sending %s", describeOfferAnswer("answer", offerAnswer)))
 		return SignalOfferAnswer(offerAnswer, e.config.WgPrivateKey, wgPubKey, e.signal, true)
 	}
 
@@ -803,6 +1312,36 @@ func (e Engine) createPeerConn(pubKey string, allowedIPs string) (*peer.Conn, er
 	return peerConn, nil
 }
 
+// toConnectionPolicy converts the connection policy of a remote peer received from the Management service
+func toConnectionPolicy(policy mgmProto.RemotePeerConfig_ConnectionPolicy) peer.ConnectionPolicy {
+	switch policy {
+	case mgmProto.RemotePeerConfig_RELAY:
+		return peer.ConnectionPolicyRelay
+	case mgmProto.RemotePeerConfig_DIRECT:
+		return peer.ConnectionPolicyDirect
+	default:
+		return peer.ConnectionPolicyAuto
+	}
+}
+
+// describeOfferAnswer returns a short description of an offer or answer for the peer trace
+func describeOfferAnswer(kind string, offerAnswer peer.OfferAnswer) string {
+	if offerAnswer.Upgrade {
+		kind = "upgrade " + kind
+	}
+	return fmt.Sprintf("%s [ufrag %s, wireguard port %d, version %s]", kind, offerAnswer.IceCredentials.UFrag,
+		offerAnswer.WgListenPort, offerAnswer.Version)
+}
+
+// describeCandidate returns a short description of an ICE candidate for the peer trace
+func describeCandidate(candidate ice.Candidate, upgrade bool) string {
+	kind := "candidate"
+	if upgrade {
+		kind = "upgrade candidate"
+	}
+	return fmt.Sprintf("%s %s", kind, candidate.String())
+}
+
 // receiveSignalEvents connects to the Signal Service event stream to negotiate connection with remote peers
 func (e *Engine) receiveSignalEvents() {
 	go func() {
@@ -822,33 +1361,57 @@ func (e *Engine) receiveSignalEvents() {
 				if err != nil {
 					return err
 				}
-				conn.OnRemoteOffer(peer.OfferAnswer{
+				offer := peer.OfferAnswer{
 					IceCredentials: peer.IceCredentials{
 						UFrag: remoteCred.UFrag,
 						Pwd:   remoteCred.Pwd,
 					},
 					WgListenPort: int(msg.GetBody().GetWgListenPort()),
 					Version:      msg.GetBody().GetNetBirdVersion(),
-				})
+					Upgrade:      msg.GetBody().GetUpgrade(),
+				}
+				e.statusRecorder.TracePeer(msg.Key, fmt.Sprintf("received %s", describeOfferAnswer("offer", offer)))
+				if offer.Upgrade {
+					conn.OnRemoteUpgradeOffer(offer)
+					return nil
+				}
+				// the remote peer wants to connect, our connection worker will answer with its own offer
+				if e.lazyConnManager != nil && e.lazyConnManager.ActivatePeer(msg.Key) {
+					log.Infof("connection offer received from idle peer %s, connecting", msg.Key)
+					go e.connWorker(conn, msg.Key)
+				}
+				conn.OnRemoteOffer(offer)
 			case sProto.Body_ANSWER:
 				remoteCred, err := signal.UnMarshalCredential(msg)
 				if err != nil {
 					return err
 				}
-				conn.OnRemoteAnswer(peer.OfferAnswer{
+				answer := peer.OfferAnswer{
 					IceCredentials: peer.IceCredentials{
 						UFrag: remoteCred.UFrag,
 						Pwd:   remoteCred.Pwd,
 					},
 					WgListenPort: int(msg.GetBody().GetWgListenPort()),
 					Version:      msg.GetBody().GetNetBirdVersion(),
-				})
+					Upgrade:      msg.GetBody().GetUpgrade(),
+				}
+				e.statusRecorder.TracePeer(msg.Key, fmt.Sprintf("received %s", describeOfferAnswer("answer", answer)))
+				if answer.Upgrade {
+					conn.OnRemoteUpgradeAnswer(answer)
+					return nil
+				}
+				conn.OnRemoteAnswer(answer)
 			case sProto.Body_CANDIDATE:
 				candidate, err := ice.UnmarshalCandidate(msg.GetBody().Payload)
 				if err != nil {
 					log.Errorf("failed on parsing remote candidate %s -> %s", candidate, err)
 					return err
 				}
+				e.statusRecorder.TracePeer(msg.Key, fmt.Sprintf("received %s", describeCandidate(candidate, msg.GetBody().GetUpgrade())))
+				if msg.GetBody().GetUpgrade() {
+					conn.OnRemoteUpgradeCandidate(candidate)
+					return nil
+				}
 				conn.OnRemoteCandidate(candidate)
 			}
 
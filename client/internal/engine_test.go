@@ -199,7 +199,7 @@ func TestEngine_UpdateNetworkMap(t *testing.T) {
 		WgPort:       33100,
 	}, nbstatus.NewRecorder())
 	engine.wgInterface, err = iface.NewWGIFace("utun102", "100.64.0.1/24", iface.DefaultMTU)
-	engine.routeManager = routemanager.NewManager(ctx, key.PublicKey().String(), engine.wgInterface, engine.statusRecorder)
+	engine.routeManager = routemanager.NewManager(ctx, key.PublicKey().String(), engine.wgInterface, engine.statusRecorder, 0, "", nil)
 
 	type testCase struct {
 		name       string
@@ -230,6 +230,12 @@ func TestEngine_UpdateNetworkMap(t *testing.T) {
 		AllowedIps: []string{"100.64.0.20/24"},
 	}
 
+	relayedPeer2 := &mgmtProto.RemotePeerConfig{
+		WgPubKey:         "LLHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=",
+		AllowedIps:       []string{"100.64.0.11/24"},
+		ConnectionPolicy: mgmtProto.RemotePeerConfig_RELAY,
+	}
+
 	case1 := testCase{
 		name: "input with a new peer to add",
 		networkMap: &mgmtProto.NetworkMap{
@@ -307,19 +313,34 @@ func TestEngine_UpdateNetworkMap(t *testing.T) {
 	}
 
 	case6 := testCase{
+		name: "input with one peer connection policy to modify",
+		networkMap: &mgmtProto.NetworkMap{
+			Serial:     5,
+			PeerConfig: nil,
+			RemotePeers: []*mgmtProto.RemotePeerConfig{
+				modifiedPeer3, relayedPeer2,
+			},
+			RemotePeersIsEmpty: false,
+		},
+		expectedLen:    2,
+		expectedPeers:  []*mgmtProto.RemotePeerConfig{relayedPeer2, modifiedPeer3},
+		expectedSerial: 5,
+	}
+
+	case7 := testCase{
 		name: "input with all peers to remove",
 		networkMap: &mgmtProto.NetworkMap{
-			Serial:             5,
+			Serial:             6,
 			PeerConfig:         nil,
 			RemotePeers:        []*mgmtProto.RemotePeerConfig{},
 			RemotePeersIsEmpty: true,
 		},
 		expectedLen:    0,
 		expectedPeers:  nil,
-		expectedSerial: 5,
+		expectedSerial: 6,
 	}
 
-	for _, c := range []testCase{case1, case2, case3, case4, case5, case6} {
+	for _, c := range []testCase{case1, case2, case3, case4, case5, case6, case7} {
 		t.Run(c.name, func(t *testing.T) {
 			err = engine.updateNetworkMap(c.networkMap)
 			if err != nil {
@@ -345,6 +366,11 @@ func TestEngine_UpdateNetworkMap(t *testing.T) {
 					t.Errorf("expecting peer %s to have AllowedIPs= %s, got %s", p.GetWgPubKey(),
 						expectedAllowedIPs, conn.GetConf().ProxyConfig.AllowedIps)
 				}
+				expectedPolicy := toConnectionPolicy(p.GetConnectionPolicy())
+				if conn.GetConf().ConnectionPolicy != expectedPolicy {
+					t.Errorf("expecting peer %s to have connection policy %s, got %s", p.GetWgPubKey(),
+						expectedPolicy, conn.GetConf().ConnectionPolicy)
+				}
 			}
 		})
 	}
@@ -0,0 +1,257 @@
+package internal
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// jwksCacheTTL is how long a fetched key set is used before it is requested again
+	jwksCacheTTL = time.Hour
+	// jwksMinRefreshInterval limits how often a key set is requested again for a token signed with an unknown key,
+	// e.g. after the provider has rotated its keys
+	jwksMinRefreshInterval = time.Minute
+)
+
+// cachedJWKS is the key set of a JWKS endpoint and the time it was fetched. The mutex is held while the key set is
+// fetched, so concurrent validations wait for a single request instead of requesting the key set each
+type cachedJWKS struct {
+	mu        sync.Mutex
+	keySet    *jsonWebKeySet
+	fetchedAt time.Time
+}
+
+var (
+	jwksCacheMu sync.Mutex
+	// jwksCache holds the key sets by the URI of their JWKS endpoint
+	jwksCache = map[string]*cachedJWKS{}
+)
+
+// errNoJWKS is returned for access tokens that can't be verified, because the Management service provides no JWKS endpoint
+var errNoJWKS = fmt.Errorf("the Management service provides no JWKS endpoint of the identity provider, " +
+	"the signature of the access token can't be verified. Update the Management service, " +
+	"or set allow-unverified-access-tokens to accept access tokens without verifying them")
+
+// jwksSigningMethods are the asymmetric algorithms access tokens verified with a JWKS may be signed with
+var jwksSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// jsonWebKeySet is a JSON Web Key Set, see https://datatracker.ietf.org/doc/html/rfc7517
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jsonWebKey is a public RSA or EC key of a JSON Web Key Set
+type jsonWebKey struct {
+	Kid string   `json:"kid"`
+	Kty string   `json:"kty"`
+	Use string   `json:"use"`
+	N   string   `json:"n"`
+	E   string   `json:"e"`
+	Crv string   `json:"crv"`
+	X   string   `json:"x"`
+	Y   string   `json:"y"`
+	X5c []string `json:"x5c"`
+}
+
+// validateAccessToken verifies the signature, expiry, audience and the issuer, if it is set, of the access token with
+// the keys the provider publishes at jwksURI. Without jwksURI the signature can't be verified and the token is
+// rejected, unless allowUnverified is set, then only the audience is checked
+func validateAccessToken(httpClient HTTPClient, token, audience, issuer, jwksURI string, allowUnverified bool) error {
+	if jwksURI == "" {
+		if !allowUnverified {
+			return errNoJWKS
+		}
+		log.Warnf("the Management service provides no JWKS endpoint of the identity provider, " +
+			"accepting the access token without verifying its signature as allowed by the configuration")
+		return isValidAccessToken(token, audience)
+	}
+	if token == "" {
+		return fmt.Errorf("token received is empty")
+	}
+
+	parser := &jwt.Parser{ValidMethods: jwksSigningMethods}
+	parsed, err := parser.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return signingKey(httpClient, jwksURI, kid)
+	})
+	if err != nil {
+		return fmt.Errorf("invalid token: %v", err)
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return fmt.Errorf("invalid token claims")
+	}
+	if !claims.VerifyAudience(audience, true) {
+		return fmt.Errorf("invalid JWT token audience field")
+	}
+	if issuer != "" && !claims.VerifyIssuer(issuer, true) {
+		return fmt.Errorf("invalid JWT token issuer field")
+	}
+	return nil
+}
+
+// signingKey returns the key with the key ID from the cached key set of the JWKS endpoint. The key set is fetched
+// if it isn't cached or has expired, and fetched again if the key isn't found in it, as the provider may have
+// rotated its keys
+func signingKey(httpClient HTTPClient, jwksURI, kid string) (interface{}, error) {
+	jwksCacheMu.Lock()
+	cached, ok := jwksCache[jwksURI]
+	if !ok {
+		cached = &cachedJWKS{}
+		jwksCache[jwksURI] = cached
+	}
+	jwksCacheMu.Unlock()
+
+	cached.mu.Lock()
+	defer cached.mu.Unlock()
+
+	if cached.keySet != nil && time.Since(cached.fetchedAt) < jwksCacheTTL {
+		key, err := cached.keySet.publicKey(kid)
+		if err == nil || time.Since(cached.fetchedAt) < jwksMinRefreshInterval {
+			return key, err
+		}
+	}
+
+	keySet, err := fetchJWKS(httpClient, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	cached.keySet = keySet
+	cached.fetchedAt = time.Now()
+	return keySet.publicKey(kid)
+}
+
+// fetchJWKS requests the key set from the JWKS endpoint of the provider
+func fetchJWKS(httpClient HTTPClient, jwksURI string) (*jsonWebKeySet, error) {
+	req, err := http.NewRequest("GET", jwksURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %v", err)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request JWKS with error: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading JWKS response body with error: %v", err)
+	}
+
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("JWKS request returned status %d error: %s", res.StatusCode, string(body))
+	}
+
+	keySet := &jsonWebKeySet{}
+	err = json.Unmarshal(body, keySet)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JWKS failed with error: %v", err)
+	}
+	return keySet, nil
+}
+
+// publicKey returns the signing key with the key ID. A token without key ID can only be verified
+// if the set has a single signing key
+func (s *jsonWebKeySet) publicKey(kid string) (interface{}, error) {
+	var candidates []jsonWebKey
+	for _, key := range s.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if kid == "" || key.Kid == kid {
+			candidates = append(candidates, key)
+		}
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("no signing key with ID %q found", kid)
+	case len(candidates) > 1:
+		return nil, fmt.Errorf("token has no key ID and the provider has %d signing keys", len(candidates))
+	}
+	return candidates[0].publicKey()
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		if k.N == "" || k.E == "" {
+			break
+		}
+		n, err := decodeBase64BigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus of key %s: %v", k.Kid, err)
+		}
+		e, err := decodeBase64BigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent of key %s: %v", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.X == "" || k.Y == "" {
+			break
+		}
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s of key %s", k.Crv, k.Kid)
+		}
+		x, err := decodeBase64BigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate of key %s: %v", k.Kid, err)
+		}
+		y, err := decodeBase64BigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate of key %s: %v", k.Kid, err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("key %s is not on curve %s", k.Kid, k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q of key %s", k.Kty, k.Kid)
+	}
+
+	// some providers only publish the certificate chain of the key
+	if len(k.X5c) == 0 {
+		return nil, fmt.Errorf("key %s has no public key parameters", k.Kid)
+	}
+	der, err := base64.StdEncoding.DecodeString(k.X5c[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate of key %s: %v", k.Kid, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate of key %s: %v", k.Kid, err)
+	}
+	return cert.PublicKey, nil
+}
+
+func decodeBase64BigInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
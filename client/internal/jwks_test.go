@@ -0,0 +1,283 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://idp.example.com/"
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// newJWKSServer serves the public keys of the RSA and EC key as JSON Web Key Set and counts the requests
+func newJWKSServer(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	keySet := jsonWebKeySet{Keys: []jsonWebKey{
+		{
+			Kid: "rsa",
+			Kty: "RSA",
+			Use: "sig",
+			N:   encodeBigInt(rsaKey.N),
+			E:   encodeBigInt(big.NewInt(int64(rsaKey.E))),
+		},
+		{
+			Kid: "ec",
+			Kty: "EC",
+			Crv: "P-256",
+			X:   encodeBigInt(ecKey.X),
+			Y:   encodeBigInt(ecKey.Y),
+		},
+	}}
+
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_ = json.NewEncoder(w).Encode(keySet)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		// another test server may get the same address
+		jwksCacheMu.Lock()
+		delete(jwksCache, server.URL)
+		jwksCacheMu.Unlock()
+	})
+	return server, requests
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestValidateAccessToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server, _ := newJWKSServer(t, rsaKey, ecKey)
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"aud": []string{"netbird", "other"},
+			"iss": testIssuer,
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		token     func() string
+		expectErr bool
+	}{
+		{
+			name:  "RSA Signed Token",
+			token: func() string { return signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()) },
+		},
+		{
+			name:  "EC Signed Token",
+			token: func() string { return signTestToken(t, jwt.SigningMethodES256, "ec", ecKey, validClaims()) },
+		},
+		{
+			name:      "Token Signed With Unknown Key",
+			token:     func() string { return signTestToken(t, jwt.SigningMethodRS256, "rsa", otherKey, validClaims()) },
+			expectErr: true,
+		},
+		{
+			name:      "Token With Unknown Key ID",
+			token:     func() string { return signTestToken(t, jwt.SigningMethodRS256, "unknown", rsaKey, validClaims()) },
+			expectErr: true,
+		},
+		{
+			name:      "Ambiguous Token Without Key ID",
+			token:     func() string { return signTestToken(t, jwt.SigningMethodRS256, "", rsaKey, validClaims()) },
+			expectErr: true,
+		},
+		{
+			name:      "HMAC Signed Token",
+			token:     func() string { return signTestToken(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), validClaims()) },
+			expectErr: true,
+		},
+		{
+			name: "Expired Token",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Token Of Another Audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Token Of Another Issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://attacker.example.com/"
+				return signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateAccessToken(http.DefaultClient, testCase.token(), "netbird", testIssuer, server.URL, false)
+			if testCase.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAccessToken_CachesKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	server, requests := newJWKSServer(t, rsaKey, ecKey)
+
+	claims := jwt.MapClaims{
+		"aud": "netbird",
+		"iss": testIssuer,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	token := signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+
+	require.NoError(t, validateAccessToken(http.DefaultClient, token, "netbird", testIssuer, server.URL, false))
+	require.NoError(t, validateAccessToken(http.DefaultClient, token, "netbird", testIssuer, server.URL, false))
+	assert.Equal(t, int32(1), requests.Load(), "the key set should be cached")
+
+	unknownKeyToken := signTestToken(t, jwt.SigningMethodRS256, "rotated", rsaKey, claims)
+	assert.Error(t, validateAccessToken(http.DefaultClient, unknownKeyToken, "netbird", testIssuer, server.URL, false))
+	assert.Equal(t, int32(1), requests.Load(), "a recently fetched key set should not be requested again")
+}
+
+func TestValidateAccessToken_WithoutJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	token := signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{
+		"aud": "netbird",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	err = validateAccessToken(http.DefaultClient, token, "netbird", "", "", false)
+	assert.ErrorIs(t, err, errNoJWKS, "tokens that can't be verified should be rejected")
+	assert.NoError(t, validateAccessToken(http.DefaultClient, token, "netbird", "", "", true),
+		"unverified tokens should be accepted when allowed")
+}
+
+func TestValidateAccessToken_ConcurrentFetch(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	server, requests := newJWKSServer(t, rsaKey, ecKey)
+
+	token := signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{
+		"aud": "netbird",
+		"iss": testIssuer,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, validateAccessToken(http.DefaultClient, token, "netbird", testIssuer, server.URL, false))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), requests.Load(), "concurrent validations should share a single request")
+}
+
+func TestHosted_RefreshToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwksServer, _ := newJWKSServer(t, rsaKey, ecKey)
+
+	accessToken := signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{
+		"aud": "netbird",
+		"iss": testIssuer,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	var rotate bool
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.PostForm.Get("grant_type") != HostedRefreshGrant || r.PostForm.Get("refresh_token") != "refresh" ||
+			r.PostForm.Get("client_id") != "client" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		response := TokenInfo{AccessToken: accessToken, TokenType: "Bearer", ExpiresIn: 3600}
+		if rotate {
+			response.RefreshToken = "rotated"
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer tokenServer.Close()
+
+	hosted := NewHostedDeviceFlow(ProviderConfig{
+		Audience:      "netbird",
+		ClientID:      "client",
+		TokenEndpoint: tokenServer.URL,
+		Issuer:        testIssuer,
+		JwksURI:       jwksServer.URL,
+	})
+
+	tokenInfo, err := hosted.RefreshToken(context.Background(), "refresh")
+	require.NoError(t, err)
+	assert.Equal(t, accessToken, tokenInfo.AccessToken)
+	assert.Equal(t, "refresh", tokenInfo.RefreshToken, "the refresh token should be kept if it isn't rotated")
+
+	rotate = true
+	tokenInfo, err = hosted.RefreshToken(context.Background(), "refresh")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", tokenInfo.RefreshToken)
+
+	_, err = hosted.RefreshToken(context.Background(), "revoked")
+	assert.ErrorContains(t, err, "invalid_grant")
+
+	_, err = hosted.RefreshToken(context.Background(), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pion/ice/v2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/portmap"
+	"github.com/netbirdio/netbird/client/internal/routemanager"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	resolvConfPath = "/etc/resolv.conf"
+	// resolvedUpstreamConfPath lists the upstream nameservers systemd-resolved forwards the queries of its local
+	// stub resolver to
+	resolvedUpstreamConfPath = "/run/systemd/resolve/resolv.conf"
+)
+
+// enableKillSwitch blocks the traffic leaving the host outside the NetBird interface and the NetBird services
+// of the running engine
+func enableKillSwitch(ctx context.Context, config *Config, wtConfig *mgmProto.WiretrusteeConfig, engine *Engine) error {
+	rules := routemanager.KillSwitchRules{
+		Interface:     config.WgIface,
+		LocalUDPPorts: engine.localUDPPorts(),
+		DNSServers:    systemDNSServers(resolvConfPath, resolvedUpstreamConfPath),
+	}
+
+	// the port mapping of the WireGuard port on the gateway has to keep working, see Engine.startPortMapping
+	if gateway, _, err := portmap.DefaultGateway(); err == nil {
+		rules.Gateway = gateway
+	}
+
+	var hosts []string
+	for _, endpoint := range managementEndpoints(config) {
+		hosts = append(hosts, endpoint.Addr)
+	}
+	hosts = append(hosts, wtConfig.GetSignal().GetUri())
+	for _, host := range hosts {
+		endpoints, err := resolveKillSwitchEndpoints(ctx, route.PortForwardProtocolTCP, host)
+		if err != nil {
+			return err
+		}
+		rules.Endpoints = append(rules.Endpoints, endpoints...)
+	}
+
+	var iceURIs []string
+	for _, stun := range wtConfig.GetStuns() {
+		iceURIs = append(iceURIs, stun.GetUri())
+	}
+	for _, turn := range wtConfig.GetTurns() {
+		iceURIs = append(iceURIs, turn.GetHostConfig().GetUri())
+	}
+	for _, uri := range iceURIs {
+		iceURL, err := ice.ParseURL(uri)
+		if err != nil {
+			return fmt.Errorf("failed parsing %s: %v", uri, err)
+		}
+		protocol := route.PortForwardProtocolUDP
+		if iceURL.Proto == ice.ProtoTypeTCP {
+			protocol = route.PortForwardProtocolTCP
+		}
+		endpoints, err := resolveKillSwitchEndpoints(ctx, protocol, net.JoinHostPort(iceURL.Host, strconv.Itoa(iceURL.Port)))
+		if err != nil {
+			return err
+		}
+		rules.Endpoints = append(rules.Endpoints, endpoints...)
+	}
+
+	err := routemanager.NewKillSwitch().Enable(rules)
+	if err != nil {
+		return err
+	}
+	log.Infof("kill-switch enabled, only traffic through %s and to %d NetBird service endpoints is allowed",
+		rules.Interface, len(rules.Endpoints))
+	return nil
+}
+
+// DisableKillSwitch removes the kill-switch rules, it is a no-op if the kill-switch isn't enabled
+func DisableKillSwitch() error {
+	return routemanager.NewKillSwitch().Disable()
+}
+
+// resolveKillSwitchEndpoints resolves a host:port address to the endpoints the kill-switch has to allow
+func resolveKillSwitchEndpoints(ctx context.Context, protocol, hostPort string) ([]routemanager.KillSwitchEndpoint, error) {
+	host, rawPort, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing address %s: %v", hostPort, err)
+	}
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing port of %s: %v", hostPort, err)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving %s: %v", host, err)
+	}
+
+	endpoints := make([]routemanager.KillSwitchEndpoint, 0, len(addrs))
+	for _, addr := range addrs {
+		endpoints = append(endpoints, routemanager.KillSwitchEndpoint{
+			Protocol: protocol,
+			Addr:     addr.Unmap(),
+			Port:     uint16(port),
+		})
+	}
+	return endpoints, nil
+}
+
+// systemDNSServers returns the nameservers of a resolv.conf file. A local stub resolver like the one of
+// systemd-resolved on 127.0.0.53 sends the queries to upstream nameservers, they are read from the upstream
+// resolv.conf file so that the kill-switch allows them
+func systemDNSServers(path, upstreamPath string) []netip.Addr {
+	servers := resolvConfNameservers(path)
+	for _, server := range servers {
+		if server.IsLoopback() {
+			return append(servers, resolvConfNameservers(upstreamPath)...)
+		}
+	}
+	return servers
+}
+
+// resolvConfNameservers returns the nameservers of a resolv.conf file, it returns nil if the file can't be read
+func resolvConfNameservers(path string) []netip.Addr {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Debugf("unable to read the system nameservers from %s: %v", path, err)
+		return nil
+	}
+	defer file.Close()
+
+	var servers []netip.Addr
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		// link-local nameservers may carry a zone, e.g. fe80::1%eth0
+		addr, err := netip.ParseAddr(strings.SplitN(fields[1], "%", 2)[0])
+		if err != nil {
+			continue
+		}
+		servers = append(servers, addr.Unmap())
+	}
+	return servers
+}
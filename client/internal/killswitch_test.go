@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemDNSServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	content := "# generated\nsearch example.com\nnameserver 192.0.2.53\nnameserver fe80::1%eth0\nnameserver invalid\noptions edns0\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	upstreamPath := filepath.Join(t.TempDir(), "upstream-resolv.conf")
+	require.NoError(t, os.WriteFile(upstreamPath, []byte("nameserver 198.51.100.53\n"), 0600))
+
+	servers := systemDNSServers(path, upstreamPath)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("192.0.2.53"), netip.MustParseAddr("fe80::1")}, servers,
+		"the upstream nameservers are only needed for a local stub resolver")
+
+	assert.Empty(t, systemDNSServers(filepath.Join(t.TempDir(), "missing"), upstreamPath))
+
+	stubPath := filepath.Join(t.TempDir(), "stub-resolv.conf")
+	require.NoError(t, os.WriteFile(stubPath, []byte("nameserver 127.0.0.53\noptions edns0 trust-ad\n"), 0600))
+	servers = systemDNSServers(stubPath, upstreamPath)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("127.0.0.53"), netip.MustParseAddr("198.51.100.53")}, servers,
+		"the upstream nameservers of systemd-resolved should be allowed")
+}
@@ -0,0 +1,317 @@
+package lazyconn
+
+import (
+	"context"
+	"fmt"
+	"github.com/netbirdio/netbird/iface"
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInactivityThreshold is the period without traffic after which a peer connection is torn down
+	DefaultInactivityThreshold = 15 * time.Minute
+	// inactivityCheckInterval is the interval between two traffic checks of the connected peers
+	inactivityCheckInterval = time.Minute
+	// keepAliveTrafficThreshold is the traffic in bytes per check interval below which a peer is considered idle.
+	// It leaves room for the Wireguard keepalive and handshake messages that are exchanged without user traffic
+	keepAliveTrafficThreshold = 1024
+)
+
+type peerState int
+
+const (
+	// stateIdle means the peer isn't connected and its placeholder waits for outbound traffic
+	stateIdle peerState = iota
+	// stateActive means a connection to the peer should be established
+	stateActive
+	// stateInactive means the peer has been idle for too long and its connection is being torn down
+	stateInactive
+)
+
+type wgInterface interface {
+	UpdatePeer(peerKey string, allowedIps string, keepAlive time.Duration, endpoint *net.UDPAddr, preSharedKey *wgtypes.Key) error
+	RemovePeer(peerKey string) error
+	GetStats(peerKey string) (iface.WGStats, error)
+}
+
+type lazyPeer struct {
+	allowedIPs string
+	state      peerState
+	// listener receives the Wireguard packets sent to the placeholder endpoint while the peer is idle
+	listener *net.UDPConn
+	// lastActivity is the last time traffic was detected for the peer
+	lastActivity time.Time
+	// lastBytes is the traffic counter of the peer at the last check
+	lastBytes int64
+}
+
+// Manager handles the peers of a lazy connection setup. Peers are configured in Wireguard with a local
+// placeholder endpoint and a connection is only requested when outbound traffic reaches the placeholder or when the
+// remote peer offers a connection. Connections are released after a period of inactivity
+type Manager struct {
+	ctx                 context.Context
+	wgInterface         wgInterface
+	inactivityThreshold time.Duration
+	// onActivity is called when a connection to the peer should be established
+	onActivity func(peerKey string)
+	// onInactivity is called when the connection to the peer should be torn down
+	onInactivity func(peerKey string)
+	mu           sync.Mutex
+	peers        map[string]*lazyPeer
+}
+
+// NewManager returns a new lazy connection Manager. The inactivityThreshold is the period without traffic after which
+// a peer connection is torn down, DefaultInactivityThreshold is used if it is not positive
+func NewManager(ctx context.Context, wgInterface wgInterface, inactivityThreshold time.Duration, onActivity, onInactivity func(peerKey string)) *Manager {
+	if inactivityThreshold <= 0 {
+		inactivityThreshold = DefaultInactivityThreshold
+	}
+	m := &Manager{
+		ctx:                 ctx,
+		wgInterface:         wgInterface,
+		inactivityThreshold: inactivityThreshold,
+		onActivity:          onActivity,
+		onInactivity:        onInactivity,
+		peers:               make(map[string]*lazyPeer),
+	}
+	go m.watchInactivity()
+	return m
+}
+
+// SetInactivityThreshold changes the period without traffic after which a peer connection is torn down,
+// DefaultInactivityThreshold is used if it is not positive
+func (m *Manager) SetInactivityThreshold(inactivityThreshold time.Duration) {
+	if inactivityThreshold <= 0 {
+		inactivityThreshold = DefaultInactivityThreshold
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inactivityThreshold = inactivityThreshold
+}
+
+// AddPeer registers an idle peer and configures its Wireguard placeholder. If the placeholder can't be configured
+// the peer is registered as active and the error is returned
+func (m *Manager) AddPeer(peerKey string, allowedIPs string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.peers[peerKey]; ok {
+		return fmt.Errorf("peer %s already exists", peerKey)
+	}
+
+	p := &lazyPeer{allowedIPs: allowedIPs}
+	m.peers[peerKey] = p
+
+	err := m.arm(peerKey, p)
+	if err != nil {
+		p.state = stateActive
+		p.lastActivity = time.Now()
+		return err
+	}
+	return nil
+}
+
+// RemovePeer unregisters a peer removing its Wireguard placeholder if the peer is idle
+func (m *Manager) RemovePeer(peerKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.peers[peerKey]
+	if !ok {
+		return
+	}
+	delete(m.peers, peerKey)
+
+	if p.state != stateIdle {
+		return
+	}
+	m.disarm(peerKey, p)
+	err := m.wgInterface.RemovePeer(peerKey)
+	if err != nil {
+		log.Warnf("failed to remove the Wireguard placeholder of peer %s: %v", peerKey, err)
+	}
+}
+
+// ActivatePeer marks the peer as active. It returns true if the peer was idle and a connection has to be started
+func (m *Manager) ActivatePeer(peerKey string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.activate(peerKey)
+}
+
+// ResumeOrArm returns true if the peer is active. Otherwise, the peer is set back to idle, its Wireguard placeholder
+// configured and false is returned. It is meant to be called by the connection worker before each attempt
+func (m *Manager) ResumeOrArm(peerKey string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.peers[peerKey]
+	if !ok {
+		return false
+	}
+
+	switch p.state {
+	case stateActive:
+		return true
+	case stateInactive:
+		err := m.arm(peerKey, p)
+		if err != nil {
+			log.Errorf("failed to configure the Wireguard placeholder of peer %s, keeping it connected: %v", peerKey, err)
+			p.state = stateActive
+			p.lastActivity = time.Now()
+			return true
+		}
+		log.Infof("peer %s is idle, waiting for traffic to connect", peerKey)
+	}
+	return false
+}
+
+// IsActive returns true if a connection to the peer should be established
+func (m *Manager) IsActive(peerKey string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.peers[peerKey]
+	return ok && p.state == stateActive
+}
+
+func (m *Manager) activate(peerKey string) bool {
+	p, ok := m.peers[peerKey]
+	if !ok {
+		return false
+	}
+
+	previousState := p.state
+	p.state = stateActive
+	p.lastActivity = time.Now()
+	p.lastBytes = m.peerBytes(peerKey)
+
+	if previousState != stateIdle {
+		return false
+	}
+	m.disarm(peerKey, p)
+	log.Debugf("activity detected for idle peer %s", peerKey)
+	return true
+}
+
+// arm starts the placeholder listener of the peer and configures it as the peer's Wireguard endpoint
+func (m *Manager) arm(peerKey string, p *lazyPeer) error {
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return fmt.Errorf("failed to listen for peer %s activity: %v", peerKey, err)
+	}
+
+	err = m.wgInterface.UpdatePeer(peerKey, p.allowedIPs, 0, listener.LocalAddr().(*net.UDPAddr), nil)
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+
+	p.state = stateIdle
+	p.listener = listener
+	go m.listen(peerKey, listener)
+	return nil
+}
+
+// disarm stops the placeholder listener of the peer, the Wireguard peer is left to be updated by the connection
+func (m *Manager) disarm(peerKey string, p *lazyPeer) {
+	if p.listener == nil {
+		return
+	}
+	err := p.listener.Close()
+	if err != nil {
+		log.Debugf("failed to close the activity listener of peer %s: %v", peerKey, err)
+	}
+	p.listener = nil
+}
+
+// listen waits for a packet sent by Wireguard to the placeholder endpoint of the peer
+func (m *Manager) listen(peerKey string, listener *net.UDPConn) {
+	buf := make([]byte, 1500)
+	_, _, err := listener.ReadFromUDP(buf)
+	if err != nil {
+		// the listener has been closed
+		return
+	}
+
+	m.mu.Lock()
+	p, ok := m.peers[peerKey]
+	if !ok || p.listener != listener {
+		m.mu.Unlock()
+		return
+	}
+	activated := m.activate(peerKey)
+	m.mu.Unlock()
+
+	if activated {
+		m.onActivity(peerKey)
+	}
+}
+
+// watchInactivity periodically checks the traffic of the active peers and releases the idle ones
+func (m *Manager) watchInactivity() {
+	ticker := time.NewTicker(inactivityCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			m.close()
+			return
+		case <-ticker.C:
+			for _, peerKey := range m.checkInactivity(time.Now()) {
+				m.onInactivity(peerKey)
+			}
+		}
+	}
+}
+
+// checkInactivity updates the traffic of the active peers and returns the peers idle for longer than the threshold
+func (m *Manager) checkInactivity(now time.Time) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var inactivePeers []string
+	for peerKey, p := range m.peers {
+		if p.state != stateActive {
+			continue
+		}
+
+		bytes := m.peerBytes(peerKey)
+		// counters are reset when the Wireguard peer is reconfigured
+		if bytes < p.lastBytes || bytes-p.lastBytes > keepAliveTrafficThreshold {
+			p.lastActivity = now
+		}
+		p.lastBytes = bytes
+
+		if now.Sub(p.lastActivity) >= m.inactivityThreshold {
+			log.Infof("no traffic with peer %s for %s, closing the connection", peerKey, m.inactivityThreshold)
+			p.state = stateInactive
+			inactivePeers = append(inactivePeers, peerKey)
+		}
+	}
+	return inactivePeers
+}
+
+func (m *Manager) peerBytes(peerKey string) int64 {
+	stats, err := m.wgInterface.GetStats(peerKey)
+	if err != nil {
+		return 0
+	}
+	return stats.RxBytes + stats.TxBytes
+}
+
+// close stops the placeholder listeners
+func (m *Manager) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for peerKey, p := range m.peers {
+		m.disarm(peerKey, p)
+	}
+}
@@ -0,0 +1,160 @@
+package lazyconn
+
+import (
+	"context"
+	"fmt"
+	"github.com/netbirdio/netbird/iface"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testPeerKey = "RRHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU="
+
+type mockWGInterface struct {
+	mu        sync.Mutex
+	endpoints map[string]*net.UDPAddr
+	stats     map[string]iface.WGStats
+}
+
+func newMockWGInterface() *mockWGInterface {
+	return &mockWGInterface{
+		endpoints: make(map[string]*net.UDPAddr),
+		stats:     make(map[string]iface.WGStats),
+	}
+}
+
+func (m *mockWGInterface) UpdatePeer(peerKey string, _ string, _ time.Duration, endpoint *net.UDPAddr, _ *wgtypes.Key) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endpoints[peerKey] = endpoint
+	return nil
+}
+
+func (m *mockWGInterface) RemovePeer(peerKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.endpoints, peerKey)
+	return nil
+}
+
+func (m *mockWGInterface) GetStats(peerKey string) (iface.WGStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, ok := m.stats[peerKey]
+	if !ok {
+		return iface.WGStats{}, fmt.Errorf("peer not found")
+	}
+	return stats, nil
+}
+
+func (m *mockWGInterface) endpoint(peerKey string) *net.UDPAddr {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.endpoints[peerKey]
+}
+
+func (m *mockWGInterface) setBytes(peerKey string, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats[peerKey] = iface.WGStats{RxBytes: bytes}
+}
+
+func TestManager_ActivityOnPlaceholder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wgInterface := newMockWGInterface()
+	activity := make(chan string, 1)
+	manager := NewManager(ctx, wgInterface, time.Minute, func(peerKey string) {
+		activity <- peerKey
+	}, func(string) {})
+
+	err := manager.AddPeer(testPeerKey, "100.64.0.10/32")
+	require.NoError(t, err)
+	require.False(t, manager.IsActive(testPeerKey), "new peers should be idle")
+
+	endpoint := wgInterface.endpoint(testPeerKey)
+	require.NotNil(t, endpoint, "the placeholder endpoint should be configured")
+	require.True(t, endpoint.IP.IsLoopback(), "the placeholder endpoint should be local")
+
+	conn, err := net.DialUDP("udp4", nil, endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("handshake"))
+	require.NoError(t, err)
+
+	select {
+	case peerKey := <-activity:
+		require.Equal(t, testPeerKey, peerKey)
+	case <-time.After(2 * time.Second):
+		t.Fatal("activity wasn't detected")
+	}
+	require.True(t, manager.IsActive(testPeerKey), "peer should be active after traffic")
+	require.False(t, manager.ActivatePeer(testPeerKey), "activating an active peer shouldn't start a connection")
+}
+
+func TestManager_Inactivity(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wgInterface := newMockWGInterface()
+	manager := NewManager(ctx, wgInterface, 10*time.Minute, func(string) {}, func(string) {})
+
+	err := manager.AddPeer(testPeerKey, "100.64.0.10/32")
+	require.NoError(t, err)
+
+	require.True(t, manager.ActivatePeer(testPeerKey), "activating an idle peer should start a connection")
+	require.True(t, manager.ResumeOrArm(testPeerKey), "active peers should keep connecting")
+
+	now := time.Now()
+	wgInterface.setBytes(testPeerKey, 10*keepAliveTrafficThreshold)
+	require.Empty(t, manager.checkInactivity(now.Add(5*time.Minute)), "peer with traffic shouldn't be idle")
+
+	wgInterface.setBytes(testPeerKey, 10*keepAliveTrafficThreshold+100)
+	require.Empty(t, manager.checkInactivity(now.Add(10*time.Minute)), "keepalive traffic shouldn't reset the threshold yet")
+
+	inactivePeers := manager.checkInactivity(now.Add(16 * time.Minute))
+	require.Equal(t, []string{testPeerKey}, inactivePeers, "peer with only keepalive traffic should be idle")
+	require.False(t, manager.IsActive(testPeerKey))
+
+	require.False(t, manager.ResumeOrArm(testPeerKey), "idle peers shouldn't keep connecting")
+	require.NotNil(t, wgInterface.endpoint(testPeerKey), "the placeholder should be configured again")
+
+	require.True(t, manager.ActivatePeer(testPeerKey), "an idle peer should be connected again")
+}
+
+func TestManager_ReactivateBeforeArm(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	manager := NewManager(ctx, newMockWGInterface(), time.Minute, func(string) {}, func(string) {})
+
+	err := manager.AddPeer(testPeerKey, "100.64.0.10/32")
+	require.NoError(t, err)
+	require.True(t, manager.ActivatePeer(testPeerKey))
+
+	require.Len(t, manager.checkInactivity(time.Now().Add(2*time.Minute)), 1)
+	require.False(t, manager.ActivatePeer(testPeerKey), "the running connection worker should resume")
+	require.True(t, manager.ResumeOrArm(testPeerKey), "a reactivated peer should keep connecting")
+}
+
+func TestManager_RemovePeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wgInterface := newMockWGInterface()
+	manager := NewManager(ctx, wgInterface, time.Minute, func(string) {}, func(string) {})
+
+	err := manager.AddPeer(testPeerKey, "100.64.0.10/32")
+	require.NoError(t, err)
+	require.Error(t, manager.AddPeer(testPeerKey, "100.64.0.10/32"), "peers should only be added once")
+
+	manager.RemovePeer(testPeerKey)
+	require.Nil(t, wgInterface.endpoint(testPeerKey), "the placeholder should be removed")
+	require.False(t, manager.ActivatePeer(testPeerKey), "removed peers shouldn't be connected")
+	require.False(t, manager.ResumeOrArm(testPeerKey), "removed peers shouldn't be connected")
+}
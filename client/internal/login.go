@@ -21,13 +21,8 @@ func Login(ctx context.Context, config *Config, setupKey string, jwtToken string
 		return err
 	}
 
-	var mgmTlsEnabled bool
-	if config.ManagementURL.Scheme == "https" {
-		mgmTlsEnabled = true
-	}
-
 	log.Debugf("connecting to the Management service %s", config.ManagementURL.String())
-	mgmClient, err := mgm.NewClient(ctx, config.ManagementURL.Host, myPrivateKey, mgmTlsEnabled)
+	mgmClient, err := newManagementClient(ctx, config, myPrivateKey)
 	if err != nil {
 		log.Errorf("failed connecting to the Management service %s %v", config.ManagementURL.String(), err)
 		return err
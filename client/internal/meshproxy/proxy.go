@@ -0,0 +1,300 @@
+// Package meshproxy provides a local SOCKS5 and HTTP CONNECT proxy that dials into the mesh. It lets the processes of
+// a host reach the peers when the tunnel runs on an in-process network stack without an interface on the host
+package meshproxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultAddress is the local address the proxy listens on by default
+	DefaultAddress = "127.0.0.1:1080"
+
+	socksVersion5      = 0x05
+	socksAuthNone      = 0x00
+	socksAuthNoAccept  = 0xff
+	socksCmdConnect    = 0x01
+	socksAddrIPv4      = 0x01
+	socksAddrDomain    = 0x03
+	socksAddrIPv6      = 0x04
+	socksReplySuccess  = 0x00
+	socksReplyFailure  = 0x01
+	socksReplyNoCmd    = 0x07
+	socksReplyNoAddrTy = 0x08
+
+	handshakeTimeout = 10 * time.Second
+	dialTimeout      = 30 * time.Second
+)
+
+// Dialer connects to an address in the mesh
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Proxy accepts SOCKS5 and HTTP CONNECT requests on a local address and tunnels them into the mesh.
+// The mesh has no resolver, domain names are resolved with the resolver of the host
+type Proxy struct {
+	dialer   Dialer
+	resolver *net.Resolver
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// New returns a proxy listening on the local address, DefaultAddress is used if it is empty
+func New(address string, dialer Dialer) (*Proxy, error) {
+	if address == "" {
+		address = DefaultAddress
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %v", address, err)
+	}
+	return &Proxy{
+		dialer:   dialer,
+		resolver: net.DefaultResolver,
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Addr returns the address the proxy listens on
+func (p *Proxy) Addr() net.Addr {
+	return p.listener.Addr()
+}
+
+// Serve accepts connections until the proxy is closed. Blocking
+func (p *Proxy) Serve() error {
+	log.Infof("SOCKS5 and HTTP CONNECT proxy to the mesh listening on %s", p.listener.Addr())
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		p.mu.Lock()
+		p.conns[conn] = struct{}{}
+		p.mu.Unlock()
+
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.handleConn(conn)
+
+			p.mu.Lock()
+			delete(p.conns, conn)
+			p.mu.Unlock()
+		}()
+	}
+}
+
+// Close stops accepting connections, closes the proxied ones and waits for their handlers
+func (p *Proxy) Close() error {
+	err := p.listener.Close()
+
+	p.mu.Lock()
+	for conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return err
+}
+
+func (p *Proxy) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	reader := bufio.NewReader(conn)
+	version, err := reader.Peek(1)
+	if err != nil {
+		return
+	}
+
+	var remote net.Conn
+	if version[0] == socksVersion5 {
+		remote, err = p.handleSOCKS5(conn, reader)
+	} else {
+		remote, err = p.handleHTTPConnect(conn, reader)
+	}
+	if err != nil {
+		log.Debugf("proxy request from %s failed: %v", conn.RemoteAddr(), err)
+		return
+	}
+	defer remote.Close()
+	_ = conn.SetDeadline(time.Time{})
+
+	relay(conn, reader, remote)
+}
+
+// handleSOCKS5 negotiates a SOCKS5 CONNECT request without authentication and dials its destination
+func (p *Proxy) handleSOCKS5(conn net.Conn, reader *bufio.Reader) (net.Conn, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, methods); err != nil {
+		return nil, err
+	}
+	if !containsByte(methods, socksAuthNone) {
+		_, _ = conn.Write([]byte{socksVersion5, socksAuthNoAccept})
+		return nil, fmt.Errorf("client doesn't support SOCKS5 without authentication")
+	}
+	if _, err := conn.Write([]byte{socksVersion5, socksAuthNone}); err != nil {
+		return nil, err
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(reader, request); err != nil {
+		return nil, err
+	}
+	if request[1] != socksCmdConnect {
+		writeSOCKS5Reply(conn, socksReplyNoCmd)
+		return nil, fmt.Errorf("unsupported SOCKS5 command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksAddrIPv4, socksAddrIPv6:
+		ip := make(net.IP, net.IPv4len)
+		if request[3] == socksAddrIPv6 {
+			ip = make(net.IP, net.IPv6len)
+		}
+		if _, err := io.ReadFull(reader, ip); err != nil {
+			return nil, err
+		}
+		host = ip.String()
+	case socksAddrDomain:
+		length, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		domain := make([]byte, length)
+		if _, err := io.ReadFull(reader, domain); err != nil {
+			return nil, err
+		}
+		host = string(domain)
+	default:
+		writeSOCKS5Reply(conn, socksReplyNoAddrTy)
+		return nil, fmt.Errorf("unsupported SOCKS5 address type %d", request[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(reader, port); err != nil {
+		return nil, err
+	}
+
+	remote, err := p.dial(net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	if err != nil {
+		writeSOCKS5Reply(conn, socksReplyFailure)
+		return nil, err
+	}
+	writeSOCKS5Reply(conn, socksReplySuccess)
+	return remote, nil
+}
+
+func writeSOCKS5Reply(conn net.Conn, reply byte) {
+	// the bound address isn't meaningful outside the mesh, it is reported as unspecified
+	_, _ = conn.Write([]byte{socksVersion5, reply, 0x00, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+}
+
+// handleHTTPConnect handles an HTTP CONNECT request and dials its destination, other methods are rejected
+func (p *Proxy) handleHTTPConnect(conn net.Conn, reader *bufio.Reader) (net.Conn, error) {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return nil, err
+	}
+	if req.Method != http.MethodConnect {
+		_, _ = io.WriteString(conn, "HTTP/1.1 405 Method Not Allowed\r\nAllow: CONNECT\r\nContent-Length: 0\r\n\r\n")
+		return nil, fmt.Errorf("unsupported HTTP method %s", req.Method)
+	}
+
+	remote, err := p.dial(req.Host)
+	if err != nil {
+		_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\nContent-Length: 0\r\n\r\n")
+		return nil, err
+	}
+	if _, err = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		remote.Close()
+		return nil, err
+	}
+	return remote, nil
+}
+
+// dial resolves the host of the address with the resolver of the host and connects to it through the mesh
+func (p *Proxy) dial(address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	if net.ParseIP(host) == nil {
+		addrs, err := p.resolver.LookupIP(ctx, "ip4", host)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s: %v", host, err)
+		}
+		host = addrs[0].String()
+	}
+
+	remote, err := p.dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %v", address, err)
+	}
+	return remote, nil
+}
+
+// relay copies the traffic between the client and the remote connection until one of them is closed.
+// The reader holds the client data buffered during the negotiation
+func relay(client net.Conn, reader io.Reader, remote net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, reader)
+		closeWrite(remote)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, remote)
+		closeWrite(client)
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+}
+
+func closeWrite(conn net.Conn) {
+	if c, ok := conn.(interface{ CloseWrite() error }); ok {
+		_ = c.CloseWrite()
+		return
+	}
+	_ = conn.Close()
+}
+
+func containsByte(b []byte, c byte) bool {
+	for _, v := range b {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package meshproxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+)
+
+// recordingDialer dials on the host and records the addresses, it stands in for the network stack of the mesh
+type recordingDialer struct {
+	dialed chan string
+}
+
+func (d *recordingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.dialed <- address
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
+
+func startEchoServer(t *testing.T) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener
+}
+
+func startProxy(t *testing.T) (*Proxy, *recordingDialer) {
+	t.Helper()
+
+	dialer := &recordingDialer{dialed: make(chan string, 1)}
+	p, err := New("127.0.0.1:0", dialer)
+	require.NoError(t, err)
+	go func() {
+		_ = p.Serve()
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, p.Close())
+	})
+	return p, dialer
+}
+
+func assertEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	_, err := conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+}
+
+func TestProxy_SOCKS5(t *testing.T) {
+	echo := startEchoServer(t)
+	p, dialer := startProxy(t)
+
+	socksDialer, err := proxy.SOCKS5("tcp", p.Addr().String(), nil, proxy.Direct)
+	require.NoError(t, err)
+
+	conn, err := socksDialer.Dial("tcp", echo.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.Equal(t, echo.Addr().String(), <-dialer.dialed)
+	assertEcho(t, conn)
+}
+
+func TestProxy_HTTPConnect(t *testing.T) {
+	echo := startEchoServer(t)
+	p, dialer := startProxy(t)
+
+	conn, err := net.Dial("tcp", p.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", echo.Addr(), echo.Addr())
+	require.NoError(t, err)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, echo.Addr().String(), <-dialer.dialed)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(reader, reply)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+}
+
+func TestProxy_HTTPMethodNotAllowed(t *testing.T) {
+	p, _ := startProxy(t)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/", p.Addr()))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
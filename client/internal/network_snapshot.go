@@ -0,0 +1,348 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/netbirdio/netbird/client/system"
+	"github.com/netbirdio/netbird/encryption"
+	mgm "github.com/netbirdio/netbird/management/client"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+// networkSnapshotSuffix is appended to the config file path to get the file the network snapshot is stored in
+const networkSnapshotSuffix = ".snapshot"
+
+// networkSnapshotPath returns the file the network snapshot of a config is stored in.
+// It returns an empty string if the config hasn't been read from a file
+func networkSnapshotPath(config *Config) string {
+	if config.path == "" {
+		return ""
+	}
+	return config.path + networkSnapshotSuffix
+}
+
+// HasNetworkSnapshot returns true if the client can be started from a network snapshot while the Management service
+// is unreachable
+func HasNetworkSnapshot(config *Config) bool {
+	path := networkSnapshotPath(config)
+	if path == "" {
+		return false
+	}
+
+	key, err := wgtypes.ParseKey(config.PrivateKey)
+	if err != nil {
+		return false
+	}
+
+	_, err = loadNetworkSnapshot(path, key)
+	return err == nil
+}
+
+// saveNetworkSnapshot encrypts the snapshot with the WireGuard key of the peer and writes it to a file
+func saveNetworkSnapshot(path string, key wgtypes.Key, snapshot *mgmProto.SyncResponse) error {
+	encrypted, err := encryption.EncryptMessage(key.PublicKey(), key, snapshot)
+	if err != nil {
+		return fmt.Errorf("encrypt network snapshot: %v", err)
+	}
+
+	err = os.WriteFile(path, encrypted, 0600)
+	if err != nil {
+		return fmt.Errorf("write network snapshot: %v", err)
+	}
+	return nil
+}
+
+// loadNetworkSnapshot reads and decrypts a snapshot written by saveNetworkSnapshot.
+// The snapshot must contain everything required to start the engine: the peer, Signal and network map config
+func loadNetworkSnapshot(path string, key wgtypes.Key) (*mgmProto.SyncResponse, error) {
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read network snapshot: %v", err)
+	}
+
+	snapshot := &mgmProto.SyncResponse{}
+	err = encryption.DecryptMessage(key.PublicKey(), key, encrypted, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt network snapshot: %v", err)
+	}
+
+	if snapshot.GetPeerConfig().GetAddress() == "" || snapshot.GetWiretrusteeConfig().GetSignal() == nil ||
+		snapshot.GetNetworkMap() == nil {
+		return nil, fmt.Errorf("network snapshot is incomplete")
+	}
+	return snapshot, nil
+}
+
+// removeNetworkSnapshot deletes the network snapshot, a missing snapshot is not an error
+func removeNetworkSnapshot(path string) error {
+	if path == "" {
+		return nil
+	}
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// mergeNetworkSnapshot returns a snapshot with the parts of the update applied to it.
+// Network maps older than the one of the snapshot are ignored the same way the engine ignores them
+func mergeNetworkSnapshot(snapshot, update *mgmProto.SyncResponse) *mgmProto.SyncResponse {
+	merged := &mgmProto.SyncResponse{}
+	if snapshot != nil {
+		merged.WiretrusteeConfig = snapshot.GetWiretrusteeConfig()
+		merged.PeerConfig = snapshot.GetPeerConfig()
+		merged.NetworkMap = snapshot.GetNetworkMap()
+	}
+
+	if update.GetWiretrusteeConfig() != nil {
+		// the TURN credentials are short-lived, they aren't stored and the engine gets new ones once the Management
+		// service is reachable again
+		wiretrusteeConfig := proto.Clone(update.GetWiretrusteeConfig()).(*mgmProto.WiretrusteeConfig)
+		wiretrusteeConfig.Turns = nil
+		merged.WiretrusteeConfig = wiretrusteeConfig
+	}
+	if update.GetPeerConfig() != nil {
+		merged.PeerConfig = update.GetPeerConfig()
+	}
+	if update.GetNetworkMap() != nil && update.GetNetworkMap().GetSerial() >= merged.GetNetworkMap().GetSerial() {
+		merged.NetworkMap = update.GetNetworkMap()
+	}
+	return merged
+}
+
+// snapshotClient is the Management service client of the engine. It keeps the network snapshot up to date with
+// the updates of the Sync stream.
+// An engine started from the snapshot while the Management service is unreachable gets the snapshot as its first
+// update, the client then connects in the background and switches to the Sync stream once it has recovered
+type snapshotClient struct {
+	ctx  context.Context
+	key  wgtypes.Key
+	path string
+
+	// connect creates a logged-in Management service client, it is used while client is nil
+	connect func() (mgm.Client, error)
+
+	mu       sync.Mutex
+	client   mgm.Client
+	snapshot *mgmProto.SyncResponse
+	// persisted is the snapshot last written to path, nil if nothing has been written yet
+	persisted *mgmProto.SyncResponse
+}
+
+// newSnapshotClient returns a Management service client persisting the updates to path.
+// The client is offline if client is nil, snapshot is then replayed to the engine before connecting.
+// Nothing is persisted if path is empty
+func newSnapshotClient(ctx context.Context, key wgtypes.Key, path string, client mgm.Client,
+	snapshot *mgmProto.SyncResponse, connect func() (mgm.Client, error)) *snapshotClient {
+	return &snapshotClient{
+		ctx:       ctx,
+		key:       key,
+		path:      path,
+		connect:   connect,
+		client:    client,
+		snapshot:  snapshot,
+		persisted: snapshot,
+	}
+}
+
+// Sync replays the network snapshot if the Management service was unreachable at start and waits for it to become
+// reachable. Then it receives the updates of the Sync stream and persists them after they have been handled
+func (c *snapshotClient) Sync(msgHandler func(msg *mgmProto.SyncResponse) error) error {
+	client := c.currentClient()
+	if client == nil {
+		c.mu.Lock()
+		snapshot := c.snapshot
+		c.mu.Unlock()
+
+		log.Infof("applying network snapshot with serial %d", snapshot.GetNetworkMap().GetSerial())
+		err := msgHandler(snapshot)
+		if err != nil {
+			return err
+		}
+
+		client, err = c.reconnect()
+		if err != nil {
+			return err
+		}
+	}
+
+	return client.Sync(func(msg *mgmProto.SyncResponse) error {
+		err := msgHandler(msg)
+		if err != nil {
+			return err
+		}
+		c.persist(msg)
+		return nil
+	})
+}
+
+// reconnect connects to the Management service with a backoff until it succeeds or the peer has been rejected
+func (c *snapshotClient) reconnect() (mgm.Client, error) {
+	var client mgm.Client
+	operation := func() error {
+		var err error
+		client, err = c.connect()
+		if err == nil {
+			return nil
+		}
+		if s, ok := gstatus.FromError(err); ok && s.Code() == codes.PermissionDenied {
+			return backoff.Permanent(err)
+		}
+		log.Debugf("the Management service is still unreachable: %v", err)
+		return err
+	}
+
+	err := backoff.Retry(operation, backoff.WithContext(&backoff.ExponentialBackOff{
+		InitialInterval:     time.Second,
+		RandomizationFactor: 1,
+		Multiplier:          1.7,
+		MaxInterval:         15 * time.Second,
+		MaxElapsedTime:      3 * 30 * 24 * time.Hour, // 3 months
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}, c.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.ctx.Done():
+		_ = client.Close()
+		return nil, c.ctx.Err()
+	default:
+	}
+	c.client = client
+	log.Infof("the Management service is reachable again, leaving the network snapshot")
+	return client, nil
+}
+
+// networkSnapshotChanged returns true if the snapshot differs from the persisted one in what the engine is started
+// with: the network map serial, the peer config or the Signal config
+func networkSnapshotChanged(persisted, snapshot *mgmProto.SyncResponse) bool {
+	if persisted == nil {
+		return true
+	}
+	return persisted.GetNetworkMap().GetSerial() != snapshot.GetNetworkMap().GetSerial() ||
+		!proto.Equal(persisted.GetPeerConfig(), snapshot.GetPeerConfig()) ||
+		!proto.Equal(persisted.GetWiretrusteeConfig().GetSignal(), snapshot.GetWiretrusteeConfig().GetSignal())
+}
+
+// persist merges the update into the snapshot and writes it if it has changed since it was last persisted
+func (c *snapshotClient) persist(update *mgmProto.SyncResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.snapshot = mergeNetworkSnapshot(c.snapshot, update)
+	if c.path == "" || c.snapshot.GetNetworkMap() == nil || !networkSnapshotChanged(c.persisted, c.snapshot) {
+		return
+	}
+
+	err := saveNetworkSnapshot(c.path, c.key, c.snapshot)
+	if err != nil {
+		log.Warnf("failed persisting network snapshot: %v", err)
+		return
+	}
+	c.persisted = c.snapshot
+}
+
+func (c *snapshotClient) currentClient() mgm.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.client
+}
+
+// onlineClient returns the connected client or an Unavailable error while the engine runs from the snapshot
+func (c *snapshotClient) onlineClient() (mgm.Client, error) {
+	client := c.currentClient()
+	if client == nil {
+		return nil, gstatus.Errorf(codes.Unavailable, "the Management service is unreachable, running from the network snapshot")
+	}
+	return client, nil
+}
+
+// Close closes the connected client
+func (c *snapshotClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == nil {
+		return nil
+	}
+	return c.client.Close()
+}
+
+// GetServerPublicKey returns the public key of the connected Management service
+func (c *snapshotClient) GetServerPublicKey() (*wgtypes.Key, error) {
+	client, err := c.onlineClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServerPublicKey()
+}
+
+// Register registers the peer with the connected Management service
+func (c *snapshotClient) Register(serverKey wgtypes.Key, setupKey string, jwtToken string, sysInfo *system.Info, sshKey []byte) (*mgmProto.LoginResponse, error) {
+	client, err := c.onlineClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Register(serverKey, setupKey, jwtToken, sysInfo, sshKey)
+}
+
+// Login logs the peer in to the connected Management service
+func (c *snapshotClient) Login(serverKey wgtypes.Key, sysInfo *system.Info, sshKey []byte) (*mgmProto.LoginResponse, error) {
+	client, err := c.onlineClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Login(serverKey, sysInfo, sshKey)
+}
+
+// GetDeviceAuthorizationFlow returns the device authorization flow of the connected Management service
+func (c *snapshotClient) GetDeviceAuthorizationFlow(serverKey wgtypes.Key) (*mgmProto.DeviceAuthorizationFlow, error) {
+	client, err := c.onlineClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetDeviceAuthorizationFlow(serverKey)
+}
+
+// GetPKCEAuthorizationFlow returns the PKCE authorization flow of the connected Management service
+func (c *snapshotClient) GetPKCEAuthorizationFlow(serverKey wgtypes.Key) (*mgmProto.PKCEAuthorizationFlow, error) {
+	client, err := c.onlineClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetPKCEAuthorizationFlow(serverKey)
+}
+
+// ReportRouteConflicts reports route conflicts to the connected Management service
+func (c *snapshotClient) ReportRouteConflicts(conflicts []*mgmProto.RouteConflict) error {
+	client, err := c.onlineClient()
+	if err != nil {
+		return err
+	}
+	return client.ReportRouteConflicts(conflicts)
+}
+
+// ReportRouteTraffic reports route traffic to the connected Management service
+func (c *snapshotClient) ReportRouteTraffic(routesTraffic []*mgmProto.RouteTraffic) error {
+	client, err := c.onlineClient()
+	if err != nil {
+		return err
+	}
+	return client.ReportRouteTraffic(routesTraffic)
+}
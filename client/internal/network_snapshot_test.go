@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	mgm "github.com/netbirdio/netbird/management/client"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+func testNetworkSnapshot(serial uint64) *mgmProto.SyncResponse {
+	peerConfig := &mgmProto.PeerConfig{Address: "100.64.0.1/24"}
+	return &mgmProto.SyncResponse{
+		WiretrusteeConfig: &mgmProto.WiretrusteeConfig{
+			Signal: &mgmProto.HostConfig{Uri: "signal.netbird.io:10000", Protocol: mgmProto.HostConfig_HTTPS},
+			Stuns:  []*mgmProto.HostConfig{{Uri: "stun:stun.netbird.io:5555", Protocol: mgmProto.HostConfig_UDP}},
+		},
+		PeerConfig: peerConfig,
+		NetworkMap: &mgmProto.NetworkMap{
+			Serial:     serial,
+			PeerConfig: peerConfig,
+			RemotePeers: []*mgmProto.RemotePeerConfig{
+				{WgPubKey: "RRHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=", AllowedIps: []string{"100.64.0.2/32"}},
+			},
+		},
+	}
+}
+
+func TestNetworkSnapshot_SaveLoad(t *testing.T) {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "config.json"+networkSnapshotSuffix)
+
+	snapshot := testNetworkSnapshot(5)
+	require.NoError(t, saveNetworkSnapshot(path, key, snapshot))
+
+	stored, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(stored), "signal.netbird.io", "snapshot should be encrypted")
+
+	loaded, err := loadNetworkSnapshot(path, key)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.GetPeerConfig().GetAddress(), loaded.GetPeerConfig().GetAddress())
+	assert.Equal(t, snapshot.GetWiretrusteeConfig().GetSignal().GetUri(), loaded.GetWiretrusteeConfig().GetSignal().GetUri())
+	assert.Equal(t, uint64(5), loaded.GetNetworkMap().GetSerial())
+	require.Len(t, loaded.GetNetworkMap().GetRemotePeers(), 1)
+	assert.Equal(t, "100.64.0.2/32", loaded.GetNetworkMap().GetRemotePeers()[0].GetAllowedIps()[0])
+
+	otherKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, err = loadNetworkSnapshot(path, otherKey)
+	assert.Error(t, err, "snapshot shouldn't be readable with another key")
+
+	incomplete := testNetworkSnapshot(5)
+	incomplete.WiretrusteeConfig = nil
+	require.NoError(t, saveNetworkSnapshot(path, key, incomplete))
+	_, err = loadNetworkSnapshot(path, key)
+	assert.Error(t, err, "snapshot without Signal config shouldn't be loaded")
+
+	require.NoError(t, removeNetworkSnapshot(path))
+	require.NoError(t, removeNetworkSnapshot(path), "removing a missing snapshot should succeed")
+	_, err = loadNetworkSnapshot(path, key)
+	assert.Error(t, err)
+}
+
+func TestMergeNetworkSnapshot(t *testing.T) {
+	snapshot := mergeNetworkSnapshot(nil, testNetworkSnapshot(5))
+	assert.Equal(t, uint64(5), snapshot.GetNetworkMap().GetSerial())
+
+	outdated := &mgmProto.SyncResponse{NetworkMap: &mgmProto.NetworkMap{Serial: 4}}
+	merged := mergeNetworkSnapshot(snapshot, outdated)
+	assert.Equal(t, uint64(5), merged.GetNetworkMap().GetSerial(), "outdated network map should be ignored")
+
+	signalOnly := &mgmProto.SyncResponse{
+		WiretrusteeConfig: &mgmProto.WiretrusteeConfig{
+			Signal: &mgmProto.HostConfig{Uri: "signal2.netbird.io:10000"},
+		},
+	}
+	merged = mergeNetworkSnapshot(snapshot, signalOnly)
+	assert.Equal(t, "signal2.netbird.io:10000", merged.GetWiretrusteeConfig().GetSignal().GetUri())
+	assert.Equal(t, snapshot.GetNetworkMap(), merged.GetNetworkMap(), "network map should be kept")
+	assert.Equal(t, snapshot.GetPeerConfig(), merged.GetPeerConfig(), "peer config should be kept")
+
+	merged = mergeNetworkSnapshot(snapshot, testNetworkSnapshot(6))
+	assert.Equal(t, uint64(6), merged.GetNetworkMap().GetSerial())
+
+	withTURN := testNetworkSnapshot(6)
+	withTURN.WiretrusteeConfig.Turns = []*mgmProto.ProtectedHostConfig{
+		{HostConfig: &mgmProto.HostConfig{Uri: "turn:turn.netbird.io:5555"}, User: "user", Password: "secret"},
+	}
+	merged = mergeNetworkSnapshot(snapshot, withTURN)
+	assert.Empty(t, merged.GetWiretrusteeConfig().GetTurns(), "TURN credentials shouldn't be stored")
+	assert.Len(t, withTURN.GetWiretrusteeConfig().GetTurns(), 1, "the update shouldn't be modified")
+}
+
+func TestSnapshotClient_PersistOnlyChanges(t *testing.T) {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "config.json"+networkSnapshotSuffix)
+
+	client := newSnapshotClient(context.Background(), key, path, &mgm.MockClient{}, nil, nil)
+	client.persist(testNetworkSnapshot(5))
+	_, err = os.Stat(path)
+	require.NoError(t, err, "the first snapshot should be persisted")
+	require.NoError(t, os.Remove(path))
+
+	turnOnly := &mgmProto.SyncResponse{
+		WiretrusteeConfig: &mgmProto.WiretrusteeConfig{
+			Signal: testNetworkSnapshot(5).GetWiretrusteeConfig().GetSignal(),
+			Turns: []*mgmProto.ProtectedHostConfig{
+				{HostConfig: &mgmProto.HostConfig{Uri: "turn:turn.netbird.io:5555"}, User: "user", Password: "secret"},
+			},
+		},
+	}
+	client.persist(turnOnly)
+	client.persist(testNetworkSnapshot(5))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "unchanged snapshots shouldn't be written")
+
+	client.persist(testNetworkSnapshot(6))
+	persisted, err := loadNetworkSnapshot(path, key)
+	require.NoError(t, err, "a new network map serial should be persisted")
+	assert.Equal(t, uint64(6), persisted.GetNetworkMap().GetSerial())
+
+	require.NoError(t, os.Remove(path))
+	signalChange := &mgmProto.SyncResponse{
+		WiretrusteeConfig: &mgmProto.WiretrusteeConfig{
+			Signal: &mgmProto.HostConfig{Uri: "signal2.netbird.io:10000", Protocol: mgmProto.HostConfig_HTTPS},
+		},
+	}
+	client.persist(signalChange)
+	persisted, err = loadNetworkSnapshot(path, key)
+	require.NoError(t, err, "a Signal config change should be persisted")
+	assert.Equal(t, "signal2.netbird.io:10000", persisted.GetWiretrusteeConfig().GetSignal().GetUri())
+}
+
+func TestSnapshotClient_Offline(t *testing.T) {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "config.json"+networkSnapshotSuffix)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attempts := 0
+	online := &mgm.MockClient{
+		SyncFunc: func(msgHandler func(msg *mgmProto.SyncResponse) error) error {
+			return msgHandler(testNetworkSnapshot(7))
+		},
+	}
+	connect := func() (mgm.Client, error) {
+		attempts++
+		if attempts < 2 {
+			return nil, assert.AnError
+		}
+		return online, nil
+	}
+
+	client := newSnapshotClient(ctx, key, path, nil, testNetworkSnapshot(5), connect)
+
+	_, err = client.GetServerPublicKey()
+	assert.Error(t, err, "offline client should fail requests")
+
+	var serials []uint64
+	err = client.Sync(func(msg *mgmProto.SyncResponse) error {
+		serials = append(serials, msg.GetNetworkMap().GetSerial())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{5, 7}, serials, "snapshot should be applied before the updates of the Sync stream")
+	assert.Equal(t, 2, attempts, "client should retry connecting")
+
+	persisted, err := loadNetworkSnapshot(path, key)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), persisted.GetNetworkMap().GetSerial(), "updates should be persisted")
+}
@@ -0,0 +1,54 @@
+package networkmonitor
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// debounceInterval groups bursts of network events (e.g. an interface coming up with its addresses and routes)
+// into a single change notification
+const debounceInterval = 2 * time.Second
+
+// ErrNotSupported is returned by Monitor.Start on platforms where network changes can't be watched
+var ErrNotSupported = errors.New("network monitor is not supported on this platform")
+
+// Monitor watches the default routes and the addresses of the local interfaces and calls onChange when they change.
+// Changes of the ignored interface (the NetBird WireGuard interface) and of other tunnel interfaces are not reported
+type Monitor struct {
+	ignoreInterface string
+	onChange        func()
+
+	mux   sync.Mutex
+	timer *time.Timer
+}
+
+// New creates a Monitor that calls onChange after network changes not related to the ignoreInterface
+func New(ignoreInterface string, onChange func()) *Monitor {
+	return &Monitor{
+		ignoreInterface: ignoreInterface,
+		onChange:        onChange,
+	}
+}
+
+// notify schedules a change notification, postponing the one already scheduled
+func (m *Monitor) notify() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.timer = time.AfterFunc(debounceInterval, m.onChange)
+}
+
+// stop cancels a scheduled change notification
+func (m *Monitor) stop() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+}
@@ -0,0 +1,133 @@
+package networkmonitor
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"net"
+)
+
+// Start subscribes to netlink route and address updates and watches them until ctx is done. Blocks
+func (m *Monitor) Start(ctx context.Context) error {
+	defer m.stop()
+
+	ignored := m.newLinkFilter()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	routeUpdates := make(chan netlink.RouteUpdate)
+	err := netlink.RouteSubscribeWithOptions(routeUpdates, done, netlink.RouteSubscribeOptions{
+		ErrorCallback: func(err error) {
+			log.Debugf("network monitor route subscription error: %v", err)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed subscribing to route updates: %v", err)
+	}
+
+	addrUpdates := make(chan netlink.AddrUpdate)
+	err = netlink.AddrSubscribeWithOptions(addrUpdates, done, netlink.AddrSubscribeOptions{
+		ErrorCallback: func(err error) {
+			log.Debugf("network monitor address subscription error: %v", err)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed subscribing to address updates: %v", err)
+	}
+
+	log.Debugf("network monitor started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debugf("network monitor stopped")
+			return nil
+		case update, ok := <-routeUpdates:
+			if !ok {
+				return fmt.Errorf("route updates subscription closed")
+			}
+			if isDefaultRouteChange(update, ignored) {
+				log.Debugf("network monitor detected a default route change: %s", update.Route.String())
+				m.notify()
+			}
+		case update, ok := <-addrUpdates:
+			if !ok {
+				return fmt.Errorf("address updates subscription closed")
+			}
+			if isAddressChange(update, ignored) {
+				log.Debugf("network monitor detected an address change: %s", update.LinkAddress.String())
+				m.notify()
+			}
+		}
+	}
+}
+
+// newLinkFilter returns a function telling whether changes of an interface should be ignored.
+// The ignored interface and other tunnel interfaces (e.g. WireGuard interfaces of other clients) don't change
+// the network used to reach the remote peers. The result is cached because deleted interfaces can't be looked up
+func (m *Monitor) newLinkFilter() func(linkIndex int) bool {
+	cache := make(map[int]bool)
+
+	links, err := netlink.LinkList()
+	if err != nil {
+		log.Debugf("network monitor failed listing interfaces: %v", err)
+	}
+	for _, link := range links {
+		cache[link.Attrs().Index] = m.isIgnoredLink(link)
+	}
+
+	return func(linkIndex int) bool {
+		ignored, found := cache[linkIndex]
+		if found {
+			return ignored
+		}
+
+		link, err := netlink.LinkByIndex(linkIndex)
+		if err != nil {
+			return false
+		}
+
+		ignored = m.isIgnoredLink(link)
+		cache[linkIndex] = ignored
+		return ignored
+	}
+}
+
+func (m *Monitor) isIgnoredLink(link netlink.Link) bool {
+	return link.Attrs().Name == m.ignoreInterface || link.Type() == "wireguard" || link.Type() == "tuntap"
+}
+
+// isDefaultRouteChange checks whether a route update adds or removes a default route of the main routing table
+// not going through an ignored interface
+func isDefaultRouteChange(update netlink.RouteUpdate, ignored func(linkIndex int) bool) bool {
+	if update.Type != unix.RTM_NEWROUTE && update.Type != unix.RTM_DELROUTE {
+		return false
+	}
+
+	if update.Table != 0 && update.Table != unix.RT_TABLE_MAIN {
+		return false
+	}
+
+	if update.Dst != nil {
+		ones, _ := update.Dst.Mask.Size()
+		if ones != 0 {
+			return false
+		}
+	}
+
+	return update.LinkIndex == 0 || !ignored(update.LinkIndex)
+}
+
+// isAddressChange checks whether an address update adds or removes a routable address of an interface
+// that isn't ignored
+func isAddressChange(update netlink.AddrUpdate, ignored func(linkIndex int) bool) bool {
+	ip := update.LinkAddress.IP
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.Equal(net.IPv4zero) {
+		return false
+	}
+
+	return !ignored(update.LinkIndex)
+}
@@ -0,0 +1,152 @@
+package networkmonitor
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"net"
+	"testing"
+	"time"
+)
+
+func ignoreIndex10(linkIndex int) bool {
+	return linkIndex == 10
+}
+
+func TestIsDefaultRouteChange(t *testing.T) {
+	_, defaultV4, _ := net.ParseCIDR("0.0.0.0/0")
+	_, defaultV6, _ := net.ParseCIDR("::/0")
+	_, network, _ := net.ParseCIDR("192.168.0.0/24")
+
+	testCases := []struct {
+		name     string
+		update   netlink.RouteUpdate
+		expected bool
+	}{
+		{
+			name:     "New Default Route",
+			update:   netlink.RouteUpdate{Type: unix.RTM_NEWROUTE, Route: netlink.Route{LinkIndex: 2, Table: unix.RT_TABLE_MAIN}},
+			expected: true,
+		},
+		{
+			name:     "Deleted Default Route",
+			update:   netlink.RouteUpdate{Type: unix.RTM_DELROUTE, Route: netlink.Route{LinkIndex: 2, Dst: defaultV4, Table: unix.RT_TABLE_MAIN}},
+			expected: true,
+		},
+		{
+			name:     "New IPv6 Default Route",
+			update:   netlink.RouteUpdate{Type: unix.RTM_NEWROUTE, Route: netlink.Route{LinkIndex: 2, Dst: defaultV6, Table: unix.RT_TABLE_MAIN}},
+			expected: true,
+		},
+		{
+			name:     "Network Route",
+			update:   netlink.RouteUpdate{Type: unix.RTM_NEWROUTE, Route: netlink.Route{LinkIndex: 2, Dst: network, Table: unix.RT_TABLE_MAIN}},
+			expected: false,
+		},
+		{
+			name:     "Default Route Of Ignored Interface",
+			update:   netlink.RouteUpdate{Type: unix.RTM_NEWROUTE, Route: netlink.Route{LinkIndex: 10, Dst: defaultV4, Table: unix.RT_TABLE_MAIN}},
+			expected: false,
+		},
+		{
+			name:     "Default Route Of Another Table",
+			update:   netlink.RouteUpdate{Type: unix.RTM_NEWROUTE, Route: netlink.Route{LinkIndex: 2, Dst: defaultV4, Table: 100}},
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, isDefaultRouteChange(testCase.update, ignoreIndex10))
+		})
+	}
+}
+
+func TestIsAddressChange(t *testing.T) {
+	testCases := []struct {
+		name     string
+		update   netlink.AddrUpdate
+		expected bool
+	}{
+		{
+			name:     "New Address",
+			update:   netlink.AddrUpdate{LinkIndex: 2, LinkAddress: net.IPNet{IP: net.ParseIP("192.168.0.10"), Mask: net.CIDRMask(24, 32)}, NewAddr: true},
+			expected: true,
+		},
+		{
+			name:     "Deleted IPv6 Address",
+			update:   netlink.AddrUpdate{LinkIndex: 2, LinkAddress: net.IPNet{IP: net.ParseIP("2001:db8::10"), Mask: net.CIDRMask(64, 128)}},
+			expected: true,
+		},
+		{
+			name:     "Link Local Address",
+			update:   netlink.AddrUpdate{LinkIndex: 2, LinkAddress: net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)}, NewAddr: true},
+			expected: false,
+		},
+		{
+			name:     "Loopback Address",
+			update:   netlink.AddrUpdate{LinkIndex: 1, LinkAddress: net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)}, NewAddr: true},
+			expected: false,
+		},
+		{
+			name:     "Address Of Ignored Interface",
+			update:   netlink.AddrUpdate{LinkIndex: 10, LinkAddress: net.IPNet{IP: net.ParseIP("100.64.0.1"), Mask: net.CIDRMask(16, 32)}, NewAddr: true},
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, isAddressChange(testCase.update, ignoreIndex10))
+		})
+	}
+}
+
+func TestMonitor_Start(t *testing.T) {
+	changed := make(chan struct{}, 1)
+	m := New("wt0", func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- m.Start(ctx)
+	}()
+
+	// give the monitor some time to subscribe
+	time.Sleep(200 * time.Millisecond)
+
+	link, err := netlink.LinkByName("lo")
+	require.NoError(t, err, "should find the loopback interface")
+
+	addr, err := netlink.ParseAddr("192.168.251.1/32")
+	require.NoError(t, err)
+	err = netlink.AddrAdd(link, addr)
+	require.NoError(t, err, "should add an address to the loopback interface")
+	defer func() {
+		_ = netlink.AddrDel(link, addr)
+	}()
+
+	select {
+	case <-changed:
+	case err = <-errCh:
+		t.Fatalf("monitor stopped unexpectedly: %v", err)
+	case <-time.After(2*debounceInterval + time.Second):
+		t.Fatal("address change wasn't notified")
+	}
+
+	cancel()
+	select {
+	case err = <-errCh:
+		require.NoError(t, err, "monitor should stop without error")
+	case <-time.After(time.Second):
+		t.Fatal("monitor didn't stop")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package networkmonitor
+
+import "context"
+
+// Start returns ErrNotSupported as network changes are only watched on Linux
+func (m *Monitor) Start(ctx context.Context) error {
+	return ErrNotSupported
+}
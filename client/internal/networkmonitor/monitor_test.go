@@ -0,0 +1,39 @@
+package networkmonitor
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitor_NotifyDebounce(t *testing.T) {
+	var calls int32
+	m := New("wt0", func() {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	for i := 0; i < 5; i++ {
+		m.notify()
+	}
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 1
+	}, 2*debounceInterval, 100*time.Millisecond, "a burst of changes should be notified once")
+
+	time.Sleep(debounceInterval)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls), "no more notifications expected")
+}
+
+func TestMonitor_Stop(t *testing.T) {
+	var calls int32
+	m := New("wt0", func() {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	m.notify()
+	m.stop()
+
+	time.Sleep(debounceInterval + 500*time.Millisecond)
+	require.Equal(t, int32(0), atomic.LoadInt32(&calls), "stopped monitor shouldn't notify")
+}
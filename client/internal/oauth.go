@@ -17,6 +17,8 @@ import (
 type OAuthClient interface {
 	RequestDeviceCode(ctx context.Context) (DeviceAuthInfo, error)
 	WaitToken(ctx context.Context, info DeviceAuthInfo) (TokenInfo, error)
+	// RefreshToken silently obtains a new access token with the refresh token of a previous login
+	RefreshToken(ctx context.Context, refreshToken string) (TokenInfo, error)
 	GetClientID(ctx context.Context) string
 }
 
@@ -60,6 +62,14 @@ type Hosted struct {
 	TokenEndpoint string
 	// DeviceAuthEndpoint to request device authorization code
 	DeviceAuthEndpoint string
+	// Scope requested with the device code, offline_access has to be requested for refresh tokens by most providers
+	Scope string
+	// Issuer of the access tokens, it isn't verified if empty
+	Issuer string
+	// JwksURI to fetch the keys verifying the signature of access tokens from
+	JwksURI string
+	// AllowUnverifiedTokens accepts access tokens without verifying their signature if JwksURI is empty
+	AllowUnverifiedTokens bool
 
 	HTTPClient HTTPClient
 }
@@ -91,7 +101,7 @@ type Claims struct {
 }
 
 // NewHostedDeviceFlow returns an Hosted OAuth client
-func NewHostedDeviceFlow(audience string, clientID string, tokenEndpoint string, deviceAuthEndpoint string) *Hosted {
+func NewHostedDeviceFlow(config ProviderConfig) *Hosted {
 	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
 	httpTransport.MaxIdleConns = 5
 
@@ -101,11 +111,16 @@ func NewHostedDeviceFlow(audience string, clientID string, tokenEndpoint string,
 	}
 
 	return &Hosted{
-		Audience:           audience,
-		ClientID:           clientID,
-		TokenEndpoint:      tokenEndpoint,
+		Audience:           config.Audience,
+		ClientID:           config.ClientID,
+		TokenEndpoint:      config.TokenEndpoint,
 		HTTPClient:         httpClient,
-		DeviceAuthEndpoint: deviceAuthEndpoint,
+		DeviceAuthEndpoint: config.DeviceAuthEndpoint,
+		Scope:              config.Scope,
+		Issuer:             config.Issuer,
+		JwksURI:            config.JwksURI,
+
+		AllowUnverifiedTokens: config.AllowUnverifiedTokens,
 	}
 }
 
@@ -119,6 +134,9 @@ func (h *Hosted) RequestDeviceCode(ctx context.Context) (DeviceAuthInfo, error)
 	form := url.Values{}
 	form.Add("client_id", h.ClientID)
 	form.Add("audience", h.Audience)
+	if h.Scope != "" {
+		form.Add("scope", h.Scope)
+	}
 	req, err := http.NewRequest("POST", h.DeviceAuthEndpoint,
 		strings.NewReader(form.Encode()))
 	if err != nil {
@@ -155,14 +173,20 @@ func (h *Hosted) requestToken(info DeviceAuthInfo) (TokenRequestResponse, error)
 	form.Add("client_id", h.ClientID)
 	form.Add("grant_type", HostedGrantType)
 	form.Add("device_code", info.DeviceCode)
-	req, err := http.NewRequest("POST", h.TokenEndpoint, strings.NewReader(form.Encode()))
+	return requestTokenForm(h.HTTPClient, h.TokenEndpoint, form)
+}
+
+// requestTokenForm posts the form of a grant to the token endpoint and parses the response.
+// Error responses of the grant are returned in the response, not as error
+func requestTokenForm(httpClient HTTPClient, tokenEndpoint string, form url.Values) (TokenRequestResponse, error) {
+	req, err := http.NewRequest("POST", tokenEndpoint, strings.NewReader(form.Encode()))
 	if err != nil {
 		return TokenRequestResponse{}, fmt.Errorf("failed to create request access token: %v", err)
 	}
 
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	res, err := h.HTTPClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return TokenRequestResponse{}, fmt.Errorf("failed to request access token with error: %v", err)
 	}
@@ -192,6 +216,35 @@ func (h *Hosted) requestToken(info DeviceAuthInfo) (TokenRequestResponse, error)
 	return tokenResponse, nil
 }
 
+// refreshAccessToken exchanges a refresh token for a new access token. Providers that don't rotate refresh tokens
+// don't return a new one, the previous refresh token is returned with the access token then
+func refreshAccessToken(httpClient HTTPClient, tokenEndpoint, clientID, clientSecret, refreshToken string) (TokenInfo, error) {
+	if refreshToken == "" {
+		return TokenInfo{}, fmt.Errorf("no refresh token available")
+	}
+
+	form := url.Values{}
+	form.Add("client_id", clientID)
+	form.Add("grant_type", HostedRefreshGrant)
+	form.Add("refresh_token", refreshToken)
+	if clientSecret != "" {
+		form.Add("client_secret", clientSecret)
+	}
+
+	tokenResponse, err := requestTokenForm(httpClient, tokenEndpoint, form)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	if tokenResponse.Error != "" {
+		return TokenInfo{}, fmt.Errorf("refresh token request failed: %s %s", tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+
+	if tokenResponse.RefreshToken == "" {
+		tokenResponse.RefreshToken = refreshToken
+	}
+	return tokenResponse.TokenInfo, nil
+}
+
 // WaitToken waits user's login and authorize the app. Once the user's authorize
 // it retrieves the access token from Hosted's endpoint and validates it before returning
 func (h *Hosted) WaitToken(ctx context.Context, info DeviceAuthInfo) (TokenInfo, error) {
@@ -220,7 +273,8 @@ func (h *Hosted) WaitToken(ctx context.Context, info DeviceAuthInfo) (TokenInfo,
 				return TokenInfo{}, fmt.Errorf(tokenResponse.ErrorDescription)
 			}
 
-			err = isValidAccessToken(tokenResponse.AccessToken, h.Audience)
+			err = validateAccessToken(h.HTTPClient, tokenResponse.AccessToken, h.Audience, h.Issuer, h.JwksURI,
+				h.AllowUnverifiedTokens)
 			if err != nil {
 				return TokenInfo{}, fmt.Errorf("validate access token failed with error: %v", err)
 			}
@@ -237,6 +291,20 @@ func (h *Hosted) WaitToken(ctx context.Context, info DeviceAuthInfo) (TokenInfo,
 	}
 }
 
+// RefreshToken requests a new access token with the refresh token of a previous login and validates it
+func (h *Hosted) RefreshToken(_ context.Context, refreshToken string) (TokenInfo, error) {
+	tokenInfo, err := refreshAccessToken(h.HTTPClient, h.TokenEndpoint, h.ClientID, "", refreshToken)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	err = validateAccessToken(h.HTTPClient, tokenInfo.AccessToken, h.Audience, h.Issuer, h.JwksURI, h.AllowUnverifiedTokens)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("validate access token failed with error: %v", err)
+	}
+	return tokenInfo, nil
+}
+
 // isValidAccessToken is a simple validation of the access token
 func isValidAccessToken(token string, audience string) error {
 	if token == "" {
@@ -0,0 +1,447 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PKCEAuthorizationGrantType grant type for the authorization code flow with PKCE
+const PKCEAuthorizationGrantType = "authorization_code"
+
+// pkceFlowTimeout is the time the user has to complete the login in the browser
+const pkceFlowTimeout = 5 * time.Minute
+
+// PKCEAuthorizationFlow represents the authorization code flow with PKCE information received from management
+type PKCEAuthorizationFlow struct {
+	ProviderConfig PKCEProviderConfig
+}
+
+// PKCEProviderConfig has all attributes needed to initiate an authorization code flow with PKCE
+type PKCEProviderConfig struct {
+	// ClientID An IDP application client id
+	ClientID string
+	// ClientSecret An IDP application client secret, public clients don't have one
+	ClientSecret string
+	// Audience An Audience for to authorization validation
+	Audience string
+	// TokenEndpoint is the endpoint of an IDP manager where clients can obtain access token
+	TokenEndpoint string
+	// AuthorizationEndpoint is the endpoint of an IDP manager where the user authorizes the client
+	AuthorizationEndpoint string
+	// Scope requested from the IDP manager
+	Scope string
+	// RedirectURLs are the loopback URLs the authorization code can be received on, the first available one is used
+	RedirectURLs []string
+	// Issuer of the access tokens, it isn't verified if empty
+	Issuer string
+	// JwksURI to fetch the keys verifying the signature of access tokens from
+	JwksURI string
+	// AllowUnverifiedTokens accepts access tokens without verifying their signature if JwksURI is empty
+	AllowUnverifiedTokens bool
+}
+
+// GetPKCEAuthorizationFlowInfo requests the authorization code flow with PKCE information from management
+func GetPKCEAuthorizationFlowInfo(ctx context.Context, config *Config) (PKCEAuthorizationFlow, error) {
+	// validate our peer's Wireguard PRIVATE key
+	myPrivateKey, err := wgtypes.ParseKey(config.PrivateKey)
+	if err != nil {
+		log.Errorf("failed parsing Wireguard key %s: [%s]", config.PrivateKey, err.Error())
+		return PKCEAuthorizationFlow{}, err
+	}
+
+	log.Debugf("connecting to Management Service %s", config.ManagementURL.String())
+	mgmClient, err := newManagementClient(ctx, config, myPrivateKey)
+	if err != nil {
+		log.Errorf("failed connecting to Management Service %s %v", config.ManagementURL.String(), err)
+		return PKCEAuthorizationFlow{}, err
+	}
+	log.Debugf("connected to the Management service %s", config.ManagementURL.String())
+	defer func() {
+		err = mgmClient.Close()
+		if err != nil {
+			log.Warnf("failed to close the Management service client %v", err)
+		}
+	}()
+
+	serverKey, err := mgmClient.GetServerPublicKey()
+	if err != nil {
+		log.Errorf("failed while getting Management Service public key: %v", err)
+		return PKCEAuthorizationFlow{}, err
+	}
+
+	protoPKCEAuthorizationFlow, err := mgmClient.GetPKCEAuthorizationFlow(*serverKey)
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Code() == codes.NotFound {
+			log.Warnf("server couldn't find pkce flow, contact admin: %v", err)
+			return PKCEAuthorizationFlow{}, err
+		}
+		log.Errorf("failed to retrieve pkce flow: %v", err)
+		return PKCEAuthorizationFlow{}, err
+	}
+
+	providerConfig := protoPKCEAuthorizationFlow.GetProviderConfig()
+	authorizationFlow := PKCEAuthorizationFlow{
+		ProviderConfig: PKCEProviderConfig{
+			ClientID:              providerConfig.GetClientID(),
+			ClientSecret:          providerConfig.GetClientSecret(),
+			Audience:              providerConfig.GetAudience(),
+			TokenEndpoint:         providerConfig.GetTokenEndpoint(),
+			AuthorizationEndpoint: providerConfig.GetAuthorizationEndpoint(),
+			Scope:                 providerConfig.GetScope(),
+			RedirectURLs:          providerConfig.GetRedirectURLs(),
+			Issuer:                providerConfig.GetIssuer(),
+			JwksURI:               providerConfig.GetJwksURI(),
+
+			AllowUnverifiedTokens: config.AllowUnverifiedAccessTokens,
+		},
+	}
+
+	err = isPKCEProviderConfigValid(authorizationFlow.ProviderConfig)
+	if err != nil {
+		return PKCEAuthorizationFlow{}, err
+	}
+
+	return authorizationFlow, nil
+}
+
+func isPKCEProviderConfigValid(config PKCEProviderConfig) error {
+	errorMSGFormat := "invalid provider configuration received from management: %s value is empty. Contact your NetBird administrator"
+	if config.Audience == "" {
+		return fmt.Errorf(errorMSGFormat, "Audience")
+	}
+	if config.ClientID == "" {
+		return fmt.Errorf(errorMSGFormat, "Client ID")
+	}
+	if config.TokenEndpoint == "" {
+		return fmt.Errorf(errorMSGFormat, "Token Endpoint")
+	}
+	if config.AuthorizationEndpoint == "" {
+		return fmt.Errorf(errorMSGFormat, "Authorization Endpoint")
+	}
+	if len(config.RedirectURLs) == 0 {
+		return fmt.Errorf(errorMSGFormat, "Redirect URLs")
+	}
+	if config.JwksURI == "" && !config.AllowUnverifiedTokens {
+		return errNoJWKS
+	}
+	return nil
+}
+
+// PKCEFlow is an OAuth client using the authorization code flow with PKCE (https://datatracker.ietf.org/doc/html/rfc7636).
+// The user authorizes the client in a browser on this machine,
+// the browser is then redirected to a loopback HTTP listener receiving the authorization code
+type PKCEFlow struct {
+	providerConfig PKCEProviderConfig
+
+	HTTPClient HTTPClient
+
+	mu sync.Mutex
+	// flows are the started logins by their state
+	flows map[string]*pkceLogin
+}
+
+// pkceLogin is a started login waiting for the redirect of the browser
+type pkceLogin struct {
+	codeVerifier string
+	redirectURL  string
+	server       *http.Server
+	result       chan pkceResult
+	closeOnce    sync.Once
+}
+
+type pkceResult struct {
+	code string
+	err  error
+}
+
+// NewPKCEFlow returns an OAuth client using the authorization code flow with PKCE
+func NewPKCEFlow(config PKCEProviderConfig) *PKCEFlow {
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.MaxIdleConns = 5
+
+	httpClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: httpTransport,
+	}
+
+	return &PKCEFlow{
+		providerConfig: config,
+		HTTPClient:     httpClient,
+		flows:          make(map[string]*pkceLogin),
+	}
+}
+
+// GetClientID returns the provider client id
+func (p *PKCEFlow) GetClientID(_ context.Context) string {
+	return p.providerConfig.ClientID
+}
+
+// RequestDeviceCode starts a login. It listens on the first available redirect URL and returns the authorization URL
+// to open in a browser as verification URI. The state of the login is returned as user code
+func (p *PKCEFlow) RequestDeviceCode(_ context.Context) (DeviceAuthInfo, error) {
+	state, err := randomURLSafeString(16)
+	if err != nil {
+		return DeviceAuthInfo{}, err
+	}
+	codeVerifier, err := randomURLSafeString(32)
+	if err != nil {
+		return DeviceAuthInfo{}, err
+	}
+
+	listener, redirectURL, err := listenOnRedirectURL(p.providerConfig.RedirectURLs)
+	if err != nil {
+		return DeviceAuthInfo{}, err
+	}
+
+	login := &pkceLogin{
+		codeVerifier: codeVerifier,
+		redirectURL:  redirectURL.String(),
+		result:       make(chan pkceResult, 1),
+	}
+	login.server = &http.Server{Handler: login.handler(redirectURL.Path, state)}
+
+	p.mu.Lock()
+	p.flows[state] = login
+	p.mu.Unlock()
+
+	go func() {
+		err := login.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Warnf("pkce redirect listener stopped: %v", err)
+		}
+	}()
+	// the listener is released even if the login isn't waited for
+	time.AfterFunc(pkceFlowTimeout, func() {
+		p.removeLogin(state)
+	})
+
+	authURL, err := p.authorizationURL(state, codeVerifier, redirectURL.String())
+	if err != nil {
+		p.removeLogin(state)
+		return DeviceAuthInfo{}, err
+	}
+
+	return DeviceAuthInfo{
+		UserCode:                state,
+		VerificationURI:         authURL,
+		VerificationURIComplete: authURL,
+		ExpiresIn:               int(pkceFlowTimeout.Seconds()),
+	}, nil
+}
+
+// WaitToken waits until the browser has been redirected with the authorization code of the login
+// and exchanges the code for an access token
+func (p *PKCEFlow) WaitToken(ctx context.Context, info DeviceAuthInfo) (TokenInfo, error) {
+	p.mu.Lock()
+	login, ok := p.flows[info.UserCode]
+	p.mu.Unlock()
+	if !ok {
+		return TokenInfo{}, fmt.Errorf("login has expired or hasn't been started")
+	}
+	defer p.removeLogin(info.UserCode)
+
+	var result pkceResult
+	select {
+	case <-ctx.Done():
+		return TokenInfo{}, ctx.Err()
+	case result = <-login.result:
+	}
+	if result.err != nil {
+		return TokenInfo{}, result.err
+	}
+
+	tokenResponse, err := p.requestToken(result.code, login.codeVerifier, login.redirectURL)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	if tokenResponse.Error != "" {
+		return TokenInfo{}, fmt.Errorf("token request failed: %s %s", tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+
+	err = p.validateAccessToken(tokenResponse.AccessToken)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("validate access token failed with error: %v", err)
+	}
+
+	return tokenResponse.TokenInfo, nil
+}
+
+// RefreshToken requests a new access token with the refresh token of a previous login and validates it
+func (p *PKCEFlow) RefreshToken(_ context.Context, refreshToken string) (TokenInfo, error) {
+	tokenInfo, err := refreshAccessToken(p.HTTPClient, p.providerConfig.TokenEndpoint, p.providerConfig.ClientID,
+		p.providerConfig.ClientSecret, refreshToken)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	err = p.validateAccessToken(tokenInfo.AccessToken)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("validate access token failed with error: %v", err)
+	}
+	return tokenInfo, nil
+}
+
+func (p *PKCEFlow) validateAccessToken(token string) error {
+	return validateAccessToken(p.HTTPClient, token, p.providerConfig.Audience, p.providerConfig.Issuer,
+		p.providerConfig.JwksURI, p.providerConfig.AllowUnverifiedTokens)
+}
+
+func (p *PKCEFlow) authorizationURL(state, codeVerifier, redirectURL string) (string, error) {
+	authURL, err := url.Parse(p.providerConfig.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parse authorization endpoint: %v", err)
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.providerConfig.ClientID)
+	query.Set("redirect_uri", redirectURL)
+	query.Set("state", state)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	query.Set("audience", p.providerConfig.Audience)
+	if p.providerConfig.Scope != "" {
+		query.Set("scope", p.providerConfig.Scope)
+	}
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+func (p *PKCEFlow) requestToken(code, codeVerifier, redirectURL string) (TokenRequestResponse, error) {
+	form := url.Values{}
+	form.Add("client_id", p.providerConfig.ClientID)
+	form.Add("grant_type", PKCEAuthorizationGrantType)
+	form.Add("code", code)
+	form.Add("code_verifier", codeVerifier)
+	form.Add("redirect_uri", redirectURL)
+	if p.providerConfig.ClientSecret != "" {
+		form.Add("client_secret", p.providerConfig.ClientSecret)
+	}
+
+	return requestTokenForm(p.HTTPClient, p.providerConfig.TokenEndpoint, form)
+}
+
+func (p *PKCEFlow) removeLogin(state string) {
+	p.mu.Lock()
+	login, ok := p.flows[state]
+	delete(p.flows, state)
+	p.mu.Unlock()
+
+	if ok {
+		login.close()
+	}
+}
+
+// handler receives the redirect of the browser and passes the authorization code on
+func (l *pkceLogin) handler(path, state string) http.Handler {
+	if path == "" {
+		path = "/"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "invalid login state", http.StatusBadRequest)
+			return
+		}
+
+		var result pkceResult
+		if authErr := query.Get("error"); authErr != "" {
+			result.err = fmt.Errorf("authorization failed: %s %s", authErr, query.Get("error_description"))
+		} else if query.Get("code") == "" {
+			result.err = fmt.Errorf("authorization code is missing in the redirect")
+		} else {
+			result.code = query.Get("code")
+		}
+
+		select {
+		case l.result <- result:
+		default:
+			http.Error(w, "login has already been completed", http.StatusConflict)
+			return
+		}
+
+		if result.err != nil {
+			http.Error(w, "NetBird login failed: "+result.err.Error(), http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("NetBird login succeeded, you can close this page."))
+	})
+	return mux
+}
+
+func (l *pkceLogin) close() {
+	l.closeOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = l.server.Shutdown(ctx)
+	})
+}
+
+// listenOnRedirectURL listens on the first loopback redirect URL with an available port
+func listenOnRedirectURL(redirectURLs []string) (net.Listener, *url.URL, error) {
+	var errs []string
+	for _, redirectURL := range redirectURLs {
+		parsedURL, err := url.Parse(redirectURL)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", redirectURL, err))
+			continue
+		}
+
+		if !isLoopbackHost(parsedURL.Hostname()) {
+			errs = append(errs, fmt.Sprintf("%s: not a loopback URL", redirectURL))
+			continue
+		}
+
+		// the browser connects to port 80 if the redirect URL has no port
+		port := parsedURL.Port()
+		if port == "" {
+			port = "80"
+		}
+
+		listener, err := net.Listen("tcp", net.JoinHostPort(parsedURL.Hostname(), port))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", redirectURL, err))
+			continue
+		}
+		return listener, parsedURL, nil
+	}
+	return nil, nil, fmt.Errorf("unable to listen on any redirect URL: %s", strings.Join(errs, ", "))
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func randomURLSafeString(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("generate random string: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider authorizes every request without user interaction and issues tokens for valid PKCE code exchanges
+type fakeOIDCProvider struct {
+	*httptest.Server
+	clientID string
+	audience string
+
+	mu         sync.Mutex
+	challenges map[string]string
+	redirects  map[string]string
+}
+
+func newFakeOIDCProvider(t *testing.T, clientID, audience string) *fakeOIDCProvider {
+	t.Helper()
+
+	provider := &fakeOIDCProvider{
+		clientID:   clientID,
+		audience:   audience,
+		challenges: make(map[string]string),
+		redirects:  make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", provider.authorize)
+	mux.HandleFunc("/token", provider.token)
+	provider.Server = httptest.NewServer(mux)
+	t.Cleanup(provider.Close)
+
+	return provider
+}
+
+func (p *fakeOIDCProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != p.clientID || query.Get("response_type") != "code" ||
+		query.Get("code_challenge_method") != "S256" || query.Get("audience") != p.audience {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	code := fmt.Sprintf("code-%d", len(p.challenges))
+	p.challenges[code] = query.Get("code_challenge")
+	p.redirects[code] = query.Get("redirect_uri")
+	p.mu.Unlock()
+
+	redirectURL, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect URI", http.StatusBadRequest)
+		return
+	}
+	redirectQuery := redirectURL.Query()
+	redirectQuery.Set("code", code)
+	redirectQuery.Set("state", query.Get("state"))
+	redirectURL.RawQuery = redirectQuery.Encode()
+
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+func (p *fakeOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+
+	p.mu.Lock()
+	challenge, ok := p.challenges[code]
+	redirectURL := p.redirects[code]
+	delete(p.challenges, code)
+	p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok || r.PostForm.Get("grant_type") != PKCEAuthorizationGrantType || r.PostForm.Get("client_id") != p.clientID ||
+		r.PostForm.Get("redirect_uri") != redirectURL || base64.RawURLEncoding.EncodeToString(verifier[:]) != challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "invalid code exchange"})
+		return
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"aud": p.audience}).SignedString([]byte("secret"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(TokenInfo{AccessToken: accessToken, TokenType: "Bearer", ExpiresIn: 3600})
+}
+
+func freeLoopbackPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	return port
+}
+
+// openInBrowser follows the authorization URL and its redirect to the loopback listener like a browser would
+func openInBrowser(t *testing.T, authURL string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestPKCEFlow(t *testing.T) {
+	provider := newFakeOIDCProvider(t, "netbird-client", "netbird")
+
+	// the first redirect URL is taken, the flow has to fall back to the second one
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+	port := freeLoopbackPort(t)
+
+	flow := NewPKCEFlow(PKCEProviderConfig{
+		ClientID:              "netbird-client",
+		Audience:              "netbird",
+		TokenEndpoint:         provider.URL + "/token",
+		AuthorizationEndpoint: provider.URL + "/authorize",
+		Scope:                 "openid profile email",
+		RedirectURLs: []string{
+			fmt.Sprintf("http://%s/callback", busy.Addr().String()),
+			fmt.Sprintf("http://localhost:%d/callback", port),
+		},
+		// the fake provider doesn't sign its tokens with a published key
+		AllowUnverifiedTokens: true,
+	})
+
+	info, err := flow.RequestDeviceCode(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, info.UserCode)
+	assert.Equal(t, info.VerificationURI, info.VerificationURIComplete)
+
+	authURL, err := url.Parse(info.VerificationURIComplete)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("http://localhost:%d/callback", port), authURL.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid profile email", authURL.Query().Get("scope"))
+	assert.Equal(t, info.UserCode, authURL.Query().Get("state"))
+
+	code, body := openInBrowser(t, info.VerificationURIComplete)
+	assert.Equal(t, http.StatusOK, code, body)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tokenInfo, err := flow.WaitToken(ctx, info)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tokenInfo.TokenType)
+	require.NoError(t, isValidAccessToken(tokenInfo.AccessToken, "netbird"))
+
+	// the redirect listener is released after the login
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	require.NoError(t, err, "redirect listener should be closed")
+	_ = listener.Close()
+
+	_, err = flow.WaitToken(ctx, info)
+	assert.Error(t, err, "a completed login can't be waited for again")
+}
+
+func TestPKCEFlow_InvalidRedirect(t *testing.T) {
+	provider := newFakeOIDCProvider(t, "netbird-client", "netbird")
+	port := freeLoopbackPort(t)
+
+	flow := NewPKCEFlow(PKCEProviderConfig{
+		ClientID:              "netbird-client",
+		Audience:              "netbird",
+		TokenEndpoint:         provider.URL + "/token",
+		AuthorizationEndpoint: provider.URL + "/authorize",
+		RedirectURLs:          []string{fmt.Sprintf("http://127.0.0.1:%d/", port)},
+	})
+
+	info, err := flow.RequestDeviceCode(context.Background())
+	require.NoError(t, err)
+
+	code, _ := openInBrowser(t, fmt.Sprintf("http://127.0.0.1:%d/?code=code-0&state=forged", port))
+	assert.Equal(t, http.StatusBadRequest, code, "redirect with another state should be rejected")
+
+	code, _ = openInBrowser(t, fmt.Sprintf("http://127.0.0.1:%d/?error=access_denied&state=%s", port, info.UserCode))
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = flow.WaitToken(ctx, info)
+	assert.ErrorContains(t, err, "access_denied")
+
+	_, _, err = listenOnRedirectURL([]string{"http://idp.example.com:53000/"})
+	assert.Error(t, err, "non-loopback redirect URLs should be refused")
+}
+
+func TestListenOnRedirectURL_DefaultPort(t *testing.T) {
+	// port 80 may be taken or need privileges, the URL must not be rejected for its missing port though
+	listener, redirectURL, err := listenOnRedirectURL([]string{"http://127.0.0.1/"})
+	if err != nil {
+		assert.NotContains(t, err.Error(), "missing port")
+		return
+	}
+	defer listener.Close()
+	assert.Equal(t, 80, listener.Addr().(*net.TCPAddr).Port)
+	assert.Equal(t, "http://127.0.0.1/", redirectURL.String(), "the redirect URL should be kept as configured")
+}
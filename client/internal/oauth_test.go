@@ -277,6 +277,8 @@ func TestHosted_WaitToken(t *testing.T) {
 				TokenEndpoint:      "test.hosted.com/token",
 				DeviceAuthEndpoint: "test.hosted.com/device/auth",
 				HTTPClient:         &httpClient,
+				// the test tokens aren't signed with a published key
+				AllowUnverifiedTokens: true,
 			}
 
 			ctx, cancel := context.WithTimeout(context.TODO(), testCase.inputTimeout)
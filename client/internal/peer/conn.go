@@ -2,11 +2,14 @@ package peer
 
 import (
 	"context"
+	"fmt"
 	nbStatus "github.com/netbirdio/netbird/client/status"
 	"github.com/netbirdio/netbird/client/system"
 	"github.com/netbirdio/netbird/iface"
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"net"
+	"net/netip"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -39,6 +42,13 @@ type ConnConfig struct {
 	UDPMuxSrflx ice.UniversalUDPMux
 
 	LocalWgPort int
+
+	// MappedAddress returns the external address of the UDP mux port mapped on the gateway, if any.
+	// It is offered to the remote peer as an additional server reflexive candidate
+	MappedAddress func() (netip.AddrPort, bool)
+
+	// ConnectionPolicy restricts the connection to a relayed or a direct one
+	ConnectionPolicy ConnectionPolicy
 }
 
 // OfferAnswer represents a session establishment offer or answer
@@ -51,6 +61,9 @@ type OfferAnswer struct {
 
 	// Version of NetBird Agent
 	Version string
+
+	// Upgrade is true when the offer or answer belongs to a background renegotiation of a relayed connection
+	Upgrade bool
 }
 
 // IceCredentials ICE protocol credentials struct
@@ -64,7 +77,7 @@ type Conn struct {
 	mu     sync.Mutex
 
 	// signalCandidate is a handler function to signal remote peer about local connection candidate
-	signalCandidate func(candidate ice.Candidate) error
+	signalCandidate func(candidate ice.Candidate, upgrade bool) error
 	// signalOffer is a handler function to signal remote peer our connection offer (credentials)
 	signalOffer  func(OfferAnswer) error
 	signalAnswer func(OfferAnswer) error
@@ -77,8 +90,15 @@ type Conn struct {
 	ctx                context.Context
 	notifyDisconnected context.CancelFunc
 
+	// remoteUpgradeAnswerCh is a channel used to wait for the remote answer to an upgrade offer
+	remoteUpgradeAnswerCh chan OfferAnswer
+
 	agent  *ice.Agent
 	status ConnStatus
+	// relayed is true when the connection goes through a TURN relay
+	relayed bool
+	// upgradeAgent is the ICE agent of a background attempt to find a direct path to a relayed peer
+	upgradeAgent *ice.Agent
 
 	statusRecorder *nbStatus.Status
 
@@ -106,6 +126,8 @@ func NewConn(config ConnConfig, statusRecorder *nbStatus.Status) (*Conn, error)
 		remoteOffersCh: make(chan OfferAnswer),
 		remoteAnswerCh: make(chan OfferAnswer),
 		statusRecorder: statusRecorder,
+
+		remoteUpgradeAnswerCh: make(chan OfferAnswer),
 	}, nil
 }
 
@@ -147,7 +169,7 @@ func (conn *Conn) reCreateAgent() error {
 		MulticastDNSMode: ice.MulticastDNSModeDisabled,
 		NetworkTypes:     []ice.NetworkType{ice.NetworkTypeUDP4},
 		Urls:             conn.config.StunTurn,
-		CandidateTypes:   []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive, ice.CandidateTypeRelay},
+		CandidateTypes:   conn.config.ConnectionPolicy.candidateTypes(),
 		FailedTimeout:    &failedTimeout,
 		InterfaceFilter:  interfaceFilter(conn.config.InterfaceBlackList),
 		UDPMux:           conn.config.UDPMux,
@@ -186,6 +208,7 @@ func (conn *Conn) Open() error {
 	peerState.IP = strings.Split(conn.config.ProxyConfig.AllowedIps, "/")[0]
 	peerState.ConnStatusUpdate = time.Now()
 	peerState.ConnStatus = conn.status.String()
+	peerState.ConnectionPolicy = conn.config.ConnectionPolicy.String()
 
 	err := conn.statusRecorder.UpdatePeerState(peerState)
 	if err != nil {
@@ -254,6 +277,7 @@ func (conn *Conn) Open() error {
 	if err != nil {
 		return err
 	}
+	conn.signalMappedCandidate(false)
 
 	// will block until connection succeeded
 	// but it won't release if ICE Agent went into Disconnected or Failed state,
@@ -289,6 +313,10 @@ func (conn *Conn) Open() error {
 		log.Infof("connected to peer %s [laddr <-> raddr] [%s <-> %s]", conn.config.Key, remoteConn.LocalAddr().String(), remoteConn.RemoteAddr().String())
 	}
 
+	if isControlling && conn.isRelayed() && conn.config.ConnectionPolicy != ConnectionPolicyRelay {
+		go conn.upgradeWorker(conn.ctx)
+	}
+
 	// wait until connection disconnected or has been closed externally (upper layer, e.g. engine)
 	select {
 	case <-conn.closeCh:
@@ -303,12 +331,17 @@ func (conn *Conn) Open() error {
 // useProxy determines whether a direct connection (without a go proxy) is possible
 // There are 3 cases: one of the peers has a public IP or both peers are in the same private network
 // Please note, that this check happens when peers were already able to ping each other using ICE layer.
-func shouldUseProxy(pair *ice.CandidatePair) bool {
+// Under the relay policy the proxy is always used, Wireguard must never send to the remote peer address directly
+func shouldUseProxy(pair *ice.CandidatePair, policy ConnectionPolicy) bool {
 	remoteIP := net.ParseIP(pair.Remote.Address())
 	myIp := net.ParseIP(pair.Local.Address())
 	remoteIsPublic := IsPublicIP(remoteIP)
 	myIsPublic := IsPublicIP(myIp)
 
+	if policy == ConnectionPolicyRelay {
+		return true
+	}
+
 	if pair.Local.Type() == ice.CandidateTypeRelay || pair.Remote.Type() == ice.CandidateTypeRelay {
 		return true
 	}
@@ -351,7 +384,7 @@ func (conn *Conn) startProxy(remoteConn net.Conn, remoteWgPort int) error {
 	}
 
 	peerState := nbStatus.PeerState{PubKey: conn.config.Key}
-	useProxy := shouldUseProxy(pair)
+	useProxy := shouldUseProxy(pair, conn.config.ConnectionPolicy)
 	var p proxy.Proxy
 	if useProxy {
 		p = proxy.NewWireguardProxy(conn.config.ProxyConfig)
@@ -372,9 +405,13 @@ func (conn *Conn) startProxy(remoteConn net.Conn, remoteWgPort int) error {
 	peerState.ConnStatusUpdate = time.Now()
 	peerState.LocalIceCandidateType = pair.Local.Type().String()
 	peerState.RemoteIceCandidateType = pair.Remote.Type().String()
+	peerState.LocalIceCandidateEndpoint = candidateEndpoint(pair.Local)
+	peerState.RemoteIceCandidateEndpoint = candidateEndpoint(pair.Remote)
 	if pair.Local.Type() == ice.CandidateTypeRelay || pair.Remote.Type() == ice.CandidateTypeRelay {
 		peerState.Relayed = true
+		peerState.RelayServerAddress = relayServerAddress(pair)
 	}
+	conn.relayed = peerState.Relayed
 
 	err = conn.statusRecorder.UpdatePeerState(peerState)
 	if err != nil {
@@ -384,12 +421,35 @@ func (conn *Conn) startProxy(remoteConn net.Conn, remoteWgPort int) error {
 	return nil
 }
 
+// relayServerAddress returns the address allocated on the TURN server of a relayed candidate pair,
+// preferring the local relay when both candidates are relayed
+func relayServerAddress(pair *ice.CandidatePair) string {
+	relay := pair.Local
+	if relay.Type() != ice.CandidateTypeRelay {
+		relay = pair.Remote
+	}
+	return candidateEndpoint(relay)
+}
+
+// candidateEndpoint returns the host:port address of an ICE candidate
+func candidateEndpoint(candidate ice.Candidate) string {
+	return net.JoinHostPort(candidate.Address(), strconv.Itoa(candidate.Port()))
+}
+
 // cleanup closes all open resources and sets status to StatusDisconnected
 func (conn *Conn) cleanup() error {
 	log.Debugf("trying to cleanup %s", conn.config.Key)
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
 
+	if conn.upgradeAgent != nil {
+		err := conn.upgradeAgent.Close()
+		if err != nil {
+			log.Debugf("error while closing upgrade agent of peer %s: %v", conn.config.Key, err)
+		}
+		conn.upgradeAgent = nil
+	}
+
 	if conn.agent != nil {
 		err := conn.agent.Close()
 		if err != nil {
@@ -412,6 +472,7 @@ func (conn *Conn) cleanup() error {
 	}
 
 	conn.status = StatusDisconnected
+	conn.relayed = false
 
 	peerState := nbStatus.PeerState{PubKey: conn.config.Key}
 	peerState.ConnStatus = conn.status.String()
@@ -440,7 +501,8 @@ func (conn *Conn) SetSignalAnswer(handler func(answer OfferAnswer) error) {
 }
 
 // SetSignalCandidate sets a handler function to be triggered by Conn when a new ICE local connection candidate has to be signalled to the remote peer
-func (conn *Conn) SetSignalCandidate(handler func(candidate ice.Candidate) error) {
+// The upgrade flag is set for candidates of a background renegotiation of a relayed connection
+func (conn *Conn) SetSignalCandidate(handler func(candidate ice.Candidate, upgrade bool) error) {
 	conn.signalCandidate = handler
 }
 
@@ -450,7 +512,7 @@ func (conn *Conn) onICECandidate(candidate ice.Candidate) {
 	if candidate != nil {
 		log.Debugf("discovered local candidate %s", candidate.String())
 		go func() {
-			err := conn.signalCandidate(candidate)
+			err := conn.signalCandidate(candidate, false)
 			if err != nil {
 				log.Errorf("failed signaling candidate to the remote peer %s %s", conn.config.Key, err)
 			}
@@ -458,14 +520,51 @@ func (conn *Conn) onICECandidate(candidate ice.Candidate) {
 	}
 }
 
+// signalMappedCandidate signals the address mapped on the gateway to the remote peer as a server reflexive candidate.
+// Connectivity checks sent to it reach the local UDP mux through the gateway
+func (conn *Conn) signalMappedCandidate(upgrade bool) {
+	if conn.config.MappedAddress == nil || conn.config.ConnectionPolicy == ConnectionPolicyRelay {
+		return
+	}
+
+	addr, ok := conn.config.MappedAddress()
+	if !ok {
+		return
+	}
+
+	candidate, err := ice.NewCandidateServerReflexive(&ice.CandidateServerReflexiveConfig{
+		Network:   "udp",
+		Address:   addr.Addr().String(),
+		Port:      int(addr.Port()),
+		Component: 1,
+		RelAddr:   "0.0.0.0",
+		RelPort:   0,
+	})
+	if err != nil {
+		log.Errorf("failed creating candidate for mapped address %s: %v", addr, err)
+		return
+	}
+
+	log.Debugf("signaling mapped address candidate %s", candidate.String())
+	go func() {
+		err := conn.signalCandidate(candidate, upgrade)
+		if err != nil {
+			log.Errorf("failed signaling mapped address candidate to the remote peer %s %s", conn.config.Key, err)
+		}
+	}()
+}
+
 func (conn *Conn) onICESelectedCandidatePair(c1 ice.Candidate, c2 ice.Candidate) {
 	log.Debugf("selected candidate pair [local <-> remote] -> [%s <-> %s], peer %s", c1.String(), c2.String(),
 		conn.config.Key)
+	conn.statusRecorder.TracePeer(conn.config.Key, fmt.Sprintf("selected candidate pair [local <-> remote] -> [%s <-> %s]",
+		c1.String(), c2.String()))
 }
 
 // onICEConnectionStateChange registers callback of an ICE Agent to track connection state
 func (conn *Conn) onICEConnectionStateChange(state ice.ConnectionState) {
 	log.Debugf("peer %s ICE ConnectionState has changed to %s", conn.config.Key, state.String())
+	conn.statusRecorder.TracePeer(conn.config.Key, fmt.Sprintf("ICE connection state has changed to %s", state.String()))
 	if state == ice.ConnectionStateFailed || state == ice.ConnectionStateDisconnected {
 		conn.notifyDisconnected()
 	}
@@ -578,6 +677,11 @@ func (conn *Conn) OnRemoteAnswer(answer OfferAnswer) bool {
 // OnRemoteCandidate Handles ICE connection Candidate provided by the remote peer.
 func (conn *Conn) OnRemoteCandidate(candidate ice.Candidate) {
 	log.Debugf("OnRemoteCandidate from peer %s -> %s", conn.config.Key, candidate.String())
+	if !conn.config.ConnectionPolicy.allowsRemoteCandidate(candidate) {
+		log.Debugf("ignoring candidate %s from peer %s, the %s connection policy doesn't allow it",
+			candidate.String(), conn.config.Key, conn.config.ConnectionPolicy)
+		return
+	}
 	go func() {
 		conn.mu.Lock()
 		defer conn.mu.Unlock()
@@ -111,6 +111,69 @@ func TestConn_OnRemoteAnswer(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestConn_OnRemoteUpgradeOffer(t *testing.T) {
+	conn, err := NewConn(connConf, nbstatus.NewRecorder())
+	if err != nil {
+		return
+	}
+
+	offer := OfferAnswer{
+		IceCredentials: IceCredentials{
+			UFrag: "test",
+			Pwd:   "test",
+		},
+		Upgrade: true,
+	}
+
+	accepted := conn.OnRemoteUpgradeOffer(offer)
+	assert.Equal(t, accepted, false, "upgrade offer shouldn't be accepted when not connected")
+
+	conn.mu.Lock()
+	conn.status = StatusConnected
+	conn.relayed = false
+	conn.mu.Unlock()
+
+	accepted = conn.OnRemoteUpgradeOffer(offer)
+	assert.Equal(t, accepted, false, "upgrade offer shouldn't be accepted when connected directly")
+}
+
+func TestConn_OnRemoteUpgradeAnswer(t *testing.T) {
+	conn, err := NewConn(connConf, nbstatus.NewRecorder())
+	if err != nil {
+		return
+	}
+
+	answer := OfferAnswer{
+		IceCredentials: IceCredentials{
+			UFrag: "test",
+			Pwd:   "test",
+		},
+		Upgrade: true,
+	}
+
+	accepted := conn.OnRemoteUpgradeAnswer(answer)
+	assert.Equal(t, accepted, false, "upgrade answer shouldn't be accepted when no upgrade is in progress")
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		<-conn.remoteUpgradeAnswerCh
+		wg.Done()
+	}()
+
+	go func() {
+		for {
+			if conn.OnRemoteUpgradeAnswer(answer) {
+				wg.Done()
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+}
+
 func TestConn_Status(t *testing.T) {
 
 	conn, err := NewConn(connConf, nbstatus.NewRecorder())
@@ -165,3 +228,30 @@ func TestConn_Close(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestRelayServerAddress(t *testing.T) {
+	host, err := ice.NewCandidateHost(&ice.CandidateHostConfig{
+		Network:   "udp",
+		Address:   "192.168.1.10",
+		Port:      51820,
+		Component: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relay, err := ice.NewCandidateRelay(&ice.CandidateRelayConfig{
+		Network:   "udp",
+		Address:   "198.51.100.1",
+		Port:      40000,
+		Component: 1,
+		RelAddr:   "203.0.113.7",
+		RelPort:   51820,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, relayServerAddress(&ice.CandidatePair{Local: relay, Remote: host}), "198.51.100.1:40000")
+	assert.Equal(t, relayServerAddress(&ice.CandidatePair{Local: host, Remote: relay}), "198.51.100.1:40000")
+}
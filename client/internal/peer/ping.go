@@ -0,0 +1,108 @@
+package peer
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/netip"
+	"os"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const icmpProtocolIPv4 = 1
+
+// Ping sends a single ICMP echo request to the given address and returns the measured round-trip time.
+// When the address is a NetBird IP of a remote peer, the request travels over the WireGuard tunnel,
+// so the result reflects the latency of the tunnel rather than of the underlying network.
+// It tries a privileged raw socket first and falls back to an unprivileged datagram socket.
+func Ping(ctx context.Context, addr netip.Addr) (time.Duration, error) {
+	if !addr.Is4() {
+		return 0, fmt.Errorf("only IPv4 addresses are supported, got %s", addr)
+	}
+
+	network := "udp4"
+	conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err == nil {
+		network = "ip4"
+	} else {
+		conn, err = icmp.ListenPacket("udp4", "0.0.0.0")
+		if err != nil {
+			return 0, fmt.Errorf("unable to open an ICMP socket: %v", err)
+		}
+	}
+	defer conn.Close() //nolint
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(time.Second)
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return 0, err
+	}
+
+	id := os.Getpid() & 0xffff
+	seq := rand.Intn(0xffff)
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("netbird")},
+	}
+	payload, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var dst net.Addr = &net.IPAddr{IP: addr.AsSlice()}
+	if network == "udp4" {
+		dst = &net.UDPAddr{IP: addr.AsSlice()}
+	}
+
+	start := time.Now()
+	_, err = conn.WriteTo(payload, dst)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return 0, err
+		}
+
+		reply, err := icmp.ParseMessage(icmpProtocolIPv4, buf[:n])
+		if err != nil || reply.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+
+		echo, ok := reply.Body.(*icmp.Echo)
+		if !ok || echo.Seq != seq || !sameHost(from, addr) {
+			continue
+		}
+
+		// unprivileged sockets get the ID rewritten by the kernel, so we only check it for raw sockets
+		if network == "ip4" && echo.ID != id {
+			continue
+		}
+
+		return time.Since(start), nil
+	}
+}
+
+func sameHost(from net.Addr, addr netip.Addr) bool {
+	var ip net.IP
+	switch a := from.(type) {
+	case *net.IPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	default:
+		return false
+	}
+	parsed, ok := netip.AddrFromSlice(ip)
+	return ok && parsed.Unmap() == addr
+}
@@ -0,0 +1,51 @@
+package peer
+
+import (
+	"github.com/pion/ice/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConnectionPolicy restricts the paths a connection to a remote peer may take
+type ConnectionPolicy int
+
+const (
+	// ConnectionPolicyAuto uses a direct path when possible and a TURN relay otherwise
+	ConnectionPolicyAuto ConnectionPolicy = iota
+	// ConnectionPolicyRelay uses only TURN relays, the remote peer is never contacted directly
+	ConnectionPolicyRelay
+	// ConnectionPolicyDirect never uses TURN relays
+	ConnectionPolicyDirect
+)
+
+func (p ConnectionPolicy) String() string {
+	switch p {
+	case ConnectionPolicyAuto:
+		return "auto"
+	case ConnectionPolicyRelay:
+		return "relay"
+	case ConnectionPolicyDirect:
+		return "direct"
+	default:
+		log.Errorf("unknown connection policy: %d", p)
+		return "INVALID_CONNECTION_POLICY"
+	}
+}
+
+// candidateTypes returns the types of the local ICE candidates gathered under the policy
+func (p ConnectionPolicy) candidateTypes() []ice.CandidateType {
+	switch p {
+	case ConnectionPolicyRelay:
+		return []ice.CandidateType{ice.CandidateTypeRelay}
+	case ConnectionPolicyDirect:
+		return []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive}
+	default:
+		return []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive, ice.CandidateTypeRelay}
+	}
+}
+
+// allowsRemoteCandidate checks whether a candidate of the remote peer may be used under the policy.
+// With only relay candidates gathered locally every pair goes through a relay anyway,
+// so only the direct policy has to reject the relay candidates of the remote peer
+func (p ConnectionPolicy) allowsRemoteCandidate(candidate ice.Candidate) bool {
+	return p != ConnectionPolicyDirect || candidate.Type() != ice.CandidateTypeRelay
+}
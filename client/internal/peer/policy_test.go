@@ -0,0 +1,57 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/pion/ice/v2"
+)
+
+func TestConnectionPolicy(t *testing.T) {
+	host, err := ice.NewCandidateHost(&ice.CandidateHostConfig{
+		Network:   "udp",
+		Address:   "192.168.1.10",
+		Port:      51820,
+		Component: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remoteHost, err := ice.NewCandidateHost(&ice.CandidateHostConfig{
+		Network:   "udp",
+		Address:   "192.168.1.20",
+		Port:      51820,
+		Component: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relay, err := ice.NewCandidateRelay(&ice.CandidateRelayConfig{
+		Network:   "udp",
+		Address:   "198.51.100.1",
+		Port:      40000,
+		Component: 1,
+		RelAddr:   "203.0.113.7",
+		RelPort:   51820,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ConnectionPolicyRelay.candidateTypes(), []ice.CandidateType{ice.CandidateTypeRelay})
+	assert.Equal(t, ConnectionPolicyDirect.candidateTypes(),
+		[]ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive})
+	assert.Equal(t, len(ConnectionPolicyAuto.candidateTypes()), 3, "auto should gather all candidate types")
+
+	assert.Equal(t, ConnectionPolicyDirect.allowsRemoteCandidate(relay), false, "direct should reject relay candidates")
+	assert.Equal(t, ConnectionPolicyDirect.allowsRemoteCandidate(host), true)
+	assert.Equal(t, ConnectionPolicyRelay.allowsRemoteCandidate(host), true)
+
+	pair := &ice.CandidatePair{Local: host, Remote: remoteHost}
+	assert.Equal(t, shouldUseProxy(pair, ConnectionPolicyAuto), false, "peers in the same network connect directly")
+	assert.Equal(t, shouldUseProxy(pair, ConnectionPolicyRelay), true, "relay should always use the proxy")
+
+	assert.Equal(t, ConnectionPolicyRelay.String(), "relay")
+}
@@ -0,0 +1,369 @@
+package peer
+
+import (
+	"context"
+	"fmt"
+	nbStatus "github.com/netbirdio/netbird/client/status"
+	"github.com/netbirdio/netbird/client/system"
+	"github.com/netbirdio/netbird/iface"
+	"net"
+	"time"
+
+	"github.com/netbirdio/netbird/client/internal/proxy"
+	"github.com/pion/ice/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// relayUpgradeInterval is the interval between attempts to find a direct path to a peer connected over a relay
+	relayUpgradeInterval = 3 * time.Minute
+	// relayUpgradeTimeout is the time an upgrade attempt has to establish a direct path
+	relayUpgradeTimeout = 20 * time.Second
+)
+
+// upgradeWorker periodically renegotiates a relayed connection in the background looking for a direct path.
+// Stops once the connection has been upgraded or ctx is done.
+// Only the controlling peer runs it, the other side answers in OnRemoteUpgradeOffer
+func (conn *Conn) upgradeWorker(ctx context.Context) {
+	ticker := time.NewTicker(relayUpgradeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !conn.isRelayed() {
+				return
+			}
+
+			err := conn.upgrade(ctx)
+			if err != nil {
+				log.Debugf("connection to peer %s stays relayed: %v", conn.config.Key, err)
+				continue
+			}
+			return
+		}
+	}
+}
+
+// upgrade offers a new ICE session to the remote peer and dials it, switching to the new path when it is direct
+func (conn *Conn) upgrade(ctx context.Context) error {
+	agent, err := conn.newUpgradeAgent()
+	if err != nil {
+		return err
+	}
+	defer conn.closeUpgradeAgent(agent)
+
+	localUFrag, localPwd, err := agent.GetLocalUserCredentials()
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("trying to upgrade relayed connection to peer %s", conn.config.Key)
+	err = conn.signalOffer(OfferAnswer{
+		IceCredentials: IceCredentials{localUFrag, localPwd},
+		WgListenPort:   conn.config.LocalWgPort,
+		Version:        system.NetbirdVersion(),
+		Upgrade:        true,
+	})
+	if err != nil {
+		return err
+	}
+
+	var remoteOfferAnswer OfferAnswer
+	select {
+	case remoteOfferAnswer = <-conn.remoteUpgradeAnswerCh:
+	case <-time.After(relayUpgradeTimeout):
+		return fmt.Errorf("no upgrade answer received in %s", relayUpgradeTimeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	err = agent.GatherCandidates()
+	if err != nil {
+		return err
+	}
+	conn.signalMappedCandidate(true)
+
+	dialCtx, cancel := context.WithTimeout(ctx, relayUpgradeTimeout)
+	defer cancel()
+	remoteConn, err := agent.Dial(dialCtx, remoteOfferAnswer.IceCredentials.UFrag, remoteOfferAnswer.IceCredentials.Pwd)
+	if err != nil {
+		return err
+	}
+
+	return conn.switchProxy(agent, remoteConn, remoteOfferAnswer.WgListenPort)
+}
+
+// acceptUpgrade answers an upgrade offer of the remote peer and accepts its ICE session,
+// switching to the new path when it is direct
+func (conn *Conn) acceptUpgrade(ctx context.Context, offer OfferAnswer) error {
+	agent, err := conn.newUpgradeAgent()
+	if err != nil {
+		return err
+	}
+	defer conn.closeUpgradeAgent(agent)
+
+	localUFrag, localPwd, err := agent.GetLocalUserCredentials()
+	if err != nil {
+		return err
+	}
+
+	err = conn.signalAnswer(OfferAnswer{
+		IceCredentials: IceCredentials{localUFrag, localPwd},
+		WgListenPort:   conn.config.LocalWgPort,
+		Version:        system.NetbirdVersion(),
+		Upgrade:        true,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = agent.GatherCandidates()
+	if err != nil {
+		return err
+	}
+	conn.signalMappedCandidate(true)
+
+	acceptCtx, cancel := context.WithTimeout(ctx, relayUpgradeTimeout)
+	defer cancel()
+	remoteConn, err := agent.Accept(acceptCtx, offer.IceCredentials.UFrag, offer.IceCredentials.Pwd)
+	if err != nil {
+		return err
+	}
+
+	return conn.switchProxy(agent, remoteConn, offer.WgListenPort)
+}
+
+// newUpgradeAgent creates the ICE agent of an upgrade attempt.
+// It doesn't gather relay candidates as the attempt is only useful if it finds a direct path
+func (conn *Conn) newUpgradeAgent() (*ice.Agent, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.upgradeAgent != nil {
+		return nil, fmt.Errorf("an upgrade is already in progress")
+	}
+
+	failedTimeout := 6 * time.Second
+	agent, err := ice.NewAgent(&ice.AgentConfig{
+		MulticastDNSMode: ice.MulticastDNSModeDisabled,
+		NetworkTypes:     []ice.NetworkType{ice.NetworkTypeUDP4},
+		Urls:             conn.config.StunTurn,
+		CandidateTypes:   []ice.CandidateType{ice.CandidateTypeHost, ice.CandidateTypeServerReflexive},
+		FailedTimeout:    &failedTimeout,
+		InterfaceFilter:  interfaceFilter(conn.config.InterfaceBlackList),
+		UDPMux:           conn.config.UDPMux,
+		UDPMuxSrflx:      conn.config.UDPMuxSrflx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = agent.OnCandidate(conn.onUpgradeICECandidate)
+	if err != nil {
+		_ = agent.Close()
+		return nil, err
+	}
+
+	// a failing upgrade attempt must not affect the relayed connection,
+	// so the agent only reports disconnections once it carries the traffic
+	err = agent.OnConnectionStateChange(func(state ice.ConnectionState) {
+		conn.mu.Lock()
+		active := conn.agent == agent
+		conn.mu.Unlock()
+		if active {
+			conn.onICEConnectionStateChange(state)
+		}
+	})
+	if err != nil {
+		_ = agent.Close()
+		return nil, err
+	}
+
+	err = agent.OnSelectedCandidatePairChange(conn.onICESelectedCandidatePair)
+	if err != nil {
+		_ = agent.Close()
+		return nil, err
+	}
+
+	conn.upgradeAgent = agent
+
+	return agent, nil
+}
+
+// closeUpgradeAgent closes the agent of an upgrade attempt unless it has replaced the connection agent
+func (conn *Conn) closeUpgradeAgent(agent *ice.Agent) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.upgradeAgent == agent {
+		conn.upgradeAgent = nil
+	}
+
+	if conn.agent == agent {
+		return
+	}
+
+	err := agent.Close()
+	if err != nil {
+		log.Debugf("error while closing upgrade agent of peer %s: %v", conn.config.Key, err)
+	}
+}
+
+// switchProxy moves the Wireguard traffic to the path found by an upgrade agent.
+// The new proxy updates the Wireguard peer endpoint before the old one is stopped, so the peer is never removed
+func (conn *Conn) switchProxy(agent *ice.Agent, remoteConn net.Conn, remoteWgPort int) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.status != StatusConnected || conn.upgradeAgent != agent {
+		return fmt.Errorf("connection has changed during the upgrade")
+	}
+
+	pair, err := agent.GetSelectedCandidatePair()
+	if err != nil {
+		return err
+	}
+
+	if pair.Local.Type() == ice.CandidateTypeRelay || pair.Remote.Type() == ice.CandidateTypeRelay {
+		return fmt.Errorf("upgrade selected a relayed candidate pair")
+	}
+
+	if remoteWgPort == 0 {
+		remoteWgPort = iface.DefaultWgPort
+	}
+
+	var p proxy.Proxy
+	direct := !shouldUseProxy(pair, conn.config.ConnectionPolicy)
+	if direct {
+		p = proxy.NewNoProxy(conn.config.ProxyConfig, remoteWgPort)
+	} else {
+		p = proxy.NewWireguardProxy(conn.config.ProxyConfig)
+	}
+
+	err = p.Start(remoteConn)
+	if err != nil {
+		_ = p.Stop()
+		return err
+	}
+
+	oldProxy := conn.proxy
+	oldAgent := conn.agent
+	conn.proxy = p
+	conn.agent = agent
+	conn.upgradeAgent = nil
+	conn.relayed = false
+
+	if oldProxy != nil {
+		err = oldProxy.Stop()
+		if err != nil {
+			log.Warnf("error while stopping relayed proxy of peer %s: %v", conn.config.Key, err)
+		}
+	}
+
+	if oldAgent != nil {
+		err = oldAgent.Close()
+		if err != nil {
+			log.Debugf("error while closing relayed agent of peer %s: %v", conn.config.Key, err)
+		}
+	}
+
+	log.Infof("upgraded relayed connection to peer %s [laddr <-> raddr] [%s <-> %s]", conn.config.Key,
+		remoteConn.LocalAddr().String(), remoteConn.RemoteAddr().String())
+
+	err = conn.statusRecorder.RecordConnSwitch(nbStatus.ConnSwitch{
+		PubKey:                     conn.config.Key,
+		Time:                       time.Now(),
+		Direct:                     direct,
+		LocalIceCandidateType:      pair.Local.Type().String(),
+		RemoteIceCandidateType:     pair.Remote.Type().String(),
+		LocalIceCandidateEndpoint:  candidateEndpoint(pair.Local),
+		RemoteIceCandidateEndpoint: candidateEndpoint(pair.Remote),
+	})
+	if err != nil {
+		log.Warnf("unable to record connection switch of peer %s, got error: %v", conn.config.Key, err)
+	}
+
+	return nil
+}
+
+// isRelayed returns true if the connection is established over a relay
+func (conn *Conn) isRelayed() bool {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.status == StatusConnected && conn.relayed
+}
+
+// onUpgradeICECandidate is a callback attached to an upgrade ICE Agent to signal its local candidates to the remote peer
+func (conn *Conn) onUpgradeICECandidate(candidate ice.Candidate) {
+	if candidate != nil {
+		log.Debugf("discovered local upgrade candidate %s", candidate.String())
+		go func() {
+			err := conn.signalCandidate(candidate, true)
+			if err != nil {
+				log.Errorf("failed signaling upgrade candidate to the remote peer %s %s", conn.config.Key, err)
+			}
+		}()
+	}
+}
+
+// OnRemoteUpgradeOffer handles an upgrade offer from the remote peer and returns true if the message was accepted.
+// Offers are only accepted while the connection is relayed
+func (conn *Conn) OnRemoteUpgradeOffer(offer OfferAnswer) bool {
+	conn.mu.Lock()
+	relayed := conn.status == StatusConnected && conn.relayed
+	ctx := conn.ctx
+	conn.mu.Unlock()
+
+	if !relayed {
+		log.Debugf("OnRemoteUpgradeOffer skipping message from peer %s because the connection is not relayed", conn.config.Key)
+		return false
+	}
+
+	if conn.config.ConnectionPolicy == ConnectionPolicyRelay {
+		log.Debugf("OnRemoteUpgradeOffer skipping message from peer %s because the connection policy is relay", conn.config.Key)
+		return false
+	}
+
+	go func() {
+		err := conn.acceptUpgrade(ctx, offer)
+		if err != nil {
+			log.Debugf("connection to peer %s stays relayed: %v", conn.config.Key, err)
+		}
+	}()
+
+	return true
+}
+
+// OnRemoteUpgradeAnswer handles an upgrade answer from the remote peer and returns true if the message was accepted.
+// doesn't block, discards the message if no upgrade is in progress
+func (conn *Conn) OnRemoteUpgradeAnswer(answer OfferAnswer) bool {
+	select {
+	case conn.remoteUpgradeAnswerCh <- answer:
+		return true
+	default:
+		log.Debugf("OnRemoteUpgradeAnswer skipping message from peer %s because no upgrade is in progress", conn.config.Key)
+		return false
+	}
+}
+
+// OnRemoteUpgradeCandidate handles an ICE candidate provided by the remote peer for an upgrade attempt
+func (conn *Conn) OnRemoteUpgradeCandidate(candidate ice.Candidate) {
+	log.Debugf("OnRemoteUpgradeCandidate from peer %s -> %s", conn.config.Key, candidate.String())
+	go func() {
+		conn.mu.Lock()
+		defer conn.mu.Unlock()
+
+		if conn.upgradeAgent == nil {
+			return
+		}
+
+		err := conn.upgradeAgent.AddRemoteCandidate(candidate)
+		if err != nil {
+			log.Errorf("error while handling remote upgrade candidate from peer %s", conn.config.Key)
+			return
+		}
+	}()
+}
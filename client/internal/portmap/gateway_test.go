@@ -0,0 +1,281 @@
+package portmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var fakeExternalIP = netip.MustParseAddr("203.0.113.7")
+
+// fakeMapping is a mapping created on the fakeGateway
+type fakeMapping struct {
+	protocol     string
+	internalPort uint16
+	lifetime     uint32
+}
+
+// fakeGateway is an in-process gateway implementing the server side of PCP, NAT-PMP and UPnP IGD.
+// PCP and NAT-PMP share the same UDP socket as on real gateways
+type fakeGateway struct {
+	pcp                bool
+	natpmp             bool
+	upnp               bool
+	onlyPermanentLease bool
+
+	pmpConn  *net.UDPConn
+	ssdpConn *net.UDPConn
+	http     *httptest.Server
+
+	mux      sync.Mutex
+	mappings map[uint16]fakeMapping
+}
+
+func newFakeGateway(t *testing.T, pcp, natpmp, upnp bool) *fakeGateway {
+	t.Helper()
+
+	g := &fakeGateway{
+		pcp:      pcp,
+		natpmp:   natpmp,
+		upnp:     upnp,
+		mappings: make(map[uint16]fakeMapping),
+	}
+
+	var err error
+	g.pmpConn, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.ssdpConn, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.http = httptest.NewServer(http.HandlerFunc(g.serveHTTP))
+
+	go g.servePMP()
+	go g.serveSSDP()
+
+	t.Cleanup(func() {
+		_ = g.pmpConn.Close()
+		_ = g.ssdpConn.Close()
+		g.http.Close()
+	})
+
+	return g
+}
+
+func (g *fakeGateway) pmpAddr() netip.AddrPort {
+	return g.pmpConn.LocalAddr().(*net.UDPAddr).AddrPort()
+}
+
+func (g *fakeGateway) ssdpAddr() netip.AddrPort {
+	return g.ssdpConn.LocalAddr().(*net.UDPAddr).AddrPort()
+}
+
+func (g *fakeGateway) getMappings() map[uint16]fakeMapping {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	mappings := make(map[uint16]fakeMapping)
+	for port, mapping := range g.mappings {
+		mappings[port] = mapping
+	}
+	return mappings
+}
+
+// allocate creates or deletes a mapping and returns the external port
+func (g *fakeGateway) allocate(protocol string, internalPort, externalPort uint16, lifetime uint32) uint16 {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	if externalPort == 0 {
+		for port, mapping := range g.mappings {
+			if mapping.internalPort == internalPort {
+				externalPort = port
+			}
+		}
+	}
+	if externalPort == 0 {
+		externalPort = internalPort + 1000
+	}
+
+	if lifetime == 0 {
+		delete(g.mappings, externalPort)
+		return externalPort
+	}
+
+	g.mappings[externalPort] = fakeMapping{protocol: protocol, internalPort: internalPort, lifetime: lifetime}
+	return externalPort
+}
+
+func (g *fakeGateway) servePMP() {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := g.pmpConn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		request := buf[:n]
+
+		var response []byte
+		switch {
+		case request[0] == pcpVersion && g.pcp:
+			response = g.handlePCP(request)
+		case request[0] == natpmpVersion && g.natpmp:
+			response = g.handleNATPMP(request)
+		case g.natpmp:
+			// NAT-PMP only gateways answer unknown versions with an unsupported version result
+			response = []byte{natpmpVersion, natpmpOpResponse + request[1], 0, 1, 0, 0, 0, 0}
+		default:
+			continue
+		}
+		_, _ = g.pmpConn.WriteToUDP(response, addr)
+	}
+}
+
+func (g *fakeGateway) handleNATPMP(request []byte) []byte {
+	if request[1] == natpmpOpExternalAddress {
+		response := make([]byte, 12)
+		response[1] = natpmpOpResponse + natpmpOpExternalAddress
+		externalIP := fakeExternalIP.As4()
+		copy(response[8:12], externalIP[:])
+		return response
+	}
+
+	internalPort := binary.BigEndian.Uint16(request[4:6])
+	lifetime := binary.BigEndian.Uint32(request[8:12])
+	externalPort := g.allocate("NAT-PMP", internalPort, binary.BigEndian.Uint16(request[6:8]), lifetime)
+
+	response := make([]byte, 16)
+	response[1] = natpmpOpResponse + natpmpOpMapUDP
+	binary.BigEndian.PutUint16(response[8:10], internalPort)
+	binary.BigEndian.PutUint16(response[10:12], externalPort)
+	binary.BigEndian.PutUint32(response[12:16], lifetime)
+	return response
+}
+
+func (g *fakeGateway) handlePCP(request []byte) []byte {
+	internalPort := binary.BigEndian.Uint16(request[40:42])
+	lifetime := binary.BigEndian.Uint32(request[4:8])
+	externalPort := g.allocate("PCP", internalPort, binary.BigEndian.Uint16(request[42:44]), lifetime)
+
+	response := make([]byte, pcpResponseLength)
+	response[0] = pcpVersion
+	response[1] = pcpOpResponse | pcpOpMap
+	binary.BigEndian.PutUint32(response[4:8], lifetime)
+	copy(response[24:40], request[24:40])
+	binary.BigEndian.PutUint16(response[40:42], internalPort)
+	binary.BigEndian.PutUint16(response[42:44], externalPort)
+	externalIP := fakeExternalIP.As16()
+	copy(response[44:60], externalIP[:])
+	return response
+}
+
+func (g *fakeGateway) serveSSDP() {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := g.ssdpConn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if !g.upnp || !strings.HasPrefix(string(buf[:n]), "M-SEARCH") {
+			continue
+		}
+
+		response := "HTTP/1.1 200 OK\r\n" +
+			"CACHE-CONTROL: max-age=120\r\n" +
+			"ST: urn:schemas-upnp-org:device:InternetGatewayDevice:1\r\n" +
+			"LOCATION: " + g.http.URL + "/rootDesc.xml\r\n\r\n"
+		_, _ = g.ssdpConn.WriteToUDP([]byte(response), addr)
+	}
+}
+
+const fakeDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+func (g *fakeGateway) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/rootDesc.xml":
+		_, _ = io.WriteString(w, fakeDescription)
+		return
+	case "/ctl/IPConn":
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	values, err := parseSOAPValues(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	action := r.Header.Get("SOAPAction")
+	action = strings.Trim(action[strings.Index(action, "#")+1:], `"`)
+
+	var result string
+	switch action {
+	case "AddPortMapping":
+		if g.onlyPermanentLease && values["NewLeaseDuration"] != "0" {
+			writeSOAPFault(w, upnpErrorOnlyPermanentLeases, "OnlyPermanentLeasesSupported")
+			return
+		}
+		var internalPort, externalPort, lifetime uint32
+		_, _ = fmt.Sscan(values["NewInternalPort"], &internalPort)
+		_, _ = fmt.Sscan(values["NewExternalPort"], &externalPort)
+		_, _ = fmt.Sscan(values["NewLeaseDuration"], &lifetime)
+		if lifetime == 0 {
+			// permanent mapping
+			lifetime = 1
+		}
+		g.allocate("UPnP", uint16(internalPort), uint16(externalPort), lifetime)
+	case "DeletePortMapping":
+		var externalPort uint32
+		_, _ = fmt.Sscan(values["NewExternalPort"], &externalPort)
+		g.allocate("UPnP", 0, uint16(externalPort), 0)
+	case "GetExternalIPAddress":
+		result = "<NewExternalIPAddress>" + fakeExternalIP.String() + "</NewExternalIPAddress>"
+	default:
+		writeSOAPFault(w, 401, "Invalid Action")
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+		`<u:%sResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">%s</u:%sResponse></s:Body></s:Envelope>`,
+		action, result, action)
+}
+
+func writeSOAPFault(w http.ResponseWriter, code int, description string) {
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>`+
+		`<faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail>`+
+		`<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>%d</errorCode><errorDescription>%s</errorDescription></UPnPError>`+
+		`</detail></s:Fault></s:Body></s:Envelope>`, code, description)
+}
@@ -0,0 +1,263 @@
+package portmap
+
+import (
+	"context"
+	"fmt"
+	"github.com/libp2p/go-netroute"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// mappingLifetime is the lifetime requested for a port mapping. Mappings are renewed at half of their granted lifetime
+	mappingLifetime = 2 * time.Hour
+	// mappingRetryInterval is the interval between mapping attempts when the gateway doesn't support any protocol
+	mappingRetryInterval = 5 * time.Minute
+	// unmapTimeout is the time given to delete a mapping when the Manager stops
+	unmapTimeout = 2 * time.Second
+)
+
+// Mapping is a UDP port mapping created on the gateway
+type Mapping struct {
+	// Protocol is the name of the protocol used to create the mapping
+	Protocol     string
+	InternalPort uint16
+	External     netip.AddrPort
+	// Lifetime granted by the gateway, zero if the mapping doesn't expire
+	Lifetime time.Duration
+}
+
+// client creates and deletes port mappings with a port mapping protocol
+type client interface {
+	Name() string
+	// Map requests a mapping of the internal UDP port, asking for the suggested external port if not zero.
+	// Mapping an already mapped port renews the mapping
+	Map(ctx context.Context, internalPort, externalPort uint16, lifetime time.Duration) (Mapping, error)
+	// Unmap deletes a mapping
+	Unmap(ctx context.Context, mapping Mapping) error
+}
+
+// Manager keeps a mapping of a local UDP port on the gateway using PCP, NAT-PMP or UPnP IGD,
+// whichever the gateway supports first
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	internalPort uint16
+	clients      []client
+
+	mux     sync.Mutex
+	mapping *Mapping
+	current client
+}
+
+// NewManager creates a Manager mapping the internalPort on the gateway.
+// localIP is the address of the local interface facing the gateway
+func NewManager(gateway, localIP netip.Addr, internalPort int) *Manager {
+	return newManager(uint16(internalPort),
+		newPCPClient(netip.AddrPortFrom(gateway, pcpPort), localIP),
+		newNATPMPClient(netip.AddrPortFrom(gateway, natpmpPort)),
+		newUPnPClient(netip.AddrPortFrom(ssdpMulticastAddr, ssdpPort), localIP),
+	)
+}
+
+func newManager(internalPort uint16, clients ...client) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+		internalPort: internalPort,
+		clients:      clients,
+	}
+}
+
+// DefaultGateway returns the IPv4 default gateway and the local address used to reach it
+func DefaultGateway() (gateway netip.Addr, localIP netip.Addr, err error) {
+	r, err := netroute.New()
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, err
+	}
+
+	_, gw, preferredSrc, err := r.Route(net.IPv4zero)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, err
+	}
+
+	gateway, ok := netip.AddrFromSlice(gw.To4())
+	if !ok {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("no IPv4 default gateway")
+	}
+
+	localIP, ok = netip.AddrFromSlice(preferredSrc.To4())
+	if !ok {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("no local IPv4 address to reach gateway %s", gateway)
+	}
+
+	return gateway, localIP, nil
+}
+
+// Start creates the mapping and keeps renewing it in the background until Stop is called
+func (m *Manager) Start() {
+	go m.run()
+}
+
+// Stop stops renewing the mapping and deletes it from the gateway
+func (m *Manager) Stop() {
+	m.cancel()
+	<-m.done
+}
+
+// ExternalAddress returns the external address of the mapping and true if the port is currently mapped
+func (m *Manager) ExternalAddress() (netip.AddrPort, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.mapping == nil {
+		return netip.AddrPort{}, false
+	}
+	return m.mapping.External, true
+}
+
+func (m *Manager) run() {
+	defer close(m.done)
+
+	for {
+		wait := mappingRetryInterval
+		mapping, err := m.refresh()
+		if err != nil {
+			log.Debugf("unable to map UDP port %d on the gateway: %v", m.internalPort, err)
+		} else {
+			wait = mappingLifetime / 2
+			if mapping.Lifetime > 0 {
+				wait = mapping.Lifetime / 2
+			}
+		}
+
+		select {
+		case <-m.ctx.Done():
+			m.unmap()
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// refresh renews the current mapping or creates a new one trying every protocol in order
+func (m *Manager) refresh() (Mapping, error) {
+	m.mux.Lock()
+	current := m.current
+	var externalPort uint16
+	if m.mapping != nil {
+		externalPort = m.mapping.External.Port()
+	}
+	m.mux.Unlock()
+
+	clients := m.clients
+	if current != nil {
+		clients = append([]client{current}, m.clients...)
+	}
+
+	var errs []string
+	for _, c := range clients {
+		mapping, err := c.Map(m.ctx, m.internalPort, externalPort, mappingLifetime)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", c.Name(), err))
+			continue
+		}
+
+		m.mux.Lock()
+		previous := m.mapping
+		m.mapping = &mapping
+		m.current = c
+		m.mux.Unlock()
+
+		if previous == nil || previous.External != mapping.External {
+			log.Infof("mapped UDP port %d to %s using %s", m.internalPort, mapping.External, c.Name())
+		}
+		return mapping, nil
+	}
+
+	m.mux.Lock()
+	m.mapping = nil
+	m.current = nil
+	m.mux.Unlock()
+
+	return Mapping{}, fmt.Errorf("no port mapping protocol succeeded: %s", strings.Join(errs, ", "))
+}
+
+// unmap deletes the current mapping from the gateway
+func (m *Manager) unmap() {
+	m.mux.Lock()
+	mapping := m.mapping
+	current := m.current
+	m.mapping = nil
+	m.current = nil
+	m.mux.Unlock()
+
+	if mapping == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unmapTimeout)
+	defer cancel()
+
+	err := current.Unmap(ctx, *mapping)
+	if err != nil {
+		log.Debugf("unable to delete %s port mapping %s: %v", current.Name(), mapping.External, err)
+		return
+	}
+	log.Debugf("deleted %s port mapping %s", current.Name(), mapping.External)
+}
+
+// udpRoundTrip sends a request to the server and returns the first response accepted by the valid function.
+// The request is retransmitted with an increasing timeout
+func udpRoundTrip(ctx context.Context, server netip.AddrPort, request []byte, valid func([]byte) bool) ([]byte, error) {
+	conn, err := net.DialUDP("udp4", nil, net.UDPAddrFromAddrPort(server))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 1500)
+	timeout := 250 * time.Millisecond
+	for attempt := 0; attempt < 3; attempt++ {
+		_, err = conn.Write(request)
+		if err != nil {
+			return nil, err
+		}
+
+		deadline := time.Now().Add(timeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		err = conn.SetReadDeadline(deadline)
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				break
+			}
+			if valid(buf[:n]) {
+				return buf[:n], nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		timeout *= 2
+	}
+
+	return nil, fmt.Errorf("no response from %s", server)
+}
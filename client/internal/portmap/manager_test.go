@@ -0,0 +1,133 @@
+package portmap
+
+import (
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+const testInternalPort = 51820
+
+var testLocalIP = netip.MustParseAddr("127.0.0.1")
+
+// newTestManager creates a Manager talking to the fake gateway
+func newTestManager(g *fakeGateway) *Manager {
+	return newManager(testInternalPort,
+		newPCPClient(g.pmpAddr(), testLocalIP),
+		newNATPMPClient(g.pmpAddr()),
+		newUPnPClient(g.ssdpAddr(), testLocalIP),
+	)
+}
+
+func TestManager_Protocols(t *testing.T) {
+	testCases := []struct {
+		name             string
+		pcp              bool
+		natpmp           bool
+		upnp             bool
+		expectedProtocol string
+		expectedPort     uint16
+	}{
+		{
+			name:             "PCP Preferred",
+			pcp:              true,
+			natpmp:           true,
+			upnp:             true,
+			expectedProtocol: "PCP",
+			expectedPort:     testInternalPort + 1000,
+		},
+		{
+			name:             "NAT-PMP Only",
+			natpmp:           true,
+			expectedProtocol: "NAT-PMP",
+			expectedPort:     testInternalPort + 1000,
+		},
+		{
+			name:             "UPnP Only",
+			upnp:             true,
+			expectedProtocol: "UPnP",
+			expectedPort:     testInternalPort,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := newFakeGateway(t, testCase.pcp, testCase.natpmp, testCase.upnp)
+			m := newTestManager(g)
+
+			mapping, err := m.refresh()
+			require.NoError(t, err, "mapping should succeed")
+			require.Equal(t, testCase.expectedProtocol, mapping.Protocol)
+
+			expectedExternal := netip.AddrPortFrom(fakeExternalIP, testCase.expectedPort)
+			external, ok := m.ExternalAddress()
+			require.True(t, ok, "port should be mapped")
+			require.Equal(t, expectedExternal, external)
+
+			mappings := g.getMappings()
+			require.Len(t, mappings, 1, "gateway should have one mapping")
+			require.Equal(t, testCase.expectedProtocol, mappings[testCase.expectedPort].protocol)
+			require.Equal(t, uint16(testInternalPort), mappings[testCase.expectedPort].internalPort)
+
+			// renewing keeps the same external address
+			mapping, err = m.refresh()
+			require.NoError(t, err, "renewal should succeed")
+			require.Equal(t, expectedExternal, mapping.External)
+			require.Len(t, g.getMappings(), 1, "renewal shouldn't create another mapping")
+
+			m.unmap()
+			_, ok = m.ExternalAddress()
+			require.False(t, ok, "port shouldn't be mapped after unmap")
+			require.Len(t, g.getMappings(), 0, "mapping should be deleted from the gateway")
+		})
+	}
+}
+
+func TestManager_UPnPOnlyPermanentLeases(t *testing.T) {
+	g := newFakeGateway(t, false, false, true)
+	g.onlyPermanentLease = true
+	m := newTestManager(g)
+
+	mapping, err := m.refresh()
+	require.NoError(t, err, "mapping should fall back to a permanent lease")
+	require.Equal(t, "UPnP", mapping.Protocol)
+	require.Zero(t, mapping.Lifetime, "permanent mapping shouldn't have a lifetime")
+}
+
+func TestManager_NoGateway(t *testing.T) {
+	g := newFakeGateway(t, false, false, false)
+	m := newTestManager(g)
+	m.clients[2].(*upnpClient).ssdpAddr = closedUDPAddr(t)
+
+	_, err := m.refresh()
+	require.Error(t, err, "mapping should fail without a supporting gateway")
+
+	_, ok := m.ExternalAddress()
+	require.False(t, ok, "port shouldn't be mapped")
+}
+
+func TestManager_StartStop(t *testing.T) {
+	g := newFakeGateway(t, true, false, false)
+	m := newTestManager(g)
+
+	m.Start()
+	require.Eventually(t, func() bool {
+		_, ok := m.ExternalAddress()
+		return ok
+	}, time.Second, 10*time.Millisecond, "port should be mapped after start")
+
+	m.Stop()
+	require.Len(t, g.getMappings(), 0, "mapping should be deleted on stop")
+}
+
+// closedUDPAddr returns a local address nothing listens on
+func closedUDPAddr(t *testing.T) netip.AddrPort {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	addr := conn.LocalAddr().(*net.UDPAddr).AddrPort()
+	_ = conn.Close()
+	return addr
+}
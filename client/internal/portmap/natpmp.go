@@ -0,0 +1,110 @@
+package portmap
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+// NAT-PMP (RFC 6886) constants
+const (
+	natpmpPort              = 5351
+	natpmpVersion           = 0
+	natpmpOpExternalAddress = 0
+	natpmpOpMapUDP          = 1
+	natpmpOpResponse        = 128
+	natpmpResultSuccess     = 0
+)
+
+// natpmpClient maps ports with NAT-PMP
+type natpmpClient struct {
+	gateway netip.AddrPort
+}
+
+func newNATPMPClient(gateway netip.AddrPort) *natpmpClient {
+	return &natpmpClient{gateway: gateway}
+}
+
+func (c *natpmpClient) Name() string {
+	return "NAT-PMP"
+}
+
+func (c *natpmpClient) Map(ctx context.Context, internalPort, externalPort uint16, lifetime time.Duration) (Mapping, error) {
+	externalIP, err := c.externalAddress(ctx)
+	if err != nil {
+		return Mapping{}, err
+	}
+
+	mappedPort, grantedLifetime, err := c.mapUDP(ctx, internalPort, externalPort, uint32(lifetime.Seconds()))
+	if err != nil {
+		return Mapping{}, err
+	}
+
+	return Mapping{
+		Protocol:     c.Name(),
+		InternalPort: internalPort,
+		External:     netip.AddrPortFrom(externalIP, mappedPort),
+		Lifetime:     time.Duration(grantedLifetime) * time.Second,
+	}, nil
+}
+
+func (c *natpmpClient) Unmap(ctx context.Context, mapping Mapping) error {
+	// a mapping is deleted by requesting it with zero lifetime and external port
+	_, _, err := c.mapUDP(ctx, mapping.InternalPort, 0, 0)
+	return err
+}
+
+func (c *natpmpClient) externalAddress(ctx context.Context) (netip.Addr, error) {
+	request := []byte{natpmpVersion, natpmpOpExternalAddress}
+	response, err := udpRoundTrip(ctx, c.gateway, request, func(response []byte) bool {
+		return len(response) >= 12 && response[1] == natpmpOpResponse+natpmpOpExternalAddress
+	})
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	err = natpmpResult(response)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	return netip.AddrFrom4([4]byte{response[8], response[9], response[10], response[11]}), nil
+}
+
+func (c *natpmpClient) mapUDP(ctx context.Context, internalPort, externalPort uint16, lifetime uint32) (uint16, uint32, error) {
+	request := make([]byte, 12)
+	request[0] = natpmpVersion
+	request[1] = natpmpOpMapUDP
+	binary.BigEndian.PutUint16(request[4:6], internalPort)
+	binary.BigEndian.PutUint16(request[6:8], externalPort)
+	binary.BigEndian.PutUint32(request[8:12], lifetime)
+
+	response, err := udpRoundTrip(ctx, c.gateway, request, func(response []byte) bool {
+		return len(response) >= 16 && response[1] == natpmpOpResponse+natpmpOpMapUDP &&
+			binary.BigEndian.Uint16(response[8:10]) == internalPort
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = natpmpResult(response)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return binary.BigEndian.Uint16(response[10:12]), binary.BigEndian.Uint32(response[12:16]), nil
+}
+
+// natpmpResult checks the version and the result code of a NAT-PMP response
+func natpmpResult(response []byte) error {
+	if response[0] != natpmpVersion {
+		return fmt.Errorf("unsupported NAT-PMP version %d", response[0])
+	}
+	result := binary.BigEndian.Uint16(response[2:4])
+	if result != natpmpResultSuccess {
+		return fmt.Errorf("NAT-PMP request failed with result code %d", result)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package portmap
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+// PCP (RFC 6887) constants
+const (
+	pcpPort           = 5351
+	pcpVersion        = 2
+	pcpOpMap          = 1
+	pcpOpResponse     = 0x80
+	pcpResultSuccess  = 0
+	pcpProtocolUDP    = 17
+	pcpHeaderLength   = 24
+	pcpMapLength      = 36
+	pcpResponseLength = pcpHeaderLength + pcpMapLength
+)
+
+// pcpClient maps ports with PCP
+type pcpClient struct {
+	gateway  netip.AddrPort
+	clientIP netip.Addr
+	// nonce identifies the mappings of this client, it must be the same to renew or delete a mapping
+	nonce [12]byte
+}
+
+func newPCPClient(gateway netip.AddrPort, clientIP netip.Addr) *pcpClient {
+	c := &pcpClient{gateway: gateway, clientIP: clientIP}
+	_, _ = rand.Read(c.nonce[:])
+	return c
+}
+
+func (c *pcpClient) Name() string {
+	return "PCP"
+}
+
+func (c *pcpClient) Map(ctx context.Context, internalPort, externalPort uint16, lifetime time.Duration) (Mapping, error) {
+	external, grantedLifetime, err := c.mapUDP(ctx, internalPort, externalPort, uint32(lifetime.Seconds()))
+	if err != nil {
+		return Mapping{}, err
+	}
+
+	return Mapping{
+		Protocol:     c.Name(),
+		InternalPort: internalPort,
+		External:     external,
+		Lifetime:     time.Duration(grantedLifetime) * time.Second,
+	}, nil
+}
+
+func (c *pcpClient) Unmap(ctx context.Context, mapping Mapping) error {
+	// a mapping is deleted by requesting it with zero lifetime
+	_, _, err := c.mapUDP(ctx, mapping.InternalPort, mapping.External.Port(), 0)
+	return err
+}
+
+func (c *pcpClient) mapUDP(ctx context.Context, internalPort, externalPort uint16, lifetime uint32) (netip.AddrPort, uint32, error) {
+	request := make([]byte, pcpResponseLength)
+	request[0] = pcpVersion
+	request[1] = pcpOpMap
+	binary.BigEndian.PutUint32(request[4:8], lifetime)
+	clientIP := c.clientIP.As16()
+	copy(request[8:24], clientIP[:])
+
+	copy(request[24:36], c.nonce[:])
+	request[36] = pcpProtocolUDP
+	binary.BigEndian.PutUint16(request[40:42], internalPort)
+	binary.BigEndian.PutUint16(request[42:44], externalPort)
+	// no suggested external address, 0.0.0.0 in IPv4-mapped IPv6 format
+	suggestedIP := netip.IPv4Unspecified().As16()
+	copy(request[44:60], suggestedIP[:])
+
+	response, err := udpRoundTrip(ctx, c.gateway, request, func(response []byte) bool {
+		// a NAT-PMP only gateway answers with its own version and an unsupported version result
+		if len(response) >= 4 && response[0] != pcpVersion {
+			return true
+		}
+		return len(response) >= pcpResponseLength && response[1] == pcpOpResponse|pcpOpMap &&
+			string(response[24:36]) == string(c.nonce[:])
+	})
+	if err != nil {
+		return netip.AddrPort{}, 0, err
+	}
+
+	if response[0] != pcpVersion {
+		return netip.AddrPort{}, 0, fmt.Errorf("unsupported PCP version %d", response[0])
+	}
+	if response[3] != pcpResultSuccess {
+		return netip.AddrPort{}, 0, fmt.Errorf("PCP request failed with result code %d", response[3])
+	}
+
+	grantedLifetime := binary.BigEndian.Uint32(response[4:8])
+	mappedPort := binary.BigEndian.Uint16(response[42:44])
+	var mappedIP [16]byte
+	copy(mappedIP[:], response[44:60])
+
+	return netip.AddrPortFrom(netip.AddrFrom16(mappedIP).Unmap(), mappedPort), grantedLifetime, nil
+}
@@ -0,0 +1,347 @@
+package portmap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ssdpPort = 1900
+	// upnpDescription is the description of the port mappings created with UPnP
+	upnpDescription = "NetBird"
+	// upnpErrorOnlyPermanentLeases is returned by gateways not supporting mappings with a lease duration
+	upnpErrorOnlyPermanentLeases = 725
+	// upnpDiscoveryTimeout is the time waiting for gateway responses to a discovery request
+	upnpDiscoveryTimeout = 2 * time.Second
+)
+
+// ssdpMulticastAddr is the SSDP multicast address used to discover gateways
+var ssdpMulticastAddr = netip.MustParseAddr("239.255.255.250")
+
+// upnpServiceTypes are the IGD services able to create port mappings, in order of preference
+var upnpServiceTypes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+// upnpClient maps ports with a UPnP Internet Gateway Device
+type upnpClient struct {
+	ssdpAddr   netip.AddrPort
+	localIP    netip.Addr
+	httpClient *http.Client
+
+	// controlURL and serviceType of the gateway service, empty until discovered
+	controlURL  string
+	serviceType string
+}
+
+func newUPnPClient(ssdpAddr netip.AddrPort, localIP netip.Addr) *upnpClient {
+	return &upnpClient{
+		ssdpAddr:   ssdpAddr,
+		localIP:    localIP,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (c *upnpClient) Name() string {
+	return "UPnP"
+}
+
+func (c *upnpClient) Map(ctx context.Context, internalPort, externalPort uint16, lifetime time.Duration) (Mapping, error) {
+	if c.controlURL == "" {
+		err := c.discover(ctx)
+		if err != nil {
+			return Mapping{}, err
+		}
+	}
+
+	if externalPort == 0 {
+		externalPort = internalPort
+	}
+
+	args := []soapArg{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(externalPort))},
+		{"NewProtocol", "UDP"},
+		{"NewInternalPort", strconv.Itoa(int(internalPort))},
+		{"NewInternalClient", c.localIP.String()},
+		{"NewEnabled", "1"},
+		{"NewPortMappingDescription", upnpDescription},
+		{"NewLeaseDuration", strconv.Itoa(int(lifetime.Seconds()))},
+	}
+	_, err := c.soapCall(ctx, "AddPortMapping", args)
+	if upnpErr, ok := err.(*upnpError); ok && upnpErr.code == upnpErrorOnlyPermanentLeases {
+		lifetime = 0
+		args[len(args)-1].value = "0"
+		_, err = c.soapCall(ctx, "AddPortMapping", args)
+	}
+	if err != nil {
+		// the gateway might have changed, discover it again on the next attempt
+		c.controlURL = ""
+		return Mapping{}, err
+	}
+
+	values, err := c.soapCall(ctx, "GetExternalIPAddress", nil)
+	if err != nil {
+		return Mapping{}, err
+	}
+	externalIP, err := netip.ParseAddr(values["NewExternalIPAddress"])
+	if err != nil {
+		return Mapping{}, fmt.Errorf("invalid external address: %v", err)
+	}
+
+	return Mapping{
+		Protocol:     c.Name(),
+		InternalPort: internalPort,
+		External:     netip.AddrPortFrom(externalIP, externalPort),
+		Lifetime:     lifetime,
+	}, nil
+}
+
+func (c *upnpClient) Unmap(ctx context.Context, mapping Mapping) error {
+	if c.controlURL == "" {
+		return fmt.Errorf("no gateway discovered")
+	}
+
+	_, err := c.soapCall(ctx, "DeletePortMapping", []soapArg{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(mapping.External.Port()))},
+		{"NewProtocol", "UDP"},
+	})
+	return err
+}
+
+// discover finds the gateway with SSDP and looks up the control URL of its port mapping service
+func (c *upnpClient) discover(ctx context.Context) error {
+	location, err := c.search(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching gateway description returned status %d", resp.StatusCode)
+	}
+
+	var root upnpRoot
+	err = xml.NewDecoder(resp.Body).Decode(&root)
+	if err != nil {
+		return fmt.Errorf("failed parsing gateway description: %v", err)
+	}
+
+	base := location
+	if root.URLBase != "" {
+		base, err = url.Parse(root.URLBase)
+		if err != nil {
+			return fmt.Errorf("invalid gateway URLBase: %v", err)
+		}
+	}
+
+	for _, serviceType := range upnpServiceTypes {
+		service, found := root.Device.findService(serviceType)
+		if !found {
+			continue
+		}
+		controlURL, err := base.Parse(service.ControlURL)
+		if err != nil {
+			return fmt.Errorf("invalid control URL of service %s: %v", serviceType, err)
+		}
+		c.controlURL = controlURL.String()
+		c.serviceType = serviceType
+		return nil
+	}
+
+	return fmt.Errorf("gateway doesn't provide a port mapping service")
+}
+
+// search sends an SSDP search request for Internet Gateway Devices and returns the description location of the first one
+func (c *upnpClient) search(ctx context.Context) (*url.URL, error) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	request := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: 239.255.255.250:1900\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 1\r\n" +
+		"ST: urn:schemas-upnp-org:device:InternetGatewayDevice:1\r\n\r\n"
+	_, err = conn.WriteToUDP([]byte(request), net.UDPAddrFromAddrPort(c.ssdpAddr))
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(upnpDiscoveryTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetReadDeadline(deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return nil, fmt.Errorf("no gateway answered the discovery: %v", err)
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		_ = resp.Body.Close()
+
+		location, err := url.Parse(resp.Header.Get("Location"))
+		if err != nil || location.Host == "" {
+			continue
+		}
+		return location, nil
+	}
+}
+
+// soapArg is an argument of a SOAP action
+type soapArg struct {
+	name  string
+	value string
+}
+
+// upnpError is a UPnP error returned by the gateway in a SOAP fault
+type upnpError struct {
+	code        int
+	description string
+}
+
+func (e *upnpError) Error() string {
+	return fmt.Sprintf("UPnP error %d: %s", e.code, e.description)
+}
+
+// soapCall invokes an action of the gateway service and returns the values of the response
+func (c *upnpClient) soapCall(ctx context.Context, action string, args []soapArg) (map[string]string, error) {
+	body := &bytes.Buffer{}
+	body.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">` +
+		`<s:Body>`)
+	fmt.Fprintf(body, `<u:%s xmlns:u="%s">`, action, c.serviceType)
+	for _, arg := range args {
+		fmt.Fprintf(body, "<%s>", arg.name)
+		_ = xml.EscapeText(body, []byte(arg.value))
+		fmt.Fprintf(body, "</%s>", arg.name)
+	}
+	fmt.Fprintf(body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.controlURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, c.serviceType, action))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	values, err := parseSOAPValues(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing %s response: %v", action, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		code, err := strconv.Atoi(values["errorCode"])
+		if err != nil {
+			return nil, fmt.Errorf("%s returned status %d", action, resp.StatusCode)
+		}
+		return nil, &upnpError{code: code, description: values["errorDescription"]}
+	}
+
+	return values, nil
+}
+
+// parseSOAPValues collects the text of the leaf elements of a SOAP message by their local name
+func parseSOAPValues(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	decoder := xml.NewDecoder(r)
+
+	var current string
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			current = t.Name.Local
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if t.Name.Local == current {
+				values[current] = strings.TrimSpace(text.String())
+			}
+			current = ""
+		}
+	}
+}
+
+// upnpRoot is the root of a UPnP device description
+type upnpRoot struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+type upnpDevice struct {
+	DeviceType string        `xml:"deviceType"`
+	Services   []upnpService `xml:"serviceList>service"`
+	Devices    []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// findService looks for a service of the device or its embedded devices
+func (d upnpDevice) findService(serviceType string) (upnpService, bool) {
+	for _, service := range d.Services {
+		if service.ServiceType == serviceType {
+			return service, true
+		}
+	}
+	for _, device := range d.Devices {
+		service, found := device.findService(serviceType)
+		if found {
+			return service, true
+		}
+	}
+	return upnpService{}, false
+}
@@ -0,0 +1,265 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/iface"
+	"github.com/netbirdio/netbird/util"
+)
+
+// DefaultProfileName is the name of the profile stored in the default config file
+const DefaultProfileName = "default"
+
+const (
+	// profilesDir is the directory next to the default config file the other profiles are stored in
+	profilesDir = "profiles"
+	// activeProfileFile is the file next to the default config file storing the name of the active profile
+	activeProfileFile = "active_profile.json"
+	// maxProfileInterfaces is the number of interface names and ports after the defaults tried for a new profile
+	maxProfileInterfaces = 1000
+)
+
+var profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,63}$`)
+
+// Profile is a named client configuration, each profile has its own keys, Management service and interface
+type Profile struct {
+	Name          string
+	ConfigPath    string
+	ManagementURL string
+	Active        bool
+}
+
+type activeProfile struct {
+	Name string
+}
+
+// ProfileManager stores profiles side by side with the default config file.
+// The default profile uses the default config file, other profiles are stored in the profiles directory next to it
+type ProfileManager struct {
+	defaultConfigPath string
+}
+
+// NewProfileManager returns a ProfileManager for the profiles stored next to the default config file
+func NewProfileManager(defaultConfigPath string) *ProfileManager {
+	return &ProfileManager{defaultConfigPath: defaultConfigPath}
+}
+
+// ProfileConfigPath returns the config file of a profile, it doesn't check whether the profile exists
+func (m *ProfileManager) ProfileConfigPath(name string) (string, error) {
+	if name == DefaultProfileName {
+		return m.defaultConfigPath, nil
+	}
+	if !profileNameRegex.MatchString(name) {
+		return "", status.Errorf(codes.InvalidArgument,
+			"invalid profile name %q, use up to 64 letters, digits, dashes and underscores", name)
+	}
+	return filepath.Join(filepath.Dir(m.defaultConfigPath), profilesDir, name+".json"), nil
+}
+
+// ActiveProfile returns the name of the active profile, the default profile is active unless another one was selected
+func (m *ProfileManager) ActiveProfile() (string, error) {
+	path := m.activeProfilePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return DefaultProfileName, nil
+	}
+
+	active := &activeProfile{}
+	if _, err := util.ReadJson(path, active); err != nil {
+		return "", fmt.Errorf("read active profile: %v", err)
+	}
+
+	if active.Name == "" {
+		return DefaultProfileName, nil
+	}
+	return active.Name, nil
+}
+
+// ActiveProfileConfigPath returns the config file of the active profile.
+// It falls back to the default profile if the active profile has been removed from disk
+func (m *ProfileManager) ActiveProfileConfigPath() (string, error) {
+	name, err := m.ActiveProfile()
+	if err != nil {
+		return "", err
+	}
+
+	path, err := m.ProfileConfigPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	if name != DefaultProfileName {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return m.defaultConfigPath, nil
+		}
+	}
+	return path, nil
+}
+
+// SelectProfile makes an existing profile the active one
+func (m *ProfileManager) SelectProfile(name string) error {
+	path, err := m.ProfileConfigPath(name)
+	if err != nil {
+		return err
+	}
+
+	if name != DefaultProfileName {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return status.Errorf(codes.NotFound, "profile %s doesn't exist", name)
+		}
+	}
+
+	return util.WriteJson(m.activeProfilePath(), &activeProfile{Name: name})
+}
+
+// AddProfile creates a new profile with a new WireGuard key for a Management service.
+// The default Management URL is used when managementURL is empty
+func (m *ProfileManager) AddProfile(name, managementURL string) (*Profile, error) {
+	if name == DefaultProfileName {
+		return nil, status.Errorf(codes.AlreadyExists, "profile %s already exists", name)
+	}
+
+	path, err := m.ProfileConfigPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "profile %s already exists", name)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("create profiles directory: %v", err)
+	}
+
+	config, err := newConfig(managementURL, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	config.WgIface, config.WgPort, err = m.unusedInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	err = util.WriteJson(path, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{Name: name, ConfigPath: path, ManagementURL: config.ManagementURL.String()}, nil
+}
+
+// RemoveProfile deletes a profile, its keys and its network snapshot. The default and the active profiles can't be removed
+func (m *ProfileManager) RemoveProfile(name string) error {
+	if name == DefaultProfileName {
+		return status.Errorf(codes.InvalidArgument, "the default profile can't be removed")
+	}
+
+	path, err := m.ProfileConfigPath(name)
+	if err != nil {
+		return err
+	}
+
+	active, err := m.ActiveProfile()
+	if err != nil {
+		return err
+	}
+	if active == name {
+		return status.Errorf(codes.FailedPrecondition, "profile %s is active, select another profile first", name)
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return status.Errorf(codes.NotFound, "profile %s doesn't exist", name)
+	} else if err != nil {
+		return err
+	}
+
+	return removeNetworkSnapshot(path + networkSnapshotSuffix)
+}
+
+// ListProfiles returns the profiles sorted by name, the default profile comes first
+func (m *ProfileManager) ListProfiles() ([]Profile, error) {
+	active, err := m.ActiveProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(m.defaultConfigPath), profilesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read profiles directory: %v", err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() || !profileNameRegex.MatchString(name) || name == DefaultProfileName {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	names = append([]string{DefaultProfileName}, names...)
+
+	profiles := make([]Profile, 0, len(names))
+	for _, name := range names {
+		path, err := m.ProfileConfigPath(name)
+		if err != nil {
+			return nil, err
+		}
+
+		profile := Profile{Name: name, ConfigPath: path, Active: name == active}
+		config := &Config{}
+		if _, err := util.ReadJson(path, config); err == nil && config.ManagementURL != nil {
+			profile.ManagementURL = config.ManagementURL.String()
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// unusedInterface returns the first interface name and WireGuard port after the defaults that no profile uses,
+// so the interfaces of the profiles don't collide
+func (m *ProfileManager) unusedInterface() (string, int, error) {
+	profiles, err := m.ListProfiles()
+	if err != nil {
+		return "", 0, err
+	}
+
+	usedNames := map[string]struct{}{iface.WgInterfaceDefault: {}}
+	usedPorts := map[int]struct{}{iface.DefaultWgPort: {}}
+	for _, profile := range profiles {
+		config := &Config{}
+		if _, err := util.ReadJson(profile.ConfigPath, config); err != nil {
+			continue
+		}
+		usedNames[config.WgIface] = struct{}{}
+		usedPorts[config.WgPort] = struct{}{}
+	}
+
+	prefix := strings.TrimRight(iface.WgInterfaceDefault, "0123456789")
+	number, _ := strconv.Atoi(strings.TrimPrefix(iface.WgInterfaceDefault, prefix))
+	for i := 1; i <= maxProfileInterfaces; i++ {
+		name := prefix + strconv.Itoa(number+i)
+		port := iface.DefaultWgPort + i
+		_, nameUsed := usedNames[name]
+		_, portUsed := usedPorts[port]
+		if !nameUsed && !portUsed && len(name) <= maxInterfaceNameLength {
+			return name, port, nil
+		}
+	}
+	return "", 0, status.Errorf(codes.ResourceExhausted, "no unused interface name and port left for a new profile")
+}
+
+func (m *ProfileManager) activeProfilePath() string {
+	return filepath.Join(filepath.Dir(m.defaultConfigPath), activeProfileFile)
+}
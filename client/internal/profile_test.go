@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/util"
+)
+
+func TestProfileManager(t *testing.T) {
+	defaultConfigPath := filepath.Join(t.TempDir(), "config.json")
+	manager := NewProfileManager(defaultConfigPath)
+
+	active, err := manager.ActiveProfile()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfileName, active)
+
+	profile, err := manager.AddProfile("customer", "https://netbird.customer.example:443")
+	require.NoError(t, err)
+	assert.Equal(t, "https://netbird.customer.example:443", profile.ManagementURL)
+	assert.NotEqual(t, defaultConfigPath, profile.ConfigPath)
+
+	_, err = manager.AddProfile("customer", "")
+	assert.Error(t, err, "profile names are unique")
+	_, err = manager.AddProfile(DefaultProfileName, "")
+	assert.Error(t, err, "the default profile always exists")
+	_, err = manager.AddProfile("../escape", "")
+	assert.Error(t, err, "invalid names are rejected")
+
+	assert.Error(t, manager.SelectProfile("unknown"))
+	require.NoError(t, manager.SelectProfile("customer"))
+
+	path, err := manager.ActiveProfileConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, profile.ConfigPath, path)
+
+	profiles, err := manager.ListProfiles()
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, DefaultProfileName, profiles[0].Name)
+	assert.False(t, profiles[0].Active)
+	assert.Equal(t, "customer", profiles[1].Name)
+	assert.True(t, profiles[1].Active)
+
+	assert.Error(t, manager.RemoveProfile("customer"), "the active profile can't be removed")
+	assert.Error(t, manager.RemoveProfile(DefaultProfileName), "the default profile can't be removed")
+
+	require.NoError(t, manager.SelectProfile(DefaultProfileName))
+	require.NoError(t, manager.RemoveProfile("customer"))
+	assert.Error(t, manager.RemoveProfile("customer"))
+
+	profiles, err = manager.ListProfiles()
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.True(t, profiles[0].Active)
+}
+
+func TestProfileManager_UniqueInterfaces(t *testing.T) {
+	defaultConfigPath := filepath.Join(t.TempDir(), "config.json")
+	manager := NewProfileManager(defaultConfigPath)
+
+	defaultConfig, err := createNewConfig("", "", defaultConfigPath, "")
+	require.NoError(t, err)
+
+	first, err := manager.AddProfile("first", "")
+	require.NoError(t, err)
+	second, err := manager.AddProfile("second", "")
+	require.NoError(t, err)
+
+	configs := []*Config{defaultConfig}
+	for _, profile := range []*Profile{first, second} {
+		config := &Config{}
+		_, err = util.ReadJson(profile.ConfigPath, config)
+		require.NoError(t, err)
+		configs = append(configs, config)
+	}
+
+	names := map[string]struct{}{}
+	ports := map[int]struct{}{}
+	for _, config := range configs {
+		names[config.WgIface] = struct{}{}
+		ports[config.WgPort] = struct{}{}
+	}
+	assert.Len(t, names, 3, "every profile has its own interface")
+	assert.Len(t, ports, 3, "every profile has its own port")
+
+	require.NoError(t, manager.RemoveProfile("first"))
+	third, err := manager.AddProfile("third", "")
+	require.NoError(t, err)
+	config := &Config{}
+	_, err = util.ReadJson(third.ConfigPath, config)
+	require.NoError(t, err)
+	assert.Equal(t, configs[1].WgIface, config.WgIface, "the interface of a removed profile is reused")
+	assert.Equal(t, configs[1].WgPort, config.WgPort)
+}
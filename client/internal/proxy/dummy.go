@@ -22,6 +22,10 @@ func NewDummyProxy(remote string) *DummyProxy {
 }
 
 func (p *DummyProxy) Close() error {
+	return p.Stop()
+}
+
+func (p *DummyProxy) Stop() error {
 	p.cancel()
 	return nil
 }
@@ -31,6 +31,11 @@ func (p *NoProxy) Close() error {
 	return nil
 }
 
+// Stop does nothing as there are no resources allocated by NoProxy
+func (p *NoProxy) Stop() error {
+	return nil
+}
+
 // Start just updates Wireguard peer with the remote IP and default Wireguard port
 func (p *NoProxy) Start(remoteConn net.Conn) error {
 
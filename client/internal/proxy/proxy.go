@@ -30,5 +30,8 @@ type Proxy interface {
 	io.Closer
 	// Start creates a local remoteConn and starts proxying data from/to remoteConn
 	Start(remoteConn net.Conn) error
+	// Stop stops proxying data without removing the remote peer from Wireguard.
+	// It is used when the traffic has already been moved to another proxy
+	Stop() error
 	Type() Type
 }
@@ -60,7 +60,7 @@ func (p *WireguardProxy) Start(remoteConn net.Conn) error {
 	return nil
 }
 
-func (p *WireguardProxy) Close() error {
+func (p *WireguardProxy) Stop() error {
 	p.cancel()
 	if c := p.localConn; c != nil {
 		err := p.localConn.Close()
@@ -68,7 +68,15 @@ func (p *WireguardProxy) Close() error {
 			return err
 		}
 	}
-	err := p.config.WgInterface.RemovePeer(p.config.RemoteKey)
+	return nil
+}
+
+func (p *WireguardProxy) Close() error {
+	err := p.Stop()
+	if err != nil {
+		return err
+	}
+	err = p.config.WgInterface.RemovePeer(p.config.RemoteKey)
 	if err != nil {
 		return err
 	}
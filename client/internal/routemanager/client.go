@@ -9,20 +9,36 @@ import (
 	"github.com/netbirdio/netbird/route"
 	log "github.com/sirupsen/logrus"
 	"net/netip"
+	"time"
 )
 
 type routerPeerStatus struct {
 	connected bool
 	relayed   bool
 	direct    bool
+	latency   time.Duration
+}
+
+// routeCandidate holds the values used to compare routes of the same network
+type routeCandidate struct {
+	id string
+	// score is derived from the route metric, a higher score is preferred
+	score int
+	// connScore prefers direct connections over relayed ones
+	connScore int
+	latency   time.Duration
 }
 
 type routesUpdate struct {
 	updateSerial uint64
 	routes       []*route.Route
+	// forceSystemRoute indicates that the system route must be added even if the network is already routed locally
+	forceSystemRoute bool
+	latencyThreshold time.Duration
 }
 
 type clientNetwork struct {
+	id                  string
 	ctx                 context.Context
 	stop                context.CancelFunc
 	statusRecorder      *status.Status
@@ -34,11 +50,16 @@ type clientNetwork struct {
 	chosenRoute         *route.Route
 	network             netip.Prefix
 	updateSerial        uint64
+	latencyThreshold    time.Duration
+	forceSystemRoute    bool
+	// systemRoutes are the prefixes added to the system route table for the chosen route
+	systemRoutes []netip.Prefix
 }
 
-func newClientNetworkWatcher(ctx context.Context, wgInterface *iface.WGIface, statusRecorder *status.Status, network netip.Prefix) *clientNetwork {
+func newClientNetworkWatcher(ctx context.Context, id string, wgInterface *iface.WGIface, statusRecorder *status.Status, network netip.Prefix, latencyThreshold time.Duration) *clientNetwork {
 	ctx, cancel := context.WithCancel(ctx)
 	client := &clientNetwork{
+		id:                  id,
 		ctx:                 ctx,
 		stop:                cancel,
 		statusRecorder:      statusRecorder,
@@ -48,6 +69,7 @@ func newClientNetworkWatcher(ctx context.Context, wgInterface *iface.WGIface, st
 		routeUpdate:         make(chan routesUpdate),
 		peerStateUpdate:     make(chan struct{}),
 		network:             network,
+		latencyThreshold:    latencyThreshold,
 	}
 	return client
 }
@@ -68,53 +90,119 @@ func (c *clientNetwork) getRouterPeerStatuses() map[string]routerPeerStatus {
 			connected: peerStatus.ConnStatus == peer.StatusConnected.String(),
 			relayed:   peerStatus.Relayed,
 			direct:    peerStatus.Direct,
+			latency:   peerStatus.Latency,
 		}
 	}
 	return routePeerStatuses
 }
 
+// getBestRouteFromStatuses returns the ID of the best route among the ones with connected routing peers.
+// Lower metrics always win. Among the routes with the best metric, the ones with a latency within the latency
+// threshold of the fastest route are considered equally fast, so small latency changes don't make the route flap.
+// These are compared by connection type, preferring the current route and finally the route ID. Routes with an
+// unknown latency are only considered when no latency is known
 func (c *clientNetwork) getBestRouteFromStatuses(routePeerStatuses map[string]routerPeerStatus) string {
-	var chosen string
-	chosenScore := 0
-
 	currID := ""
 	if c.chosenRoute != nil {
 		currID = c.chosenRoute.ID
 	}
 
+	var candidates []routeCandidate
 	for _, r := range c.routes {
-		tempScore := 0
 		peerStatus, found := routePeerStatuses[r.ID]
 		if !found || !peerStatus.connected {
 			continue
 		}
-		if r.Metric < route.MaxMetric {
-			metricDiff := route.MaxMetric - r.Metric
-			tempScore = metricDiff * 10
-		}
-		if !peerStatus.relayed {
-			tempScore++
-		}
-		if !peerStatus.direct {
-			tempScore++
-		}
-		if tempScore > chosenScore || (tempScore == chosenScore && currID == r.ID) {
-			chosen = r.ID
-			chosenScore = tempScore
-		}
+		candidates = append(candidates, newRouteCandidate(r, peerStatus))
 	}
 
-	if chosen == "" {
+	if len(candidates) == 0 {
 		var peers []string
 		for _, r := range c.routes {
 			peers = append(peers, r.Peer)
 		}
 		log.Warnf("no route was chosen for network %s because no peers from list %s were connected", c.network, peers)
-	} else if chosen != currID {
-		log.Infof("new chosen route is %s with peer %s with score %d", chosen, c.routes[chosen].Peer, chosenScore)
+		return ""
+	}
+
+	bestScore := candidates[0].score
+	for _, candidate := range candidates {
+		if candidate.score > bestScore {
+			bestScore = candidate.score
+		}
+	}
+
+	var fastest time.Duration
+	for _, candidate := range candidates {
+		if candidate.score == bestScore && candidate.latency > 0 && (fastest == 0 || candidate.latency < fastest) {
+			fastest = candidate.latency
+		}
+	}
+
+	var chosen *routeCandidate
+	for i, candidate := range candidates {
+		if candidate.score != bestScore || !candidate.isFastEnough(fastest, c.latencyThreshold) {
+			continue
+		}
+		if chosen == nil || candidate.isBetterThan(*chosen, currID) {
+			chosen = &candidates[i]
+		}
+	}
+
+	if chosen.id != currID {
+		log.Infof("new chosen route is %s with peer %s with score %d and latency %s",
+			chosen.id, c.routes[chosen.id].Peer, chosen.score, chosen.latency)
+	}
+
+	return chosen.id
+}
+
+func newRouteCandidate(r *route.Route, peerStatus routerPeerStatus) routeCandidate {
+	candidate := routeCandidate{
+		id:      r.ID,
+		latency: peerStatus.latency,
+	}
+	if r.Metric < route.MaxMetric {
+		candidate.score = route.MaxMetric - r.Metric
+	}
+	if !peerStatus.relayed {
+		candidate.connScore++
+	}
+	if !peerStatus.direct {
+		candidate.connScore++
+	}
+	return candidate
+}
+
+// isFastEnough returns true if the latency of the candidate is within the threshold of the fastest latency.
+// A zero fastest latency means no latency is known and every candidate qualifies
+func (rc routeCandidate) isFastEnough(fastest, threshold time.Duration) bool {
+	if fastest == 0 {
+		return true
 	}
+	return rc.latency > 0 && rc.latency-fastest <= threshold
+}
+
+// isBetterThan compares two route candidates that are equally fast using a total order, so the result doesn't
+// depend on the iteration order: connection type first, then the current route and finally the route ID
+func (rc routeCandidate) isBetterThan(other routeCandidate, currID string) bool {
+	if rc.connScore != other.connScore {
+		return rc.connScore > other.connScore
+	}
+	if (rc.id == currID) != (other.id == currID) {
+		return rc.id == currID
+	}
+	return rc.id < other.id
+}
 
-	return chosen
+// sendLatencyUpdate triggers a route recalculation after the latency of the routing peers has been measured
+func (c *clientNetwork) sendLatencyUpdate() {
+	go func() {
+		select {
+		case c.peerStateUpdate <- struct{}{}:
+		case <-c.ctx.Done():
+		}
+	}()
 }
 
 func (c *clientNetwork) watchPeerStatusChanges(ctx context.Context, peerKey string, peerStateUpdate chan struct{}, closer chan struct{}) {
@@ -165,12 +253,46 @@ func (c *clientNetwork) removeRouteFromPeerAndSystem() error {
 		if err != nil {
 			return err
 		}
-		err = removeFromRouteTableIfNonSystem(c.network, c.wgInterface.GetAddress().IP.String())
+		for _, prefix := range c.systemRoutes {
+			err = removeFromRouteTableIfNonSystem(prefix, c.wgInterface.GetAddress().IP.String())
+			if err != nil {
+				return fmt.Errorf("couldn't remove route %s from system, err: %v",
+					prefix, err)
+			}
+		}
+		c.systemRoutes = nil
+	}
+	return nil
+}
+
+// addSystemRoutes adds the network to the system route table. A forced route is added as the two halves of
+// the network, so it takes over the traffic of an overlapping local network without clashing with its
+// connected route
+func (c *clientNetwork) addSystemRoutes() error {
+	// the in-process network stack sends all traffic to the tunnel, the allowed IPs select the routing peer
+	if c.wgInterface.IsNetstack() {
+		return nil
+	}
+
+	addr := c.wgInterface.GetAddress().IP.String()
+	if !c.forceSystemRoute {
+		err := addToRouteTableIfNoExists(c.network, addr)
 		if err != nil {
-			return fmt.Errorf("couldn't remove route %s from system, err: %v",
-				c.network, err)
+			return err
 		}
+		c.systemRoutes = []netip.Prefix{c.network}
+		return nil
 	}
+
+	prefixes := splitPrefix(c.network)
+	for i, prefix := range prefixes {
+		err := addToRouteTable(prefix, addr)
+		if err != nil {
+			c.systemRoutes = prefixes[:i]
+			return err
+		}
+	}
+	c.systemRoutes = prefixes
 	return nil
 }
 
@@ -188,6 +310,7 @@ func (c *clientNetwork) recalculateRouteAndUpdatePeerAndSystem() error {
 		}
 
 		c.chosenRoute = nil
+		c.updateRoutePeerStatus("")
 
 		return nil
 	}
@@ -204,7 +327,7 @@ func (c *clientNetwork) recalculateRouteAndUpdatePeerAndSystem() error {
 			return err
 		}
 	} else {
-		err = addToRouteTableIfNoExists(c.network, c.wgInterface.GetAddress().IP.String())
+		err = c.addSystemRoutes()
 		if err != nil {
 			return fmt.Errorf("route %s couldn't be added for peer %s, err: %v",
 				c.network.String(), c.wgInterface.GetAddress().IP.String(), err)
@@ -212,6 +335,7 @@ func (c *clientNetwork) recalculateRouteAndUpdatePeerAndSystem() error {
 	}
 
 	c.chosenRoute = c.routes[chosen]
+	c.updateRoutePeerStatus(c.chosenRoute.Peer)
 	err = c.wgInterface.AddAllowedIP(c.chosenRoute.Peer, c.network.String())
 	if err != nil {
 		log.Errorf("couldn't add allowed IP %s added for peer %s, err: %v",
@@ -221,6 +345,14 @@ func (c *clientNetwork) recalculateRouteAndUpdatePeerAndSystem() error {
 	return nil
 }
 
+// updateRoutePeerStatus records the routing peer in use in the status recorder
+func (c *clientNetwork) updateRoutePeerStatus(peerKey string) {
+	err := c.statusRecorder.UpdateRoutePeer(c.id, peerKey)
+	if err != nil {
+		log.Debugf("unable to update routing peer of network %s: %v", c.network, err)
+	}
+}
+
 func (c *clientNetwork) sendUpdateToClientNetworkWatcher(update routesUpdate) {
 	go func() {
 		c.routeUpdate <- update
@@ -243,6 +375,8 @@ func (c *clientNetwork) handleUpdate(update routesUpdate) {
 	}
 
 	c.routes = updateMap
+	c.forceSystemRoute = update.forceSystemRoute
+	c.latencyThreshold = update.latencyThreshold
 }
 
 // peersStateAndUpdateWatcher is the main point of reacting on client network routing events.
@@ -256,6 +390,7 @@ func (c *clientNetwork) peersStateAndUpdateWatcher() {
 			if err != nil {
 				log.Error(err)
 			}
+			c.updateRoutePeerStatus("")
 			return
 		case <-c.peerStateUpdate:
 			err := c.recalculateRouteAndUpdatePeerAndSystem()
@@ -0,0 +1,144 @@
+package routemanager
+
+import (
+	"github.com/netbirdio/netbird/route"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestGetBestRouteFromStatuses(t *testing.T) {
+	testCases := []struct {
+		name           string
+		statuses       map[string]routerPeerStatus
+		existingRoutes map[string]*route.Route
+		currentRoute   *route.Route
+		expectedRoute  string
+	}{
+		{
+			name: "lower metric wins regardless of latency",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: true, direct: true, latency: 100 * time.Millisecond},
+				"route2": {connected: true, direct: true, latency: 5 * time.Millisecond},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: 1, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: 10, Peer: "peer2"},
+			},
+			expectedRoute: "route1",
+		},
+		{
+			name: "lower latency wins with equal metrics",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: true, direct: true, latency: 100 * time.Millisecond},
+				"route2": {connected: true, relayed: true, latency: 5 * time.Millisecond},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: route.MaxMetric, Peer: "peer2"},
+			},
+			expectedRoute: "route2",
+		},
+		{
+			name: "connection type decides when latency is unknown",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: true, relayed: true},
+				"route2": {connected: true},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: route.MaxMetric, Peer: "peer2"},
+			},
+			expectedRoute: "route2",
+		},
+		{
+			name: "connection type decides within the latency threshold",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: true, relayed: true, latency: 10 * time.Millisecond},
+				"route2": {connected: true, latency: 25 * time.Millisecond},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: route.MaxMetric, Peer: "peer2"},
+			},
+			expectedRoute: "route2",
+		},
+		{
+			name: "disconnected peers are ignored",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: false, direct: true, latency: 5 * time.Millisecond},
+				"route2": {connected: true, direct: true, latency: 100 * time.Millisecond},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: route.MaxMetric, Peer: "peer2"},
+			},
+			expectedRoute: "route2",
+		},
+		{
+			name: "current route is kept below the latency threshold",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: true, direct: true, latency: 30 * time.Millisecond},
+				"route2": {connected: true, direct: true, latency: 15 * time.Millisecond},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: route.MaxMetric, Peer: "peer2"},
+			},
+			currentRoute:  &route.Route{ID: "route1"},
+			expectedRoute: "route1",
+		},
+		{
+			name: "current route is replaced above the latency threshold",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: true, direct: true, latency: 80 * time.Millisecond},
+				"route2": {connected: true, direct: true, latency: 15 * time.Millisecond},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: route.MaxMetric, Peer: "peer2"},
+			},
+			currentRoute:  &route.Route{ID: "route1"},
+			expectedRoute: "route2",
+		},
+		{
+			name: "mixed known and unknown latencies are ordered deterministically",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: true, relayed: true, latency: 5 * time.Millisecond},
+				"route2": {connected: true, direct: true},
+				"route3": {connected: true, direct: true, latency: 50 * time.Millisecond},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+				"route2": {ID: "route2", Metric: route.MaxMetric, Peer: "peer2"},
+				"route3": {ID: "route3", Metric: route.MaxMetric, Peer: "peer3"},
+			},
+			expectedRoute: "route1",
+		},
+		{
+			name: "no route is chosen without connected peers",
+			statuses: map[string]routerPeerStatus{
+				"route1": {connected: false},
+			},
+			existingRoutes: map[string]*route.Route{
+				"route1": {ID: "route1", Metric: route.MaxMetric, Peer: "peer1"},
+			},
+			expectedRoute: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := &clientNetwork{
+				network:          netip.MustParsePrefix("192.168.0.0/24"),
+				routes:           testCase.existingRoutes,
+				chosenRoute:      testCase.currentRoute,
+				latencyThreshold: DefaultLatencyThreshold,
+			}
+
+			chosenRoute := client.getBestRouteFromStatuses(testCase.statuses)
+			require.Equal(t, testCase.expectedRoute, chosenRoute, "chosen route should match")
+		})
+	}
+}
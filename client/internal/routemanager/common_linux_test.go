@@ -1,5 +1,7 @@
 package routemanager
 
+import "net/netip"
+
 var insertRuleTestCases = []struct {
 	name      string
 	inputPair routerPair
@@ -73,3 +75,43 @@ var removeRuleTestCases = []struct {
 		ipVersion: ipv6,
 	},
 }
+
+var portForwardRuleTestCases = []struct {
+	name      string
+	inputRule portForwardRule
+	ipVersion string
+}{
+	{
+		name: "Port Forward TCP IPV4 Rules",
+		inputRule: portForwardRule{
+			ID:            "pfa",
+			protocol:      "tcp",
+			externalPort:  8080,
+			target:        netip.MustParseAddrPort("100.100.100.5:80"),
+			meshInterface: "wt0",
+		},
+		ipVersion: ipv4,
+	},
+	{
+		name: "Port Forward UDP IPV4 Rules",
+		inputRule: portForwardRule{
+			ID:            "pfa",
+			protocol:      "udp",
+			externalPort:  5353,
+			target:        netip.MustParseAddrPort("100.100.100.5:53"),
+			meshInterface: "wt0",
+		},
+		ipVersion: ipv4,
+	},
+	{
+		name: "Port Forward TCP IPV6 Rules",
+		inputRule: portForwardRule{
+			ID:            "pfa",
+			protocol:      "tcp",
+			externalPort:  8080,
+			target:        netip.MustParseAddrPort("[fc12::5]:80"),
+			meshInterface: "wt0",
+		},
+		ipVersion: ipv6,
+	},
+}
@@ -1,5 +1,15 @@
 package routemanager
 
+// routeCounters contains the traffic forwarded by the routing rules of a route
+type routeCounters struct {
+	// txBytes and txPackets count the traffic forwarded to the routed network
+	txBytes   uint64
+	txPackets uint64
+	// rxBytes and rxPackets count the traffic forwarded from the routed network
+	rxBytes   uint64
+	rxPackets uint64
+}
+
 type firewallManager interface {
 	// RestoreOrCreateContainers restores or creates a firewall container set of rules, tables and default rules
 	RestoreOrCreateContainers() error
@@ -7,6 +17,39 @@ type firewallManager interface {
 	InsertRoutingRules(pair routerPair) error
 	// RemoveRoutingRules removes a routing firewall rule
 	RemoveRoutingRules(pair routerPair) error
+	// InsertPortForwardRules inserts the port forwarding firewall rules
+	InsertPortForwardRules(rule portForwardRule) error
+	// RemovePortForwardRules removes the port forwarding firewall rules
+	RemovePortForwardRules(rule portForwardRule) error
+	// GetRoutingRulesCounters returns the traffic counters of the routing rules indexed by route ID
+	GetRoutingRulesCounters() (map[string]routeCounters, error)
 	// CleanRoutingRules cleans a firewall set of containers
 	CleanRoutingRules()
 }
+
+// unimplementedFirewall is used where the firewall of the host can't be managed, e.g. on other systems than linux
+// or in netstack mode
+type unimplementedFirewall struct{}
+
+func (unimplementedFirewall) RestoreOrCreateContainers() error {
+	return nil
+}
+func (unimplementedFirewall) InsertRoutingRules(pair routerPair) error {
+	return nil
+}
+func (unimplementedFirewall) RemoveRoutingRules(pair routerPair) error {
+	return nil
+}
+func (unimplementedFirewall) InsertPortForwardRules(rule portForwardRule) error {
+	return nil
+}
+func (unimplementedFirewall) RemovePortForwardRules(rule portForwardRule) error {
+	return nil
+}
+func (unimplementedFirewall) GetRoutingRulesCounters() (map[string]routeCounters, error) {
+	return nil, nil
+}
+
+func (unimplementedFirewall) CleanRoutingRules() {
+	return
+}
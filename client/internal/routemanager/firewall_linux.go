@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/coreos/go-iptables/iptables"
 	log "github.com/sirupsen/logrus"
+	"strings"
 )
 import "github.com/google/nftables"
 
@@ -17,8 +18,15 @@ const (
 	forwardingFormat   = "netbird-fwd-%s"
 	inNatFormat        = "netbird-nat-in-%s"
 	inForwardingFormat = "netbird-fwd-in-%s"
+	ipv6Dnat           = "netbird-rt-ipv6-dnat"
+	ipv4Dnat           = "netbird-rt-ipv4-dnat"
 	ipv6               = "ipv6"
 	ipv4               = "ipv4"
+	// port forward rule keys
+	dnatFormat             = "netbird-dnat-%s"
+	dnatForwardingFormat   = "netbird-dnat-fwd-%s"
+	inDnatForwardingFormat = "netbird-dnat-fwd-in-%s"
+	dnatNatFormat          = "netbird-dnat-nat-%s"
 )
 
 func genKey(format string, input string) string {
@@ -56,6 +64,28 @@ func NewFirewall(parentCTX context.Context) firewallManager {
 	return manager
 }
 
+// addRuleCounters adds the counters of a forwarding rule to the counters of its route, rules that aren't
+// forwarding rules of a route are ignored
+func addRuleCounters(counters map[string]routeCounters, ruleKey string, packets, bytes uint64) {
+	inPrefix := strings.TrimSuffix(inForwardingFormat, "%s")
+	outPrefix := strings.TrimSuffix(forwardingFormat, "%s")
+
+	switch {
+	case strings.HasPrefix(ruleKey, inPrefix):
+		routeID := strings.TrimPrefix(ruleKey, inPrefix)
+		routeCounter := counters[routeID]
+		routeCounter.rxPackets += packets
+		routeCounter.rxBytes += bytes
+		counters[routeID] = routeCounter
+	case strings.HasPrefix(ruleKey, outPrefix):
+		routeID := strings.TrimPrefix(ruleKey, outPrefix)
+		routeCounter := counters[routeID]
+		routeCounter.txPackets += packets
+		routeCounter.txBytes += bytes
+		counters[routeID] = routeCounter
+	}
+}
+
 func getInPair(pair routerPair) routerPair {
 	return routerPair{
 		ID: pair.ID,
@@ -7,6 +7,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"net/netip"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -23,10 +24,13 @@ const (
 	iptablesNatTable               = "nat"
 	iptablesForwardChain           = "FORWARD"
 	iptablesPostRoutingChain       = "POSTROUTING"
+	iptablesPreRoutingChain        = "PREROUTING"
 	iptablesRoutingNatChain        = "NETBIRD-RT-NAT"
 	iptablesRoutingForwardingChain = "NETBIRD-RT-FWD"
+	iptablesRoutingDnatChain       = "NETBIRD-RT-DNAT"
 	routingFinalForwardJump        = "ACCEPT"
 	routingFinalNatJump            = "MASQUERADE"
+	portForwardFinalDnatJump       = "DNAT"
 )
 
 // some presets for building nftable rules
@@ -35,6 +39,7 @@ var (
 	iptablesDefaultNetbirdForwardingRule = []string{"-j", "RETURN"}
 	iptablesDefaultNatRule               = []string{"-j", iptablesRoutingNatChain, "-m", "comment", "--comment"}
 	iptablesDefaultNetbirdNatRule        = []string{"-j", "RETURN"}
+	iptablesDefaultDnatRule              = []string{"-j", iptablesRoutingDnatChain, "-m", "comment", "--comment"}
 )
 
 type iptablesManager struct {
@@ -78,6 +83,16 @@ func (i *iptablesManager) CleanRoutingRules() {
 		log.Errorf(errMSGFormat, ipv6, iptablesRoutingNatChain, err)
 	}
 
+	err = i.ipv4Client.ClearAndDeleteChain(iptablesNatTable, iptablesRoutingDnatChain)
+	if err != nil {
+		log.Errorf(errMSGFormat, ipv4, iptablesRoutingDnatChain, err)
+	}
+
+	err = i.ipv6Client.ClearAndDeleteChain(iptablesNatTable, iptablesRoutingDnatChain)
+	if err != nil {
+		log.Errorf(errMSGFormat, ipv6, iptablesRoutingDnatChain, err)
+	}
+
 	log.Info("done cleaning up iptables rules")
 }
 
@@ -113,6 +128,16 @@ func (i *iptablesManager) RestoreOrCreateContainers() error {
 		return fmt.Errorf(errMSGFormat, ipv6, iptablesRoutingNatChain, err)
 	}
 
+	err = createChain(i.ipv4Client, iptablesNatTable, iptablesRoutingDnatChain)
+	if err != nil {
+		return fmt.Errorf(errMSGFormat, ipv4, iptablesRoutingDnatChain, err)
+	}
+
+	err = createChain(i.ipv6Client, iptablesNatTable, iptablesRoutingDnatChain)
+	if err != nil {
+		return fmt.Errorf(errMSGFormat, ipv6, iptablesRoutingDnatChain, err)
+	}
+
 	err = i.restoreRules(i.ipv4Client)
 	if err != nil {
 		return fmt.Errorf("iptables: error while restoring ipv4 rules: %v", err)
@@ -166,6 +191,20 @@ func (i *iptablesManager) addJumpRules() error {
 	}
 	i.rules[ipv6][ipv6Nat] = rule
 
+	rule = append(iptablesDefaultDnatRule, ipv4Dnat)
+	err = i.ipv4Client.Insert(iptablesNatTable, iptablesPreRoutingChain, 1, rule...)
+	if err != nil {
+		return err
+	}
+	i.rules[ipv4][ipv4Dnat] = rule
+
+	rule = append(iptablesDefaultDnatRule, ipv6Dnat)
+	err = i.ipv6Client.Insert(iptablesNatTable, iptablesPreRoutingChain, 1, rule...)
+	if err != nil {
+		return err
+	}
+	i.rules[ipv6][ipv6Dnat] = rule
+
 	return nil
 }
 
@@ -205,6 +244,22 @@ func (i *iptablesManager) cleanJumpRules() error {
 			return fmt.Errorf(errMSGFormat, ipv6, iptablesPostRoutingChain, err)
 		}
 	}
+	rule, found = i.rules[ipv4][ipv4Dnat]
+	if found {
+		log.Debugf("iptables: removing %s rule: %s ", ipv4, ipv4Dnat)
+		err = i.ipv4Client.DeleteIfExists(iptablesNatTable, iptablesPreRoutingChain, rule...)
+		if err != nil {
+			return fmt.Errorf(errMSGFormat, ipv4, iptablesPreRoutingChain, err)
+		}
+	}
+	rule, found = i.rules[ipv6][ipv6Dnat]
+	if found {
+		log.Debugf("iptables: removing %s rule: %s ", ipv6, ipv6Dnat)
+		err = i.ipv6Client.DeleteIfExists(iptablesNatTable, iptablesPreRoutingChain, rule...)
+		if err != nil {
+			return fmt.Errorf(errMSGFormat, ipv6, iptablesPreRoutingChain, err)
+		}
+	}
 	return nil
 }
 
@@ -238,7 +293,7 @@ func (i *iptablesManager) restoreRules(iptablesClient *iptables.IPTables) error
 	}
 
 	table = iptablesNatTable
-	for _, chain := range []string{iptablesPostRoutingChain, iptablesRoutingNatChain} {
+	for _, chain := range []string{iptablesPostRoutingChain, iptablesRoutingNatChain, iptablesPreRoutingChain, iptablesRoutingDnatChain} {
 		rules, err := iptablesClient.List(table, chain)
 		if err != nil {
 			return err
@@ -306,6 +361,17 @@ func getRuleRouteID(rule []string) string {
 	return ""
 }
 
+// getStatRuleID returns the rule ID of a listed rule statistic if its comment matches our prefix
+func getStatRuleID(options string) string {
+	fields := strings.Fields(options)
+	for i, field := range fields {
+		if field == "/*" && i+1 < len(fields) && strings.HasPrefix(fields[i+1], "netbird-") {
+			return fields[i+1]
+		}
+	}
+	return ""
+}
+
 // InsertRoutingRules inserts an iptables rule pair to the forwarding chain and if enabled, to the nat chain
 func (i *iptablesManager) InsertRoutingRules(pair routerPair) error {
 	i.mux.Lock()
@@ -434,3 +500,141 @@ func getIptablesRuleType(table string) string {
 	}
 	return ruleType
 }
+
+// GetRoutingRulesCounters returns the counters of the forwarding rules indexed by route ID
+func (i *iptablesManager) GetRoutingRulesCounters() (map[string]routeCounters, error) {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+
+	counters := make(map[string]routeCounters)
+	for _, iptablesClient := range []*iptables.IPTables{i.ipv4Client, i.ipv6Client} {
+		stats, err := iptablesClient.StructuredStats(iptablesFilterTable, iptablesRoutingForwardingChain)
+		if err != nil {
+			return nil, fmt.Errorf("iptables: unable to list %s forwarding rules counters: %v",
+				iptablesProtoToString(iptablesClient.Proto()), err)
+		}
+		for _, stat := range stats {
+			ruleKey := getStatRuleID(stat.Options)
+			if ruleKey != "" {
+				addRuleCounters(counters, ruleKey, stat.Packets, stat.Bytes)
+			}
+		}
+	}
+	return counters, nil
+}
+
+// genDnatRuleSpec generates a port forward destination nat rule specification with comment identifier. It only
+// matches traffic addressed to the host itself that wasn't received on the mesh interface
+func genDnatRuleSpec(id string, rule portForwardRule) []string {
+	return []string{"!", "-i", rule.meshInterface, "-m", "addrtype", "--dst-type", "LOCAL",
+		"-p", rule.protocol, "--dport", strconv.Itoa(int(rule.externalPort)), "-j", portForwardFinalDnatJump,
+		"--to-destination", rule.target.String(), "-m", "comment", "--comment", id}
+}
+
+// genPortForwardRuleSpec generates a rule specification matching the port forward target in the given direction
+func genPortForwardRuleSpec(jump, id, direction string, rule portForwardRule) []string {
+	addrFlag, portFlag := "-d", "--dport"
+	if direction == exprDirectionSource {
+		addrFlag, portFlag = "-s", "--sport"
+	}
+	return []string{"-p", rule.protocol, addrFlag, rule.target.Addr().String(), portFlag, strconv.Itoa(int(rule.target.Port())),
+		"-j", jump, "-m", "comment", "--comment", id}
+}
+
+// InsertPortForwardRules inserts the port forward destination nat rule, the forwarding rules for both directions and
+// the nat rule that makes the target reply through the routing peer
+func (i *iptablesManager) InsertPortForwardRules(rule portForwardRule) error {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+
+	err := i.insertPortForwardRule(iptablesNatTable, iptablesRoutingDnatChain, genKey(dnatFormat, rule.ID), rule,
+		genDnatRuleSpec(genKey(dnatFormat, rule.ID), rule))
+	if err != nil {
+		return err
+	}
+
+	err = i.insertPortForwardRule(iptablesFilterTable, iptablesRoutingForwardingChain, genKey(dnatForwardingFormat, rule.ID), rule,
+		genPortForwardRuleSpec(routingFinalForwardJump, genKey(dnatForwardingFormat, rule.ID), exprDirectionDestination, rule))
+	if err != nil {
+		return err
+	}
+
+	err = i.insertPortForwardRule(iptablesFilterTable, iptablesRoutingForwardingChain, genKey(inDnatForwardingFormat, rule.ID), rule,
+		genPortForwardRuleSpec(routingFinalForwardJump, genKey(inDnatForwardingFormat, rule.ID), exprDirectionSource, rule))
+	if err != nil {
+		return err
+	}
+
+	return i.insertPortForwardRule(iptablesNatTable, iptablesRoutingNatChain, genKey(dnatNatFormat, rule.ID), rule,
+		genPortForwardRuleSpec(routingFinalNatJump, genKey(dnatNatFormat, rule.ID), exprDirectionDestination, rule))
+}
+
+// insertPortForwardRule inserts an iptables port forward rule, replacing the existing rule with the same key
+func (i *iptablesManager) insertPortForwardRule(table, chain, ruleKey string, rule portForwardRule, spec []string) error {
+	iptablesClient, ipVersion := i.portForwardClient(rule)
+
+	existingRule, found := i.rules[ipVersion][ruleKey]
+	if found {
+		err := iptablesClient.DeleteIfExists(table, chain, existingRule...)
+		if err != nil {
+			return fmt.Errorf("iptables: error while removing existing %s port forward rule for %s: %v", getIptablesRuleType(table), rule.target, err)
+		}
+		delete(i.rules[ipVersion], ruleKey)
+	}
+
+	err := iptablesClient.Insert(table, chain, 1, spec...)
+	if err != nil {
+		return fmt.Errorf("iptables: error while adding new %s port forward rule for %s: %v", getIptablesRuleType(table), rule.target, err)
+	}
+
+	i.rules[ipVersion][ruleKey] = spec
+
+	return nil
+}
+
+// RemovePortForwardRules removes the port forward rules from the dnat, forwarding and nat chains
+func (i *iptablesManager) RemovePortForwardRules(rule portForwardRule) error {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+
+	err := i.removePortForwardRule(iptablesNatTable, iptablesRoutingDnatChain, genKey(dnatFormat, rule.ID), rule)
+	if err != nil {
+		return err
+	}
+
+	err = i.removePortForwardRule(iptablesFilterTable, iptablesRoutingForwardingChain, genKey(dnatForwardingFormat, rule.ID), rule)
+	if err != nil {
+		return err
+	}
+
+	err = i.removePortForwardRule(iptablesFilterTable, iptablesRoutingForwardingChain, genKey(inDnatForwardingFormat, rule.ID), rule)
+	if err != nil {
+		return err
+	}
+
+	return i.removePortForwardRule(iptablesNatTable, iptablesRoutingNatChain, genKey(dnatNatFormat, rule.ID), rule)
+}
+
+// removePortForwardRule removes an iptables port forward rule
+func (i *iptablesManager) removePortForwardRule(table, chain, ruleKey string, rule portForwardRule) error {
+	iptablesClient, ipVersion := i.portForwardClient(rule)
+
+	existingRule, found := i.rules[ipVersion][ruleKey]
+	if found {
+		err := iptablesClient.DeleteIfExists(table, chain, existingRule...)
+		if err != nil {
+			return fmt.Errorf("iptables: error while removing existing %s port forward rule for %s: %v", getIptablesRuleType(table), rule.target, err)
+		}
+	}
+	delete(i.rules[ipVersion], ruleKey)
+
+	return nil
+}
+
+// portForwardClient returns the iptables client and ip version matching the port forward target
+func (i *iptablesManager) portForwardClient(rule portForwardRule) (*iptables.IPTables, string) {
+	if rule.target.Addr().Unmap().Is6() {
+		return i.ipv6Client, ipv6
+	}
+	return i.ipv4Client, ipv4
+}
@@ -32,7 +32,7 @@ func TestIptablesManager_RestoreOrCreateContainers(t *testing.T) {
 
 	require.Len(t, manager.rules, 2, "should have created maps for ipv4 and ipv6")
 
-	require.Len(t, manager.rules[ipv4], 2, "should have created minimal rules for ipv4")
+	require.Len(t, manager.rules[ipv4], 3, "should have created minimal rules for ipv4")
 
 	exists, err := ipv4Client.Exists(iptablesFilterTable, iptablesForwardChain, manager.rules[ipv4][ipv4Forwarding]...)
 	require.NoError(t, err, "should be able to query the iptables %s %s table and %s chain", ipv4, iptablesFilterTable, iptablesForwardChain)
@@ -42,7 +42,7 @@ func TestIptablesManager_RestoreOrCreateContainers(t *testing.T) {
 	require.NoError(t, err, "should be able to query the iptables %s %s table and %s chain", ipv4, iptablesNatTable, iptablesPostRoutingChain)
 	require.True(t, exists, "postrouting rule should exist")
 
-	require.Len(t, manager.rules[ipv6], 2, "should have created minimal rules for ipv6")
+	require.Len(t, manager.rules[ipv6], 3, "should have created minimal rules for ipv6")
 
 	exists, err = ipv6Client.Exists(iptablesFilterTable, iptablesForwardChain, manager.rules[ipv6][ipv6Forwarding]...)
 	require.NoError(t, err, "should be able to query the iptables %s %s table and %s chain", ipv6, iptablesFilterTable, iptablesForwardChain)
@@ -95,7 +95,7 @@ func TestIptablesManager_RestoreOrCreateContainers(t *testing.T) {
 	err = manager.RestoreOrCreateContainers()
 	require.NoError(t, err, "shouldn't return error")
 
-	require.Len(t, manager.rules[ipv4], 4, "should have restored all rules for ipv4")
+	require.Len(t, manager.rules[ipv4], 5, "should have restored all rules for ipv4")
 
 	foundRule, found := manager.rules[ipv4][forward4RuleKey]
 	require.True(t, found, "forwarding rule should exist in the map")
@@ -105,7 +105,7 @@ func TestIptablesManager_RestoreOrCreateContainers(t *testing.T) {
 	require.True(t, found, "nat rule should exist in the map")
 	require.Equal(t, nat4Rule[:4], foundRule[:4], "stored nat rule should match")
 
-	require.Len(t, manager.rules[ipv6], 4, "should have restored all rules for ipv6")
+	require.Len(t, manager.rules[ipv6], 5, "should have restored all rules for ipv6")
 
 	foundRule, found = manager.rules[ipv6][forward6RuleKey]
 	require.True(t, found, "forwarding rule should exist in the map")
@@ -298,3 +298,130 @@ func TestIptablesManager_RemoveRoutingRules(t *testing.T) {
 		})
 	}
 }
+
+func TestIptablesManager_PortForwardRules(t *testing.T) {
+
+	if !isIptablesSupported() {
+		t.SkipNow()
+	}
+
+	for _, testCase := range portForwardRuleTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			ipv4Client, _ := iptables.NewWithProtocol(iptables.ProtocolIPv4)
+			ipv6Client, _ := iptables.NewWithProtocol(iptables.ProtocolIPv6)
+			iptablesClient := ipv4Client
+			if testCase.ipVersion == ipv6 {
+				iptablesClient = ipv6Client
+			}
+
+			manager := &iptablesManager{
+				ctx:        ctx,
+				stop:       cancel,
+				ipv4Client: ipv4Client,
+				ipv6Client: ipv6Client,
+				rules:      make(map[string]map[string][]string),
+			}
+
+			defer manager.CleanRoutingRules()
+
+			err := manager.RestoreOrCreateContainers()
+			require.NoError(t, err, "shouldn't return error")
+
+			err = manager.InsertPortForwardRules(testCase.inputRule)
+			require.NoError(t, err, "port forward rules should be inserted")
+
+			dnatRuleKey := genKey(dnatFormat, testCase.inputRule.ID)
+			forwardRuleKey := genKey(dnatForwardingFormat, testCase.inputRule.ID)
+			inForwardRuleKey := genKey(inDnatForwardingFormat, testCase.inputRule.ID)
+			natRuleKey := genKey(dnatNatFormat, testCase.inputRule.ID)
+
+			expectedRules := []struct {
+				table string
+				chain string
+				key   string
+				spec  []string
+			}{
+				{iptablesNatTable, iptablesRoutingDnatChain, dnatRuleKey, genDnatRuleSpec(dnatRuleKey, testCase.inputRule)},
+				{iptablesFilterTable, iptablesRoutingForwardingChain, forwardRuleKey,
+					genPortForwardRuleSpec(routingFinalForwardJump, forwardRuleKey, exprDirectionDestination, testCase.inputRule)},
+				{iptablesFilterTable, iptablesRoutingForwardingChain, inForwardRuleKey,
+					genPortForwardRuleSpec(routingFinalForwardJump, inForwardRuleKey, exprDirectionSource, testCase.inputRule)},
+				{iptablesNatTable, iptablesRoutingNatChain, natRuleKey,
+					genPortForwardRuleSpec(routingFinalNatJump, natRuleKey, exprDirectionDestination, testCase.inputRule)},
+			}
+
+			for _, expected := range expectedRules {
+				exists, err := iptablesClient.Exists(expected.table, expected.chain, expected.spec...)
+				require.NoError(t, err, "should be able to query the iptables %s %s table and %s chain", testCase.ipVersion, expected.table, expected.chain)
+				require.True(t, exists, "rule %s should exist", expected.key)
+
+				_, found := manager.rules[testCase.ipVersion][expected.key]
+				require.True(t, found, "rule %s should exist in the manager map", expected.key)
+			}
+
+			err = manager.RemovePortForwardRules(testCase.inputRule)
+			require.NoError(t, err, "port forward rules should be removed")
+
+			for _, expected := range expectedRules {
+				exists, err := iptablesClient.Exists(expected.table, expected.chain, expected.spec...)
+				require.NoError(t, err, "should be able to query the iptables %s %s table and %s chain", testCase.ipVersion, expected.table, expected.chain)
+				require.False(t, exists, "rule %s should not exist", expected.key)
+
+				_, found := manager.rules[testCase.ipVersion][expected.key]
+				require.False(t, found, "rule %s should not exist in the manager map", expected.key)
+			}
+		})
+	}
+}
+
+func TestIptablesManager_GetRoutingRulesCounters(t *testing.T) {
+
+	if !isIptablesSupported() {
+		t.SkipNow()
+	}
+
+	for _, testCase := range insertRuleTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			ipv4Client, _ := iptables.NewWithProtocol(iptables.ProtocolIPv4)
+			ipv6Client, _ := iptables.NewWithProtocol(iptables.ProtocolIPv6)
+
+			manager := &iptablesManager{
+				ctx:        ctx,
+				stop:       cancel,
+				ipv4Client: ipv4Client,
+				ipv6Client: ipv6Client,
+				rules:      make(map[string]map[string][]string),
+			}
+
+			defer manager.CleanRoutingRules()
+
+			err := manager.RestoreOrCreateContainers()
+			require.NoError(t, err, "shouldn't return error")
+
+			err = manager.InsertRoutingRules(testCase.inputPair)
+			require.NoError(t, err, "forwarding pair should be inserted")
+
+			counters, err := manager.GetRoutingRulesCounters()
+			require.NoError(t, err, "should read the routing rules counters")
+			require.Len(t, counters, 1, "should only return counters of the inserted route")
+			_, found := counters[testCase.inputPair.ID]
+			require.True(t, found, "should return counters for the inserted route")
+
+			err = manager.RemoveRoutingRules(testCase.inputPair)
+			require.NoError(t, err, "shouldn't return error")
+
+			counters, err = manager.GetRoutingRulesCounters()
+			require.NoError(t, err, "should read the routing rules counters")
+			require.Len(t, counters, 0, "should not return counters of removed routes")
+		})
+	}
+}
+
+func TestGetStatRuleID(t *testing.T) {
+	require.Equal(t, "netbird-fwd-in-zxa", getStatRuleID("/* netbird-fwd-in-zxa */"))
+	require.Equal(t, "netbird-fwd-zxa", getStatRuleID("tcp dpt:80 /* netbird-fwd-zxa */"))
+	require.Equal(t, "", getStatRuleID("/* other-rule */"))
+	require.Equal(t, "", getStatRuleID(""))
+}
@@ -0,0 +1,40 @@
+package routemanager
+
+import (
+	"net/netip"
+)
+
+// KillSwitchEndpoint is a remote endpoint the client must still reach while the kill-switch is enabled,
+// e.g. the Management, Signal or a STUN/TURN service
+type KillSwitchEndpoint struct {
+	// Protocol is the transport protocol of the endpoint, route.PortForwardProtocolTCP or route.PortForwardProtocolUDP
+	Protocol string
+	Addr     netip.Addr
+	Port     uint16
+}
+
+// KillSwitchRules is the traffic leaving the host that is allowed while the kill-switch is enabled.
+// Everything else leaving the host is dropped
+type KillSwitchRules struct {
+	// Interface is the NetBird interface, all traffic through it is allowed
+	Interface string
+	// LocalUDPPorts are the ports WireGuard and ICE send from, peers may be reached from them on any address
+	LocalUDPPorts []uint16
+	// Endpoints are the NetBird services
+	Endpoints []KillSwitchEndpoint
+	// DNSServers are the resolvers of the host, they are required to resolve the NetBird services after a restart
+	DNSServers []netip.Addr
+	// Gateway is the default gateway the WireGuard port is mapped on with PCP, NAT-PMP or UPnP, it is invalid if
+	// there is none. The port mapping requests to it and the UPnP discovery are allowed
+	Gateway netip.Addr
+}
+
+// KillSwitch blocks the traffic of the host that doesn't go through the NetBird interface or to the NetBird
+// services, so that nothing leaks to the local network when the tunnel drops.
+// The rules stay in place when the client stops and have to be removed explicitly
+type KillSwitch interface {
+	// Enable installs the kill-switch rules, replacing the rules of a previously enabled kill-switch
+	Enable(rules KillSwitchRules) error
+	// Disable removes the kill-switch rules, it is a no-op if the kill-switch isn't enabled
+	Disable() error
+}
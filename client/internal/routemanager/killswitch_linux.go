@@ -0,0 +1,387 @@
+package routemanager
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"sync"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	iptablesOutputChain     = "OUTPUT"
+	iptablesKillSwitchChain = "NETBIRD-KILLSWITCH"
+	killSwitchRuleComment   = "netbird-killswitch"
+	nftablesKillSwitchTable = "netbird-killswitch"
+	nftablesKillSwitchChain = "netbird-killswitch-out"
+	// dhcpServerPort and dhcpv6ServerPort are the ports DHCP clients send their requests to
+	dhcpServerPort   = 67
+	dhcpv6ServerPort = 547
+	dnsPort          = 53
+	loopbackName     = "lo"
+	// portMappingPort is the gateway port of PCP and NAT-PMP, ssdpPort the port of the UPnP discovery
+	portMappingPort = 5351
+	ssdpPort        = 1900
+)
+
+// ssdpMulticastAddr is the address UPnP gateways are discovered on
+var ssdpMulticastAddr = netip.MustParseAddr("239.255.255.250")
+
+var iptablesKillSwitchJumpRule = []string{"-j", iptablesKillSwitchChain, "-m", "comment", "--comment", killSwitchRuleComment}
+
+// NewKillSwitch if supported, returns an iptables kill-switch, otherwise returns a nftables kill-switch
+func NewKillSwitch() KillSwitch {
+	if isIptablesSupported() {
+		ipv4Client, _ := iptables.NewWithProtocol(iptables.ProtocolIPv4)
+		ipv6Client, _ := iptables.NewWithProtocol(iptables.ProtocolIPv6)
+		return &iptablesKillSwitch{ipv4Client: ipv4Client, ipv6Client: ipv6Client}
+	}
+	return &nftablesKillSwitch{conn: &nftables.Conn{}}
+}
+
+// isFamily returns true if the address belongs to the ip version
+func isFamily(addr netip.Addr, ipVersion string) bool {
+	addr = addr.Unmap()
+	if ipVersion == ipv4 {
+		return addr.Is4()
+	}
+	return addr.Is6()
+}
+
+type iptablesKillSwitch struct {
+	ipv4Client *iptables.IPTables
+	ipv6Client *iptables.IPTables
+	mux        sync.Mutex
+}
+
+// Enable fills the kill-switch chain with the rules and jumps to it first from the output chain
+func (k *iptablesKillSwitch) Enable(rules KillSwitchRules) error {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	for ipVersion, client := range map[string]*iptables.IPTables{ipv4: k.ipv4Client, ipv6: k.ipv6Client} {
+		err := client.ClearChain(iptablesFilterTable, iptablesKillSwitchChain)
+		if err != nil {
+			return fmt.Errorf("iptables: failed creating %s chain %s, error: %v", ipVersion, iptablesKillSwitchChain, err)
+		}
+
+		for _, spec := range iptablesKillSwitchRuleSpecs(rules, ipVersion) {
+			err = client.Append(iptablesFilterTable, iptablesKillSwitchChain, spec...)
+			if err != nil {
+				return fmt.Errorf("iptables: failed adding %s kill-switch rule %v, error: %v", ipVersion, spec, err)
+			}
+		}
+
+		exists, err := client.Exists(iptablesFilterTable, iptablesOutputChain, iptablesKillSwitchJumpRule...)
+		if err != nil {
+			return fmt.Errorf("iptables: failed checking %s kill-switch jump rule, error: %v", ipVersion, err)
+		}
+		if !exists {
+			err = client.Insert(iptablesFilterTable, iptablesOutputChain, 1, iptablesKillSwitchJumpRule...)
+			if err != nil {
+				return fmt.Errorf("iptables: failed inserting %s kill-switch jump rule, error: %v", ipVersion, err)
+			}
+		}
+	}
+
+	log.Infof("kill-switch enabled, only traffic through %s and to the NetBird services may leave the host", rules.Interface)
+	return nil
+}
+
+// Disable removes the jump to the kill-switch chain and the chain itself
+func (k *iptablesKillSwitch) Disable() error {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	for ipVersion, client := range map[string]*iptables.IPTables{ipv4: k.ipv4Client, ipv6: k.ipv6Client} {
+		exists, err := client.ChainExists(iptablesFilterTable, iptablesKillSwitchChain)
+		if err != nil {
+			return fmt.Errorf("iptables: failed checking %s chain %s, error: %v", ipVersion, iptablesKillSwitchChain, err)
+		}
+		if !exists {
+			continue
+		}
+
+		err = client.DeleteIfExists(iptablesFilterTable, iptablesOutputChain, iptablesKillSwitchJumpRule...)
+		if err != nil {
+			return fmt.Errorf("iptables: failed removing %s kill-switch jump rule, error: %v", ipVersion, err)
+		}
+		err = client.ClearAndDeleteChain(iptablesFilterTable, iptablesKillSwitchChain)
+		if err != nil {
+			return fmt.Errorf("iptables: failed removing %s chain %s, error: %v", ipVersion, iptablesKillSwitchChain, err)
+		}
+	}
+
+	log.Infof("kill-switch disabled")
+	return nil
+}
+
+// iptablesKillSwitchRuleSpecs returns the rules of the kill-switch chain for the ip version, the last one drops
+// all traffic that hasn't been accepted
+func iptablesKillSwitchRuleSpecs(rules KillSwitchRules, ipVersion string) [][]string {
+	specs := [][]string{{"-o", loopbackName, "-j", "ACCEPT"}}
+	if rules.Interface != "" {
+		specs = append(specs, []string{"-o", rules.Interface, "-j", "ACCEPT"})
+	}
+
+	if ipVersion == ipv4 {
+		specs = append(specs, []string{"-p", "udp", "--dport", strconv.Itoa(dhcpServerPort), "-j", "ACCEPT"})
+	} else {
+		specs = append(specs,
+			[]string{"-p", "udp", "--dport", strconv.Itoa(dhcpv6ServerPort), "-j", "ACCEPT"},
+			// neighbor discovery and router advertisements keep the IPv6 link working
+			[]string{"-p", "ipv6-icmp", "-j", "ACCEPT"},
+		)
+	}
+
+	for _, port := range rules.LocalUDPPorts {
+		specs = append(specs, []string{"-p", "udp", "--sport", strconv.Itoa(int(port)), "-j", "ACCEPT"})
+	}
+
+	for _, endpoint := range rules.Endpoints {
+		if !isFamily(endpoint.Addr, ipVersion) {
+			continue
+		}
+		specs = append(specs, []string{"-p", endpoint.Protocol, "-d", endpoint.Addr.Unmap().String(),
+			"--dport", strconv.Itoa(int(endpoint.Port)), "-j", "ACCEPT"})
+	}
+
+	for _, server := range rules.DNSServers {
+		if !isFamily(server, ipVersion) {
+			continue
+		}
+		for _, protocol := range []string{route.PortForwardProtocolUDP, route.PortForwardProtocolTCP} {
+			specs = append(specs, []string{"-p", protocol, "-d", server.Unmap().String(),
+				"--dport", strconv.Itoa(dnsPort), "-j", "ACCEPT"})
+		}
+	}
+
+	// the UPnP control URL of the gateway may use any TCP port
+	if rules.Gateway.IsValid() && isFamily(rules.Gateway, ipVersion) {
+		gateway := rules.Gateway.Unmap().String()
+		specs = append(specs,
+			[]string{"-p", "udp", "-d", gateway, "--dport", strconv.Itoa(portMappingPort), "-j", "ACCEPT"},
+			[]string{"-p", "udp", "-d", ssdpMulticastAddr.String(), "--dport", strconv.Itoa(ssdpPort), "-j", "ACCEPT"},
+			[]string{"-p", "tcp", "-d", gateway, "-j", "ACCEPT"},
+		)
+	}
+
+	return append(specs, []string{"-j", "DROP"})
+}
+
+type nftablesKillSwitch struct {
+	conn *nftables.Conn
+	mux  sync.Mutex
+}
+
+// Enable replaces the rules of the kill-switch tables in a single transaction, so there is no moment
+// without kill-switch rules while they are updated
+func (k *nftablesKillSwitch) Enable(rules KillSwitchRules) error {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	families := map[string]nftables.TableFamily{ipv4: nftables.TableFamilyIPv4, ipv6: nftables.TableFamilyIPv6}
+	ruleExprs := make(map[string][][]expr.Any, len(families))
+	for ipVersion := range families {
+		exprs, err := nftablesKillSwitchRuleExprs(rules, ipVersion)
+		if err != nil {
+			return err
+		}
+		ruleExprs[ipVersion] = exprs
+	}
+
+	existing, err := k.killSwitchTables()
+	if err != nil {
+		return err
+	}
+	for _, table := range existing {
+		k.conn.FlushTable(table)
+	}
+
+	for ipVersion, family := range families {
+		table := k.conn.AddTable(&nftables.Table{
+			Name:   nftablesKillSwitchTable,
+			Family: family,
+		})
+		chain := k.conn.AddChain(&nftables.Chain{
+			Name:     nftablesKillSwitchChain,
+			Table:    table,
+			Hooknum:  nftables.ChainHookOutput,
+			Priority: nftables.ChainPriorityFilter,
+			Type:     nftables.ChainTypeFilter,
+		})
+
+		for _, exprs := range ruleExprs[ipVersion] {
+			k.conn.AddRule(&nftables.Rule{
+				Table: table,
+				Chain: chain,
+				Exprs: exprs,
+			})
+		}
+	}
+
+	err = k.conn.Flush()
+	if err != nil {
+		return fmt.Errorf("nftables: unable to install the kill-switch rules: %v", err)
+	}
+
+	log.Infof("kill-switch enabled, only traffic through %s and to the NetBird services may leave the host", rules.Interface)
+	return nil
+}
+
+// Disable deletes the kill-switch tables
+func (k *nftablesKillSwitch) Disable() error {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	existing, err := k.killSwitchTables()
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	for _, table := range existing {
+		k.conn.DelTable(table)
+	}
+	err = k.conn.Flush()
+	if err != nil {
+		return fmt.Errorf("nftables: unable to remove the kill-switch rules: %v", err)
+	}
+
+	log.Infof("kill-switch disabled")
+	return nil
+}
+
+func (k *nftablesKillSwitch) killSwitchTables() ([]*nftables.Table, error) {
+	tables, err := k.conn.ListTables()
+	if err != nil {
+		return nil, fmt.Errorf("nftables: unable to list tables: %v", err)
+	}
+
+	var killSwitchTables []*nftables.Table
+	for _, table := range tables {
+		if table.Name == nftablesKillSwitchTable {
+			killSwitchTables = append(killSwitchTables, table)
+		}
+	}
+	return killSwitchTables, nil
+}
+
+// nftablesKillSwitchRuleExprs returns the rules of the kill-switch chain for the ip version, the last one drops
+// all traffic that hasn't been accepted
+func nftablesKillSwitchRuleExprs(rules KillSwitchRules, ipVersion string) ([][]expr.Any, error) {
+	ruleExprs := [][]expr.Any{append(generateOutInterfaceMatcherExpressions(loopbackName), exprCounterAccept...)}
+	if rules.Interface != "" {
+		ruleExprs = append(ruleExprs, append(generateOutInterfaceMatcherExpressions(rules.Interface), exprCounterAccept...))
+	}
+
+	udpExprs, err := generateProtocolMatcherExpressions(route.PortForwardProtocolUDP)
+	if err != nil {
+		return nil, err
+	}
+	portExprs := func(protocol string, direction string, port uint16) ([]expr.Any, error) {
+		protocolExprs, err := generateProtocolMatcherExpressions(protocol)
+		if err != nil {
+			return nil, err
+		}
+		return append(protocolExprs, generatePortMatcherExpressions(direction, port)...), nil
+	}
+
+	if ipVersion == ipv4 {
+		ruleExprs = append(ruleExprs, concatExprs(udpExprs, generatePortMatcherExpressions(exprDirectionDestination, dhcpServerPort), exprCounterAccept))
+	} else {
+		ruleExprs = append(ruleExprs,
+			concatExprs(udpExprs, generatePortMatcherExpressions(exprDirectionDestination, dhcpv6ServerPort), exprCounterAccept),
+			// neighbor discovery and router advertisements keep the IPv6 link working
+			concatExprs([]expr.Any{
+				&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+				&expr.Cmp{Register: 1, Data: []byte{unix.IPPROTO_ICMPV6}},
+			}, exprCounterAccept),
+		)
+	}
+
+	for _, port := range rules.LocalUDPPorts {
+		ruleExprs = append(ruleExprs, concatExprs(udpExprs, generatePortMatcherExpressions(exprDirectionSource, port), exprCounterAccept))
+	}
+
+	for _, endpoint := range rules.Endpoints {
+		if !isFamily(endpoint.Addr, ipVersion) {
+			continue
+		}
+		exprs, err := portExprs(endpoint.Protocol, exprDirectionDestination, endpoint.Port)
+		if err != nil {
+			return nil, err
+		}
+		ruleExprs = append(ruleExprs, concatExprs(generateAddrMatcherExpressions(endpoint.Addr), exprs, exprCounterAccept))
+	}
+
+	for _, server := range rules.DNSServers {
+		if !isFamily(server, ipVersion) {
+			continue
+		}
+		for _, protocol := range []string{route.PortForwardProtocolUDP, route.PortForwardProtocolTCP} {
+			exprs, err := portExprs(protocol, exprDirectionDestination, dnsPort)
+			if err != nil {
+				return nil, err
+			}
+			ruleExprs = append(ruleExprs, concatExprs(generateAddrMatcherExpressions(server), exprs, exprCounterAccept))
+		}
+	}
+
+	// the UPnP control URL of the gateway may use any TCP port
+	if rules.Gateway.IsValid() && isFamily(rules.Gateway, ipVersion) {
+		tcpExprs, err := generateProtocolMatcherExpressions(route.PortForwardProtocolTCP)
+		if err != nil {
+			return nil, err
+		}
+		ruleExprs = append(ruleExprs,
+			concatExprs(generateAddrMatcherExpressions(rules.Gateway), udpExprs,
+				generatePortMatcherExpressions(exprDirectionDestination, portMappingPort), exprCounterAccept),
+			concatExprs(generateAddrMatcherExpressions(ssdpMulticastAddr), udpExprs,
+				generatePortMatcherExpressions(exprDirectionDestination, ssdpPort), exprCounterAccept),
+			concatExprs(generateAddrMatcherExpressions(rules.Gateway), tcpExprs, exprCounterAccept),
+		)
+	}
+
+	return append(ruleExprs, []expr.Any{&expr.Counter{}, &expr.Verdict{Kind: expr.VerdictDrop}}), nil
+}
+
+// generateOutInterfaceMatcherExpressions generates nftables expressions that matches the output interface
+func generateOutInterfaceMatcherExpressions(name string) []expr.Any {
+	ifname := make([]byte, unix.IFNAMSIZ)
+	copy(ifname, name)
+
+	return []expr.Any{
+		&expr.Meta{
+			Key:      expr.MetaKeyOIFNAME,
+			Register: 1,
+		},
+		&expr.Cmp{
+			Register: 1,
+			Data:     ifname,
+		},
+	}
+}
+
+// generateAddrMatcherExpressions generates nftables expressions that matches a destination address
+func generateAddrMatcherExpressions(addr netip.Addr) []expr.Any {
+	addr = addr.Unmap()
+	return generateCIDRMatcherExpressions(exprDirectionDestination, netip.PrefixFrom(addr, addr.BitLen()).String())
+}
+
+func concatExprs(exprs ...[]expr.Any) []expr.Any {
+	var concatenated []expr.Any
+	for _, e := range exprs {
+		concatenated = append(concatenated, e...)
+	}
+	return concatenated
+}
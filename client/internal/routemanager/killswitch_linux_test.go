@@ -0,0 +1,86 @@
+package routemanager
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/nftables/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+var testKillSwitchRules = KillSwitchRules{
+	Interface:     "wt0",
+	LocalUDPPorts: []uint16{51820},
+	Endpoints: []KillSwitchEndpoint{
+		{Protocol: route.PortForwardProtocolTCP, Addr: netip.MustParseAddr("192.0.2.10"), Port: 443},
+		{Protocol: route.PortForwardProtocolUDP, Addr: netip.MustParseAddr("2001:db8::10"), Port: 3478},
+	},
+	DNSServers: []netip.Addr{netip.MustParseAddr("192.0.2.53")},
+}
+
+func TestIptablesKillSwitchRuleSpecs(t *testing.T) {
+	specs := iptablesKillSwitchRuleSpecs(testKillSwitchRules, ipv4)
+	assert.Equal(t, [][]string{
+		{"-o", "lo", "-j", "ACCEPT"},
+		{"-o", "wt0", "-j", "ACCEPT"},
+		{"-p", "udp", "--dport", "67", "-j", "ACCEPT"},
+		{"-p", "udp", "--sport", "51820", "-j", "ACCEPT"},
+		{"-p", "tcp", "-d", "192.0.2.10", "--dport", "443", "-j", "ACCEPT"},
+		{"-p", "udp", "-d", "192.0.2.53", "--dport", "53", "-j", "ACCEPT"},
+		{"-p", "tcp", "-d", "192.0.2.53", "--dport", "53", "-j", "ACCEPT"},
+		{"-j", "DROP"},
+	}, specs)
+
+	specs = iptablesKillSwitchRuleSpecs(testKillSwitchRules, ipv6)
+	assert.Contains(t, specs, []string{"-p", "udp", "-d", "2001:db8::10", "--dport", "3478", "-j", "ACCEPT"})
+	assert.NotContains(t, specs, []string{"-p", "tcp", "-d", "192.0.2.10", "--dport", "443", "-j", "ACCEPT"},
+		"IPv4 endpoints shouldn't be added to the IPv6 chain")
+	assert.Equal(t, []string{"-j", "DROP"}, specs[len(specs)-1], "the last rule should drop everything else")
+}
+
+func TestIptablesKillSwitchRuleSpecs_Gateway(t *testing.T) {
+	rules := testKillSwitchRules
+	rules.Gateway = netip.MustParseAddr("192.168.1.1")
+
+	specs := iptablesKillSwitchRuleSpecs(rules, ipv4)
+	assert.Equal(t, [][]string{
+		{"-p", "udp", "-d", "192.168.1.1", "--dport", "5351", "-j", "ACCEPT"},
+		{"-p", "udp", "-d", "239.255.255.250", "--dport", "1900", "-j", "ACCEPT"},
+		{"-p", "tcp", "-d", "192.168.1.1", "-j", "ACCEPT"},
+		{"-j", "DROP"},
+	}, specs[len(specs)-4:], "the port mapping requests to the gateway should be allowed")
+
+	specs = iptablesKillSwitchRuleSpecs(rules, ipv6)
+	assert.NotContains(t, specs, []string{"-p", "tcp", "-d", "192.168.1.1", "-j", "ACCEPT"},
+		"the IPv4 gateway shouldn't be added to the IPv6 chain")
+}
+
+func TestNftablesKillSwitchRuleExprs(t *testing.T) {
+	ruleExprs, err := nftablesKillSwitchRuleExprs(testKillSwitchRules, ipv4)
+	require.NoError(t, err)
+	// lo, wt0, dhcp, the local port, one endpoint, dns over udp and tcp and the final drop
+	require.Len(t, ruleExprs, 8)
+	assert.Equal(t, []expr.Any{&expr.Counter{}, &expr.Verdict{Kind: expr.VerdictDrop}}, ruleExprs[len(ruleExprs)-1])
+
+	ruleExprs, err = nftablesKillSwitchRuleExprs(testKillSwitchRules, ipv6)
+	require.NoError(t, err)
+	// lo, wt0, dhcpv6, icmpv6, the local port, one endpoint and the final drop
+	require.Len(t, ruleExprs, 7)
+
+	rules := testKillSwitchRules
+	rules.Gateway = netip.MustParseAddr("192.168.1.1")
+	gatewayRuleExprs, err := nftablesKillSwitchRuleExprs(rules, ipv4)
+	require.NoError(t, err)
+	// PCP and NAT-PMP, the UPnP discovery and the UPnP control
+	assert.Len(t, gatewayRuleExprs, 11)
+	gatewayRuleExprs, err = nftablesKillSwitchRuleExprs(rules, ipv6)
+	require.NoError(t, err)
+	assert.Len(t, gatewayRuleExprs, 7, "the IPv4 gateway shouldn't be added to the IPv6 chain")
+
+	rules.Endpoints = []KillSwitchEndpoint{{Protocol: "sctp", Addr: netip.MustParseAddr("192.0.2.10"), Port: 443}}
+	_, err = nftablesKillSwitchRuleExprs(rules, ipv4)
+	assert.Error(t, err, "unsupported protocols should be rejected")
+}
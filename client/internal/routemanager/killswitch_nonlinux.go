@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package routemanager
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type unimplementedKillSwitch struct{}
+
+func (unimplementedKillSwitch) Enable(KillSwitchRules) error {
+	return fmt.Errorf("the kill-switch isn't supported on %s", runtime.GOOS)
+}
+
+func (unimplementedKillSwitch) Disable() error {
+	return nil
+}
+
+// NewKillSwitch returns an unimplemented kill-switch
+func NewKillSwitch() KillSwitch {
+	return unimplementedKillSwitch{}
+}
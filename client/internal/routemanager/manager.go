@@ -8,13 +8,25 @@ import (
 	"github.com/netbirdio/netbird/iface"
 	"github.com/netbirdio/netbird/route"
 	log "github.com/sirupsen/logrus"
+	"reflect"
 	"runtime"
 	"sync"
+	"time"
+)
+
+const (
+	// DefaultLatencyThreshold is the minimum latency improvement required to switch between routing peers
+	DefaultLatencyThreshold = 20 * time.Millisecond
 )
 
 // Manager is a route manager interface
 type Manager interface {
 	UpdateRoutes(updateSerial uint64, newRoutes []*route.Route) error
+	UpdatePortForwards(newPortForwards []*route.PortForward) error
+	// LatencyUpdated triggers a route recalculation after the latency of the peers was measured
+	LatencyUpdated()
+	// UpdateSettings changes the latency threshold and the overlap policy and reapplies the client routes
+	UpdateSettings(latencyThreshold time.Duration, overlapPolicy OverlapPolicy)
 	Stop()
 }
 
@@ -29,25 +41,59 @@ type DefaultManager struct {
 	statusRecorder *status.Status
 	wgInterface    *iface.WGIface
 	pubKey         string
+	// routerPeers holds the public keys of the peers routing the client networks
+	routerPeers      map[string]struct{}
+	latencyThreshold time.Duration
+	overlapPolicy    OverlapPolicy
+	routeSelector    *RouteSelector
+	// updateSerial and clientRoutes hold the last received client routes to reapply them on selection changes
+	updateSerial uint64
+	clientRoutes map[string][]*route.Route
 }
 
-// NewManager returns a new route manager
-func NewManager(ctx context.Context, pubKey string, wgInterface *iface.WGIface, statusRecorder *status.Status) *DefaultManager {
+// NewManager returns a new route manager. The latencyThreshold is the minimum latency improvement required to
+// switch between routing peers of the same network, DefaultLatencyThreshold is used if it is not positive.
+// The overlapPolicy defines how routes overlapping with local networks are handled and the routeSelector which
+// client networks are activated, all networks are activated if it is nil
+func NewManager(ctx context.Context, pubKey string, wgInterface *iface.WGIface, statusRecorder *status.Status, latencyThreshold time.Duration, overlapPolicy OverlapPolicy, routeSelector *RouteSelector) *DefaultManager {
 	mCTX, cancel := context.WithCancel(ctx)
-	return &DefaultManager{
+	if latencyThreshold <= 0 {
+		latencyThreshold = DefaultLatencyThreshold
+	}
+	if overlapPolicy == "" {
+		overlapPolicy = DefaultOverlapPolicy
+	}
+	if routeSelector == nil {
+		routeSelector = NewRouteSelector(nil)
+	}
+	firewall := NewFirewall(ctx)
+	if wgInterface.IsNetstack() {
+		// the in-process network stack doesn't forward traffic of the host
+		firewall = unimplementedFirewall{}
+	}
+	m := &DefaultManager{
 		ctx:            mCTX,
 		stop:           cancel,
 		clientNetworks: make(map[string]*clientNetwork),
 		serverRoutes:   make(map[string]*route.Route),
 		serverRouter: &serverRouter{
 			routes:                   make(map[string]*route.Route),
+			portForwards:             make(map[string]*route.PortForward),
 			netForwardHistoryEnabled: isNetForwardHistoryEnabled(),
-			firewall:                 NewFirewall(ctx),
+			firewall:                 firewall,
 		},
-		statusRecorder: statusRecorder,
-		wgInterface:    wgInterface,
-		pubKey:         pubKey,
+		statusRecorder:   statusRecorder,
+		wgInterface:      wgInterface,
+		pubKey:           pubKey,
+		routerPeers:      make(map[string]struct{}),
+		latencyThreshold: latencyThreshold,
+		overlapPolicy:    overlapPolicy,
+		routeSelector:    routeSelector,
+		clientRoutes:     make(map[string][]*route.Route),
 	}
+	go m.watchRouteSelection()
+	go m.watchRoutesTraffic()
+	return m
 }
 
 // Stop stops the manager watchers and clean firewall rules
@@ -56,7 +102,7 @@ func (m *DefaultManager) Stop() {
 	m.serverRouter.firewall.CleanRoutingRules()
 }
 
-func (m *DefaultManager) updateClientNetworks(updateSerial uint64, networks map[string][]*route.Route) {
+func (m *DefaultManager) updateClientNetworks(updateSerial uint64, networks map[string][]*route.Route, forceSystemRoute map[string]bool) {
 	// removing routes that do not exist as per the update from the Management service.
 	for id, client := range m.clientNetworks {
 		_, found := networks[id]
@@ -67,16 +113,22 @@ func (m *DefaultManager) updateClientNetworks(updateSerial uint64, networks map[
 		}
 	}
 
+	m.routerPeers = make(map[string]struct{})
 	for id, routes := range networks {
+		for _, r := range routes {
+			m.routerPeers[r.Peer] = struct{}{}
+		}
 		clientNetworkWatcher, found := m.clientNetworks[id]
 		if !found {
-			clientNetworkWatcher = newClientNetworkWatcher(m.ctx, m.wgInterface, m.statusRecorder, routes[0].Network)
+			clientNetworkWatcher = newClientNetworkWatcher(m.ctx, id, m.wgInterface, m.statusRecorder, routes[0].Network, m.latencyThreshold)
 			m.clientNetworks[id] = clientNetworkWatcher
 			go clientNetworkWatcher.peersStateAndUpdateWatcher()
 		}
 		update := routesUpdate{
-			updateSerial: updateSerial,
-			routes:       routes,
+			updateSerial:     updateSerial,
+			routes:           routes,
+			forceSystemRoute: forceSystemRoute[id],
+			latencyThreshold: m.latencyThreshold,
 		}
 
 		clientNetworkWatcher.sendUpdateToClientNetworkWatcher(update)
@@ -155,6 +207,10 @@ func (m *DefaultManager) UpdateRoutes(updateSerial uint64, newRoutes []*route.Ro
 					log.Warnf("received a route to manage, but agent doesn't support router mode on %s OS", runtime.GOOS)
 					continue
 				}
+				if m.wgInterface.IsNetstack() {
+					log.Warnf("received a route to manage, but agent doesn't support router mode in netstack mode")
+					continue
+				}
 				newServerRoutesMap[newRoute.ID] = newRoute
 			} else {
 				// if prefix is too small, lets assume is a possible default route which is not yet supported
@@ -169,7 +225,9 @@ func (m *DefaultManager) UpdateRoutes(updateSerial uint64, newRoutes []*route.Ro
 			}
 		}
 
-		m.updateClientNetworks(updateSerial, newClientRoutesIDMap)
+		m.updateSerial = updateSerial
+		m.clientRoutes = newClientRoutesIDMap
+		m.applyClientRoutes()
 
 		err := m.updateServerRoutes(newServerRoutesMap)
 		if err != nil {
@@ -179,3 +237,106 @@ func (m *DefaultManager) UpdateRoutes(updateSerial uint64, newRoutes []*route.Ro
 		return nil
 	}
 }
+
+// applyClientRoutes activates the selected client networks and records the state of all available ones
+func (m *DefaultManager) applyClientRoutes() {
+	routeStates := make([]status.RouteState, 0, len(m.clientRoutes))
+	selectedNetworks := make(map[string][]*route.Route)
+	for id, routes := range m.clientRoutes {
+		selected := m.routeSelector.IsSelected(routes[0].NetID)
+		routeStates = append(routeStates, status.RouteState{
+			ID:       id,
+			NetID:    routes[0].NetID,
+			Network:  routes[0].Network.String(),
+			Selected: selected,
+		})
+		if selected {
+			selectedNetworks[id] = routes
+		}
+	}
+	m.statusRecorder.UpdateRouteStates(routeStates)
+
+	conflicts, forceSystemRoute := m.checkOverlappingNetworks(selectedNetworks)
+	m.updateRouteConflicts(conflicts)
+
+	m.updateClientNetworks(m.updateSerial, selectedNetworks, forceSystemRoute)
+}
+
+// watchRouteSelection reapplies the last received client routes when the route selection changes
+func (m *DefaultManager) watchRouteSelection() {
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-m.routeSelector.GetChangeNotifier():
+			m.mux.Lock()
+			log.Debugf("route selection changed, deselected networks: %s", m.routeSelector.GetDeselectedRoutes())
+			m.applyClientRoutes()
+			m.mux.Unlock()
+		}
+	}
+}
+
+// checkOverlappingNetworks applies the overlap policy to the client networks overlapping with local networks
+func (m *DefaultManager) checkOverlappingNetworks(networks map[string][]*route.Route) ([]status.RouteConflict, map[string]bool) {
+	// the in-process network stack sends all traffic to the tunnel, local networks of the host aren't reachable
+	if len(networks) == 0 || m.wgInterface.IsNetstack() {
+		return nil, nil
+	}
+
+	localNetworks, err := getLocalNetworks(m.wgInterface.Name)
+	if err != nil {
+		log.Warnf("unable to get local networks, skipping route overlap detection: %v", err)
+		return nil, nil
+	}
+
+	return filterOverlappingNetworks(m.overlapPolicy, networks, localNetworks)
+}
+
+// updateRouteConflicts stores the detected route conflicts in the status recorder, logging them if they changed
+func (m *DefaultManager) updateRouteConflicts(conflicts []status.RouteConflict) {
+	if reflect.DeepEqual(conflicts, m.statusRecorder.GetRouteConflicts()) {
+		return
+	}
+
+	for _, conflict := range conflicts {
+		log.Warnf("route %s for network %s overlaps with local network %s on interface %s, route %s by policy %s",
+			conflict.NetID, conflict.Network, conflict.LocalNetwork, conflict.Interface, conflict.Action, m.overlapPolicy)
+	}
+	m.statusRecorder.UpdateRouteConflicts(conflicts)
+}
+
+// LatencyUpdated triggers a route recalculation of the client networks, so routing peers are chosen with the
+// latency measured by the engine
+func (m *DefaultManager) LatencyUpdated() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if len(m.routerPeers) == 0 {
+		return
+	}
+	for _, client := range m.clientNetworks {
+		client.sendLatencyUpdate()
+	}
+}
+
+// UpdateSettings changes the latency threshold and the overlap policy of a running manager. The client routes are
+// reapplied, so the routing peers are chosen and the overlapping networks are handled with the new settings
+func (m *DefaultManager) UpdateSettings(latencyThreshold time.Duration, overlapPolicy OverlapPolicy) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if latencyThreshold <= 0 {
+		latencyThreshold = DefaultLatencyThreshold
+	}
+	if overlapPolicy == "" {
+		overlapPolicy = DefaultOverlapPolicy
+	}
+	if latencyThreshold == m.latencyThreshold && overlapPolicy == m.overlapPolicy {
+		return
+	}
+
+	m.latencyThreshold = latencyThreshold
+	m.overlapPolicy = overlapPolicy
+	m.applyClientRoutes()
+}
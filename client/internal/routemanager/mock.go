@@ -3,12 +3,16 @@ package routemanager
 import (
 	"fmt"
 	"github.com/netbirdio/netbird/route"
+	"time"
 )
 
 // MockManager is the mock instance of a route manager
 type MockManager struct {
-	UpdateRoutesFunc func(updateSerial uint64, newRoutes []*route.Route) error
-	StopFunc         func()
+	UpdateRoutesFunc       func(updateSerial uint64, newRoutes []*route.Route) error
+	UpdatePortForwardsFunc func(newPortForwards []*route.PortForward) error
+	LatencyUpdatedFunc     func()
+	UpdateSettingsFunc     func(latencyThreshold time.Duration, overlapPolicy OverlapPolicy)
+	StopFunc               func()
 }
 
 // UpdateRoutes mock implementation of UpdateRoutes from Manager interface
@@ -19,6 +23,28 @@ func (m *MockManager) UpdateRoutes(updateSerial uint64, newRoutes []*route.Route
 	return fmt.Errorf("method UpdateRoutes is not implemented")
 }
 
+// UpdatePortForwards mock implementation of UpdatePortForwards from Manager interface
+func (m *MockManager) UpdatePortForwards(newPortForwards []*route.PortForward) error {
+	if m.UpdatePortForwardsFunc != nil {
+		return m.UpdatePortForwardsFunc(newPortForwards)
+	}
+	return fmt.Errorf("method UpdatePortForwards is not implemented")
+}
+
+// LatencyUpdated mock implementation of LatencyUpdated from Manager interface
+func (m *MockManager) LatencyUpdated() {
+	if m.LatencyUpdatedFunc != nil {
+		m.LatencyUpdatedFunc()
+	}
+}
+
+// UpdateSettings mock implementation of UpdateSettings from Manager interface
+func (m *MockManager) UpdateSettings(latencyThreshold time.Duration, overlapPolicy OverlapPolicy) {
+	if m.UpdateSettingsFunc != nil {
+		m.UpdateSettingsFunc(latencyThreshold, overlapPolicy)
+	}
+}
+
 // Stop mock implementation of Stop from Manager interface
 func (m *MockManager) Stop() {
 	if m.StopFunc != nil {
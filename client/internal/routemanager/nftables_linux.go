@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"github.com/google/nftables/binaryutil"
 	"github.com/google/nftables/expr"
+	"github.com/netbirdio/netbird/route"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 	"net"
 	"net/netip"
 	"sync"
@@ -16,6 +18,7 @@ const (
 	nftablesTable                  = "netbird-rt"
 	nftablesRoutingForwardingChain = "netbird-rt-fwd"
 	nftablesRoutingNatChain        = "netbird-rt-nat"
+	nftablesRoutingDnatChain       = "netbird-rt-dnat"
 )
 
 // constants needed to create nftable rules
@@ -28,6 +31,9 @@ const (
 	ipv6DestOffset           = 24
 	exprDirectionSource      = "source"
 	exprDirectionDestination = "destination"
+	transportSrcPortOffset   = 0
+	transportDestPortOffset  = 2
+	transportPortLen         = 2
 )
 
 // some presets for building nftable rules
@@ -187,6 +193,26 @@ func (n *nftablesManager) RestoreOrCreateContainers() error {
 		})
 	}
 
+	if _, found := n.chains[ipv4][nftablesRoutingDnatChain]; !found {
+		n.chains[ipv4][nftablesRoutingDnatChain] = n.conn.AddChain(&nftables.Chain{
+			Name:     nftablesRoutingDnatChain,
+			Table:    n.tableIPv4,
+			Hooknum:  nftables.ChainHookPrerouting,
+			Priority: nftables.ChainPriorityNATDest,
+			Type:     nftables.ChainTypeNAT,
+		})
+	}
+
+	if _, found := n.chains[ipv6][nftablesRoutingDnatChain]; !found {
+		n.chains[ipv6][nftablesRoutingDnatChain] = n.conn.AddChain(&nftables.Chain{
+			Name:     nftablesRoutingDnatChain,
+			Table:    n.tableIPv6,
+			Hooknum:  nftables.ChainHookPrerouting,
+			Priority: nftables.ChainPriorityNATDest,
+			Type:     nftables.ChainTypeNAT,
+		})
+	}
+
 	err = n.refreshRulesMap()
 	if err != nil {
 		return err
@@ -383,6 +409,238 @@ func (n *nftablesManager) removeRoutingRule(format string, pair routerPair) erro
 	return nil
 }
 
+// GetRoutingRulesCounters returns the counters of the forwarding rules indexed by route ID
+func (n *nftablesManager) GetRoutingRulesCounters() (map[string]routeCounters, error) {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	counters := make(map[string]routeCounters)
+	for _, registeredChains := range n.chains {
+		chain, found := registeredChains[nftablesRoutingForwardingChain]
+		if !found {
+			continue
+		}
+		rules, err := n.conn.GetRules(chain.Table, chain)
+		if err != nil {
+			return nil, fmt.Errorf("nftables: unable to list rules: %v", err)
+		}
+		for _, rule := range rules {
+			if len(rule.UserData) == 0 {
+				continue
+			}
+			for _, e := range rule.Exprs {
+				counter, ok := e.(*expr.Counter)
+				if ok {
+					addRuleCounters(counters, string(rule.UserData), counter.Packets, counter.Bytes)
+				}
+			}
+		}
+	}
+	return counters, nil
+}
+
+// InsertPortForwardRules inserts the port forward destination nat rule, the forwarding rules for both directions and
+// the nat rule that makes the target reply through the routing peer
+func (n *nftablesManager) InsertPortForwardRules(rule portForwardRule) error {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	err := n.refreshRulesMap()
+	if err != nil {
+		return err
+	}
+
+	protocolExp, err := generateProtocolMatcherExpressions(rule.protocol)
+	if err != nil {
+		return err
+	}
+
+	targetPrefix := netip.PrefixFrom(rule.target.Addr(), rule.target.Addr().BitLen()).String()
+	natFamily := uint32(unix.NFPROTO_IPV4)
+	if rule.target.Addr().Is6() {
+		natFamily = unix.NFPROTO_IPV6
+	}
+
+	dnatExp := concatExprs(
+		generateInInterfaceNotMatcherExpressions(rule.meshInterface),
+		generateLocalDestinationMatcherExpressions(),
+		protocolExp,
+		generatePortMatcherExpressions(exprDirectionDestination, rule.externalPort),
+	)
+	dnatExp = append(dnatExp,
+		&expr.Immediate{
+			Register: 1,
+			Data:     rule.target.Addr().AsSlice(),
+		},
+		&expr.Immediate{
+			Register: 2,
+			Data:     binaryutil.BigEndian.PutUint16(rule.target.Port()),
+		},
+		&expr.Counter{},
+		&expr.NAT{
+			Type:        expr.NATTypeDestNAT,
+			Family:      natFamily,
+			RegAddrMin:  1,
+			RegProtoMin: 2,
+		},
+	)
+
+	targetExp := func(direction string) []expr.Any {
+		exp := append([]expr.Any{}, protocolExp...)
+		exp = append(exp, generateCIDRMatcherExpressions(direction, targetPrefix)...)
+		return append(exp, generatePortMatcherExpressions(direction, rule.target.Port())...)
+	}
+
+	n.insertPortForwardRule(genKey(dnatFormat, rule.ID), nftablesRoutingDnatChain, rule, dnatExp)
+	n.insertPortForwardRule(genKey(dnatForwardingFormat, rule.ID), nftablesRoutingForwardingChain, rule,
+		append(targetExp(exprDirectionDestination), exprCounterAccept...))
+	n.insertPortForwardRule(genKey(inDnatForwardingFormat, rule.ID), nftablesRoutingForwardingChain, rule,
+		append(targetExp(exprDirectionSource), exprCounterAccept...))
+	n.insertPortForwardRule(genKey(dnatNatFormat, rule.ID), nftablesRoutingNatChain, rule,
+		append(targetExp(exprDirectionDestination), &expr.Counter{}, &expr.Masq{}))
+
+	err = n.conn.Flush()
+	if err != nil {
+		return fmt.Errorf("nftables: unable to insert port forward rules for %s: %v", rule.target, err)
+	}
+	return nil
+}
+
+// insertPortForwardRule inserts a nftable port forward rule to the conn client flush queue, replacing the existing
+// rule with the same key
+func (n *nftablesManager) insertPortForwardRule(ruleKey, chain string, rule portForwardRule, expression []expr.Any) {
+	if existingRule, exists := n.rules[ruleKey]; exists {
+		err := n.conn.DelRule(existingRule)
+		if err != nil {
+			log.Debugf("nftables: unable to remove existing port forward rule %s: %v", ruleKey, err)
+		}
+		delete(n.rules, ruleKey)
+	}
+
+	table, ipVersion := n.tableIPv4, ipv4
+	if rule.target.Addr().Is6() {
+		table, ipVersion = n.tableIPv6, ipv6
+	}
+
+	n.rules[ruleKey] = n.conn.InsertRule(&nftables.Rule{
+		Table:    table,
+		Chain:    n.chains[ipVersion][chain],
+		Exprs:    expression,
+		UserData: []byte(ruleKey),
+	})
+}
+
+// RemovePortForwardRules removes the port forward rules from the dnat, forwarding and nat chains
+func (n *nftablesManager) RemovePortForwardRules(rule portForwardRule) error {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	err := n.refreshRulesMap()
+	if err != nil {
+		return err
+	}
+
+	for _, format := range []string{dnatFormat, dnatForwardingFormat, inDnatForwardingFormat, dnatNatFormat} {
+		ruleKey := genKey(format, rule.ID)
+		existingRule, found := n.rules[ruleKey]
+		if !found {
+			continue
+		}
+		err = n.conn.DelRule(existingRule)
+		if err != nil {
+			return fmt.Errorf("nftables: unable to remove port forward rule %s for %s: %v", ruleKey, rule.target, err)
+		}
+		delete(n.rules, ruleKey)
+	}
+
+	err = n.conn.Flush()
+	if err != nil {
+		return fmt.Errorf("nftables: received error while applying port forward rule removal for %s: %v", rule.target, err)
+	}
+	log.Debugf("nftables: removed port forward rules for %s", rule.target)
+	return nil
+}
+
+// generateProtocolMatcherExpressions generates nftables expressions that matches the transport protocol
+// generateInInterfaceNotMatcherExpressions generates nftables expressions that matches traffic not received on the
+// given interface
+func generateInInterfaceNotMatcherExpressions(name string) []expr.Any {
+	ifname := make([]byte, unix.IFNAMSIZ)
+	copy(ifname, name)
+
+	return []expr.Any{
+		&expr.Meta{
+			Key:      expr.MetaKeyIIFNAME,
+			Register: 1,
+		},
+		&expr.Cmp{
+			Op:       expr.CmpOpNeq,
+			Register: 1,
+			Data:     ifname,
+		},
+	}
+}
+
+// generateLocalDestinationMatcherExpressions generates nftables expressions that matches traffic addressed to
+// one of the local addresses, like fib daddr type local
+func generateLocalDestinationMatcherExpressions() []expr.Any {
+	return []expr.Any{
+		&expr.Fib{
+			Register:       1,
+			FlagDADDR:      true,
+			ResultADDRTYPE: true,
+		},
+		&expr.Cmp{
+			Register: 1,
+			Data:     binaryutil.NativeEndian.PutUint32(unix.RTN_LOCAL),
+		},
+	}
+}
+
+func generateProtocolMatcherExpressions(protocol string) ([]expr.Any, error) {
+	var protoNum byte
+	switch protocol {
+	case route.PortForwardProtocolTCP:
+		protoNum = unix.IPPROTO_TCP
+	case route.PortForwardProtocolUDP:
+		protoNum = unix.IPPROTO_UDP
+	default:
+		return nil, fmt.Errorf("nftables: unsupported port forward protocol %s", protocol)
+	}
+
+	return []expr.Any{
+		&expr.Meta{
+			Key:      expr.MetaKeyL4PROTO,
+			Register: 1,
+		},
+		&expr.Cmp{
+			Register: 1,
+			Data:     []byte{protoNum},
+		},
+	}, nil
+}
+
+// generatePortMatcherExpressions generates nftables expressions that matches a transport port
+func generatePortMatcherExpressions(direction string, port uint16) []expr.Any {
+	offset := uint32(transportDestPortOffset)
+	if direction == exprDirectionSource {
+		offset = transportSrcPortOffset
+	}
+
+	return []expr.Any{
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseTransportHeader,
+			Offset:       offset,
+			Len:          transportPortLen,
+		},
+		&expr.Cmp{
+			Register: 1,
+			Data:     binaryutil.BigEndian.PutUint16(port),
+		},
+	}
+}
+
 // getPayloadDirectives get expression directives based on ip version and direction
 func getPayloadDirectives(direction string, isIPv4 bool, isIPv6 bool) (uint32, uint32, []byte) {
 	switch {
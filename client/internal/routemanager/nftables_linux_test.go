@@ -29,8 +29,8 @@ func TestNftablesManager_RestoreOrCreateContainers(t *testing.T) {
 	require.NoError(t, err, "shouldn't return error")
 
 	require.Len(t, manager.chains, 2, "should have created chains for ipv4 and ipv6")
-	require.Len(t, manager.chains[ipv4], 2, "should have created chains for ipv4")
-	require.Len(t, manager.chains[ipv4], 2, "should have created chains for ipv6")
+	require.Len(t, manager.chains[ipv4], 3, "should have created chains for ipv4")
+	require.Len(t, manager.chains[ipv4], 3, "should have created chains for ipv6")
 	require.Len(t, manager.rules, 2, "should have created rules for ipv4 and ipv6")
 
 	pair := routerPair{
@@ -104,8 +104,8 @@ func TestNftablesManager_RestoreOrCreateContainers(t *testing.T) {
 	require.NoError(t, err, "shouldn't return error")
 
 	require.Len(t, manager.chains, 2, "should have created chains for ipv4 and ipv6")
-	require.Len(t, manager.chains[ipv4], 2, "should have created chains for ipv4")
-	require.Len(t, manager.chains[ipv4], 2, "should have created chains for ipv6")
+	require.Len(t, manager.chains[ipv4], 3, "should have created chains for ipv4")
+	require.Len(t, manager.chains[ipv4], 3, "should have created chains for ipv6")
 	require.Len(t, manager.rules, 6, "should have restored all rules for ipv4 and ipv6")
 
 	foundRule, found := manager.rules[forward4RuleKey]
@@ -331,3 +331,130 @@ func TestNftablesManager_RemoveRoutingRules(t *testing.T) {
 		})
 	}
 }
+
+func TestNftablesManager_PortForwardRules(t *testing.T) {
+
+	for _, testCase := range portForwardRuleTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			manager := &nftablesManager{
+				ctx:    ctx,
+				stop:   cancel,
+				conn:   &nftables.Conn{},
+				chains: make(map[string]map[string]*nftables.Chain),
+				rules:  make(map[string]*nftables.Rule),
+			}
+
+			nftablesTestingClient := &nftables.Conn{}
+
+			defer manager.CleanRoutingRules()
+
+			err := manager.RestoreOrCreateContainers()
+			require.NoError(t, err, "shouldn't return error")
+
+			err = manager.InsertPortForwardRules(testCase.inputRule)
+			require.NoError(t, err, "port forward rules should be inserted")
+
+			expectedChains := map[string]string{
+				genKey(dnatFormat, testCase.inputRule.ID):             nftablesRoutingDnatChain,
+				genKey(dnatForwardingFormat, testCase.inputRule.ID):   nftablesRoutingForwardingChain,
+				genKey(inDnatForwardingFormat, testCase.inputRule.ID): nftablesRoutingForwardingChain,
+				genKey(dnatNatFormat, testCase.inputRule.ID):          nftablesRoutingNatChain,
+			}
+
+			for ruleKey, chainName := range expectedChains {
+				chain := manager.chains[testCase.ipVersion][chainName]
+				rules, err := nftablesTestingClient.GetRules(chain.Table, chain)
+				require.NoError(t, err, "should list rules for %s table and %s chain", chain.Table.Name, chain.Name)
+
+				found := false
+				for _, rule := range rules {
+					if string(rule.UserData) == ruleKey {
+						found = true
+						if ruleKey == genKey(dnatFormat, testCase.inputRule.ID) {
+							require.True(t, containsInInterfaceMatch(rule.Exprs), "dnat rule should match the input interface")
+						}
+					}
+				}
+				require.True(t, found, "rule %s should exist in %s chain", ruleKey, chainName)
+			}
+
+			manager.tableIPv4 = nil
+			manager.tableIPv6 = nil
+			manager.rules = make(map[string]*nftables.Rule)
+
+			err = manager.RestoreOrCreateContainers()
+			require.NoError(t, err, "shouldn't return error")
+
+			for ruleKey := range expectedChains {
+				_, found := manager.rules[ruleKey]
+				require.True(t, found, "rule %s should be restored", ruleKey)
+			}
+
+			err = manager.RemovePortForwardRules(testCase.inputRule)
+			require.NoError(t, err, "port forward rules should be removed")
+
+			for _, registeredChains := range manager.chains {
+				for _, chain := range registeredChains {
+					rules, err := nftablesTestingClient.GetRules(chain.Table, chain)
+					require.NoError(t, err, "should list rules for %s table and %s chain", chain.Table.Name, chain.Name)
+					for _, rule := range rules {
+						_, found := expectedChains[string(rule.UserData)]
+						require.False(t, found, "rule %s should not exist", string(rule.UserData))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNftablesManager_GetRoutingRulesCounters(t *testing.T) {
+
+	for _, testCase := range insertRuleTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			manager := &nftablesManager{
+				ctx:    ctx,
+				stop:   cancel,
+				conn:   &nftables.Conn{},
+				chains: make(map[string]map[string]*nftables.Chain),
+				rules:  make(map[string]*nftables.Rule),
+			}
+
+			defer manager.CleanRoutingRules()
+
+			err := manager.RestoreOrCreateContainers()
+			require.NoError(t, err, "shouldn't return error")
+
+			err = manager.InsertRoutingRules(testCase.inputPair)
+			require.NoError(t, err, "forwarding pair should be inserted")
+
+			counters, err := manager.GetRoutingRulesCounters()
+			require.NoError(t, err, "should read the routing rules counters")
+			require.Len(t, counters, 1, "should only return counters of the inserted route")
+			_, found := counters[testCase.inputPair.ID]
+			require.True(t, found, "should return counters for the inserted route")
+
+			err = manager.RemoveRoutingRules(testCase.inputPair)
+			require.NoError(t, err, "shouldn't return error")
+
+			counters, err = manager.GetRoutingRulesCounters()
+			require.NoError(t, err, "should read the routing rules counters")
+			require.Len(t, counters, 0, "should not return counters of removed routes")
+		})
+	}
+}
+
+// containsInInterfaceMatch checks that the rule matches the input interface. The fib expression matching the local
+// destination can't be checked, as the nftables library doesn't decode it
+func containsInInterfaceMatch(exprs []expr.Any) bool {
+	for _, e := range exprs {
+		meta, ok := e.(*expr.Meta)
+		if ok && meta.Key == expr.MetaKeyIIFNAME {
+			return true
+		}
+	}
+	return false
+}
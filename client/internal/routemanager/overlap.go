@@ -0,0 +1,157 @@
+package routemanager
+
+import (
+	"fmt"
+	"github.com/netbirdio/netbird/client/status"
+	"github.com/netbirdio/netbird/route"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"net/netip"
+	"sort"
+)
+
+// OverlapPolicy defines how routes that overlap with local networks are handled
+type OverlapPolicy string
+
+const (
+	// OverlapPolicySkip skips every route that overlaps with a local network
+	OverlapPolicySkip OverlapPolicy = "skip"
+	// OverlapPolicyPreferLocal applies an overlapping route only if the local network remains preferred,
+	// which is the case when the route is less specific than the local network
+	OverlapPolicyPreferLocal OverlapPolicy = "prefer-local"
+	// OverlapPolicyPreferNetBird applies overlapping routes, taking over traffic of the local network
+	OverlapPolicyPreferNetBird OverlapPolicy = "prefer-netbird"
+
+	// DefaultOverlapPolicy is used when no overlap policy is configured
+	DefaultOverlapPolicy = OverlapPolicyPreferLocal
+)
+
+const (
+	conflictActionSkipped = "skipped"
+	conflictActionApplied = "applied"
+)
+
+// ParseOverlapPolicy parses an overlap policy, an empty value returns DefaultOverlapPolicy
+func ParseOverlapPolicy(policy string) (OverlapPolicy, error) {
+	switch OverlapPolicy(policy) {
+	case "":
+		return DefaultOverlapPolicy, nil
+	case OverlapPolicySkip, OverlapPolicyPreferLocal, OverlapPolicyPreferNetBird:
+		return OverlapPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown route overlap policy %q, supported policies are %s, %s and %s",
+			policy, OverlapPolicySkip, OverlapPolicyPreferLocal, OverlapPolicyPreferNetBird)
+	}
+}
+
+// localNetwork is a network the local machine is directly attached to
+type localNetwork struct {
+	prefix    netip.Prefix
+	ifaceName string
+}
+
+// getLocalNetworks returns the networks of the local interfaces except the loopback and the excluded one
+var getLocalNetworks = func(excludeInterface string) ([]localNetwork, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var networks []localNetwork
+	for _, iface := range interfaces {
+		if iface.Name == excludeInterface || iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			log.Debugf("unable to get addresses of interface %s: %v", iface.Name, err)
+			continue
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip, ok := netip.AddrFromSlice(ipNet.IP)
+			if !ok || ip.Unmap().IsLinkLocalUnicast() {
+				continue
+			}
+			ones, _ := ipNet.Mask.Size()
+			networks = append(networks, localNetwork{
+				prefix:    netip.PrefixFrom(ip.Unmap(), ones).Masked(),
+				ifaceName: iface.Name,
+			})
+		}
+	}
+	return networks, nil
+}
+
+// findOverlappingNetwork returns the first local network that overlaps with the given prefix
+func findOverlappingNetwork(prefix netip.Prefix, localNetworks []localNetwork) (localNetwork, bool) {
+	for _, local := range localNetworks {
+		if local.prefix.Overlaps(prefix) {
+			return local, true
+		}
+	}
+	return localNetwork{}, false
+}
+
+// applyOverlapPolicy decides whether a route overlapping with a local network should be applied
+func applyOverlapPolicy(policy OverlapPolicy, prefix netip.Prefix, local localNetwork) bool {
+	switch policy {
+	case OverlapPolicyPreferNetBird:
+		return true
+	case OverlapPolicyPreferLocal:
+		// the local network keeps its traffic as long as it is more specific than the route
+		return prefix.Bits() < local.prefix.Bits()
+	default:
+		return false
+	}
+}
+
+// filterOverlappingNetworks removes the client networks that overlap with local networks according to the policy.
+// It returns the detected conflicts and the IDs of the applied networks that must take over the system route
+func filterOverlappingNetworks(policy OverlapPolicy, networks map[string][]*route.Route, localNetworks []localNetwork) ([]status.RouteConflict, map[string]bool) {
+	var conflicts []status.RouteConflict
+	forced := make(map[string]bool)
+
+	for id, routes := range networks {
+		prefix := routes[0].Network
+		local, found := findOverlappingNetwork(prefix, localNetworks)
+		if !found {
+			continue
+		}
+
+		action := conflictActionSkipped
+		if applyOverlapPolicy(policy, prefix, local) {
+			action = conflictActionApplied
+			if policy == OverlapPolicyPreferNetBird {
+				forced[id] = true
+			}
+		} else {
+			delete(networks, id)
+		}
+
+		log.Debugf("route %s for network %s overlaps with local network %s on interface %s, applying policy %s: %s",
+			routes[0].NetID, prefix, local.prefix, local.ifaceName, policy, action)
+
+		conflicts = append(conflicts, status.RouteConflict{
+			NetID:        routes[0].NetID,
+			Network:      prefix.String(),
+			LocalNetwork: local.prefix.String(),
+			Interface:    local.ifaceName,
+			Action:       action,
+		})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Network != conflicts[j].Network {
+			return conflicts[i].Network < conflicts[j].Network
+		}
+		return conflicts[i].NetID < conflicts[j].NetID
+	})
+
+	return conflicts, forced
+}
@@ -0,0 +1,125 @@
+package routemanager
+
+import (
+	"github.com/netbirdio/netbird/route"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"testing"
+)
+
+func TestParseOverlapPolicy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedPolicy OverlapPolicy
+		shouldFail     bool
+	}{
+		{
+			name:           "Empty Policy Should Return Default",
+			input:          "",
+			expectedPolicy: DefaultOverlapPolicy,
+		},
+		{
+			name:           "Should Parse Skip",
+			input:          "skip",
+			expectedPolicy: OverlapPolicySkip,
+		},
+		{
+			name:           "Should Parse Prefer NetBird",
+			input:          "prefer-netbird",
+			expectedPolicy: OverlapPolicyPreferNetBird,
+		},
+		{
+			name:       "Should Fail On Unknown Policy",
+			input:      "prefer-remote",
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policy, err := ParseOverlapPolicy(testCase.input)
+			if testCase.shouldFail {
+				require.Error(t, err, "should return error")
+				return
+			}
+			require.NoError(t, err, "should not return error")
+			require.Equal(t, testCase.expectedPolicy, policy, "policy should match")
+		})
+	}
+}
+
+func TestFilterOverlappingNetworks(t *testing.T) {
+	localNetworks := []localNetwork{
+		{prefix: netip.MustParsePrefix("192.168.1.0/24"), ifaceName: "eth0"},
+	}
+
+	testCases := []struct {
+		name              string
+		policy            OverlapPolicy
+		network           netip.Prefix
+		shouldBeApplied   bool
+		shouldBeForced    bool
+		expectedConflicts int
+	}{
+		{
+			name:            "Non Overlapping Network Should Be Applied",
+			policy:          OverlapPolicySkip,
+			network:         netip.MustParsePrefix("10.0.0.0/24"),
+			shouldBeApplied: true,
+		},
+		{
+			name:              "Skip Should Skip Wider Network",
+			policy:            OverlapPolicySkip,
+			network:           netip.MustParsePrefix("192.168.0.0/16"),
+			expectedConflicts: 1,
+		},
+		{
+			name:              "Prefer Local Should Skip Equal Network",
+			policy:            OverlapPolicyPreferLocal,
+			network:           netip.MustParsePrefix("192.168.1.0/24"),
+			expectedConflicts: 1,
+		},
+		{
+			name:              "Prefer Local Should Skip Narrower Network",
+			policy:            OverlapPolicyPreferLocal,
+			network:           netip.MustParsePrefix("192.168.1.128/25"),
+			expectedConflicts: 1,
+		},
+		{
+			name:              "Prefer Local Should Apply Wider Network",
+			policy:            OverlapPolicyPreferLocal,
+			network:           netip.MustParsePrefix("192.168.0.0/16"),
+			shouldBeApplied:   true,
+			expectedConflicts: 1,
+		},
+		{
+			name:              "Prefer NetBird Should Force Equal Network",
+			policy:            OverlapPolicyPreferNetBird,
+			network:           netip.MustParsePrefix("192.168.1.0/24"),
+			shouldBeApplied:   true,
+			shouldBeForced:    true,
+			expectedConflicts: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			networks := map[string][]*route.Route{
+				"net1": {{ID: "route1", NetID: "net1", Network: testCase.network}},
+			}
+
+			conflicts, forced := filterOverlappingNetworks(testCase.policy, networks, localNetworks)
+			require.Len(t, conflicts, testCase.expectedConflicts, "conflicts count should match")
+
+			_, applied := networks["net1"]
+			require.Equal(t, testCase.shouldBeApplied, applied, "network applied state should match")
+			require.Equal(t, testCase.shouldBeForced, forced["net1"], "network forced state should match")
+
+			if testCase.expectedConflicts > 0 {
+				require.Equal(t, "eth0", conflicts[0].Interface, "conflict interface should match")
+				require.Equal(t, "192.168.1.0/24", conflicts[0].LocalNetwork, "conflict local network should match")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package routemanager
+
+import (
+	"fmt"
+	"github.com/netbirdio/netbird/route"
+	log "github.com/sirupsen/logrus"
+	"net/netip"
+	"runtime"
+)
+
+// portForwardRule is the firewall representation of a port forward. Traffic addressed to the routing peer itself
+// on the external port is translated to the target address and port, unless it was received on the mesh interface
+type portForwardRule struct {
+	ID            string
+	protocol      string
+	externalPort  uint16
+	target        netip.AddrPort
+	meshInterface string
+}
+
+func portForwardToRule(portForward *route.PortForward, meshInterface string) portForwardRule {
+	return portForwardRule{
+		ID:            portForward.ID,
+		protocol:      portForward.Protocol,
+		externalPort:  uint16(portForward.ExternalPort),
+		target:        netip.AddrPortFrom(portForward.TargetIP.Unmap(), uint16(portForward.TargetPort)),
+		meshInterface: meshInterface,
+	}
+}
+
+// UpdatePortForwards compares received port forwards with the applied ones and remove, update or add their firewall rules
+func (m *DefaultManager) UpdatePortForwards(newPortForwards []*route.PortForward) error {
+	select {
+	case <-m.ctx.Done():
+		log.Infof("not updating port forwards as context is closed")
+		return m.ctx.Err()
+	default:
+		m.serverRouter.mux.Lock()
+		defer m.serverRouter.mux.Unlock()
+
+		newPortForwardsMap := make(map[string]*route.PortForward)
+		for _, portForward := range newPortForwards {
+			if !portForward.TargetIP.IsValid() {
+				log.Warnf("received port forward %s without a valid target, skipping it", portForward.ID)
+				continue
+			}
+			newPortForwardsMap[portForward.ID] = portForward
+		}
+
+		if len(newPortForwardsMap) > 0 {
+			// only linux is supported for now
+			if runtime.GOOS != "linux" {
+				log.Warnf("received port forwards to manage, but agent doesn't support router mode on %s OS", runtime.GOOS)
+				return nil
+			}
+			if m.wgInterface.IsNetstack() {
+				log.Warnf("received port forwards to manage, but agent doesn't support router mode in netstack mode")
+				return nil
+			}
+
+			err := m.serverRouter.firewall.RestoreOrCreateContainers()
+			if err != nil {
+				return fmt.Errorf("couldn't initialize firewall containers, got err: %v", err)
+			}
+		}
+
+		for id, oldPortForward := range m.serverRouter.portForwards {
+			update, found := newPortForwardsMap[id]
+			if found && update.IsEqual(oldPortForward) {
+				continue
+			}
+
+			err := m.serverRouter.firewall.RemovePortForwardRules(portForwardToRule(oldPortForward, m.wgInterface.Name))
+			if err != nil {
+				log.Errorf("unable to remove port forward id: %s, port %s/%d, got: %v",
+					oldPortForward.ID, oldPortForward.Protocol, oldPortForward.ExternalPort, err)
+			}
+			delete(m.serverRouter.portForwards, id)
+		}
+
+		for id, newPortForward := range newPortForwardsMap {
+			_, found := m.serverRouter.portForwards[id]
+			if found {
+				continue
+			}
+
+			err := m.serverRouter.firewall.InsertPortForwardRules(portForwardToRule(newPortForward, m.wgInterface.Name))
+			if err != nil {
+				log.Errorf("unable to add port forward %s, got: %v", newPortForward.ID, err)
+				continue
+			}
+			m.serverRouter.portForwards[id] = newPortForward
+		}
+
+		if len(m.serverRouter.portForwards) > 0 {
+			err := enableIPForwarding()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package routemanager
+
+import (
+	"sort"
+	"sync"
+)
+
+// RouteSelector keeps track of the client networks deselected by the user.
+// It is shared between the daemon, which changes the selection, and the route manager, which only activates the
+// selected networks. Networks are selected by default, so new networks received from management are activated
+type RouteSelector struct {
+	mux          sync.Mutex
+	deselected   map[string]struct{}
+	changeNotify chan struct{}
+}
+
+// NewRouteSelector returns a new RouteSelector with the given network IDs deselected
+func NewRouteSelector(deselected []string) *RouteSelector {
+	rs := &RouteSelector{
+		deselected:   make(map[string]struct{}),
+		changeNotify: make(chan struct{}),
+	}
+	for _, netID := range deselected {
+		rs.deselected[netID] = struct{}{}
+	}
+	return rs
+}
+
+// IsSelected returns true if the network with the given ID is selected
+func (rs *RouteSelector) IsSelected(netID string) bool {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	_, found := rs.deselected[netID]
+	return !found
+}
+
+// SelectRoutes selects the networks with the given IDs
+func (rs *RouteSelector) SelectRoutes(netIDs []string) {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	for _, netID := range netIDs {
+		delete(rs.deselected, netID)
+	}
+	rs.notify()
+}
+
+// SelectAllRoutes selects all networks
+func (rs *RouteSelector) SelectAllRoutes() {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	rs.deselected = make(map[string]struct{})
+	rs.notify()
+}
+
+// DeselectRoutes deselects the networks with the given IDs
+func (rs *RouteSelector) DeselectRoutes(netIDs []string) {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	for _, netID := range netIDs {
+		rs.deselected[netID] = struct{}{}
+	}
+	rs.notify()
+}
+
+// GetDeselectedRoutes returns the sorted IDs of the deselected networks
+func (rs *RouteSelector) GetDeselectedRoutes() []string {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	deselected := make([]string, 0, len(rs.deselected))
+	for netID := range rs.deselected {
+		deselected = append(deselected, netID)
+	}
+	sort.Strings(deselected)
+	return deselected
+}
+
+// GetChangeNotifier returns a channel that is closed when the selection changes
+func (rs *RouteSelector) GetChangeNotifier() <-chan struct{} {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	return rs.changeNotify
+}
+
+func (rs *RouteSelector) notify() {
+	close(rs.changeNotify)
+	rs.changeNotify = make(chan struct{})
+}
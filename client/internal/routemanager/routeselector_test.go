@@ -0,0 +1,29 @@
+package routemanager
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRouteSelector(t *testing.T) {
+	rs := NewRouteSelector([]string{"net1"})
+
+	require.False(t, rs.IsSelected("net1"), "network should be deselected")
+	require.True(t, rs.IsSelected("net2"), "new networks should be selected by default")
+
+	notifier := rs.GetChangeNotifier()
+	rs.DeselectRoutes([]string{"net3", "net2"})
+	select {
+	case <-notifier:
+	default:
+		t.Fatal("change notifier should be closed after deselecting routes")
+	}
+	require.Equal(t, []string{"net1", "net2", "net3"}, rs.GetDeselectedRoutes(), "deselected routes should be sorted")
+
+	rs.SelectRoutes([]string{"net2"})
+	require.True(t, rs.IsSelected("net2"), "network should be selected")
+	require.Equal(t, []string{"net1", "net3"}, rs.GetDeselectedRoutes())
+
+	rs.SelectAllRoutes()
+	require.Empty(t, rs.GetDeselectedRoutes(), "all networks should be selected")
+}
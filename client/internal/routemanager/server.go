@@ -8,7 +8,8 @@ import (
 )
 
 type serverRouter struct {
-	routes map[string]*route.Route
+	routes       map[string]*route.Route
+	portForwards map[string]*route.PortForward
 	// best effort to keep net forward configuration as it was
 	netForwardHistoryEnabled bool
 	mux                      sync.Mutex
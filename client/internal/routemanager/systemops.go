@@ -56,3 +56,18 @@ func getExistingRIBRouteGateway(prefix netip.Prefix) (net.IP, error) {
 
 	return gateway, nil
 }
+
+// splitPrefix returns the two halves of a prefix, or the prefix itself if it is a single address
+func splitPrefix(prefix netip.Prefix) []netip.Prefix {
+	prefix = prefix.Masked()
+	if prefix.Bits() >= prefix.Addr().BitLen() {
+		return []netip.Prefix{prefix}
+	}
+
+	lower := netip.PrefixFrom(prefix.Addr(), prefix.Bits()+1)
+	upperAddr := prefix.Addr().AsSlice()
+	upperAddr[prefix.Bits()/8] |= 0x80 >> (prefix.Bits() % 8)
+	upper, _ := netip.AddrFromSlice(upperAddr)
+
+	return []netip.Prefix{lower, netip.PrefixFrom(upper, prefix.Bits()+1)}
+}
@@ -2,6 +2,7 @@ package routemanager
 
 import (
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 	"io/ioutil"
 	"net"
 	"net/netip"
@@ -9,6 +10,10 @@ import (
 
 const ipv4ForwardingPath = "/proc/sys/net/ipv4/ip_forward"
 
+// routeProtocol marks the routes added by NetBird, so removing a route never matches a route of another origin
+// like the connected route of a local network
+const routeProtocol = 0x4e
+
 func addToRouteTable(prefix netip.Prefix, addr string) error {
 	_, ipNet, err := net.ParseCIDR(prefix.String())
 	if err != nil {
@@ -26,9 +31,11 @@ func addToRouteTable(prefix netip.Prefix, addr string) error {
 	}
 
 	route := &netlink.Route{
-		Scope: netlink.SCOPE_UNIVERSE,
-		Dst:   ipNet,
-		Gw:    ip,
+		Scope:    netlink.SCOPE_UNIVERSE,
+		Dst:      ipNet,
+		Gw:       ip,
+		Protocol: routeProtocol,
+		Table:    unix.RT_TABLE_MAIN,
 	}
 
 	err = netlink.RouteAdd(route)
@@ -46,8 +53,10 @@ func removeFromRouteTable(prefix netip.Prefix) error {
 	}
 
 	route := &netlink.Route{
-		Scope: netlink.SCOPE_UNIVERSE,
-		Dst:   ipNet,
+		Scope:    netlink.SCOPE_UNIVERSE,
+		Dst:      ipNet,
+		Protocol: routeProtocol,
+		Table:    unix.RT_TABLE_MAIN,
 	}
 
 	err = netlink.RouteDel(route)
@@ -109,3 +109,29 @@ func TestGetExistingRIBRouteGateway(t *testing.T) {
 		t.Fatalf("local ip should match with testing IP: want %s got %s", testingIP, localIP.String())
 	}
 }
+
+func TestSplitPrefix(t *testing.T) {
+	testCases := []struct {
+		prefix   netip.Prefix
+		expected []netip.Prefix
+	}{
+		{
+			prefix:   netip.MustParsePrefix("192.168.1.0/24"),
+			expected: []netip.Prefix{netip.MustParsePrefix("192.168.1.0/25"), netip.MustParsePrefix("192.168.1.128/25")},
+		},
+		{
+			prefix:   netip.MustParsePrefix("10.0.0.0/7"),
+			expected: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("11.0.0.0/8")},
+		},
+		{
+			prefix:   netip.MustParsePrefix("10.0.0.1/32"),
+			expected: []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.prefix.String(), func(t *testing.T) {
+			require.Equal(t, testCase.expected, splitPrefix(testCase.prefix), "split prefixes should match")
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package routemanager
+
+import (
+	"github.com/netbirdio/netbird/client/status"
+	"github.com/netbirdio/netbird/route"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"time"
+)
+
+// routesTrafficInterval is the interval between two readings of the routing rules counters
+const routesTrafficInterval = 30 * time.Second
+
+// watchRoutesTraffic periodically reads the traffic forwarded for the server routes
+func (m *DefaultManager) watchRoutesTraffic() {
+	ticker := time.NewTicker(routesTrafficInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.updateRoutesTraffic()
+		}
+	}
+}
+
+// updateRoutesTraffic reads the counters of the routing rules and stores the traffic of the server routes
+// in the status recorder
+func (m *DefaultManager) updateRoutesTraffic() {
+	m.mux.Lock()
+	serverRoutes := make([]*route.Route, 0, len(m.serverRoutes))
+	for _, serverRoute := range m.serverRoutes {
+		serverRoutes = append(serverRoutes, serverRoute)
+	}
+	m.mux.Unlock()
+
+	if len(serverRoutes) == 0 {
+		m.statusRecorder.UpdateRoutesTraffic(nil)
+		return
+	}
+
+	counters, err := m.serverRouter.firewall.GetRoutingRulesCounters()
+	if err != nil {
+		log.Warnf("unable to read the routing rules counters: %v", err)
+		return
+	}
+
+	m.statusRecorder.UpdateRoutesTraffic(toRoutesTraffic(serverRoutes, counters))
+}
+
+// toRoutesTraffic returns the traffic of the server routes sorted by network ID and network, routes without
+// counters are reported with no traffic
+func toRoutesTraffic(serverRoutes []*route.Route, counters map[string]routeCounters) []status.RouteTraffic {
+	routesTraffic := make([]status.RouteTraffic, 0, len(serverRoutes))
+	for _, serverRoute := range serverRoutes {
+		routeCounter := counters[serverRoute.ID]
+		routesTraffic = append(routesTraffic, status.RouteTraffic{
+			ID:        serverRoute.ID,
+			NetID:     serverRoute.NetID,
+			Network:   serverRoute.Network.String(),
+			TxBytes:   routeCounter.txBytes,
+			TxPackets: routeCounter.txPackets,
+			RxBytes:   routeCounter.rxBytes,
+			RxPackets: routeCounter.rxPackets,
+		})
+	}
+	sort.Slice(routesTraffic, func(i, j int) bool {
+		if routesTraffic[i].NetID != routesTraffic[j].NetID {
+			return routesTraffic[i].NetID < routesTraffic[j].NetID
+		}
+		return routesTraffic[i].Network < routesTraffic[j].Network
+	})
+	return routesTraffic
+}
@@ -0,0 +1,55 @@
+package routemanager
+
+import (
+	"github.com/netbirdio/netbird/client/status"
+	"github.com/netbirdio/netbird/route"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"testing"
+)
+
+func TestToRoutesTraffic(t *testing.T) {
+	serverRoutes := []*route.Route{
+		{
+			ID:      "routeB",
+			NetID:   "netB",
+			Network: netip.MustParsePrefix("10.0.0.0/8"),
+		},
+		{
+			ID:      "routeA",
+			NetID:   "netA",
+			Network: netip.MustParsePrefix("192.168.0.0/16"),
+		},
+	}
+	counters := map[string]routeCounters{
+		"routeA": {
+			txBytes:   1000,
+			txPackets: 10,
+			rxBytes:   2000,
+			rxPackets: 20,
+		},
+		"unknownRoute": {
+			txBytes:   1,
+			txPackets: 1,
+		},
+	}
+
+	expected := []status.RouteTraffic{
+		{
+			ID:        "routeA",
+			NetID:     "netA",
+			Network:   "192.168.0.0/16",
+			TxBytes:   1000,
+			TxPackets: 10,
+			RxBytes:   2000,
+			RxPackets: 20,
+		},
+		{
+			ID:      "routeB",
+			NetID:   "netB",
+			Network: "10.0.0.0/8",
+		},
+	}
+
+	require.Equal(t, expected, toRoutesTraffic(serverRoutes, counters))
+}
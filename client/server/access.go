@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// DefaultDaemonGroup is the group whose members may operate the daemon without being root
+const DefaultDaemonGroup = "netbird"
+
+// peerCredentialsAuthType is the auth type of the PeerCredentials of Unix socket connections
+const peerCredentialsAuthType = "peercred"
+
+// operatorMethods are the daemon RPCs members of the daemon group may call.
+// All other RPCs change the daemon configuration, the routes or the profile, or expose its logs and are root-only
+var operatorMethods = map[string]struct{}{
+	"/daemon.DaemonService/Login":           {},
+	"/daemon.DaemonService/WaitSSOLogin":    {},
+	"/daemon.DaemonService/Up":              {},
+	"/daemon.DaemonService/Status":          {},
+	"/daemon.DaemonService/Down":            {},
+	"/daemon.DaemonService/GetConfig":       {},
+	"/daemon.DaemonService/ListRoutes":      {},
+	"/daemon.DaemonService/SubscribeEvents": {},
+	"/daemon.DaemonService/Ping":            {},
+	"/daemon.DaemonService/TracePeer":       {},
+	"/daemon.DaemonService/ListProfiles":    {},
+}
+
+// readOnlyMethods are the daemon RPCs every user may call if the daemon group doesn't exist. They keep the status
+// of installations from before the daemon group readable until the group is created
+var readOnlyMethods = map[string]struct{}{
+	"/daemon.DaemonService/Status":          {},
+	"/daemon.DaemonService/GetConfig":       {},
+	"/daemon.DaemonService/SubscribeEvents": {},
+}
+
+// TCPAuthorizationWarning is logged when the daemon listens on a TCP address. The callers of a TCP daemon address
+// can't be identified, so every local user may call every RPC
+const TCPAuthorizationWarning = "the daemon listens on a TCP address, callers can't be authorized and every user " +
+	"who can connect to it may change the daemon configuration. Use a unix:// daemon address to restrict the daemon " +
+	"to root and the members of the daemon group"
+
+// PeerCredentials are the credentials of the process connected to the daemon Unix socket
+type PeerCredentials struct {
+	credentials.CommonAuthInfo
+	UID uint32
+	GID uint32
+}
+
+// AuthType returns the auth type of the peer credentials
+func (c *PeerCredentials) AuthType() string {
+	return peerCredentialsAuthType
+}
+
+// peerCredentialsTransport are insecure transport credentials that read the peer credentials of Unix socket
+// connections during the handshake. Connections of other networks have no auth info
+type peerCredentialsTransport struct{}
+
+// NewPeerCredentialsTransport returns server transport credentials reading the peer credentials of the daemon
+// socket clients, they are required by the Authorizer
+func NewPeerCredentialsTransport() credentials.TransportCredentials {
+	return peerCredentialsTransport{}
+}
+
+func (peerCredentialsTransport) ClientHandshake(_ context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return conn, nil, nil
+}
+
+func (peerCredentialsTransport) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return conn, nil, nil
+	}
+
+	creds, err := peerCredentials(unixConn)
+	if err != nil {
+		// the connection is kept, calls are rejected by the Authorizer without credentials
+		log.Warnf("unable to read the credentials of the daemon client: %v", err)
+		return conn, nil, nil
+	}
+	creds.SecurityLevel = credentials.NoSecurity
+	return conn, creds, nil
+}
+
+func (peerCredentialsTransport) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: peerCredentialsAuthType}
+}
+
+func (t peerCredentialsTransport) Clone() credentials.TransportCredentials {
+	return t
+}
+
+func (peerCredentialsTransport) OverrideServerName(string) error {
+	return nil
+}
+
+// Authorizer authorizes daemon RPCs by the peer credentials of the caller.
+// Root and the user the daemon runs as may call every RPC, members of the daemon group only the operatorMethods
+// and without changing the Management service or the pre-shared key on login.
+// If the daemon group doesn't exist, other users may call the readOnlyMethods.
+// Calls over TCP carry no credentials and aren't restricted, a TCP daemon address turns off the authorization,
+// see TCPAuthorizationWarning
+type Authorizer struct {
+	group string
+	// groupID is the ID of the daemon group, it is empty if the group doesn't exist
+	groupID string
+	// lookupGroupIDs returns the IDs of the groups a user is a member of
+	lookupGroupIDs func(uid string) ([]string, error)
+}
+
+// NewAuthorizer returns an Authorizer allowing the members of group to operate the daemon.
+// Only root may call the daemon if the group is empty. If the group doesn't exist, only root may operate the daemon
+// and other users may read its status
+func NewAuthorizer(group string) *Authorizer {
+	authorizer := &Authorizer{group: group, lookupGroupIDs: lookupGroupIDs}
+	if group == "" {
+		return authorizer
+	}
+
+	daemonGroup, err := user.LookupGroup(group)
+	if err != nil {
+		log.Warnf("daemon group %s not found, only root may operate the daemon and other users may read its status. "+
+			"To let users operate the daemon, create the group, add the users to it and restart the daemon, "+
+			"e.g. groupadd %s && usermod -aG %s <user>: %v", group, group, group, err)
+		return authorizer
+	}
+	authorizer.groupID = daemonGroup.Gid
+	log.Infof("members of the group %s may operate the daemon", group)
+	return authorizer
+}
+
+// UnaryInterceptor rejects unauthorized unary calls
+func (a *Authorizer) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := a.authorize(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor rejects unauthorized streaming calls
+func (a *Authorizer) StreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := a.authorize(stream.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authorize authorizes the call of the method, req is nil for streaming calls
+func (a *Authorizer) authorize(ctx context.Context, method string, req interface{}) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return gstatus.Errorf(codes.PermissionDenied, "unable to identify the caller")
+	}
+	if _, ok := p.Addr.(*net.UnixAddr); !ok {
+		return nil
+	}
+
+	creds, ok := p.AuthInfo.(*PeerCredentials)
+	if !ok {
+		return gstatus.Errorf(codes.PermissionDenied, "unable to identify the caller")
+	}
+
+	if creds.UID == 0 || int(creds.UID) == os.Geteuid() {
+		return nil
+	}
+
+	uid := strconv.FormatUint(uint64(creds.UID), 10)
+	if !a.isGroupMember(uid, strconv.FormatUint(uint64(creds.GID), 10)) {
+		if a.groupID == "" && a.group != "" {
+			if _, ok := readOnlyMethods[method]; ok {
+				return nil
+			}
+			return gstatus.Errorf(codes.PermissionDenied, "only root may operate the daemon, "+
+				"the group %s that allows other users doesn't exist", a.group)
+		}
+		if a.groupID == "" {
+			return gstatus.Errorf(codes.PermissionDenied, "only root may call the daemon")
+		}
+		return gstatus.Errorf(codes.PermissionDenied, "user %s isn't allowed to call the daemon, "+
+			"only root and members of the group %s are", uid, a.group)
+	}
+
+	if _, ok := operatorMethods[method]; !ok {
+		return gstatus.Errorf(codes.PermissionDenied, "%s changes the daemon configuration and requires root", method)
+	}
+
+	if login, ok := req.(*proto.LoginRequest); ok &&
+		(login.GetManagementUrl() != "" || login.GetAdminURL() != "" || login.GetPreSharedKey() != "") {
+		return gstatus.Errorf(codes.PermissionDenied, "changing the Management URL, admin URL or pre-shared key on login requires root")
+	}
+	return nil
+}
+
+// isGroupMember returns true if the group of the process or one of the groups of its user is the daemon group
+func (a *Authorizer) isGroupMember(uid, gid string) bool {
+	if a.groupID == "" {
+		return false
+	}
+	if gid == a.groupID {
+		return true
+	}
+
+	groupIDs, err := a.lookupGroupIDs(uid)
+	if err != nil {
+		log.Debugf("unable to look up the groups of user %s: %v", uid, err)
+		return false
+	}
+	for _, groupID := range groupIDs {
+		if groupID == a.groupID {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupGroupIDs(uid string) ([]string, error) {
+	u, err := user.LookupId(uid)
+	if err != nil {
+		return nil, err
+	}
+	return u.GroupIds()
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func callerContext(addr net.Addr, creds *PeerCredentials) context.Context {
+	p := &peer.Peer{Addr: addr}
+	if creds != nil {
+		p.AuthInfo = creds
+	}
+	return peer.NewContext(context.Background(), p)
+}
+
+func TestAuthorizer(t *testing.T) {
+	unixAddr := &net.UnixAddr{Name: "/var/run/netbird.sock", Net: "unix"}
+	tcpAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 41731}
+
+	authorizer := &Authorizer{
+		group:   DefaultDaemonGroup,
+		groupID: "2000",
+		lookupGroupIDs: func(uid string) ([]string, error) {
+			if uid == "1001" {
+				return []string{"1001", "2000"}, nil
+			}
+			return []string{uid}, nil
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		method    string
+		req       interface{}
+		allowed   bool
+		errorCode codes.Code
+	}{
+		{
+			name:    "Root May Change The Config",
+			ctx:     callerContext(unixAddr, &PeerCredentials{UID: 0, GID: 0}),
+			method:  "/daemon.DaemonService/SetConfig",
+			allowed: true,
+		},
+		{
+			name:    "Primary Group Member May Connect",
+			ctx:     callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}),
+			method:  "/daemon.DaemonService/Up",
+			allowed: true,
+		},
+		{
+			name:    "Supplementary Group Member May Query The Status",
+			ctx:     callerContext(unixAddr, &PeerCredentials{UID: 1001, GID: 1001}),
+			method:  "/daemon.DaemonService/Status",
+			allowed: true,
+		},
+		{
+			name:   "Group Member May Not Change The Config",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1001, GID: 1001}),
+			method: "/daemon.DaemonService/SetConfig",
+		},
+		{
+			name:    "Group Member May Log In",
+			ctx:     callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}),
+			method:  "/daemon.DaemonService/Login",
+			req:     &proto.LoginRequest{SetupKey: "A2C8E62B-38F5-4553-B31E-DD66C696CEBB"},
+			allowed: true,
+		},
+		{
+			name:   "Group Member May Not Change The Management URL On Login",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}),
+			method: "/daemon.DaemonService/Login",
+			req:    &proto.LoginRequest{ManagementUrl: "https://attacker.example.com:443"},
+		},
+		{
+			name:   "Group Member May Not Change The Pre-Shared Key On Login",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}),
+			method: "/daemon.DaemonService/Login",
+			req:    &proto.LoginRequest{PreSharedKey: "secret"},
+		},
+		{
+			name:    "Root May Change The Management URL On Login",
+			ctx:     callerContext(unixAddr, &PeerCredentials{UID: 0, GID: 0}),
+			method:  "/daemon.DaemonService/Login",
+			req:     &proto.LoginRequest{ManagementUrl: "https://netbird.example.com:443"},
+			allowed: true,
+		},
+		{
+			name:   "Group Member May Not Follow The Logs",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}),
+			method: "/daemon.DaemonService/FollowLogs",
+		},
+		{
+			name:   "Group Member May Not Select Routes",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}),
+			method: "/daemon.DaemonService/SelectRoutes",
+		},
+		{
+			name:   "Group Member May Not Deselect Routes",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}),
+			method: "/daemon.DaemonService/DeselectRoutes",
+		},
+		{
+			name:   "Group Member May Not Select A Profile",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1001, GID: 1001}),
+			method: "/daemon.DaemonService/SelectProfile",
+		},
+		{
+			name:    "Root May Select A Profile",
+			ctx:     callerContext(unixAddr, &PeerCredentials{UID: 0, GID: 0}),
+			method:  "/daemon.DaemonService/SelectProfile",
+			allowed: true,
+		},
+		{
+			name:   "Other Users Are Rejected",
+			ctx:    callerContext(unixAddr, &PeerCredentials{UID: 1002, GID: 1002}),
+			method: "/daemon.DaemonService/Status",
+		},
+		{
+			name:   "Callers Without Credentials Are Rejected",
+			ctx:    callerContext(unixAddr, nil),
+			method: "/daemon.DaemonService/Status",
+		},
+		{
+			name:    "TCP Callers Aren't Restricted",
+			ctx:     callerContext(tcpAddr, nil),
+			method:  "/daemon.DaemonService/SetConfig",
+			allowed: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := authorizer.authorize(testCase.ctx, testCase.method, testCase.req)
+			if testCase.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, codes.PermissionDenied, gstatus.Code(err))
+		})
+	}
+
+	rootOnly := NewAuthorizer("")
+	err := rootOnly.authorize(callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}), "/daemon.DaemonService/Status", nil)
+	assert.Equal(t, codes.PermissionDenied, gstatus.Code(err), "only root may call the daemon without a group")
+
+	missingGroup := NewAuthorizer("netbird-missing-test-group")
+	err = missingGroup.authorize(callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}), "/daemon.DaemonService/Status", nil)
+	assert.NoError(t, err, "the status should be readable while the daemon group doesn't exist")
+	err = missingGroup.authorize(callerContext(unixAddr, &PeerCredentials{UID: 1000, GID: 2000}), "/daemon.DaemonService/Up", nil)
+	assert.Equal(t, codes.PermissionDenied, gstatus.Code(err), "only root may operate the daemon while the daemon group doesn't exist")
+}
+
+func TestPeerCredentials(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skipf("peer credentials aren't supported on %s", runtime.GOOS)
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := net.Dial("unix", socketPath)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Read(make([]byte, 1))
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, authInfo, err := NewPeerCredentialsTransport().ServerHandshake(conn)
+	require.NoError(t, err)
+	creds, ok := authInfo.(*PeerCredentials)
+	require.True(t, ok, "unix socket connections should have peer credentials")
+	assert.Equal(t, fmt.Sprint(os.Getuid()), fmt.Sprint(creds.UID))
+	assert.Equal(t, peerCredentialsAuthType, creds.AuthType())
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// clientStopTimeout is the time the running client has to stop before a restart is given up
+const clientStopTimeout = 30 * time.Second
+
+// SetConfig validates and persists changes to the client configuration.
+// A running engine is only restarted if a setting it is built with has changed, e.g. the interface, the port,
+// the Management URLs or the keys. The other settings are applied to the running engine
+func (s *Server) SetConfig(_ context.Context, req *proto.SetConfigRequest) (*proto.SetConfigResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(req.GetSettings()) == 0 {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "no settings provided")
+	}
+
+	config := s.config
+	if config == nil {
+		var err error
+		managementURL, adminURL := s.urlOverrides()
+		config, err = internal.ReadConfig(managementURL, adminURL, s.configPath, nil)
+		if errorStatus, ok := gstatus.FromError(err); ok && errorStatus.Code() == codes.NotFound {
+			return nil, gstatus.Errorf(codes.FailedPrecondition, "config doesn't exist, please run login first")
+		} else if err != nil {
+			return nil, gstatus.Errorf(codes.Internal, "unable to read config: %v", err)
+		}
+	}
+
+	newConfig, changed, err := internal.UpdateConfigSettings(config, req.GetSettings())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if !changed {
+		return &proto.SetConfigResponse{}, nil
+	}
+
+	err = internal.WriteOutConfig(s.configPath, newConfig)
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "unable to write config: %v", err)
+	}
+	s.config = newConfig
+	log.Infof("updated client configuration settings")
+
+	if !s.isClientRunning() {
+		return &proto.SetConfigResponse{}, nil
+	}
+
+	if !internal.SettingsRequireRestart(config, newConfig) {
+		err = s.applyEngineSettings(newConfig)
+		if err != nil {
+			return nil, gstatus.Errorf(codes.Internal, "configuration saved, but it couldn't be applied to the engine: %v", err)
+		}
+		return &proto.SetConfigResponse{}, nil
+	}
+
+	err = s.restartClient()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "configuration saved, but the engine couldn't be restarted: %v", err)
+	}
+
+	return &proto.SetConfigResponse{EngineRestarted: true}, nil
+}
+
+// isClientRunning returns true if a client started by Start, Up or SetConfig hasn't stopped yet.
+// The caller must hold the lock
+func (s *Server) isClientRunning() bool {
+	if s.clientDone == nil {
+		return false
+	}
+	select {
+	case <-s.clientDone:
+		return false
+	default:
+		return true
+	}
+}
+
+// stopClient stops the running client and waits until it has stopped. The caller must hold the lock
+func (s *Server) stopClient() error {
+	s.actCancel()
+	return waitForClient(s.clientDone)
+}
+
+// waitForClient waits until the client closing done has stopped, done is nil if no client has been started
+func waitForClient(done chan struct{}) error {
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-time.After(clientStopTimeout):
+		return fmt.Errorf("client didn't stop in %s", clientStopTimeout)
+	}
+}
+
+// restartClient stops the running client and starts it again with the current config.
+// The caller must hold the lock
+func (s *Server) restartClient() error {
+	log.Infof("restarting the engine to apply the configuration changes")
+
+	err := s.stopClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(s.rootCtx)
+	s.actCancel = cancel
+	s.runClient(ctx)
+
+	return nil
+}
+
+// onEngineStarted keeps track of the running engine and applies the settings changed since the client started
+func (s *Server) onEngineStarted(ctx context.Context, engine *internal.Engine) {
+	s.engineMutex.Lock()
+	defer s.engineMutex.Unlock()
+
+	if s.engineConfig != nil {
+		if err := engine.UpdateSettings(s.engineConfig); err != nil {
+			log.Errorf("failed applying the configuration settings to the engine: %v", err)
+		}
+	}
+	s.engine = engine
+
+	go func() {
+		<-ctx.Done()
+		s.engineMutex.Lock()
+		defer s.engineMutex.Unlock()
+		if s.engine == engine {
+			s.engine = nil
+		}
+	}()
+}
+
+// applyEngineSettings applies the settings that don't require a restart to the running client. If the client is
+// still connecting, they are applied once its engine has started
+func (s *Server) applyEngineSettings(config *internal.Config) error {
+	s.engineMutex.Lock()
+	defer s.engineMutex.Unlock()
+
+	s.engineConfig = config
+	if s.engine == nil {
+		return nil
+	}
+	log.Infof("applying the configuration changes to the running engine")
+	return s.engine.UpdateSettings(config)
+}
+
+// resetEngineConfig forgets the settings applied to the previous client, a new client starts with the current config
+func (s *Server) resetEngineConfig() {
+	s.engineMutex.Lock()
+	defer s.engineMutex.Unlock()
+	s.engineConfig = nil
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/anonymize"
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+	"github.com/netbirdio/netbird/client/system"
+)
+
+// maxBundleLogSize is the size of the most recent part of the log file added to a debug bundle
+const maxBundleLogSize = 10 * 1024 * 1024
+
+// redactedConfigFields are the secrets of the client configuration, they are never added to a debug bundle
+var redactedConfigFields = map[string]struct{}{
+	"PrivateKey":      {},
+	"PreSharedKey":    {},
+	"SSHKey":          {},
+	"SSORefreshToken": {},
+}
+
+// DebugBundle creates a debug bundle archive with logs, configuration and network state of the daemon.
+// Secrets are always redacted, public IP addresses and domains are replaced when anonymization is requested
+func (s *Server) DebugBundle(ctx context.Context, req *proto.DebugBundleRequest) (*proto.DebugBundleResponse, error) {
+	s.mutex.Lock()
+	config := s.config
+	logFile := s.logFile
+	s.mutex.Unlock()
+
+	bundle, err := os.CreateTemp("", "netbird.debug.*.zip")
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "unable to create debug bundle file: %v", err)
+	}
+	defer bundle.Close()
+
+	var anonymizer *anonymize.Anonymizer
+	if req.GetAnonymize() {
+		anonymizer = anonymize.NewAnonymizer()
+	}
+
+	zipWriter := zip.NewWriter(bundle)
+
+	files := []struct {
+		name    string
+		content func() (string, error)
+	}{
+		{"status.txt", func() (string, error) { return req.GetStatus(), nil }},
+		{"config.json", func() (string, error) { return configForBundle(config) }},
+		{"client.log", func() (string, error) { return logForBundle(logFile) }},
+		{"wireguard.txt", func() (string, error) { return wireguardForBundle(config) }},
+		{"routes.txt", routesForBundle},
+		{"firewall.txt", firewallForBundle},
+		{"system.txt", func() (string, error) { return systemInfoForBundle(ctx), nil }},
+	}
+
+	for _, file := range files {
+		content, err := file.content()
+		if err != nil {
+			log.Warnf("unable to collect %s for the debug bundle: %v", file.name, err)
+			content = fmt.Sprintf("unable to collect %s: %v\n", file.name, err)
+		}
+		if anonymizer != nil {
+			content = anonymizer.AnonymizeString(content)
+		}
+
+		err = addBundleFile(zipWriter, file.name, content)
+		if err != nil {
+			_ = zipWriter.Close()
+			_ = os.Remove(bundle.Name())
+			return nil, gstatus.Errorf(codes.Internal, "unable to write %s to debug bundle: %v", file.name, err)
+		}
+	}
+
+	err = zipWriter.Close()
+	if err != nil {
+		_ = os.Remove(bundle.Name())
+		return nil, gstatus.Errorf(codes.Internal, "unable to write debug bundle: %v", err)
+	}
+
+	log.Infof("created debug bundle %s", bundle.Name())
+
+	return &proto.DebugBundleResponse{Path: bundle.Name()}, nil
+}
+
+func addBundleFile(zipWriter *zip.Writer, name, content string) error {
+	writer, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(writer, content)
+	return err
+}
+
+// configForBundle returns the client configuration as it is stored in the config file with the secrets redacted
+func configForBundle(config *internal.Config) (string, error) {
+	if config == nil {
+		return "", fmt.Errorf("configuration is not loaded")
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	var fields map[string]interface{}
+	err = json.Unmarshal(raw, &fields)
+	if err != nil {
+		return "", err
+	}
+
+	for field, value := range fields {
+		if _, ok := redactedConfigFields[field]; ok && value != "" {
+			fields[field] = "[redacted]"
+		}
+	}
+
+	output, err := json.MarshalIndent(fields, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(output) + "\n", nil
+}
+
+// logForBundle returns the most recent part of the log file
+func logForBundle(logFile string) (string, error) {
+	if logFile == "" || logFile == "console" {
+		return "", fmt.Errorf("the daemon logs to the console, no log file available")
+	}
+
+	file, err := os.Open(logFile)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if stat.Size() > maxBundleLogSize {
+		_, err = file.Seek(stat.Size()-maxBundleLogSize, io.SeekStart)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	// drop the partial first line when the log was truncated
+	if stat.Size() > maxBundleLogSize {
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+
+	return string(content), nil
+}
+
+// wireguardForBundle returns the state of the WireGuard interface and its peers, without the private key
+func wireguardForBundle(config *internal.Config) (string, error) {
+	if config == nil {
+		return "", fmt.Errorf("configuration is not loaded")
+	}
+
+	wg, err := wgctrl.New()
+	if err != nil {
+		return "", err
+	}
+	defer wg.Close()
+
+	device, err := wg.Device(config.WgIface)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	fmt.Fprintf(&output, "interface: %s\n type: %s\n public key: %s\n listening port: %d\n firewall mark: %d\n",
+		device.Name, device.Type.String(), device.PublicKey.String(), device.ListenPort, device.FirewallMark)
+
+	peers := device.Peers
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].PublicKey.String() < peers[j].PublicKey.String()
+	})
+
+	for _, peer := range peers {
+		endpoint := "-"
+		if peer.Endpoint != nil {
+			endpoint = peer.Endpoint.String()
+		}
+
+		allowedIPs := make([]string, 0, len(peer.AllowedIPs))
+		for _, allowedIP := range peer.AllowedIPs {
+			allowedIPs = append(allowedIPs, allowedIP.String())
+		}
+
+		lastHandshake := "-"
+		if !peer.LastHandshakeTime.IsZero() {
+			lastHandshake = peer.LastHandshakeTime.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(&output, "\npeer: %s\n endpoint: %s\n allowed ips: %s\n latest handshake: %s\n"+
+			" transfer: %d B received, %d B sent\n persistent keepalive: %s\n",
+			peer.PublicKey.String(), endpoint, strings.Join(allowedIPs, ", "), lastHandshake,
+			peer.ReceiveBytes, peer.TransmitBytes, peer.PersistentKeepaliveInterval)
+	}
+
+	return output.String(), nil
+}
+
+// systemInfoForBundle returns the system information reported to the Management service
+func systemInfoForBundle(ctx context.Context) string {
+	info := system.GetInfo(ctx)
+	return fmt.Sprintf(
+		"NetBird version: %s\n"+
+			"OS: %s %s\n"+
+			"Platform: %s\n"+
+			"Kernel: %s\n"+
+			"Core: %s\n"+
+			"Go OS/Arch: %s/%s\n"+
+			"CPUs: %d\n"+
+			"Hostname: %s\n",
+		system.NetbirdVersion(),
+		info.OS, info.OSVersion,
+		info.Platform,
+		info.Kernel,
+		info.Core,
+		info.GoOS, runtime.GOARCH,
+		info.CPUs,
+		info.Hostname,
+	)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// routesForBundle returns the routes of all routing tables and the routing rules
+func routesForBundle() (string, error) {
+	var output bytes.Buffer
+
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{Table: unix.RT_TABLE_UNSPEC}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return "", err
+	}
+
+	output.WriteString("Routes:\n")
+	for _, route := range routes {
+		linkName := "-"
+		if route.LinkIndex > 0 {
+			link, err := netlink.LinkByIndex(route.LinkIndex)
+			if err == nil {
+				linkName = link.Attrs().Name
+			}
+		}
+
+		dst := "default"
+		if route.Dst != nil {
+			dst = route.Dst.String()
+		}
+
+		gw := "-"
+		if route.Gw != nil {
+			gw = route.Gw.String()
+		}
+
+		fmt.Fprintf(&output, " %s via %s dev %s table %d metric %d\n", dst, gw, linkName, route.Table, route.Priority)
+	}
+
+	rules, err := netlink.RuleList(netlink.FAMILY_ALL)
+	if err != nil {
+		return output.String(), err
+	}
+
+	output.WriteString("\nRules:\n")
+	for _, rule := range rules {
+		fmt.Fprintf(&output, " %s\n", rule.String())
+	}
+
+	return output.String(), nil
+}
+
+// firewallForBundle returns the NetBird nftables table and iptables chains
+func firewallForBundle() (string, error) {
+	var output bytes.Buffer
+
+	for _, family := range []string{"ip", "ip6"} {
+		fmt.Fprintf(&output, "nftables %s netbird-rt:\n", family)
+		out, err := exec.Command("nft", "list", "table", family, "netbird-rt").CombinedOutput()
+		if err != nil {
+			fmt.Fprintf(&output, " unavailable: %v %s\n", err, strings.TrimSpace(string(out)))
+		} else {
+			output.Write(out)
+		}
+		output.WriteString("\n")
+	}
+
+	for _, command := range []string{"iptables-save", "ip6tables-save"} {
+		fmt.Fprintf(&output, "%s NetBird chains:\n", command)
+		out, err := exec.Command(command).CombinedOutput()
+		if err != nil {
+			fmt.Fprintf(&output, " unavailable: %v %s\n", err, strings.TrimSpace(string(out)))
+			output.WriteString("\n")
+			continue
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if strings.HasPrefix(line, "*") || strings.Contains(line, "NETBIRD") {
+				output.WriteString(line)
+				output.WriteString("\n")
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String(), nil
+}
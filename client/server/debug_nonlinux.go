@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package server
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// routesForBundle returns the routing table as printed by netstat
+func routesForBundle() (string, error) {
+	out, err := exec.Command("netstat", "-rn").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("netstat failed: %v %s", err, out)
+	}
+	return string(out), nil
+}
+
+// firewallForBundle is only supported on linux, where routing peers manage firewall rules
+func firewallForBundle() (string, error) {
+	return fmt.Sprintf("firewall rules are not managed on %s\n", runtime.GOOS), nil
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal"
+)
+
+func TestConfigForBundle(t *testing.T) {
+	managementURL, err := url.Parse("https://mgmt.example.com:33073")
+	require.NoError(t, err)
+
+	config := &internal.Config{
+		PrivateKey:    "private-key",
+		PreSharedKey:  "",
+		SSHKey:        "ssh-key",
+		ManagementURL: managementURL,
+		WgIface:       "wt0",
+		WgPort:        51820,
+	}
+
+	output, err := configForBundle(config)
+	require.NoError(t, err)
+
+	assert.NotContains(t, output, "private-key")
+	assert.NotContains(t, output, "ssh-key")
+	assert.Contains(t, output, `"PrivateKey": "[redacted]"`)
+	assert.Contains(t, output, `"PreSharedKey": ""`, "empty secrets should be kept empty")
+	assert.Contains(t, output, "mgmt.example.com:33073")
+	assert.Contains(t, output, `"WgIface": "wt0"`)
+}
+
+func TestLogForBundle(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "client.log")
+
+	firstLine := "first line " + strings.Repeat("x", 1024) + "\n"
+	lastLine := "last line\n"
+	content := firstLine + strings.Repeat("y", maxBundleLogSize-len(lastLine)-10) + "\n" + lastLine
+	require.NoError(t, os.WriteFile(logFile, []byte(content), 0600))
+
+	output, err := logForBundle(logFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(output, lastLine), "most recent logs should be included")
+	assert.NotContains(t, output, "first line", "old logs should be dropped")
+	assert.LessOrEqual(t, len(output), maxBundleLogSize)
+
+	_, err = logForBundle("console")
+	assert.Error(t, err)
+}
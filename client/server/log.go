@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const (
+	// recentLogLines is the number of log lines kept in memory and sent first to log followers
+	recentLogLines = 500
+	// logFollowerBuffer is the number of log lines a follower can lag behind before lines are dropped for it
+	logFollowerBuffer = 1000
+)
+
+var (
+	logs                = newLogBroadcaster()
+	installLogsHookOnce sync.Once
+)
+
+// installLogBroadcaster adds the hook that copies the daemon log lines to the log followers, once per process
+func installLogBroadcaster() {
+	installLogsHookOnce.Do(func() {
+		log.AddHook(logs)
+	})
+}
+
+// logBroadcaster is a logrus hook keeping the recent log lines and sending new ones to the followers
+type logBroadcaster struct {
+	mu        sync.Mutex
+	recent    []string
+	next      int
+	followers map[chan string]struct{}
+}
+
+func newLogBroadcaster() *logBroadcaster {
+	return &logBroadcaster{
+		recent:    make([]string, 0, recentLogLines),
+		followers: make(map[chan string]struct{}),
+	}
+}
+
+// Levels returns the levels the hook is fired for, the logger level still applies
+func (b *logBroadcaster) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire formats a log entry as it is written to the log and passes it to the followers.
+// It must not log as it runs inside the logger
+func (b *logBroadcaster) Fire(entry *log.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return err
+	}
+	line = strings.TrimSuffix(line, "\n")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.recent) < recentLogLines {
+		b.recent = append(b.recent, line)
+	} else {
+		b.recent[b.next] = line
+		b.next = (b.next + 1) % recentLogLines
+	}
+
+	for follower := range b.followers {
+		select {
+		case follower <- line:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// subscribe returns the recent log lines and, when following, a channel receiving the lines logged afterwards
+func (b *logBroadcaster) subscribe(follow bool) ([]string, chan string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	recent := make([]string, 0, len(b.recent))
+	recent = append(recent, b.recent[b.next:]...)
+	recent = append(recent, b.recent[:b.next]...)
+
+	if !follow {
+		return recent, nil
+	}
+
+	follower := make(chan string, logFollowerBuffer)
+	b.followers[follower] = struct{}{}
+	return recent, follower
+}
+
+func (b *logBroadcaster) unsubscribe(follower chan string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.followers, follower)
+}
+
+// FollowLogs streams the recent and, when following, the new log lines of the daemon until the client goes away
+func (s *Server) FollowLogs(req *proto.FollowLogsRequest, stream proto.DaemonService_FollowLogsServer) error {
+	recent, follower := logs.subscribe(req.GetFollow())
+	if follower != nil {
+		defer logs.unsubscribe(follower)
+	}
+
+	for _, line := range recent {
+		err := stream.Send(&proto.LogLine{Line: line})
+		if err != nil {
+			return err
+		}
+	}
+
+	if follower == nil {
+		return nil
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case line := <-follower:
+			err := stream.Send(&proto.LogLine{Line: line})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SetLogLevel changes the log level of the daemon. With a duration the previous level is restored once it expires,
+// a new change replaces a pending one and restores the level set before the first temporary change
+func (s *Server) SetLogLevel(_ context.Context, req *proto.SetLogLevelRequest) (*proto.SetLogLevelResponse, error) {
+	level, err := log.ParseLevel(req.GetLevel())
+	if err != nil {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "invalid log level: %v", err)
+	}
+
+	duration := req.GetDuration().AsDuration()
+	if duration < 0 {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "duration can't be negative, got %s", duration)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previousLevel := log.GetLevel()
+	if s.logLevelTimer != nil {
+		s.logLevelTimer.Stop()
+		s.logLevelTimer = nil
+		previousLevel = s.logLevelToRestore
+	}
+	s.logLevelChanges++
+
+	setLogLevel(level)
+	log.Infof("log level changed to %s", level)
+
+	resp := &proto.SetLogLevelResponse{PreviousLevel: previousLevel.String()}
+	if duration == 0 {
+		return resp, nil
+	}
+
+	change := s.logLevelChanges
+	s.logLevelToRestore = previousLevel
+	s.logLevelTimer = time.AfterFunc(duration, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if s.logLevelChanges != change {
+			return
+		}
+		s.logLevelTimer = nil
+		setLogLevel(s.logLevelToRestore)
+		log.Infof("temporary log level expired, log level restored to %s", s.logLevelToRestore)
+	})
+	resp.RevertAt = timestamppb.New(time.Now().Add(duration))
+
+	return resp, nil
+}
+
+// setLogLevel sets the level of the logger, reporting the caller on debug levels as util.InitLog does
+func setLogLevel(level log.Level) {
+	log.SetLevel(level)
+	log.SetReportCaller(level >= log.DebugLevel)
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestLogBroadcaster(t *testing.T) {
+	logger := log.New()
+	logger.SetFormatter(&log.TextFormatter{DisableTimestamp: true})
+	broadcaster := newLogBroadcaster()
+	logger.AddHook(broadcaster)
+
+	for i := 0; i < recentLogLines+5; i++ {
+		logger.Infof("line %d", i)
+	}
+
+	recent, follower := broadcaster.subscribe(false)
+	assert.Nil(t, follower, "no follower channel should be created when not following")
+	require.Len(t, recent, recentLogLines, "only the most recent lines should be kept")
+	assert.Equal(t, `level=info msg="line 5"`, recent[0])
+	assert.Equal(t, fmt.Sprintf(`level=info msg="line %d"`, recentLogLines+4), recent[len(recent)-1])
+
+	_, follower = broadcaster.subscribe(true)
+	defer broadcaster.unsubscribe(follower)
+
+	logger.Debug("not logged at info level")
+	logger.Warn("new line")
+
+	select {
+	case line := <-follower:
+		assert.Equal(t, `level=warning msg="new line"`, line)
+	case <-time.After(time.Second):
+		t.Fatal("follower didn't receive the new line")
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	initialLevel := log.GetLevel()
+	defer log.SetLevel(initialLevel)
+	log.SetLevel(log.InfoLevel)
+
+	s := New(context.Background(), "", "", "", "")
+
+	_, err := s.SetLogLevel(context.Background(), &proto.SetLogLevelRequest{Level: "verbose"})
+	assert.Error(t, err, "invalid levels should be rejected")
+
+	resp, err := s.SetLogLevel(context.Background(), &proto.SetLogLevelRequest{
+		Level:    "debug",
+		Duration: durationpb.New(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "info", resp.GetPreviousLevel())
+	assert.NotNil(t, resp.GetRevertAt())
+	assert.Equal(t, log.DebugLevel, log.GetLevel())
+
+	// a new temporary change restores the level set before the first one
+	resp, err = s.SetLogLevel(context.Background(), &proto.SetLogLevelRequest{
+		Level:    "trace",
+		Duration: durationpb.New(50 * time.Millisecond),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "info", resp.GetPreviousLevel())
+	assert.Equal(t, log.TraceLevel, log.GetLevel())
+
+	assert.Eventually(t, func() bool {
+		return log.GetLevel() == log.InfoLevel
+	}, time.Second, 10*time.Millisecond, "level should be restored once the duration expires")
+
+	resp, err = s.SetLogLevel(context.Background(), &proto.SetLogLevelRequest{Level: "warn"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.GetRevertAt(), "level should be kept without a duration")
+	assert.Equal(t, log.WarnLevel, log.GetLevel())
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentials reads the credentials of the process connected to the Unix socket
+func peerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var xucred *unix.Xucred
+	var credErr error
+	err = rawConn.Control(func(fd uintptr) {
+		xucred, credErr = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if credErr != nil {
+		return nil, fmt.Errorf("get peer credentials: %v", credErr)
+	}
+
+	creds := &PeerCredentials{UID: xucred.Uid}
+	if xucred.Ngroups > 0 {
+		creds.GID = xucred.Groups[0]
+	}
+	return creds, nil
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentials reads the credentials of the process connected to the Unix socket
+func peerCredentials(conn *net.UnixConn) (*PeerCredentials, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var ucred *unix.Ucred
+	var credErr error
+	err = rawConn.Control(func(fd uintptr) {
+		ucred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if credErr != nil {
+		return nil, fmt.Errorf("get peer credentials: %v", credErr)
+	}
+
+	return &PeerCredentials{UID: ucred.Uid, GID: ucred.Gid}, nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// peerCredentials isn't supported on this platform, callers on the Unix socket can't be authorized
+func peerCredentials(*net.UnixConn) (*PeerCredentials, error) {
+	return nil, fmt.Errorf("peer credentials aren't supported on %s", runtime.GOOS)
+}
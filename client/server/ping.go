@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+	nbStatus "github.com/netbirdio/netbird/client/status"
+)
+
+const (
+	defaultPingCount = 4
+	maxPingCount     = 100
+	// pingInterval is the time between two round-trip time probes
+	pingInterval = time.Second
+	// pingTimeout is the time a probe waits for its reply
+	pingTimeout = 2 * time.Second
+)
+
+// Ping reports the connection state of a peer and measures the round-trip time over the tunnel.
+// Probes are sent even if the peer is not connected, an idle peer is connected by the traffic
+func (s *Server) Ping(ctx context.Context, msg *proto.PingRequest) (*proto.PingResponse, error) {
+	statusRecorder := s.getStatusRecorder()
+
+	peerState, found := findPeer(statusRecorder, msg.GetPeer())
+	if !found {
+		return &proto.PingResponse{InNetworkMap: false}, nil
+	}
+
+	addr, err := netip.ParseAddr(peerState.IP)
+	if err != nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "peer %s has no valid NetBird IP: %v", peerState.PubKey, err)
+	}
+
+	count := int(msg.GetCount())
+	if count == 0 {
+		count = defaultPingCount
+	}
+	if count > maxPingCount {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "count can't be greater than %d", maxPingCount)
+	}
+
+	var results []*proto.PingResult
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(pingInterval):
+			}
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		rtt, err := peer.Ping(pingCtx, addr)
+		cancel()
+
+		result := &proto.PingResult{Rtt: durationpb.New(rtt)}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	// the probes may have changed the connection, e.g. connected an idle peer
+	peerState, err = statusRecorder.GetPeer(peerState.PubKey)
+	if err != nil {
+		return &proto.PingResponse{InNetworkMap: false, Results: results}, nil
+	}
+
+	return &proto.PingResponse{
+		InNetworkMap: true,
+		PeerState:    toProtoPeerState(peerState),
+		Results:      results,
+	}, nil
+}
+
+// TracePeer streams the signal and ICE negotiation steps of a peer connection until the client goes away
+func (s *Server) TracePeer(msg *proto.TracePeerRequest, stream proto.DaemonService_TracePeerServer) error {
+	statusRecorder := s.getStatusRecorder()
+
+	peerState, found := findPeer(statusRecorder, msg.GetPeer())
+	if !found {
+		return gstatus.Errorf(codes.NotFound, "peer %s is not in the network map", msg.GetPeer())
+	}
+
+	sub := statusRecorder.SubscribeToPeerTrace(peerState.PubKey)
+	defer statusRecorder.UnsubscribeFromEvents(sub)
+
+	return sendEvents(sub, stream)
+}
+
+// getStatusRecorder returns the status recorder shared with the engine, creating it if the engine didn't start yet
+func (s *Server) getStatusRecorder() *nbStatus.Status {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.statusRecorder == nil {
+		s.statusRecorder = nbStatus.NewRecorder()
+	}
+	return s.statusRecorder
+}
+
+// findPeer looks up a peer by its NetBird IP, WireGuard public key or name. A name matches the peer name
+// case-insensitively, a hostname also matches a fully qualified name starting with it and vice versa
+func findPeer(statusRecorder *nbStatus.Status, target string) (nbStatus.PeerState, bool) {
+	peerState, err := statusRecorder.GetPeer(target)
+	if err == nil {
+		return peerState, true
+	}
+
+	peers := statusRecorder.GetFullStatus().Peers
+	for _, peerState := range peers {
+		if peerState.IP == target {
+			return peerState, true
+		}
+	}
+
+	target = strings.TrimSuffix(target, ".")
+	for _, peerState := range peers {
+		if peerState.Name != "" && strings.EqualFold(peerState.Name, target) {
+			return peerState, true
+		}
+	}
+	for _, peerState := range peers {
+		if peerState.Name != "" && strings.EqualFold(hostnameOf(peerState.Name), hostnameOf(target)) {
+			return peerState, true
+		}
+	}
+	return nbStatus.PeerState{}, false
+}
+
+// hostnameOf returns the first label of a fully qualified domain name
+func hostnameOf(fqdn string) string {
+	hostname, _, _ := strings.Cut(fqdn, ".")
+	return hostname
+}
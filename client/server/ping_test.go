@@ -0,0 +1,44 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbStatus "github.com/netbirdio/netbird/client/status"
+)
+
+func TestFindPeer(t *testing.T) {
+	statusRecorder := nbStatus.NewRecorder()
+	peers := []nbStatus.PeerState{
+		{PubKey: "laptopKey", IP: "100.64.0.10", Name: "Laptop"},
+		{PubKey: "serverKey", IP: "100.64.0.11", Name: "server.office.example"},
+	}
+	for _, peerState := range peers {
+		require.NoError(t, statusRecorder.AddPeer(peerState.PubKey))
+		require.NoError(t, statusRecorder.UpdatePeerState(peerState))
+		require.NoError(t, statusRecorder.UpdatePeerName(peerState.PubKey, peerState.Name))
+	}
+
+	testCases := []struct {
+		target      string
+		expectedKey string
+	}{
+		{target: "laptopKey", expectedKey: "laptopKey"},
+		{target: "100.64.0.11", expectedKey: "serverKey"},
+		{target: "laptop", expectedKey: "laptopKey"},
+		{target: "laptop.office.example.", expectedKey: "laptopKey"},
+		{target: "server.office.example", expectedKey: "serverKey"},
+		{target: "server", expectedKey: "serverKey"},
+		{target: "printer"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.target, func(t *testing.T) {
+			peerState, found := findPeer(statusRecorder, testCase.target)
+			assert.Equal(t, testCase.expectedKey != "", found)
+			assert.Equal(t, testCase.expectedKey, peerState.PubKey)
+		})
+	}
+}
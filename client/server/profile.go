@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// ListProfiles returns the client profiles, the default profile comes first
+func (s *Server) ListProfiles(_ context.Context, _ *proto.ListProfilesRequest) (*proto.ListProfilesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	profiles, err := s.profileManager.ListProfiles()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "unable to list profiles: %v", err)
+	}
+
+	resp := &proto.ListProfilesResponse{}
+	for _, profile := range profiles {
+		resp.Profiles = append(resp.Profiles, toProtoProfile(profile))
+	}
+	return resp, nil
+}
+
+// AddProfile creates a new client profile with its own keys. It has to be logged in once selected
+func (s *Server) AddProfile(_ context.Context, req *proto.AddProfileRequest) (*proto.AddProfileResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	profile, err := s.profileManager.AddProfile(req.GetName(), req.GetManagementUrl())
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("added profile %s for the Management service %s", profile.Name, profile.ManagementURL)
+
+	return &proto.AddProfileResponse{Profile: toProtoProfile(*profile)}, nil
+}
+
+// RemoveProfile deletes a client profile and its keys
+func (s *Server) RemoveProfile(_ context.Context, req *proto.RemoveProfileRequest) (*proto.RemoveProfileResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.profileManager.RemoveProfile(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("removed profile %s", req.GetName())
+
+	return &proto.RemoveProfileResponse{}, nil
+}
+
+// SelectProfile switches the active profile. A running engine is stopped and started again with the keys,
+// Management service and interface of the selected profile
+func (s *Server) SelectProfile(_ context.Context, req *proto.SelectProfileRequest) (*proto.SelectProfileResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	active, err := s.profileManager.ActiveProfile()
+	if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "unable to read the active profile: %v", err)
+	}
+	if req.GetName() == active {
+		return &proto.SelectProfileResponse{}, nil
+	}
+
+	configPath, err := s.profileManager.ProfileConfigPath(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.profileManager.SelectProfile(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("switched from profile %s to profile %s", active, req.GetName())
+
+	running := s.isClientRunning()
+	if running {
+		err = s.stopClient()
+		if err != nil {
+			return nil, gstatus.Errorf(codes.Internal, "profile selected, but the engine couldn't be stopped: %v", err)
+		}
+	}
+
+	// the daemon flags configure the default profile only, they are kept for switching back to it
+	s.configPath = configPath
+	s.config = nil
+	s.routeSelector = nil
+	internal.CtxGetState(s.rootCtx).Set(internal.StatusIdle)
+
+	managementURL, adminURL := s.urlOverrides()
+	config, err := internal.ReadConfig(managementURL, adminURL, configPath, nil)
+	if errorStatus, ok := gstatus.FromError(err); ok && errorStatus.Code() == codes.NotFound {
+		// the default profile has no config before the first login
+		return &proto.SelectProfileResponse{}, nil
+	} else if err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "unable to read the config of profile %s: %v", req.GetName(), err)
+	}
+	s.config = config
+
+	if !running {
+		return &proto.SelectProfileResponse{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(s.rootCtx)
+	s.actCancel = cancel
+	s.runClient(ctx)
+
+	return &proto.SelectProfileResponse{EngineRestarted: true}, nil
+}
+
+func toProtoProfile(profile internal.Profile) *proto.Profile {
+	return &proto.Profile{
+		Name:          profile.Name,
+		ConfigFile:    profile.ConfigPath,
+		ManagementUrl: profile.ManagementURL,
+		Active:        profile.Active,
+	}
+}
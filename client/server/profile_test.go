@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestSelectProfile(t *testing.T) {
+	defaultConfigPath := filepath.Join(t.TempDir(), "config.json")
+	ctx := internal.CtxInitState(context.Background())
+	s := New(ctx, "", "", defaultConfigPath, "")
+
+	addResp, err := s.AddProfile(ctx, &proto.AddProfileRequest{Name: "customer", ManagementUrl: "https://netbird.customer.example:443"})
+	require.NoError(t, err)
+
+	selectResp, err := s.SelectProfile(ctx, &proto.SelectProfileRequest{Name: "customer"})
+	require.NoError(t, err)
+	assert.False(t, selectResp.GetEngineRestarted(), "the engine isn't running")
+	assert.Equal(t, addResp.GetProfile().GetConfigFile(), s.configPath)
+	require.NotNil(t, s.config)
+	assert.Equal(t, "https://netbird.customer.example:443", s.config.ManagementURL.String())
+
+	configResp, err := s.GetConfig(ctx, &proto.GetConfigRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "customer", configResp.GetActiveProfile())
+	assert.Equal(t, addResp.GetProfile().GetConfigFile(), configResp.GetConfigFile())
+
+	_, err = s.RemoveProfile(ctx, &proto.RemoveProfileRequest{Name: "customer"})
+	assert.Error(t, err, "the active profile can't be removed")
+
+	// a restarted daemon continues with the selected profile
+	restarted := New(ctx, "", "", defaultConfigPath, "")
+	assert.Equal(t, addResp.GetProfile().GetConfigFile(), restarted.configPath)
+
+	_, err = s.SelectProfile(ctx, &proto.SelectProfileRequest{Name: internal.DefaultProfileName})
+	require.NoError(t, err)
+	assert.Equal(t, defaultConfigPath, s.configPath)
+	assert.Nil(t, s.config, "the default profile isn't logged in")
+
+	listResp, err := s.ListProfiles(ctx, &proto.ListProfilesRequest{})
+	require.NoError(t, err)
+	require.Len(t, listResp.GetProfiles(), 2)
+	assert.True(t, listResp.GetProfiles()[0].GetActive())
+}
+
+func TestSelectProfile_ManagementURLFlag(t *testing.T) {
+	defaultConfigPath := filepath.Join(t.TempDir(), "config.json")
+	ctx := internal.CtxInitState(context.Background())
+	s := New(ctx, "https://netbird.flag.example:443", "", defaultConfigPath, "")
+
+	_, err := s.AddProfile(ctx, &proto.AddProfileRequest{Name: "customer", ManagementUrl: "https://netbird.customer.example:443"})
+	require.NoError(t, err)
+
+	_, err = s.SelectProfile(ctx, &proto.SelectProfileRequest{Name: "customer"})
+	require.NoError(t, err)
+	require.NotNil(t, s.config)
+	assert.Equal(t, "https://netbird.customer.example:443", s.config.ManagementURL.String(), "the flag only applies to the default profile")
+
+	configResp, err := s.GetConfig(ctx, &proto.GetConfigRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://netbird.customer.example:443", configResp.GetManagementUrl())
+
+	_, err = s.SelectProfile(ctx, &proto.SelectProfileRequest{Name: internal.DefaultProfileName})
+	require.NoError(t, err)
+
+	configResp, err = s.GetConfig(ctx, &proto.GetConfigRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://netbird.flag.example:443", configResp.GetManagementUrl(), "the flag is kept for the default profile")
+}
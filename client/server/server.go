@@ -5,6 +5,7 @@ import (
 	"fmt"
 	nbStatus "github.com/netbirdio/netbird/client/status"
 	"github.com/netbirdio/netbird/client/system"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"sync"
 	"time"
@@ -16,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/internal/routemanager"
 	"github.com/netbirdio/netbird/client/proto"
 )
 
@@ -23,11 +25,20 @@ import (
 type Server struct {
 	rootCtx   context.Context
 	actCancel context.CancelFunc
+	// clientDone is closed once the client started by Start, Up or SetConfig has stopped
+	clientDone chan struct{}
 
+	// managementURL and adminURL are set by the daemon flags or the last login of the default profile,
+	// they only apply to the default profile
 	managementURL string
 	adminURL      string
-	configPath    string
-	logFile       string
+	// configPath is the config file of the active profile
+	configPath string
+	// defaultConfigPath is the config file of the default profile
+	defaultConfigPath string
+	logFile           string
+
+	profileManager *internal.ProfileManager
 
 	oauthAuthFlow oauthAuthFlow
 
@@ -36,26 +47,63 @@ type Server struct {
 	proto.UnimplementedDaemonServiceServer
 
 	statusRecorder *nbStatus.Status
+	routeSelector  *routemanager.RouteSelector
+
+	// engineMutex guards engine and engineConfig. It's separate from mutex, as the client starts engines while
+	// a caller holding mutex may wait for it to stop
+	engineMutex sync.Mutex
+	// engine is the running engine, nil while the client isn't connected
+	engine *internal.Engine
+	// engineConfig holds the settings changed without restarting the client, nil if there are none. The engines
+	// started again on reconnects are built from the config the client started with and get these applied
+	engineConfig *internal.Config
+
+	// logLevelToRestore is the level restored once a temporary log level change expires
+	logLevelToRestore log.Level
+	logLevelTimer     *time.Timer
+	// logLevelChanges identifies the latest log level change so that an expired timer doesn't revert a newer one
+	logLevelChanges uint64
 }
 
 type oauthAuthFlow struct {
 	expiresAt  time.Time
 	client     internal.OAuthClient
+	flow       proto.SSOFlow
 	info       internal.DeviceAuthInfo
 	waitCancel context.CancelFunc
 }
 
 // New server instance constructor.
 func New(ctx context.Context, managementURL, adminURL, configPath, logFile string) *Server {
+	installLogBroadcaster()
+
+	profileManager := internal.NewProfileManager(configPath)
+	activeConfigPath, err := profileManager.ActiveProfileConfigPath()
+	if err != nil {
+		log.Warnf("unable to read the active profile, using the default profile: %v", err)
+		activeConfigPath = configPath
+	}
+
 	return &Server{
-		rootCtx:       ctx,
-		managementURL: managementURL,
-		adminURL:      adminURL,
-		configPath:    configPath,
-		logFile:       logFile,
+		rootCtx:           ctx,
+		managementURL:     managementURL,
+		adminURL:          adminURL,
+		configPath:        activeConfigPath,
+		defaultConfigPath: configPath,
+		logFile:           logFile,
+		profileManager:    profileManager,
 	}
 }
 
+// urlOverrides returns the management and admin URLs that override the config of the active profile.
+// The daemon flags only apply to the default profile, the other profiles use the URLs of their config
+func (s *Server) urlOverrides() (string, string) {
+	if s.configPath != s.defaultConfigPath {
+		return "", ""
+	}
+	return s.managementURL, s.adminURL
+}
+
 func (s *Server) Start() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -76,16 +124,22 @@ func (s *Server) Start() error {
 	ctx, cancel := context.WithCancel(s.rootCtx)
 	s.actCancel = cancel
 
+	if s.statusRecorder == nil {
+		s.statusRecorder = nbStatus.NewRecorder()
+	}
+
 	// if configuration exists, we just start connections. if is new config we skip and set status NeedsLogin
 	// on failure we return error to retry
-	config, err := internal.ReadConfig(s.managementURL, s.adminURL, s.configPath, nil)
+	managementURL, adminURL := s.urlOverrides()
+	config, err := internal.ReadConfig(managementURL, adminURL, s.configPath, nil)
 	if errorStatus, ok := gstatus.FromError(err); ok && errorStatus.Code() == codes.NotFound {
-		config, err = internal.GetConfig(s.managementURL, s.adminURL, s.configPath, "")
+		config, err = internal.GetConfig(managementURL, adminURL, s.configPath, "")
 		if err != nil {
 			log.Warnf("unable to create configuration file: %v", err)
 			return err
 		}
 		state.Set(internal.StatusNeedsLogin)
+		s.statusRecorder.PublishEvent(nbStatus.EventLoginRequired, "peer is not registered, login required", nil)
 		return nil
 	} else if err != nil {
 		log.Warnf("unable to create configuration file: %v", err)
@@ -97,12 +151,12 @@ func (s *Server) Start() error {
 
 	s.config = config
 
-	if s.statusRecorder == nil {
-		s.statusRecorder = nbStatus.NewRecorder()
-	}
-
+	s.resetEngineConfig()
+	done := make(chan struct{})
+	s.clientDone = done
 	go func() {
-		if err := internal.RunClient(ctx, config, s.statusRecorder); err != nil {
+		defer close(done)
+		if err := internal.RunClientWithEngineListener(ctx, config, s.statusRecorder, s.getRouteSelector(), s.onEngineStarted); err != nil {
 			log.Errorf("init connections: %v", err)
 		}
 	}()
@@ -152,16 +206,20 @@ func (s *Server) Login(callerCtx context.Context, msg *proto.LoginRequest) (*pro
 	}()
 
 	s.mutex.Lock()
-	managementURL := s.managementURL
+	isDefaultProfile := s.configPath == s.defaultConfigPath
+	managementURL, adminURL := s.urlOverrides()
 	if msg.ManagementUrl != "" {
 		managementURL = msg.ManagementUrl
-		s.managementURL = msg.ManagementUrl
+		if isDefaultProfile {
+			s.managementURL = msg.ManagementUrl
+		}
 	}
 
-	adminURL := s.adminURL
 	if msg.AdminURL != "" {
 		adminURL = msg.AdminURL
-		s.adminURL = msg.AdminURL
+		if isDefaultProfile {
+			s.adminURL = msg.AdminURL
+		}
 	}
 	s.mutex.Unlock()
 
@@ -173,7 +231,9 @@ func (s *Server) Login(callerCtx context.Context, msg *proto.LoginRequest) (*pro
 	if msg.ManagementUrl == "" {
 		config, _ = internal.UpdateOldManagementPort(ctx, config, s.configPath)
 		s.config = config
-		s.managementURL = config.ManagementURL.String()
+		if isDefaultProfile {
+			s.managementURL = config.ManagementURL.String()
+		}
 	}
 
 	s.mutex.Lock()
@@ -188,31 +248,19 @@ func (s *Server) Login(callerCtx context.Context, msg *proto.LoginRequest) (*pro
 	state.Set(internal.StatusConnecting)
 
 	if msg.SetupKey == "" {
-		providerConfig, err := internal.GetDeviceAuthorizationFlowInfo(ctx, config)
+		oAuthClient, err := newOAuthClient(ctx, config, msg.GetSsoFlow())
 		if err != nil {
 			state.Set(internal.StatusLoginFailed)
-			s, ok := gstatus.FromError(err)
-			if ok && s.Code() == codes.NotFound {
-				return nil, gstatus.Errorf(codes.NotFound, "no SSO provider returned from management. "+
-					"If you are using hosting Netbird see documentation at "+
-					"https://github.com/netbirdio/netbird/tree/main/management for details")
-			} else if ok && s.Code() == codes.Unimplemented {
-				return nil, gstatus.Errorf(codes.Unimplemented, "the management server, %s, does not support SSO providers, "+
-					"please update your server or use Setup Keys to login", config.ManagementURL)
-			} else {
-				log.Errorf("getting device authorization flow info failed with error: %v", err)
-				return nil, err
-			}
+			return nil, err
 		}
 
-		hostedClient := internal.NewHostedDeviceFlow(
-			providerConfig.ProviderConfig.Audience,
-			providerConfig.ProviderConfig.ClientID,
-			providerConfig.ProviderConfig.TokenEndpoint,
-			providerConfig.ProviderConfig.DeviceAuthEndpoint,
-		)
+		if s.refreshLogin(ctx, oAuthClient) {
+			state.Set(internal.StatusIdle)
+			return &proto.LoginResponse{}, nil
+		}
 
-		if s.oauthAuthFlow.client != nil && s.oauthAuthFlow.client.GetClientID(ctx) == hostedClient.GetClientID(context.TODO()) {
+		if s.oauthAuthFlow.client != nil && s.oauthAuthFlow.flow == msg.GetSsoFlow() &&
+			s.oauthAuthFlow.client.GetClientID(ctx) == oAuthClient.GetClientID(context.TODO()) {
 			if s.oauthAuthFlow.expiresAt.After(time.Now().Add(90 * time.Second)) {
 				log.Debugf("using previous device flow info")
 				return &proto.LoginResponse{
@@ -227,14 +275,15 @@ func (s *Server) Login(callerCtx context.Context, msg *proto.LoginRequest) (*pro
 			}
 		}
 
-		deviceAuthInfo, err := hostedClient.RequestDeviceCode(context.TODO())
+		deviceAuthInfo, err := oAuthClient.RequestDeviceCode(context.TODO())
 		if err != nil {
 			log.Errorf("getting a request device code failed: %v", err)
 			return nil, err
 		}
 
 		s.mutex.Lock()
-		s.oauthAuthFlow.client = hostedClient
+		s.oauthAuthFlow.client = oAuthClient
+		s.oauthAuthFlow.flow = msg.GetSsoFlow()
 		s.oauthAuthFlow.info = deviceAuthInfo
 		s.oauthAuthFlow.expiresAt = time.Now().Add(time.Duration(deviceAuthInfo.ExpiresIn) * time.Second)
 		s.mutex.Unlock()
@@ -257,6 +306,43 @@ func (s *Server) Login(callerCtx context.Context, msg *proto.LoginRequest) (*pro
 	return &proto.LoginResponse{}, nil
 }
 
+// newOAuthClient returns a client of the requested SSO flow configured with the provider received from management
+func newOAuthClient(ctx context.Context, config *internal.Config, flow proto.SSOFlow) (internal.OAuthClient, error) {
+	var oAuthClient internal.OAuthClient
+	var err error
+	if flow == proto.SSOFlow_PKCE {
+		var pkceFlow internal.PKCEAuthorizationFlow
+		pkceFlow, err = internal.GetPKCEAuthorizationFlowInfo(ctx, config)
+		if err == nil {
+			oAuthClient = internal.NewPKCEFlow(pkceFlow.ProviderConfig)
+		}
+	} else {
+		var providerConfig internal.DeviceAuthorizationFlow
+		providerConfig, err = internal.GetDeviceAuthorizationFlowInfo(ctx, config)
+		if err == nil {
+			oAuthClient = internal.NewHostedDeviceFlow(providerConfig.ProviderConfig)
+		}
+	}
+	if err == nil {
+		return oAuthClient, nil
+	}
+
+	s, ok := gstatus.FromError(err)
+	if ok && s.Code() == codes.NotFound && flow == proto.SSOFlow_PKCE {
+		return nil, gstatus.Errorf(codes.NotFound, "no PKCE SSO provider returned from management. "+
+			"Use the device code flow or ask your administrator to configure the PKCEAuthorizationFlow")
+	} else if ok && s.Code() == codes.NotFound {
+		return nil, gstatus.Errorf(codes.NotFound, "no SSO provider returned from management. "+
+			"If you are using hosting Netbird see documentation at "+
+			"https://github.com/netbirdio/netbird/tree/main/management for details")
+	} else if ok && s.Code() == codes.Unimplemented {
+		return nil, gstatus.Errorf(codes.Unimplemented, "the management server, %s, does not support SSO providers, "+
+			"please update your server or use Setup Keys to login", config.ManagementURL)
+	}
+	log.Errorf("getting %s authorization flow info failed with error: %v", flow, err)
+	return nil, err
+}
+
 // WaitSSOLogin uses the userCode to validate the TokenInfo and
 // waits for the user to continue with the login on a browser
 func (s *Server) WaitSSOLogin(callerCtx context.Context, msg *proto.WaitSSOLoginRequest) (*proto.WaitSSOLoginResponse, error) {
@@ -331,9 +417,48 @@ func (s *Server) WaitSSOLogin(callerCtx context.Context, msg *proto.WaitSSOLogin
 		return nil, err
 	}
 
+	s.storeRefreshToken(tokenInfo.RefreshToken)
+
 	return &proto.WaitSSOLoginResponse{}, nil
 }
 
+// refreshLogin logs in with an access token obtained with the stored refresh token of a previous SSO login.
+// It returns false if the user has to log in interactively
+func (s *Server) refreshLogin(ctx context.Context, oAuthClient internal.OAuthClient) bool {
+	s.mutex.Lock()
+	refreshToken := s.config.SSORefreshToken
+	s.mutex.Unlock()
+	if refreshToken == "" {
+		return false
+	}
+
+	tokenInfo, err := oAuthClient.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		log.Infof("the stored SSO refresh token can't be used, an interactive login is required: %v", err)
+		s.storeRefreshToken("")
+		return false
+	}
+
+	if _, err := s.loginAttempt(ctx, "", tokenInfo.AccessToken); err != nil {
+		return false
+	}
+	log.Infof("logged in with the stored SSO refresh token")
+
+	s.storeRefreshToken(tokenInfo.RefreshToken)
+	return true
+}
+
+// storeRefreshToken persists the refresh token of an SSO login in the config
+func (s *Server) storeRefreshToken(refreshToken string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := internal.UpdateSSORefreshToken(s.config, refreshToken)
+	if err != nil {
+		log.Warnf("failed storing the SSO refresh token: %v", err)
+	}
+}
+
 // Up starts engine work in the daemon.
 func (s *Server) Up(callerCtx context.Context, msg *proto.UpRequest) (*proto.UpResponse, error) {
 	s.mutex.Lock()
@@ -373,25 +498,56 @@ func (s *Server) Up(callerCtx context.Context, msg *proto.UpRequest) (*proto.UpR
 		s.statusRecorder = nbStatus.NewRecorder()
 	}
 
+	s.runClient(ctx)
+
+	return &proto.UpResponse{}, nil
+}
+
+// runClient starts the client with the current config in the background, the caller must hold the lock
+func (s *Server) runClient(ctx context.Context) {
+	state := internal.CtxGetState(s.rootCtx)
+	config := s.config
+	routeSelector := s.getRouteSelector()
+	s.resetEngineConfig()
+	done := make(chan struct{})
+	s.clientDone = done
 	go func() {
-		if err := internal.RunClient(ctx, s.config, s.statusRecorder); err != nil {
+		defer close(done)
+		if err := internal.RunClientWithEngineListener(ctx, config, s.statusRecorder, routeSelector, s.onEngineStarted); err != nil {
 			log.Errorf("run client connection: %v", state.Wrap(err))
 			return
 		}
 	}()
-
-	return &proto.UpResponse{}, nil
 }
 
 // Down engine work in the daemon.
 func (s *Server) Down(ctx context.Context, msg *proto.DownRequest) (*proto.DownResponse, error) {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
 	if s.actCancel == nil {
+		s.mutex.Unlock()
 		return nil, fmt.Errorf("service is not up")
 	}
-	s.actCancel()
+	cancel, done := s.actCancel, s.clientDone
+	s.mutex.Unlock()
+
+	// the client is stopped without holding the lock, so the other calls aren't blocked while it shuts down.
+	// The kill-switch is only disabled once the client has stopped, a client still starting could enable it again
+	cancel()
+	if err := waitForClient(done); err != nil {
+		return nil, gstatus.Errorf(codes.Internal, "the kill-switch is left enabled: %v", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.isClientRunning() {
+		log.Infof("a client was started while the previous one stopped, the kill-switch is left enabled")
+		return &proto.DownResponse{}, nil
+	}
+
+	if err := internal.DisableKillSwitch(); err != nil {
+		log.Errorf("failed disabling the kill-switch: %v", err)
+	}
 
 	return &proto.DownResponse{}, nil
 }
@@ -424,13 +580,56 @@ func (s *Server) Status(
 	return &statusResponse, nil
 }
 
+// SubscribeEvents streams the state change events of the daemon until the client goes away
+func (s *Server) SubscribeEvents(_ *proto.SubscribeEventsRequest, stream proto.DaemonService_SubscribeEventsServer) error {
+	statusRecorder := s.getStatusRecorder()
+
+	sub := statusRecorder.SubscribeToEvents()
+	defer statusRecorder.UnsubscribeFromEvents(sub)
+
+	return sendEvents(sub, stream)
+}
+
+// eventStream is a server stream of daemon events
+type eventStream interface {
+	Send(*proto.SystemEvent) error
+	Context() context.Context
+}
+
+// sendEvents forwards the events of a subscription to a stream until the client goes away
+func sendEvents(sub *nbStatus.EventSubscription, stream eventStream) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			err := stream.Send(toProtoSystemEvent(event))
+			if err != nil {
+				log.Debugf("unable to send event to subscriber: %v", err)
+				return err
+			}
+		}
+	}
+}
+
+func toProtoSystemEvent(event nbStatus.Event) *proto.SystemEvent {
+	return &proto.SystemEvent{
+		Type:     string(event.Type),
+		Time:     timestamppb.New(event.Time),
+		Message:  event.Message,
+		Metadata: event.Metadata,
+	}
+}
+
 // GetConfig of the daemon.
 func (s *Server) GetConfig(ctx context.Context, msg *proto.GetConfigRequest) (*proto.GetConfigResponse, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	managementURL := s.managementURL
-	adminURL := s.adminURL
+	managementURL, adminURL := s.urlOverrides()
 	preSharedKey := ""
 
 	if s.config != nil {
@@ -449,15 +648,48 @@ func (s *Server) GetConfig(ctx context.Context, msg *proto.GetConfigRequest) (*p
 
 	}
 
+	var settings map[string]string
+	if s.config != nil {
+		settings = internal.GetConfigSettings(s.config)
+	}
+
+	activeProfile, err := s.profileManager.ActiveProfile()
+	if err != nil {
+		log.Warnf("unable to read the active profile: %v", err)
+	}
+
 	return &proto.GetConfigResponse{
 		ManagementUrl: managementURL,
 		AdminURL:      adminURL,
 		ConfigFile:    s.configPath,
 		LogFile:       s.logFile,
 		PreSharedKey:  preSharedKey,
+		Settings:      settings,
+		ActiveProfile: activeProfile,
 	}, nil
 }
 
+func toProtoPeerState(peerState nbStatus.PeerState) *proto.PeerState {
+	return &proto.PeerState{
+		IP:                         peerState.IP,
+		PubKey:                     peerState.PubKey,
+		ConnStatus:                 peerState.ConnStatus,
+		ConnStatusUpdate:           timestamppb.New(peerState.ConnStatusUpdate),
+		Relayed:                    peerState.Relayed,
+		Direct:                     peerState.Direct,
+		LocalIceCandidateType:      peerState.LocalIceCandidateType,
+		RemoteIceCandidateType:     peerState.RemoteIceCandidateType,
+		Latency:                    durationpb.New(peerState.Latency),
+		LastWireguardHandshake:     timestamppb.New(peerState.LastWireguardHandshake),
+		BytesRx:                    peerState.BytesRx,
+		BytesTx:                    peerState.BytesTx,
+		RelayServerAddress:         peerState.RelayServerAddress,
+		LocalIceCandidateEndpoint:  peerState.LocalIceCandidateEndpoint,
+		RemoteIceCandidateEndpoint: peerState.RemoteIceCandidateEndpoint,
+		ConnectionPolicy:           peerState.ConnectionPolicy,
+	}
+}
+
 func toProtoFullStatus(fullStatus nbStatus.FullStatus) *proto.FullStatus {
 	pbFullStatus := proto.FullStatus{
 		ManagementState: &proto.ManagementState{},
@@ -477,17 +709,168 @@ func toProtoFullStatus(fullStatus nbStatus.FullStatus) *proto.FullStatus {
 	pbFullStatus.LocalPeerState.KernelInterface = fullStatus.LocalPeerState.KernelInterface
 
 	for _, peerState := range fullStatus.Peers {
-		pbPeerState := &proto.PeerState{
-			IP:                     peerState.IP,
-			PubKey:                 peerState.PubKey,
-			ConnStatus:             peerState.ConnStatus,
-			ConnStatusUpdate:       timestamppb.New(peerState.ConnStatusUpdate),
-			Relayed:                peerState.Relayed,
-			Direct:                 peerState.Direct,
-			LocalIceCandidateType:  peerState.LocalIceCandidateType,
-			RemoteIceCandidateType: peerState.RemoteIceCandidateType,
-		}
-		pbFullStatus.Peers = append(pbFullStatus.Peers, pbPeerState)
+		pbFullStatus.Peers = append(pbFullStatus.Peers, toProtoPeerState(peerState))
+	}
+
+	for _, conflict := range fullStatus.RouteConflicts {
+		pbFullStatus.RouteConflicts = append(pbFullStatus.RouteConflicts, &proto.RouteConflict{
+			NetID:        conflict.NetID,
+			Network:      conflict.Network,
+			LocalNetwork: conflict.LocalNetwork,
+			Interface:    conflict.Interface,
+			Action:       conflict.Action,
+		})
+	}
+
+	for _, connSwitch := range fullStatus.ConnSwitches {
+		pbFullStatus.ConnSwitches = append(pbFullStatus.ConnSwitches, &proto.ConnSwitch{
+			PubKey:                     connSwitch.PubKey,
+			Time:                       timestamppb.New(connSwitch.Time),
+			Direct:                     connSwitch.Direct,
+			LocalIceCandidateType:      connSwitch.LocalIceCandidateType,
+			RemoteIceCandidateType:     connSwitch.RemoteIceCandidateType,
+			LocalIceCandidateEndpoint:  connSwitch.LocalIceCandidateEndpoint,
+			RemoteIceCandidateEndpoint: connSwitch.RemoteIceCandidateEndpoint,
+		})
+	}
+
+	for _, traffic := range fullStatus.RoutesTraffic {
+		pbFullStatus.RoutesTraffic = append(pbFullStatus.RoutesTraffic, &proto.RouteTraffic{
+			ID:        traffic.ID,
+			NetID:     traffic.NetID,
+			Network:   traffic.Network,
+			TxBytes:   traffic.TxBytes,
+			TxPackets: traffic.TxPackets,
+			RxBytes:   traffic.RxBytes,
+			RxPackets: traffic.RxPackets,
+		})
 	}
 	return &pbFullStatus
 }
+
+// ListRoutes returns the networks available through routing peers.
+func (s *Server) ListRoutes(ctx context.Context, msg *proto.ListRoutesRequest) (*proto.ListRoutesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.statusRecorder == nil {
+		s.statusRecorder = nbStatus.NewRecorder()
+	}
+
+	var pbRoutes []*proto.Route
+	for _, routeState := range s.statusRecorder.GetRouteStates() {
+		pbRoutes = append(pbRoutes, &proto.Route{
+			ID:       routeState.ID,
+			NetID:    routeState.NetID,
+			Network:  routeState.Network,
+			Selected: routeState.Selected,
+			Peer:     routeState.Peer,
+		})
+	}
+
+	return &proto.ListRoutesResponse{Routes: pbRoutes}, nil
+}
+
+// SelectRoutes selects networks to be routed through routing peers.
+func (s *Server) SelectRoutes(ctx context.Context, msg *proto.SelectRoutesRequest) (*proto.SelectRoutesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.config == nil {
+		return nil, fmt.Errorf("config is not defined, please call login command first")
+	}
+
+	if msg.GetAll() {
+		s.getRouteSelector().SelectAllRoutes()
+	} else {
+		err := s.validateNetIDs(msg.GetNetIDs())
+		if err != nil {
+			return nil, err
+		}
+		s.getRouteSelector().SelectRoutes(msg.GetNetIDs())
+	}
+
+	err := s.persistRouteSelection()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SelectRoutesResponse{}, nil
+}
+
+// DeselectRoutes deselects networks so they are no longer routed through routing peers.
+func (s *Server) DeselectRoutes(ctx context.Context, msg *proto.SelectRoutesRequest) (*proto.SelectRoutesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.config == nil {
+		return nil, fmt.Errorf("config is not defined, please call login command first")
+	}
+
+	netIDs := msg.GetNetIDs()
+	if msg.GetAll() {
+		netIDs = nil
+		if s.statusRecorder != nil {
+			for _, routeState := range s.statusRecorder.GetRouteStates() {
+				netIDs = append(netIDs, routeState.NetID)
+			}
+		}
+	} else {
+		err := s.validateNetIDs(netIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.getRouteSelector().DeselectRoutes(netIDs)
+
+	err := s.persistRouteSelection()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SelectRoutesResponse{}, nil
+}
+
+// getRouteSelector returns the route selector shared with the engine, creating it from the config if needed
+func (s *Server) getRouteSelector() *routemanager.RouteSelector {
+	if s.routeSelector == nil {
+		var deselected []string
+		if s.config != nil {
+			deselected = s.config.DeselectedRoutes
+		}
+		s.routeSelector = routemanager.NewRouteSelector(deselected)
+	}
+	return s.routeSelector
+}
+
+// validateNetIDs checks that the networks are available through routing peers
+func (s *Server) validateNetIDs(netIDs []string) error {
+	if len(netIDs) == 0 {
+		return gstatus.Errorf(codes.InvalidArgument, "no network IDs provided")
+	}
+
+	available := make(map[string]struct{})
+	if s.statusRecorder != nil {
+		for _, routeState := range s.statusRecorder.GetRouteStates() {
+			available[routeState.NetID] = struct{}{}
+		}
+	}
+
+	for _, netID := range netIDs {
+		if _, found := available[netID]; !found {
+			return gstatus.Errorf(codes.NotFound, "network %s is not available", netID)
+		}
+	}
+	return nil
+}
+
+// persistRouteSelection stores the route selection in the config file
+func (s *Server) persistRouteSelection() error {
+	s.config.DeselectedRoutes = s.getRouteSelector().GetDeselectedRoutes()
+	err := internal.WriteOutConfig(s.configPath, s.config)
+	if err != nil {
+		log.Errorf("failed to persist the route selection: %v", err)
+		return gstatus.Errorf(codes.Internal, "failed to persist the route selection: %v", err)
+	}
+	return nil
+}
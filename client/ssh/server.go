@@ -23,6 +23,12 @@ func DefaultSSHServer(hostKeyPEM []byte, addr string) (Server, error) {
 	return newDefaultServer(hostKeyPEM, addr)
 }
 
+// DefaultSSHServerOnListener is a function that creates DefaultServer accepting connections on the given listener,
+// e.g. a listener of the in-process network stack in netstack mode
+func DefaultSSHServerOnListener(hostKeyPEM []byte, listener net.Listener) (Server, error) {
+	return newServerOnListener(hostKeyPEM, listener), nil
+}
+
 // Server is an interface of SSH server
 type Server interface {
 	// Stop stops SSH server.
@@ -51,8 +57,13 @@ func newDefaultServer(hostKeyPEM []byte, addr string) (*DefaultServer, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newServerOnListener(hostKeyPEM, ln), nil
+}
+
+// newServerOnListener creates new server with provided host key accepting connections on the listener
+func newServerOnListener(hostKeyPEM []byte, listener net.Listener) *DefaultServer {
 	allowedKeys := make(map[string]ssh.PublicKey)
-	return &DefaultServer{listener: ln, mu: sync.Mutex{}, hostKeyPEM: hostKeyPEM, authorizedKeys: allowedKeys, sessions: make([]ssh.Session, 0)}, nil
+	return &DefaultServer{listener: listener, mu: sync.Mutex{}, hostKeyPEM: hostKeyPEM, authorizedKeys: allowedKeys, sessions: make([]ssh.Session, 0)}
 }
 
 // RemoveAuthorizedKey removes SSH key of a given peer from the authorized keys
@@ -0,0 +1,145 @@
+package status
+
+import (
+	"time"
+)
+
+// EventType is the kind of change reported by an Event
+type EventType string
+
+const (
+	EventPeerConnected          EventType = "PeerConnected"
+	EventPeerDisconnected       EventType = "PeerDisconnected"
+	EventRouteChanged           EventType = "RouteChanged"
+	EventManagementConnected    EventType = "ManagementConnected"
+	EventManagementDisconnected EventType = "ManagementDisconnected"
+	EventSignalConnected        EventType = "SignalConnected"
+	EventSignalDisconnected     EventType = "SignalDisconnected"
+	EventLoginRequired          EventType = "LoginRequired"
+	// EventPeerTrace reports a connection negotiation step of a traced peer, only sent to its trace subscribers
+	EventPeerTrace EventType = "PeerTrace"
+)
+
+// eventBufferSize is the number of events a subscriber can lag behind before new events are dropped for it
+const eventBufferSize = 100
+
+// connStatusConnected is the connection status of a connected peer, matches peer.StatusConnected
+const connStatusConnected = "Connected"
+
+// Event is a state change published to the event subscribers
+type Event struct {
+	Type    EventType
+	Time    time.Time
+	Message string
+	// Metadata contains event specific values, e.g. the public key of a peer or the network of a route
+	Metadata map[string]string
+}
+
+// EventSubscription receives the events published after it was created
+type EventSubscription struct {
+	events chan Event
+	// tracedPeer is the public key of the peer whose trace events are received, empty for state change events
+	tracedPeer string
+}
+
+// Events returns the channel the subscription receives events on. It is closed once unsubscribed
+func (s *EventSubscription) Events() <-chan Event {
+	return s.events
+}
+
+// SubscribeToEvents returns a new subscription to the state change events
+func (d *Status) SubscribeToEvents() *EventSubscription {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	sub := &EventSubscription{events: make(chan Event, eventBufferSize)}
+	d.subscriptions[sub] = struct{}{}
+	return sub
+}
+
+// SubscribeToPeerTrace returns a new subscription to the trace events of a peer
+func (d *Status) SubscribeToPeerTrace(peerPubKey string) *EventSubscription {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	sub := &EventSubscription{events: make(chan Event, eventBufferSize), tracedPeer: peerPubKey}
+	d.subscriptions[sub] = struct{}{}
+	d.tracedPeers[peerPubKey]++
+	return sub
+}
+
+// TracePeer sends a trace event to the trace subscribers of a peer
+func (d *Status) TracePeer(peerPubKey string, message string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.tracedPeers[peerPubKey] == 0 {
+		return
+	}
+
+	event := Event{
+		Type:     EventPeerTrace,
+		Time:     time.Now(),
+		Message:  message,
+		Metadata: map[string]string{"pubKey": peerPubKey},
+	}
+
+	for sub := range d.subscriptions {
+		if sub.tracedPeer != peerPubKey {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// UnsubscribeFromEvents removes a subscription and closes its events channel
+func (d *Status) UnsubscribeFromEvents(sub *EventSubscription) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	_, ok := d.subscriptions[sub]
+	if !ok {
+		return
+	}
+	delete(d.subscriptions, sub)
+	close(sub.events)
+
+	if sub.tracedPeer != "" {
+		d.tracedPeers[sub.tracedPeer]--
+		if d.tracedPeers[sub.tracedPeer] <= 0 {
+			delete(d.tracedPeers, sub.tracedPeer)
+		}
+	}
+}
+
+// PublishEvent sends an event to all subscribers
+func (d *Status) PublishEvent(eventType EventType, message string, metadata map[string]string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.publishEvent(eventType, message, metadata)
+}
+
+// publishEvent sends an event to all subscribers, the caller must hold the lock.
+// Subscribers that don't keep up miss events instead of blocking the state updates
+func (d *Status) publishEvent(eventType EventType, message string, metadata map[string]string) {
+	event := Event{
+		Type:     eventType,
+		Time:     time.Now(),
+		Message:  message,
+		Metadata: metadata,
+	}
+
+	for sub := range d.subscriptions {
+		if sub.tracedPeer != "" {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
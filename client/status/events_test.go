@@ -0,0 +1,157 @@
+package status
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func receiveEvent(t *testing.T, sub *EventSubscription) Event {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an event")
+		return Event{}
+	}
+}
+
+func assertNoEvent(t *testing.T, sub *EventSubscription) {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("unexpected event %s: %s", event.Type, event.Message)
+	default:
+	}
+}
+
+func TestPeerConnectionEvents(t *testing.T) {
+	key := "abc"
+	status := NewRecorder()
+	require.NoError(t, status.AddPeer(key))
+
+	sub := status.SubscribeToEvents()
+	defer status.UnsubscribeFromEvents(sub)
+
+	err := status.UpdatePeerState(PeerState{PubKey: key, IP: "100.64.0.10", ConnStatus: "Connecting"})
+	require.NoError(t, err)
+	assertNoEvent(t, sub)
+
+	err = status.UpdatePeerState(PeerState{PubKey: key, ConnStatus: "Connected", Relayed: true})
+	require.NoError(t, err)
+	event := receiveEvent(t, sub)
+	assert.Equal(t, EventPeerConnected, event.Type)
+	assert.Equal(t, "100.64.0.10", event.Metadata["ip"], "ip of the previous state should be used")
+	assert.Equal(t, "Relayed", event.Metadata["connType"])
+
+	err = status.UpdatePeerState(PeerState{PubKey: key, ConnStatus: "Connected"})
+	require.NoError(t, err)
+	assertNoEvent(t, sub)
+
+	err = status.UpdatePeerState(PeerState{PubKey: key, ConnStatus: "Disconnected"})
+	require.NoError(t, err)
+	event = receiveEvent(t, sub)
+	assert.Equal(t, EventPeerDisconnected, event.Type)
+	assert.Equal(t, key, event.Metadata["pubKey"])
+}
+
+func TestConnectivityEvents(t *testing.T) {
+	status := NewRecorder()
+	sub := status.SubscribeToEvents()
+	defer status.UnsubscribeFromEvents(sub)
+
+	status.MarkManagementDisconnected("https://mgm")
+	assertNoEvent(t, sub)
+
+	status.MarkManagementConnected("https://mgm")
+	assert.Equal(t, EventManagementConnected, receiveEvent(t, sub).Type)
+	status.MarkManagementConnected("https://mgm")
+	assertNoEvent(t, sub)
+	status.MarkManagementDisconnected("https://mgm")
+	assert.Equal(t, EventManagementDisconnected, receiveEvent(t, sub).Type)
+
+	status.MarkSignalConnected("https://signal")
+	assert.Equal(t, EventSignalConnected, receiveEvent(t, sub).Type)
+	status.MarkSignalDisconnected("https://signal")
+	assert.Equal(t, EventSignalDisconnected, receiveEvent(t, sub).Type)
+}
+
+func TestRouteChangedEvents(t *testing.T) {
+	status := NewRecorder()
+	status.UpdateRouteStates([]RouteState{{ID: "net1", NetID: "net1", Network: "10.0.0.0/24", Selected: true}})
+
+	sub := status.SubscribeToEvents()
+	defer status.UnsubscribeFromEvents(sub)
+
+	require.NoError(t, status.UpdateRoutePeer("net1", "peerA"))
+	event := receiveEvent(t, sub)
+	assert.Equal(t, EventRouteChanged, event.Type)
+	assert.Equal(t, "peerA", event.Metadata["peer"])
+
+	require.NoError(t, status.UpdateRoutePeer("net1", "peerA"))
+	assertNoEvent(t, sub)
+
+	require.NoError(t, status.UpdateRoutePeer("net1", ""))
+	event = receiveEvent(t, sub)
+	assert.Equal(t, EventRouteChanged, event.Type)
+	assert.Equal(t, "", event.Metadata["peer"])
+}
+
+func TestUnsubscribeFromEvents(t *testing.T) {
+	status := NewRecorder()
+	sub := status.SubscribeToEvents()
+	other := status.SubscribeToEvents()
+	defer status.UnsubscribeFromEvents(other)
+
+	status.UnsubscribeFromEvents(sub)
+	_, ok := <-sub.Events()
+	assert.False(t, ok, "events channel should be closed")
+
+	// unsubscribing twice must not panic
+	status.UnsubscribeFromEvents(sub)
+
+	status.PublishEvent(EventLoginRequired, "login required", nil)
+	assert.Equal(t, EventLoginRequired, receiveEvent(t, other).Type)
+}
+
+func TestPublishEvent_SlowSubscriber(t *testing.T) {
+	status := NewRecorder()
+	sub := status.SubscribeToEvents()
+	defer status.UnsubscribeFromEvents(sub)
+
+	for i := 0; i < eventBufferSize+10; i++ {
+		status.PublishEvent(EventLoginRequired, "login required", nil)
+	}
+
+	assert.Len(t, sub.Events(), eventBufferSize, "events beyond the buffer should be dropped")
+}
+
+func TestTracePeer(t *testing.T) {
+	status := NewRecorder()
+	events := status.SubscribeToEvents()
+	defer status.UnsubscribeFromEvents(events)
+
+	status.TracePeer("abc", "not traced")
+
+	trace := status.SubscribeToPeerTrace("abc")
+	otherTrace := status.SubscribeToPeerTrace("def")
+	defer status.UnsubscribeFromEvents(otherTrace)
+
+	status.TracePeer("abc", "sending offer")
+	event := receiveEvent(t, trace)
+	assert.Equal(t, EventPeerTrace, event.Type)
+	assert.Equal(t, "sending offer", event.Message)
+	assert.Equal(t, "abc", event.Metadata["pubKey"])
+
+	assertNoEvent(t, events)
+	assertNoEvent(t, otherTrace)
+
+	status.PublishEvent(EventLoginRequired, "login required", nil)
+	assertNoEvent(t, trace)
+	assert.Equal(t, EventLoginRequired, receiveEvent(t, events).Type)
+
+	status.UnsubscribeFromEvents(trace)
+	assert.NotContains(t, status.tracedPeers, "abc", "untraced peers should be removed")
+}
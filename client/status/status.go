@@ -2,6 +2,8 @@ package status
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -16,6 +18,22 @@ type PeerState struct {
 	Direct                 bool
 	LocalIceCandidateType  string
 	RemoteIceCandidateType string
+	// LocalIceCandidateEndpoint and RemoteIceCandidateEndpoint are the addresses of the selected ICE candidate pair
+	LocalIceCandidateEndpoint  string
+	RemoteIceCandidateEndpoint string
+	// Latency is the last round-trip time measured over the tunnel, zero if unknown
+	Latency time.Duration
+	// LastWireguardHandshake is the time of the last WireGuard handshake with the peer, zero if none happened
+	LastWireguardHandshake time.Time
+	// BytesRx and BytesTx are the bytes received from and sent to the peer by WireGuard
+	BytesRx int64
+	BytesTx int64
+	// RelayServerAddress is the address of the TURN relay used to reach the peer, empty if not relayed
+	RelayServerAddress string
+	// ConnectionPolicy is the policy of the connection to the peer set by the Management service
+	ConnectionPolicy string
+	// Name is the name of the peer set by the Management service, e.g. its hostname
+	Name string
 }
 
 // LocalPeerState contains the latest state of the local peer
@@ -37,12 +55,63 @@ type ManagementState struct {
 	Connected bool
 }
 
+// RouteConflict contains a route that overlaps with a network the local peer is directly attached to
+type RouteConflict struct {
+	NetID        string
+	Network      string
+	LocalNetwork string
+	Interface    string
+	Action       string
+}
+
+// RouteState contains the latest state of a network routed by remote peers
+type RouteState struct {
+	// ID is the client network ID of the route manager
+	ID       string
+	NetID    string
+	Network  string
+	Selected bool
+	// Peer is the public key of the routing peer in use, empty if no route is active
+	Peer string
+}
+
+// RouteTraffic contains the traffic forwarded by the local peer for a route it routes
+type RouteTraffic struct {
+	// ID is the route ID assigned by the Management service
+	ID      string
+	NetID   string
+	Network string
+	// TxBytes and TxPackets count the traffic forwarded to the routed network
+	TxBytes   uint64
+	TxPackets uint64
+	// RxBytes and RxPackets count the traffic forwarded from the routed network
+	RxBytes   uint64
+	RxPackets uint64
+}
+
+// ConnSwitch records a peer connection moved from a relayed path to a direct one without reconnecting
+type ConnSwitch struct {
+	PubKey                     string
+	Time                       time.Time
+	Direct                     bool
+	LocalIceCandidateType      string
+	RemoteIceCandidateType     string
+	LocalIceCandidateEndpoint  string
+	RemoteIceCandidateEndpoint string
+}
+
+// maxConnSwitches is the number of most recent connection switches kept by the Status instance
+const maxConnSwitches = 100
+
 // FullStatus contains the full state held by the Status instance
 type FullStatus struct {
 	Peers           []PeerState
 	ManagementState ManagementState
 	SignalState     SignalState
 	LocalPeerState  LocalPeerState
+	RouteConflicts  []RouteConflict
+	RoutesTraffic   []RouteTraffic
+	ConnSwitches    []ConnSwitch
 }
 
 // Status holds a state of peers, signal and management connections
@@ -53,6 +122,13 @@ type Status struct {
 	signal       SignalState
 	management   ManagementState
 	localPeer    LocalPeerState
+	conflicts    []RouteConflict
+	routes       map[string]RouteState
+	traffic      []RouteTraffic
+	switches     []ConnSwitch
+
+	subscriptions map[*EventSubscription]struct{}
+	tracedPeers   map[string]int
 }
 
 // NewRecorder returns a new Status instance
@@ -60,6 +136,10 @@ func NewRecorder() *Status {
 	return &Status{
 		peers:        make(map[string]PeerState),
 		changeNotify: make(map[string]chan struct{}),
+		routes:       make(map[string]RouteState),
+
+		subscriptions: make(map[*EventSubscription]struct{}),
+		tracedPeers:   make(map[string]int),
 	}
 }
 
@@ -116,13 +196,23 @@ func (d *Status) UpdatePeerState(receivedState PeerState) error {
 		peerState.IP = receivedState.IP
 	}
 
+	if receivedState.ConnectionPolicy != "" {
+		peerState.ConnectionPolicy = receivedState.ConnectionPolicy
+	}
+
 	if receivedState.ConnStatus != peerState.ConnStatus {
+		d.publishPeerConnStatusEvent(peerState, receivedState)
+
 		peerState.ConnStatus = receivedState.ConnStatus
 		peerState.ConnStatusUpdate = receivedState.ConnStatusUpdate
 		peerState.Direct = receivedState.Direct
 		peerState.Relayed = receivedState.Relayed
 		peerState.LocalIceCandidateType = receivedState.LocalIceCandidateType
 		peerState.RemoteIceCandidateType = receivedState.RemoteIceCandidateType
+		peerState.LocalIceCandidateEndpoint = receivedState.LocalIceCandidateEndpoint
+		peerState.RemoteIceCandidateEndpoint = receivedState.RemoteIceCandidateEndpoint
+		peerState.RelayServerAddress = receivedState.RelayServerAddress
+		peerState.Latency = 0
 	}
 
 	d.peers[receivedState.PubKey] = peerState
@@ -136,6 +226,121 @@ func (d *Status) UpdatePeerState(receivedState PeerState) error {
 	return nil
 }
 
+// publishPeerConnStatusEvent publishes an event when a peer gets connected or loses its connection
+func (d *Status) publishPeerConnStatusEvent(oldState, newState PeerState) {
+	ip := newState.IP
+	if ip == "" {
+		ip = oldState.IP
+	}
+	metadata := map[string]string{"pubKey": newState.PubKey, "ip": ip}
+
+	switch {
+	case newState.ConnStatus == connStatusConnected:
+		connType := "P2P"
+		if newState.Relayed {
+			connType = "Relayed"
+		}
+		metadata["connType"] = connType
+		d.publishEvent(EventPeerConnected, fmt.Sprintf("peer %s connected (%s)", ip, connType), metadata)
+	case oldState.ConnStatus == connStatusConnected:
+		d.publishEvent(EventPeerDisconnected, fmt.Sprintf("peer %s disconnected", ip), metadata)
+	}
+}
+
+// UpdatePeerName updates the name of a peer.
+// It doesn't trigger the peer state change notifier as the name doesn't affect the connection
+func (d *Status) UpdatePeerName(peerPubKey string, name string) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[peerPubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+
+	peerState.Name = name
+	d.peers[peerPubKey] = peerState
+	return nil
+}
+
+// UpdatePeerLatency updates the measured latency of a peer.
+// It doesn't trigger the peer state change notifier as latency changes constantly
+func (d *Status) UpdatePeerLatency(peerPubKey string, latency time.Duration) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[peerPubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+
+	peerState.Latency = latency
+	d.peers[peerPubKey] = peerState
+
+	return nil
+}
+
+// UpdateWireguardPeerStats updates the WireGuard handshake and transfer statistics of a peer.
+// It doesn't trigger the peer state change notifier as the statistics change constantly
+func (d *Status) UpdateWireguardPeerStats(peerPubKey string, lastHandshake time.Time, bytesRx, bytesTx int64) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[peerPubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+
+	peerState.LastWireguardHandshake = lastHandshake
+	peerState.BytesRx = bytesRx
+	peerState.BytesTx = bytesTx
+	d.peers[peerPubKey] = peerState
+
+	return nil
+}
+
+// RecordConnSwitch updates the connection path of a connected peer and keeps a record of the switch
+func (d *Status) RecordConnSwitch(connSwitch ConnSwitch) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	peerState, ok := d.peers[connSwitch.PubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+
+	peerState.Relayed = false
+	peerState.Direct = connSwitch.Direct
+	peerState.LocalIceCandidateType = connSwitch.LocalIceCandidateType
+	peerState.RemoteIceCandidateType = connSwitch.RemoteIceCandidateType
+	peerState.LocalIceCandidateEndpoint = connSwitch.LocalIceCandidateEndpoint
+	peerState.RemoteIceCandidateEndpoint = connSwitch.RemoteIceCandidateEndpoint
+	peerState.RelayServerAddress = ""
+	peerState.Latency = 0
+	d.peers[connSwitch.PubKey] = peerState
+
+	d.switches = append(d.switches, connSwitch)
+	if len(d.switches) > maxConnSwitches {
+		d.switches = d.switches[len(d.switches)-maxConnSwitches:]
+	}
+
+	ch, found := d.changeNotify[connSwitch.PubKey]
+	if found && ch != nil {
+		close(ch)
+		d.changeNotify[connSwitch.PubKey] = nil
+	}
+
+	return nil
+}
+
+// GetConnSwitches returns the most recent connection switches
+func (d *Status) GetConnSwitches() []ConnSwitch {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	return append([]ConnSwitch(nil), d.switches...)
+}
+
 // GetPeerStateChangeNotifier returns a change notifier channel for a peer
 func (d *Status) GetPeerStateChangeNotifier(peer string) <-chan struct{} {
 	d.mux.Lock()
@@ -168,6 +373,9 @@ func (d *Status) CleanLocalPeerState() {
 func (d *Status) MarkManagementDisconnected(managementURL string) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
+	if d.management.Connected {
+		d.publishEvent(EventManagementDisconnected, "disconnected from management "+managementURL, map[string]string{"url": managementURL})
+	}
 	d.management = ManagementState{
 		URL:       managementURL,
 		Connected: false,
@@ -178,6 +386,9 @@ func (d *Status) MarkManagementDisconnected(managementURL string) {
 func (d *Status) MarkManagementConnected(managementURL string) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
+	if !d.management.Connected {
+		d.publishEvent(EventManagementConnected, "connected to management "+managementURL, map[string]string{"url": managementURL})
+	}
 	d.management = ManagementState{
 		URL:       managementURL,
 		Connected: true,
@@ -188,6 +399,9 @@ func (d *Status) MarkManagementConnected(managementURL string) {
 func (d *Status) MarkSignalDisconnected(signalURL string) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
+	if d.signal.Connected {
+		d.publishEvent(EventSignalDisconnected, "disconnected from signal "+signalURL, map[string]string{"url": signalURL})
+	}
 	d.signal = SignalState{
 		signalURL,
 		false,
@@ -198,12 +412,106 @@ func (d *Status) MarkSignalDisconnected(signalURL string) {
 func (d *Status) MarkSignalConnected(signalURL string) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
+	if !d.signal.Connected {
+		d.publishEvent(EventSignalConnected, "connected to signal "+signalURL, map[string]string{"url": signalURL})
+	}
 	d.signal = SignalState{
 		signalURL,
 		true,
 	}
 }
 
+// UpdateRouteConflicts replaces the route conflicts detected by the route manager
+func (d *Status) UpdateRouteConflicts(conflicts []RouteConflict) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.conflicts = append([]RouteConflict(nil), conflicts...)
+}
+
+// GetRouteConflicts returns the route conflicts detected by the route manager
+func (d *Status) GetRouteConflicts() []RouteConflict {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	return append([]RouteConflict(nil), d.conflicts...)
+}
+
+// UpdateRoutesTraffic replaces the traffic of the routes routed by the local peer
+func (d *Status) UpdateRoutesTraffic(routesTraffic []RouteTraffic) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.traffic = append([]RouteTraffic(nil), routesTraffic...)
+}
+
+// GetRoutesTraffic returns the traffic of the routes routed by the local peer
+func (d *Status) GetRoutesTraffic() []RouteTraffic {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	return append([]RouteTraffic(nil), d.traffic...)
+}
+
+// UpdateRouteStates replaces the available client networks keeping the routing peer in use of the existing ones
+func (d *Status) UpdateRouteStates(routeStates []RouteState) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	routes := make(map[string]RouteState)
+	for _, routeState := range routeStates {
+		existing, found := d.routes[routeState.ID]
+		if found && routeState.Selected {
+			routeState.Peer = existing.Peer
+		}
+		routes[routeState.ID] = routeState
+	}
+	d.routes = routes
+}
+
+// UpdateRoutePeer updates the routing peer in use of a client network
+func (d *Status) UpdateRoutePeer(id string, peerPubKey string) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	routeState, found := d.routes[id]
+	if !found {
+		return errors.New("route doesn't exist")
+	}
+	if routeState.Peer != peerPubKey {
+		message := fmt.Sprintf("route %s (%s) is no longer active", routeState.NetID, routeState.Network)
+		if peerPubKey != "" {
+			message = fmt.Sprintf("route %s (%s) is routed through peer %s", routeState.NetID, routeState.Network, peerPubKey)
+		}
+		d.publishEvent(EventRouteChanged, message, map[string]string{
+			"netID":   routeState.NetID,
+			"network": routeState.Network,
+			"peer":    peerPubKey,
+		})
+	}
+	routeState.Peer = peerPubKey
+	d.routes[id] = routeState
+	return nil
+}
+
+// GetRouteStates returns the available client networks sorted by network ID and network
+func (d *Status) GetRouteStates() []RouteState {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	routeStates := make([]RouteState, 0, len(d.routes))
+	for _, routeState := range d.routes {
+		routeStates = append(routeStates, routeState)
+	}
+	sort.Slice(routeStates, func(i, j int) bool {
+		if routeStates[i].NetID != routeStates[j].NetID {
+			return routeStates[i].NetID < routeStates[j].NetID
+		}
+		return routeStates[i].Network < routeStates[j].Network
+	})
+	return routeStates
+}
+
 // GetFullStatus gets full status
 func (d *Status) GetFullStatus() FullStatus {
 	d.mux.Lock()
@@ -213,6 +521,9 @@ func (d *Status) GetFullStatus() FullStatus {
 		ManagementState: d.management,
 		SignalState:     d.signal,
 		LocalPeerState:  d.localPeer,
+		RouteConflicts:  append([]RouteConflict(nil), d.conflicts...),
+		RoutesTraffic:   append([]RouteTraffic(nil), d.traffic...),
+		ConnSwitches:    append([]ConnSwitch(nil), d.switches...),
 	}
 
 	for _, status := range d.peers {
@@ -3,6 +3,7 @@ package status
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestAddPeer(t *testing.T) {
@@ -54,6 +55,83 @@ func TestUpdatePeerState(t *testing.T) {
 	assert.Equal(t, ip, state.IP, "ip should be equal")
 }
 
+func TestUpdatePeerLatency(t *testing.T) {
+	key := "abc"
+	latency := 15 * time.Millisecond
+	status := NewRecorder()
+	status.peers[key] = PeerState{
+		PubKey: key,
+	}
+
+	err := status.UpdatePeerLatency(key, latency)
+	assert.NoError(t, err, "shouldn't return error")
+
+	state := status.peers[key]
+	assert.Equal(t, latency, state.Latency, "latency should be equal")
+
+	err = status.UpdatePeerLatency("non_existing_key", latency)
+	assert.Error(t, err, "should return error when peer doesn't exist")
+}
+
+func TestUpdateWireguardPeerStats(t *testing.T) {
+	key := "abc"
+	handshake := time.Now()
+	status := NewRecorder()
+	status.peers[key] = PeerState{
+		PubKey: key,
+	}
+
+	err := status.UpdateWireguardPeerStats(key, handshake, 100, 200)
+	assert.NoError(t, err, "shouldn't return error")
+
+	state := status.peers[key]
+	assert.Equal(t, handshake, state.LastWireguardHandshake, "handshake should be equal")
+	assert.Equal(t, int64(100), state.BytesRx, "received bytes should be equal")
+	assert.Equal(t, int64(200), state.BytesTx, "sent bytes should be equal")
+
+	err = status.UpdateWireguardPeerStats("non_existing_key", handshake, 100, 200)
+	assert.Error(t, err, "should return error when peer doesn't exist")
+}
+
+func TestRecordConnSwitch(t *testing.T) {
+	key := "abc"
+	status := NewRecorder()
+	status.peers[key] = PeerState{
+		PubKey:                 key,
+		ConnStatus:             "Connected",
+		Relayed:                true,
+		LocalIceCandidateType:  "relay",
+		RemoteIceCandidateType: "host",
+	}
+
+	connSwitch := ConnSwitch{
+		PubKey:                 key,
+		Time:                   time.Now(),
+		Direct:                 true,
+		LocalIceCandidateType:  "srflx",
+		RemoteIceCandidateType: "host",
+	}
+
+	err := status.RecordConnSwitch(connSwitch)
+	assert.NoError(t, err, "shouldn't return error")
+
+	state := status.peers[key]
+	assert.False(t, state.Relayed, "peer shouldn't be relayed after a switch")
+	assert.True(t, state.Direct, "peer should be direct")
+	assert.Equal(t, "srflx", state.LocalIceCandidateType, "local candidate type should be updated")
+	assert.Equal(t, "Connected", state.ConnStatus, "connection status shouldn't change")
+	assert.Equal(t, []ConnSwitch{connSwitch}, status.GetConnSwitches(), "switch should be recorded")
+
+	for i := 0; i < maxConnSwitches+10; i++ {
+		err = status.RecordConnSwitch(connSwitch)
+		assert.NoError(t, err, "shouldn't return error")
+	}
+	assert.Len(t, status.GetConnSwitches(), maxConnSwitches, "recorded switches should be capped")
+
+	err = status.RecordConnSwitch(ConnSwitch{PubKey: "non_existing_key"})
+	assert.Error(t, err, "should return error when peer doesn't exist")
+}
+
 func TestGetPeerStateChangeNotifierLogic(t *testing.T) {
 	key := "abc"
 	ip := "10.10.10.10"
@@ -127,6 +205,75 @@ func TestCleanLocalPeerState(t *testing.T) {
 	assert.Equal(t, emptyLocalPeerState, status.localPeer, "local peer status should be empty")
 }
 
+func TestUpdateRouteConflicts(t *testing.T) {
+	conflicts := []RouteConflict{
+		{
+			NetID:        "office",
+			Network:      "192.168.1.0/24",
+			LocalNetwork: "192.168.1.0/24",
+			Interface:    "eth0",
+			Action:       "skipped",
+		},
+	}
+	status := NewRecorder()
+
+	status.UpdateRouteConflicts(conflicts)
+	assert.Equal(t, conflicts, status.GetRouteConflicts(), "route conflicts should be equal")
+	assert.Equal(t, conflicts, status.GetFullStatus().RouteConflicts, "full status route conflicts should be equal")
+
+	status.UpdateRouteConflicts(nil)
+	assert.Empty(t, status.GetRouteConflicts(), "route conflicts should be empty")
+}
+
+func TestUpdateRoutesTraffic(t *testing.T) {
+	routesTraffic := []RouteTraffic{
+		{
+			ID:        "route1",
+			NetID:     "office",
+			Network:   "192.168.1.0/24",
+			TxBytes:   1000,
+			TxPackets: 10,
+			RxBytes:   2000,
+			RxPackets: 20,
+		},
+	}
+	status := NewRecorder()
+
+	status.UpdateRoutesTraffic(routesTraffic)
+	assert.Equal(t, routesTraffic, status.GetRoutesTraffic(), "routes traffic should be equal")
+	assert.Equal(t, routesTraffic, status.GetFullStatus().RoutesTraffic, "full status routes traffic should be equal")
+
+	status.UpdateRoutesTraffic(nil)
+	assert.Empty(t, status.GetRoutesTraffic(), "routes traffic should be empty")
+}
+
+func TestUpdateRouteStates(t *testing.T) {
+	status := NewRecorder()
+
+	status.UpdateRouteStates([]RouteState{
+		{ID: "b-10.0.0.0/24", NetID: "b", Network: "10.0.0.0/24", Selected: true},
+		{ID: "a-10.1.0.0/24", NetID: "a", Network: "10.1.0.0/24", Selected: true},
+	})
+
+	err := status.UpdateRoutePeer("b-10.0.0.0/24", "abc")
+	assert.NoError(t, err, "shouldn't return error")
+
+	err = status.UpdateRoutePeer("not existing", "abc")
+	assert.Error(t, err, "should return error when route doesn't exist")
+
+	routeStates := status.GetRouteStates()
+	assert.Len(t, routeStates, 2, "should have two routes")
+	assert.Equal(t, "a", routeStates[0].NetID, "routes should be sorted by network ID")
+	assert.Equal(t, "abc", routeStates[1].Peer, "routing peer should be updated")
+
+	status.UpdateRouteStates([]RouteState{
+		{ID: "b-10.0.0.0/24", NetID: "b", Network: "10.0.0.0/24", Selected: true},
+	})
+	routeStates = status.GetRouteStates()
+	assert.Len(t, routeStates, 1, "should have one route")
+	assert.Equal(t, "abc", routeStates[0].Peer, "routing peer should be kept for existing routes")
+}
+
 func TestUpdateSignalState(t *testing.T) {
 	url := "https://signal"
 	var tests = []struct {
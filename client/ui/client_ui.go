@@ -14,6 +14,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -30,7 +31,9 @@ import (
 	"github.com/skratchdot/open-golang/open"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	gstatus "google.golang.org/grpc/status"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -101,9 +104,14 @@ type serviceClient struct {
 	mUp         *systray.MenuItem
 	mDown       *systray.MenuItem
 	mAdminPanel *systray.MenuItem
+	mProfiles   *systray.MenuItem
 	mSettings   *systray.MenuItem
 	mQuit       *systray.MenuItem
 
+	// profileItems are the submenu items of mProfiles by profile name
+	profileItems   map[string]*systray.MenuItem
+	profileItemsMu sync.Mutex
+
 	// application with main windows.
 	app          fyne.App
 	wSettings    fyne.Window
@@ -235,7 +243,13 @@ func (s *serviceClient) login() error {
 		return err
 	}
 
-	loginResp, err := conn.Login(s.ctx, &proto.LoginRequest{})
+	// the desktop client logs in with the browser of this machine, the device code flow is the fallback
+	// for Management services without a PKCE provider or that don't implement the PKCE flow
+	loginResp, err := conn.Login(s.ctx, &proto.LoginRequest{SsoFlow: proto.SSOFlow_PKCE})
+	if st, ok := gstatus.FromError(err); ok && (st.Code() == codes.NotFound || st.Code() == codes.Unimplemented) {
+		log.Debugf("no PKCE provider, falling back to the device code flow: %v", err)
+		loginResp, err = conn.Login(s.ctx, &proto.LoginRequest{SsoFlow: proto.SSOFlow_DEVICE_CODE})
+	}
 	if err != nil {
 		log.Errorf("login to management URL with: %v", err)
 		return err
@@ -371,6 +385,8 @@ func (s *serviceClient) onTrayReady() {
 	s.mDown.Disable()
 	s.mAdminPanel = systray.AddMenuItem("Admin Panel", "Wiretrustee Admin Panel")
 	systray.AddSeparator()
+	s.mProfiles = systray.AddMenuItem("Profile", "Select the client profile")
+	systray.AddSeparator()
 	s.mSettings = systray.AddMenuItem("Settings", "Settings of the application")
 	systray.AddSeparator()
 	v := systray.AddMenuItem("v"+system.NetbirdVersion(), "Client Version: "+system.NetbirdVersion())
@@ -385,6 +401,10 @@ func (s *serviceClient) onTrayReady() {
 			if err != nil {
 				log.Errorf("error while updating status: %v", err)
 			}
+			err = s.updateProfiles()
+			if err != nil {
+				log.Errorf("error while updating profiles: %v", err)
+			}
 			time.Sleep(2 * time.Second)
 		}
 	}()
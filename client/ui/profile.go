@@ -0,0 +1,85 @@
+//go:build !(linux && 386)
+// +build !linux !386
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/getlantern/systray"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// updateProfiles syncs the profiles submenu with the profiles of the daemon and marks the active one.
+// Menu items can't be removed, items of removed profiles are hidden
+func (s *serviceClient) updateProfiles() error {
+	conn, err := s.getSrvClient(defaultFailTimeout)
+	if err != nil {
+		return err
+	}
+
+	resp, err := conn.ListProfiles(s.ctx, &proto.ListProfilesRequest{})
+	if err != nil {
+		return fmt.Errorf("list profiles: %v", err)
+	}
+
+	s.profileItemsMu.Lock()
+	defer s.profileItemsMu.Unlock()
+
+	if s.profileItems == nil {
+		s.profileItems = make(map[string]*systray.MenuItem)
+	}
+
+	listed := make(map[string]struct{})
+	for _, profile := range resp.GetProfiles() {
+		listed[profile.GetName()] = struct{}{}
+
+		item, ok := s.profileItems[profile.GetName()]
+		if !ok {
+			item = s.mProfiles.AddSubMenuItemCheckbox(profile.GetName(), profile.GetManagementUrl(), profile.GetActive())
+			s.profileItems[profile.GetName()] = item
+			go s.handleProfileClicks(profile.GetName(), item)
+		}
+		item.Show()
+
+		if profile.GetActive() {
+			item.Check()
+			s.mProfiles.SetTitle("Profile: " + profile.GetName())
+		} else {
+			item.Uncheck()
+		}
+	}
+
+	for name, item := range s.profileItems {
+		if _, ok := listed[name]; !ok {
+			item.Hide()
+		}
+	}
+
+	return nil
+}
+
+// handleProfileClicks selects the profile of a submenu item when it is clicked
+func (s *serviceClient) handleProfileClicks(name string, item *systray.MenuItem) {
+	for range item.ClickedCh {
+		conn, err := s.getSrvClient(defaultFailTimeout)
+		if err != nil {
+			log.Errorf("get client: %v", err)
+			continue
+		}
+
+		_, err = conn.SelectProfile(s.ctx, &proto.SelectProfileRequest{Name: name})
+		if err != nil {
+			log.Errorf("select profile %s: %v", name, err)
+			continue
+		}
+
+		s.getSrvConfig()
+		err = s.updateProfiles()
+		if err != nil {
+			log.Errorf("error while updating profiles: %v", err)
+		}
+	}
+}
@@ -21,6 +21,10 @@ func (w *WGIface) GetAddress() WGAddress {
 
 // configureDevice configures the wireguard device
 func (w *WGIface) configureDevice(config wgtypes.Config) error {
+	if w.device != nil {
+		return w.device.IpcSet(toUAPI(config))
+	}
+
 	wg, err := wgctrl.New()
 	if err != nil {
 		return err
@@ -70,13 +74,7 @@ func (w *WGIface) GetListenPort() (*int, error) {
 	log.Debugf("getting Wireguard listen port of interface %s", w.Name)
 
 	//discover Wireguard current configuration
-	wg, err := wgctrl.New()
-	if err != nil {
-		return nil, err
-	}
-	defer wg.Close()
-
-	d, err := wg.Device(w.Name)
+	d, err := w.getDevice()
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +170,7 @@ func (w *WGIface) RemoveAllowedIP(peerKey string, allowedIP string) error {
 		return err
 	}
 
-	existingPeer, err := getPeer(w.Name, peerKey)
+	existingPeer, err := w.getPeer(peerKey)
 	if err != nil {
 		return err
 	}
@@ -206,10 +204,19 @@ func (w *WGIface) RemoveAllowedIP(peerKey string, allowedIP string) error {
 	return nil
 }
 
-func getPeer(ifaceName, peerPubKey string) (wgtypes.Peer, error) {
+// getDevice returns the current configuration of the wireguard device
+func (w *WGIface) getDevice() (*wgtypes.Device, error) {
+	if w.device != nil {
+		uapi, err := w.device.IpcGet()
+		if err != nil {
+			return nil, err
+		}
+		return parseUAPI(w.Name, uapi)
+	}
+
 	wg, err := wgctrl.New()
 	if err != nil {
-		return wgtypes.Peer{}, err
+		return nil, err
 	}
 	defer func() {
 		err = wg.Close()
@@ -218,7 +225,11 @@ func getPeer(ifaceName, peerPubKey string) (wgtypes.Peer, error) {
 		}
 	}()
 
-	wgDevice, err := wg.Device(ifaceName)
+	return wg.Device(w.Name)
+}
+
+func (w *WGIface) getPeer(peerPubKey string) (wgtypes.Peer, error) {
+	wgDevice, err := w.getDevice()
 	if err != nil {
 		return wgtypes.Peer{}, err
 	}
@@ -256,3 +267,23 @@ func (w *WGIface) RemovePeer(peerKey string) error {
 	}
 	return nil
 }
+
+// WGStats contains the traffic statistics of a Wireguard peer
+type WGStats struct {
+	LastHandshake time.Time
+	TxBytes       int64
+	RxBytes       int64
+}
+
+// GetStats returns the traffic statistics of a Wireguard peer of the interface
+func (w *WGIface) GetStats(peerKey string) (WGStats, error) {
+	peer, err := w.getPeer(peerKey)
+	if err != nil {
+		return WGStats{}, err
+	}
+	return WGStats{
+		LastHandshake: peer.LastHandshakeTime,
+		TxBytes:       peer.TransmitBytes,
+		RxBytes:       peer.ReceiveBytes,
+	}, nil
+}
@@ -6,6 +6,10 @@ import (
 	"os"
 	"runtime"
 	"sync"
+
+	"golang.zx2c4.com/wireguard/device"
+
+	"github.com/netbirdio/netbird/iface/netstack"
 )
 
 const (
@@ -21,6 +25,12 @@ type WGIface struct {
 	Address   WGAddress
 	Interface NetInterface
 	mu        sync.Mutex
+
+	// netstack runs the device on an in-process network stack, see NewWGIFaceNetstack
+	netstack    bool
+	netstackTun *netstack.Tun
+	// device is the wireguard-go device configured without UAPI socket, nil unless netstack is set
+	device *device.Device
 }
 
 // WGAddress Wireguard parsed address
@@ -74,6 +84,12 @@ func (w *WGIface) Close() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.device != nil {
+		// closing the device closes the network stack too
+		w.device.Close()
+		return nil
+	}
+
 	err := w.Interface.Close()
 	if err != nil {
 		return err
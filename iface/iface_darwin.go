@@ -10,6 +10,10 @@ func (w *WGIface) Create() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.netstack {
+		return w.createWithNetstack()
+	}
+
 	return w.createWithUserspace()
 }
 
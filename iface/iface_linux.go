@@ -17,6 +17,10 @@ func (w *WGIface) Create() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.netstack {
+		return w.createWithNetstack()
+	}
+
 	if WireguardModuleIsLoaded() {
 		log.Info("using kernel WireGuard")
 		return w.createWithKernel()
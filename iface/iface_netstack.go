@@ -0,0 +1,106 @@
+package iface
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+
+	"github.com/netbirdio/netbird/iface/netstack"
+)
+
+// NewWGIFaceNetstack creates a new Wireguard interface instance that runs on an in-process network stack instead
+// of a TUN device, it doesn't need root privileges. The mesh is only reachable with the Dial and Listen functions
+func NewWGIFaceNetstack(iface string, address string, mtu int) (*WGIface, error) {
+	wgIface, err := NewWGIFace(iface, address, mtu)
+	wgIface.netstack = true
+	return wgIface, err
+}
+
+// IsNetstack returns true if the interface runs on an in-process network stack
+func (w *WGIface) IsNetstack() bool {
+	return w.netstack
+}
+
+// createWithNetstack creates a wireguard-go device on an in-process network stack. It has no UAPI socket,
+// the device is configured directly
+func (w *WGIface) createWithNetstack() error {
+	addr, err := w.netstackPrefix()
+	if err != nil {
+		return err
+	}
+
+	tunIface, err := netstack.CreateTUN(w.Name, addr, w.MTU)
+	if err != nil {
+		return err
+	}
+
+	w.Interface = tunIface
+	w.netstackTun = tunIface
+
+	w.device = device.NewDevice(tunIface, conn.NewDefaultBind(), device.NewLogger(device.LogLevelSilent, "[netbird] "))
+	err = w.device.Up()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("using userspace WireGuard on an in-process network stack with address %s", addr)
+	return nil
+}
+
+// updateNetstackAddr replaces the address of the in-process network stack
+func (w *WGIface) updateNetstackAddr() error {
+	addr, err := w.netstackPrefix()
+	if err != nil {
+		return err
+	}
+	return w.netstackTun.SetAddress(addr)
+}
+
+func (w *WGIface) netstackPrefix() (netip.Prefix, error) {
+	ip, ok := netip.AddrFromSlice(w.Address.IP)
+	if !ok {
+		return netip.Prefix{}, fmt.Errorf("invalid address %s", w.Address.IP)
+	}
+	maskSize, _ := w.Address.Network.Mask.Size()
+	return netip.PrefixFrom(ip.Unmap(), maskSize), nil
+}
+
+// DialContext connects to the address on the named network through the mesh, it is only available in netstack mode.
+// The host has to be an IP address
+func (w *WGIface) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if w.netstackTun == nil {
+		return nil, fmt.Errorf("interface %s doesn't run on a network stack", w.Name)
+	}
+	return w.netstackTun.DialContext(ctx, network, address)
+}
+
+// Listen listens for TCP connections from the mesh, it is only available in netstack mode
+func (w *WGIface) Listen(network, address string) (net.Listener, error) {
+	if w.netstackTun == nil {
+		return nil, fmt.Errorf("interface %s doesn't run on a network stack", w.Name)
+	}
+	return w.netstackTun.Listen(network, address)
+}
+
+// ListenUDP listens for UDP packets from the mesh, it is only available in netstack mode
+func (w *WGIface) ListenUDP(address string) (net.PacketConn, error) {
+	if w.netstackTun == nil {
+		return nil, fmt.Errorf("interface %s doesn't run on a network stack", w.Name)
+	}
+	return w.netstackTun.ListenUDP(address)
+}
+
+// Ping measures the round-trip time to the address over the mesh with an ICMP echo request, it is only available
+// in netstack mode
+func (w *WGIface) Ping(ctx context.Context, addr netip.Addr) (time.Duration, error) {
+	if w.netstackTun == nil {
+		return 0, fmt.Errorf("interface %s doesn't run on a network stack", w.Name)
+	}
+	return w.netstackTun.Ping(ctx, addr)
+}
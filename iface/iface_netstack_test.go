@@ -0,0 +1,141 @@
+package iface
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func TestWGIface_Netstack(t *testing.T) {
+	peer1Key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	peer2Key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	iface1 := createNetstackIface(t, "nsnb1", "100.64.10.1/24", peer1Key)
+	iface2 := createNetstackIface(t, "nsnb2", "100.64.10.2/24", peer2Key)
+
+	connectNetstackPeers(t, iface1, iface2)
+	connectNetstackPeers(t, iface2, iface1)
+
+	listener, err := iface2.Listen("tcp", ":8080")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := iface1.DialContext(ctx, "tcp", "100.64.10.2:8080")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+
+	stats, err := iface1.GetStats(peer2Key.PublicKey().String())
+	require.NoError(t, err)
+	assert.False(t, stats.LastHandshake.IsZero(), "the peers should have done a WireGuard handshake")
+	assert.Greater(t, stats.RxBytes, int64(0), "the reply should have been received through the tunnel")
+
+	rtt, err := iface1.Ping(ctx, netip.MustParseAddr("100.64.10.2"))
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+
+	require.NoError(t, iface2.UpdateAddr("100.64.10.3/24"))
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), time.Second)
+	defer shortCancel()
+	_, err = iface1.DialContext(shortCtx, "tcp", "100.64.10.3:8080")
+	assert.Error(t, err, "the peer's allowed IPs don't contain the new address")
+}
+
+func createNetstackIface(t *testing.T, name, address string, key wgtypes.Key) *WGIface {
+	t.Helper()
+
+	wgIface, err := NewWGIFaceNetstack(name, address, DefaultMTU)
+	require.NoError(t, err)
+	require.NoError(t, wgIface.Create())
+	t.Cleanup(func() {
+		assert.NoError(t, wgIface.Close())
+	})
+	require.NoError(t, wgIface.Configure(key.String(), 0))
+	return wgIface
+}
+
+func connectNetstackPeers(t *testing.T, from, to *WGIface) {
+	t.Helper()
+
+	port, err := to.GetListenPort()
+	require.NoError(t, err)
+	endpoint, err := net.ResolveUDPAddr("udp", fmt.Sprintf("127.0.0.1:%d", *port))
+	require.NoError(t, err)
+
+	toDevice, err := to.getDevice()
+	require.NoError(t, err)
+	allowedIP := fmt.Sprintf("%s/32", to.Address.IP)
+	require.NoError(t, from.UpdatePeer(toDevice.PublicKey.String(), allowedIP, 0, endpoint, nil))
+}
+
+func TestUAPI(t *testing.T) {
+	privateKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	peerKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	peerPublicKey := peerKey.PublicKey()
+	port := 51830
+	keepAlive := 25 * time.Second
+	_, allowedIP, err := net.ParseCIDR("100.64.0.2/32")
+	require.NoError(t, err)
+
+	config := wgtypes.Config{
+		PrivateKey:   &privateKey,
+		ListenPort:   &port,
+		ReplacePeers: true,
+		Peers: []wgtypes.PeerConfig{{
+			PublicKey:                   peerPublicKey,
+			Endpoint:                    &net.UDPAddr{IP: net.ParseIP("192.168.1.10"), Port: 51820},
+			PersistentKeepaliveInterval: &keepAlive,
+			ReplaceAllowedIPs:           true,
+			AllowedIPs:                  []net.IPNet{*allowedIP},
+		}},
+	}
+	assert.Equal(t, fmt.Sprintf("private_key=%x\nlisten_port=51830\nreplace_peers=true\npublic_key=%x\n"+
+		"endpoint=192.168.1.10:51820\npersistent_keepalive_interval=25\nreplace_allowed_ips=true\nallowed_ip=100.64.0.2/32\n",
+		privateKey[:], peerPublicKey[:]), toUAPI(config))
+
+	device, err := parseUAPI("wt0", fmt.Sprintf("private_key=%x\nlisten_port=51830\npublic_key=%x\n"+
+		"endpoint=192.168.1.10:51820\nlast_handshake_time_sec=1700000000\nlast_handshake_time_nsec=0\n"+
+		"tx_bytes=100\nrx_bytes=200\npersistent_keepalive_interval=25\nallowed_ip=100.64.0.2/32\nerrno=0\n",
+		privateKey[:], peerPublicKey[:]))
+	require.NoError(t, err)
+	assert.Equal(t, privateKey.PublicKey(), device.PublicKey)
+	assert.Equal(t, port, device.ListenPort)
+	require.Len(t, device.Peers, 1)
+	peer := device.Peers[0]
+	assert.Equal(t, peerPublicKey, peer.PublicKey)
+	assert.Equal(t, "192.168.1.10:51820", peer.Endpoint.String())
+	assert.Equal(t, time.Unix(1700000000, 0), peer.LastHandshakeTime)
+	assert.Equal(t, int64(100), peer.TransmitBytes)
+	assert.Equal(t, int64(200), peer.ReceiveBytes)
+	assert.Equal(t, keepAlive, peer.PersistentKeepaliveInterval)
+	assert.Equal(t, []net.IPNet{*allowedIP}, peer.AllowedIPs)
+}
@@ -73,5 +73,8 @@ func (w *WGIface) UpdateAddr(newAddr string) error {
 	}
 
 	w.Address = addr
+	if w.netstack {
+		return w.updateNetstackAddr()
+	}
 	return w.assignAddr()
 }
@@ -14,6 +14,10 @@ func (w *WGIface) Create() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	if w.netstack {
+		return w.createWithNetstack()
+	}
+
 	WintunStaticRequestedGUID, _ := windows.GenerateGUID()
 	adapter, err := driver.CreateAdapter(w.Name, "WireGuard", &WintunStaticRequestedGUID)
 	if err != nil {
@@ -48,13 +52,16 @@ func (w *WGIface) UpdateAddr(newAddr string) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	luid := w.Interface.(*driver.Adapter).LUID()
 	addr, err := parseAddress(newAddr)
 	if err != nil {
 		return err
 	}
 
 	w.Address = addr
+	if w.netstack {
+		return w.updateNetstackAddr()
+	}
+	luid := w.Interface.(*driver.Adapter).LUID()
 	return w.assignAddr(luid)
 }
 
@@ -0,0 +1,365 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2017-2023 WireGuard LLC. All Rights Reserved.
+ *
+ * Adapted from golang.zx2c4.com/wireguard/tun/netstack. The package can't be imported: the wireguard version this
+ * module pins doesn't include it, and the versions that do implement a different tun.Device interface.
+ * SetAddress and Ping were added for NetBird.
+ */
+
+// Package netstack runs the tunnel on an in-process gVisor network stack instead of a TUN device of the host.
+// It doesn't need root privileges, the traffic of the mesh only reaches the connections opened on the stack.
+package netstack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	xicmp "golang.org/x/net/icmp"
+	xipv4 "golang.org/x/net/ipv4"
+	"golang.zx2c4.com/wireguard/tun"
+	"gvisor.dev/gvisor/pkg/buffer"
+	"gvisor.dev/gvisor/pkg/tcpip"
+	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
+	"gvisor.dev/gvisor/pkg/tcpip/header"
+	"gvisor.dev/gvisor/pkg/tcpip/link/channel"
+	"gvisor.dev/gvisor/pkg/tcpip/network/ipv4"
+	"gvisor.dev/gvisor/pkg/tcpip/network/ipv6"
+	"gvisor.dev/gvisor/pkg/tcpip/stack"
+	"gvisor.dev/gvisor/pkg/tcpip/transport/icmp"
+	"gvisor.dev/gvisor/pkg/tcpip/transport/tcp"
+	"gvisor.dev/gvisor/pkg/tcpip/transport/udp"
+	"gvisor.dev/gvisor/pkg/waiter"
+)
+
+const (
+	nicID = 1
+	// outboundQueueSize is the number of packets the stack can queue for the WireGuard device
+	outboundQueueSize = 1024
+)
+
+// Tun is a tun.Device backed by an in-process network stack
+type Tun struct {
+	name           string
+	mtu            int
+	ep             *channel.Endpoint
+	stack          *stack.Stack
+	events         chan tun.Event
+	incomingPacket chan []byte
+	closeOnce      sync.Once
+
+	mu   sync.Mutex
+	addr netip.Prefix
+}
+
+// CreateTUN creates a network stack with the given address and returns the tun.Device WireGuard reads the
+// outgoing packets of the stack from and writes the decrypted packets into
+func CreateTUN(name string, addr netip.Prefix, mtu int) (*Tun, error) {
+	opts := stack.Options{
+		NetworkProtocols:   []stack.NetworkProtocolFactory{ipv4.NewProtocol, ipv6.NewProtocol},
+		TransportProtocols: []stack.TransportProtocolFactory{tcp.NewProtocol, udp.NewProtocol, icmp.NewProtocol4, icmp.NewProtocol6},
+		HandleLocal:        true,
+	}
+	t := &Tun{
+		name:           name,
+		mtu:            mtu,
+		ep:             channel.New(outboundQueueSize, uint32(mtu), ""),
+		stack:          stack.New(opts),
+		events:         make(chan tun.Event, 10),
+		incomingPacket: make(chan []byte),
+	}
+
+	sackEnabledOpt := tcpip.TCPSACKEnabled(true)
+	if tcpipErr := t.stack.SetTransportProtocolOption(tcp.ProtocolNumber, &sackEnabledOpt); tcpipErr != nil {
+		return nil, fmt.Errorf("enable TCP SACK: %v", tcpipErr)
+	}
+
+	t.ep.AddNotify(t)
+	if tcpipErr := t.stack.CreateNIC(nicID, t.ep); tcpipErr != nil {
+		return nil, fmt.Errorf("create NIC: %v", tcpipErr)
+	}
+
+	if err := t.SetAddress(addr); err != nil {
+		return nil, err
+	}
+
+	t.stack.AddRoute(tcpip.Route{Destination: header.IPv4EmptySubnet, NIC: nicID})
+	t.stack.AddRoute(tcpip.Route{Destination: header.IPv6EmptySubnet, NIC: nicID})
+
+	t.events <- tun.EventUp
+	return t, nil
+}
+
+// SetAddress replaces the address of the stack
+func (t *Tun) SetAddress(addr netip.Prefix) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.addr.IsValid() {
+		if tcpipErr := t.stack.RemoveAddress(nicID, tcpip.AddrFromSlice(t.addr.Addr().AsSlice())); tcpipErr != nil {
+			return fmt.Errorf("remove address %s: %v", t.addr, tcpipErr)
+		}
+	}
+
+	protocol := ipv4.ProtocolNumber
+	if addr.Addr().Is6() {
+		protocol = ipv6.ProtocolNumber
+	}
+	protoAddr := tcpip.ProtocolAddress{
+		Protocol: protocol,
+		AddressWithPrefix: tcpip.AddressWithPrefix{
+			Address:   tcpip.AddrFromSlice(addr.Addr().AsSlice()),
+			PrefixLen: addr.Bits(),
+		},
+	}
+	if tcpipErr := t.stack.AddProtocolAddress(nicID, protoAddr, stack.AddressProperties{}); tcpipErr != nil {
+		return fmt.Errorf("add address %s: %v", addr, tcpipErr)
+	}
+	t.addr = addr
+	return nil
+}
+
+// Address returns the address of the stack
+func (t *Tun) Address() netip.Prefix {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.addr
+}
+
+// File returns nil, the stack has no file descriptor
+func (t *Tun) File() *os.File {
+	return nil
+}
+
+// Name returns the name of the interface the stack replaces
+func (t *Tun) Name() (string, error) {
+	return t.name, nil
+}
+
+// MTU returns the MTU of the stack
+func (t *Tun) MTU() (int, error) {
+	return t.mtu, nil
+}
+
+// Events returns the channel WireGuard receives the state changes of the device from
+func (t *Tun) Events() chan tun.Event {
+	return t.events
+}
+
+// Read blocks until the stack sends a packet and copies it into buf at offset
+func (t *Tun) Read(buf []byte, offset int) (int, error) {
+	packet, ok := <-t.incomingPacket
+	if !ok {
+		return 0, os.ErrClosed
+	}
+	return copy(buf[offset:], packet), nil
+}
+
+// Write delivers the packet in buf at offset to the stack
+func (t *Tun) Write(buf []byte, offset int) (int, error) {
+	packet := buf[offset:]
+	if len(packet) == 0 {
+		return 0, nil
+	}
+
+	pkb := stack.NewPacketBuffer(stack.PacketBufferOptions{Payload: buffer.MakeWithData(packet)})
+	defer pkb.DecRef()
+	switch packet[0] >> 4 {
+	case 4:
+		t.ep.InjectInbound(header.IPv4ProtocolNumber, pkb)
+	case 6:
+		t.ep.InjectInbound(header.IPv6ProtocolNumber, pkb)
+	}
+
+	return len(buf), nil
+}
+
+// Flush does nothing, the packets are delivered on Write
+func (t *Tun) Flush() error {
+	return nil
+}
+
+// WriteNotify is called by the stack when a packet is queued for WireGuard
+func (t *Tun) WriteNotify() {
+	pkt := t.ep.Read()
+	if pkt == nil {
+		return
+	}
+
+	view := pkt.ToView()
+	pkt.DecRef()
+
+	t.incomingPacket <- view.AsSlice()
+}
+
+// Close stops the stack and the device
+func (t *Tun) Close() error {
+	t.closeOnce.Do(func() {
+		t.stack.RemoveNIC(nicID)
+		t.stack.Close()
+		t.ep.Close()
+
+		t.events <- tun.EventDown
+		close(t.events)
+		close(t.incomingPacket)
+	})
+	return nil
+}
+
+func convertToFullAddr(endpoint netip.AddrPort) (tcpip.FullAddress, tcpip.NetworkProtocolNumber) {
+	protoNumber := ipv4.ProtocolNumber
+	if endpoint.Addr().Is6() {
+		protoNumber = ipv6.ProtocolNumber
+	}
+	return tcpip.FullAddress{
+		NIC:  nicID,
+		Addr: tcpip.AddrFromSlice(endpoint.Addr().AsSlice()),
+		Port: endpoint.Port(),
+	}, protoNumber
+}
+
+// DialContext connects to the address on the named network over the stack, tcp and udp networks are supported
+func (t *Tun) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	addrPort, err := resolveAddrPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	fa, pn := convertToFullAddr(addrPort)
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return gonet.DialContextTCP(ctx, t.stack, fa, pn)
+	case "udp", "udp4", "udp6":
+		return gonet.DialUDP(t.stack, nil, &fa, pn)
+	default:
+		return nil, fmt.Errorf("unsupported network %s", network)
+	}
+}
+
+// Dial connects to the address on the named network over the stack
+func (t *Tun) Dial(network, address string) (net.Conn, error) {
+	return t.DialContext(context.Background(), network, address)
+}
+
+// Listen listens for TCP connections on the address of the stack
+func (t *Tun) Listen(network, address string) (net.Listener, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("unsupported network %s", network)
+	}
+
+	addrPort, err := resolveListenAddrPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	fa, pn := convertToFullAddr(addrPort)
+	if !addrPort.Addr().IsValid() || addrPort.Addr().IsUnspecified() {
+		fa.Addr = tcpip.Address{}
+	}
+	return gonet.ListenTCP(t.stack, fa, pn)
+}
+
+// ListenUDP listens for UDP packets on the address of the stack
+func (t *Tun) ListenUDP(address string) (net.PacketConn, error) {
+	addrPort, err := resolveListenAddrPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	fa, pn := convertToFullAddr(addrPort)
+	if !addrPort.Addr().IsValid() || addrPort.Addr().IsUnspecified() {
+		fa.Addr = tcpip.Address{}
+	}
+	return gonet.DialUDP(t.stack, &fa, nil, pn)
+}
+
+// Ping sends an ICMP echo request to the IPv4 address over the stack and returns the round-trip time of the reply
+func (t *Tun) Ping(ctx context.Context, addr netip.Addr) (time.Duration, error) {
+	if !addr.Is4() {
+		return 0, fmt.Errorf("only IPv4 addresses are supported, got %s", addr)
+	}
+
+	var wq waiter.Queue
+	ep, tcpipErr := t.stack.NewEndpoint(icmp.ProtocolNumber4, ipv4.ProtocolNumber, &wq)
+	if tcpipErr != nil {
+		return 0, fmt.Errorf("create ICMP endpoint: %v", tcpipErr)
+	}
+	defer ep.Close()
+
+	fa, _ := convertToFullAddr(netip.AddrPortFrom(addr, 0))
+	if tcpipErr = ep.Connect(fa); tcpipErr != nil {
+		return 0, fmt.Errorf("connect ICMP endpoint to %s: %v", addr, tcpipErr)
+	}
+
+	// the stack sets the echo identifier to the one of the endpoint
+	msg := xicmp.Message{
+		Type: xipv4.ICMPTypeEcho,
+		Body: &xicmp.Echo{Seq: rand.Intn(0xffff), Data: []byte("netbird")},
+	}
+	payload, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	waitEntry, notifyCh := waiter.NewChannelEntry(waiter.EventIn)
+	wq.EventRegister(&waitEntry)
+	defer wq.EventUnregister(&waitEntry)
+
+	start := time.Now()
+	if _, tcpipErr = ep.Write(bytes.NewReader(payload), tcpip.WriteOptions{}); tcpipErr != nil {
+		return 0, fmt.Errorf("send ICMP echo request to %s: %v", addr, tcpipErr)
+	}
+
+	for {
+		var reply bytes.Buffer
+		_, tcpipErr = ep.Read(&reply, tcpip.ReadOptions{})
+		if _, ok := tcpipErr.(*tcpip.ErrWouldBlock); ok {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-notifyCh:
+				continue
+			}
+		}
+		if tcpipErr != nil {
+			return 0, fmt.Errorf("receive ICMP echo reply from %s: %v", addr, tcpipErr)
+		}
+
+		rtt := time.Since(start)
+		parsed, err := xicmp.ParseMessage(1, reply.Bytes())
+		if err != nil || parsed.Type != xipv4.ICMPTypeEchoReply {
+			continue
+		}
+		return rtt, nil
+	}
+}
+
+// resolveAddrPort parses a host:port address, the stack has no resolver so the host has to be an IP address
+func resolveAddrPort(address string) (netip.AddrPort, error) {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return netip.AddrPort{}, fmt.Errorf("parse address %s, only IP addresses are supported: %v", address, err)
+	}
+	return netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port()), nil
+}
+
+// resolveListenAddrPort parses a listen address, the host may be empty to listen on any address
+func resolveListenAddrPort(address string) (netip.AddrPort, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	return resolveAddrPort(net.JoinHostPort(host, port))
+}
@@ -0,0 +1,176 @@
+package iface
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+// toUAPI converts the configuration to the set operation of the WireGuard cross-platform userspace protocol.
+// It is used to configure a wireguard-go device directly when it has no UAPI socket, e.g. in netstack mode
+func toUAPI(config wgtypes.Config) string {
+	var b strings.Builder
+
+	if config.PrivateKey != nil {
+		fmt.Fprintf(&b, "private_key=%s\n", hex.EncodeToString(config.PrivateKey[:]))
+	}
+	if config.ListenPort != nil {
+		fmt.Fprintf(&b, "listen_port=%d\n", *config.ListenPort)
+	}
+	if config.FirewallMark != nil {
+		fmt.Fprintf(&b, "fwmark=%d\n", *config.FirewallMark)
+	}
+	if config.ReplacePeers {
+		b.WriteString("replace_peers=true\n")
+	}
+
+	for _, peer := range config.Peers {
+		fmt.Fprintf(&b, "public_key=%s\n", hex.EncodeToString(peer.PublicKey[:]))
+		if peer.Remove {
+			b.WriteString("remove=true\n")
+			continue
+		}
+		if peer.UpdateOnly {
+			b.WriteString("update_only=true\n")
+		}
+		if peer.PresharedKey != nil {
+			fmt.Fprintf(&b, "preshared_key=%s\n", hex.EncodeToString(peer.PresharedKey[:]))
+		}
+		if peer.Endpoint != nil {
+			fmt.Fprintf(&b, "endpoint=%s\n", peer.Endpoint.String())
+		}
+		if peer.PersistentKeepaliveInterval != nil {
+			fmt.Fprintf(&b, "persistent_keepalive_interval=%d\n", int(peer.PersistentKeepaliveInterval.Seconds()))
+		}
+		if peer.ReplaceAllowedIPs {
+			b.WriteString("replace_allowed_ips=true\n")
+		}
+		for _, allowedIP := range peer.AllowedIPs {
+			fmt.Fprintf(&b, "allowed_ip=%s\n", allowedIP.String())
+		}
+	}
+
+	return b.String()
+}
+
+// parseUAPI parses the result of the get operation of the WireGuard cross-platform userspace protocol
+func parseUAPI(name, uapi string) (*wgtypes.Device, error) {
+	device := &wgtypes.Device{Name: name, Type: wgtypes.Userspace}
+	var peer *wgtypes.Peer
+	var handshakeSec, handshakeNsec int64
+
+	scanner := bufio.NewScanner(strings.NewReader(uapi))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid UAPI line %q", line)
+		}
+
+		if key == "public_key" {
+			if peer != nil {
+				peer.LastHandshakeTime = handshakeTime(handshakeSec, handshakeNsec)
+				device.Peers = append(device.Peers, *peer)
+			}
+			peer = &wgtypes.Peer{}
+			handshakeSec, handshakeNsec = 0, 0
+		}
+
+		var err error
+		if peer == nil {
+			err = parseUAPIDeviceField(device, key, value)
+		} else {
+			err = parseUAPIPeerField(peer, key, value, &handshakeSec, &handshakeNsec)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid UAPI line %q: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if peer != nil {
+		peer.LastHandshakeTime = handshakeTime(handshakeSec, handshakeNsec)
+		device.Peers = append(device.Peers, *peer)
+	}
+	return device, nil
+}
+
+func parseUAPIDeviceField(device *wgtypes.Device, key, value string) error {
+	var err error
+	switch key {
+	case "private_key":
+		device.PrivateKey, err = parseHexKey(value)
+		if err == nil {
+			device.PublicKey = device.PrivateKey.PublicKey()
+		}
+	case "listen_port":
+		device.ListenPort, err = strconv.Atoi(value)
+	case "fwmark":
+		device.FirewallMark, err = strconv.Atoi(value)
+	case "errno":
+		if value != "0" {
+			err = fmt.Errorf("device returned errno %s", value)
+		}
+	}
+	return err
+}
+
+func parseUAPIPeerField(peer *wgtypes.Peer, key, value string, handshakeSec, handshakeNsec *int64) error {
+	var err error
+	switch key {
+	case "public_key":
+		peer.PublicKey, err = parseHexKey(value)
+	case "preshared_key":
+		peer.PresharedKey, err = parseHexKey(value)
+	case "endpoint":
+		peer.Endpoint, err = net.ResolveUDPAddr("udp", value)
+	case "persistent_keepalive_interval":
+		var seconds int
+		seconds, err = strconv.Atoi(value)
+		peer.PersistentKeepaliveInterval = time.Duration(seconds) * time.Second
+	case "last_handshake_time_sec":
+		*handshakeSec, err = strconv.ParseInt(value, 10, 64)
+	case "last_handshake_time_nsec":
+		*handshakeNsec, err = strconv.ParseInt(value, 10, 64)
+	case "rx_bytes":
+		peer.ReceiveBytes, err = strconv.ParseInt(value, 10, 64)
+	case "tx_bytes":
+		peer.TransmitBytes, err = strconv.ParseInt(value, 10, 64)
+	case "allowed_ip":
+		var ipNet *net.IPNet
+		_, ipNet, err = net.ParseCIDR(value)
+		if err == nil {
+			peer.AllowedIPs = append(peer.AllowedIPs, *ipNet)
+		}
+	case "protocol_version":
+		peer.ProtocolVersion, err = strconv.Atoi(value)
+	}
+	return err
+}
+
+func parseHexKey(value string) (wgtypes.Key, error) {
+	b, err := hex.DecodeString(value)
+	if err != nil {
+		return wgtypes.Key{}, err
+	}
+	return wgtypes.NewKey(b)
+}
+
+// handshakeTime returns the zero time if there was no handshake yet, like wgctrl does
+func handshakeTime(sec, nsec int64) time.Time {
+	if sec == 0 && nsec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, nsec)
+}
@@ -76,18 +76,25 @@ func startManagement(t *testing.T) (*grpc.Server, net.Listener) {
 }
 
 func startMockManagement(t *testing.T) (*grpc.Server, net.Listener, *mock_server.ManagementServiceServerMock, wgtypes.Key) {
-	lis, err := net.Listen("tcp", ":0")
+	serverKey, err := wgtypes.GenerateKey()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	s := grpc.NewServer()
+	s, lis, mgmtMockServer := startMockManagementWithKey(t, serverKey)
+	return s, lis, mgmtMockServer, serverKey
+}
 
-	serverKey, err := wgtypes.GenerateKey()
+// startMockManagementWithKey starts a mock Management service that presents the key and sends an empty update
+// on every Sync stream
+func startMockManagementWithKey(t *testing.T, serverKey wgtypes.Key) (*grpc.Server, net.Listener, *mock_server.ManagementServiceServerMock) {
+	lis, err := net.Listen("tcp", ":0")
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	s := grpc.NewServer()
+
 	mgmtMockServer := &mock_server.ManagementServiceServerMock{
 		GetServerKeyFunc: func(context.Context, *proto.Empty) (*proto.ServerKeyResponse, error) {
 			response := &proto.ServerKeyResponse{
@@ -95,6 +102,22 @@ func startMockManagement(t *testing.T) (*grpc.Server, net.Listener, *mock_server
 			}
 			return response, nil
 		},
+		SyncFunc: func(msg *proto.EncryptedMessage, stream proto.ManagementService_SyncServer) error {
+			peerKey, err := wgtypes.ParseKey(msg.GetWgPubKey())
+			if err != nil {
+				return err
+			}
+			body, err := encryption.EncryptMessage(peerKey, serverKey, &proto.SyncResponse{})
+			if err != nil {
+				return err
+			}
+			err = stream.Send(&proto.EncryptedMessage{WgPubKey: serverKey.PublicKey().String(), Body: body})
+			if err != nil {
+				return err
+			}
+			<-stream.Context().Done()
+			return nil
+		},
 	}
 
 	mgmtProto.RegisterManagementServiceServer(s, mgmtMockServer)
@@ -105,7 +128,7 @@ func startMockManagement(t *testing.T) (*grpc.Server, net.Listener, *mock_server
 		}
 	}()
 
-	return s, lis, mgmtMockServer, serverKey
+	return s, lis, mgmtMockServer
 }
 
 func closeManagementSilently(s *grpc.Server, listener net.Listener) {
@@ -394,3 +417,191 @@ func Test_GetDeviceAuthorizationFlow(t *testing.T) {
 	assert.Equal(t, expectedFlowInfo.Provider, flowInfo.Provider, "provider should match")
 	assert.Equal(t, expectedFlowInfo.ProviderConfig.ClientID, flowInfo.ProviderConfig.ClientID, "provider configured client ID should match")
 }
+
+func Test_GetPKCEAuthorizationFlow(t *testing.T) {
+	s, lis, mgmtMockServer, serverKey := startMockManagement(t)
+	defer s.GracefulStop()
+
+	testKey, err := wgtypes.GenerateKey()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	serverAddr := lis.Addr().String()
+	ctx := context.Background()
+
+	client, err := NewClient(ctx, serverAddr, testKey, false)
+	if err != nil {
+		log.Fatalf("error while creating testClient: %v", err)
+	}
+
+	expectedFlowInfo := &proto.PKCEAuthorizationFlow{
+		ProviderConfig: &proto.ProviderConfig{
+			ClientID:              "client",
+			AuthorizationEndpoint: "https://idp.example.com/authorize",
+			RedirectURLs:          []string{"http://localhost:53000/"},
+		},
+	}
+
+	mgmtMockServer.GetPKCEAuthorizationFlowFunc = func(ctx context.Context, req *mgmtProto.EncryptedMessage) (*proto.EncryptedMessage, error) {
+		encryptedResp, err := encryption.EncryptMessage(serverKey, client.key, expectedFlowInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		return &mgmtProto.EncryptedMessage{
+			WgPubKey: serverKey.PublicKey().String(),
+			Body:     encryptedResp,
+			Version:  0,
+		}, nil
+	}
+
+	flowInfo, err := client.GetPKCEAuthorizationFlow(serverKey)
+	if err != nil {
+		t.Error("error while retrieving pkce auth flow information")
+	}
+
+	assert.Equal(t, expectedFlowInfo.ProviderConfig.ClientID, flowInfo.ProviderConfig.ClientID, "provider configured client ID should match")
+	assert.Equal(t, expectedFlowInfo.ProviderConfig.AuthorizationEndpoint, flowInfo.ProviderConfig.AuthorizationEndpoint, "authorization endpoint should match")
+	assert.Equal(t, expectedFlowInfo.ProviderConfig.RedirectURLs, flowInfo.ProviderConfig.RedirectURLs, "redirect URLs should match")
+}
+
+func TestClient_FailoverOnConnect(t *testing.T) {
+	testKey, err := wgtypes.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadListener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadAddr := deadListener.Addr().String()
+	_ = deadListener.Close()
+
+	s, listener := startManagement(t)
+	defer closeManagementSilently(s, listener)
+
+	client, err := NewFailoverClient(context.Background(), []Endpoint{
+		{Addr: deadAddr},
+		{Addr: listener.Addr().String()},
+	}, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close() //nolint
+
+	assert.Equal(t, listener.Addr().String(), client.Endpoint().Addr, "client should connect to the healthy endpoint")
+
+	serverKey, err := client.GetServerPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := wgtypes.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Register(otherKey.PublicKey(), ValidKey, "", system.GetInfo(context.TODO()), nil)
+	assert.Error(t, err, "messages encrypted for another endpoint must not be sent")
+
+	_, err = client.Register(*serverKey, ValidKey, "", system.GetInfo(context.TODO()), nil)
+	assert.NoError(t, err)
+
+	_, err = NewFailoverClient(context.Background(), []Endpoint{{Addr: deadAddr}}, testKey)
+	assert.Error(t, err)
+}
+
+func TestClient_FailoverOnSync(t *testing.T) {
+	testKey, err := wgtypes.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverKey, err := wgtypes.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the endpoints of one Management service share its key
+	sA, listenerA, _ := startMockManagementWithKey(t, serverKey)
+	sB, listenerB, _ := startMockManagementWithKey(t, serverKey)
+	defer closeManagementSilently(sB, listenerB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := NewFailoverClient(ctx, []Endpoint{
+		{Addr: listenerA.Addr().String()},
+		{Addr: listenerB.Addr().String()},
+	}, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close() //nolint
+
+	changes := make(chan Endpoint, 1)
+	client.SetEndpointChangeListener(func(endpoint Endpoint) {
+		changes <- endpoint
+	})
+
+	syncs := make(chan struct{}, 10)
+	go func() {
+		_ = client.Sync(func(msg *mgmtProto.SyncResponse) error {
+			syncs <- struct{}{}
+			return nil
+		})
+	}()
+
+	select {
+	case <-syncs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the first sync")
+	}
+
+	sA.Stop()
+	_ = listenerA.Close()
+
+	select {
+	case endpoint := <-changes:
+		assert.Equal(t, listenerB.Addr().String(), endpoint.Addr)
+	case <-time.After(20 * time.Second):
+		t.Fatal("timeout waiting for the failover")
+	}
+
+	select {
+	case <-syncs:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for a sync from the failover endpoint")
+	}
+}
+
+func TestClient_FailoverRejectsAnotherServerKey(t *testing.T) {
+	testKey, err := wgtypes.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sA, listenerA, _, serverKeyA := startMockManagement(t)
+	defer closeManagementSilently(sA, listenerA)
+	sB, listenerB, _, _ := startMockManagement(t)
+	defer closeManagementSilently(sB, listenerB)
+
+	client, err := NewFailoverClient(context.Background(), []Endpoint{
+		{Addr: listenerA.Addr().String()},
+		{Addr: listenerB.Addr().String()},
+	}, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close() //nolint
+
+	err = client.connect(1)
+	assert.Error(t, err, "an endpoint presenting another key must be rejected")
+	assert.Equal(t, listenerA.Addr().String(), client.Endpoint().Addr)
+
+	serverKey, err := client.GetServerPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, serverKeyA.PublicKey(), *serverKey)
+}
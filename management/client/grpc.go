@@ -7,6 +7,8 @@ import (
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 	"io"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -22,26 +24,88 @@ import (
 	"google.golang.org/grpc/keepalive"
 )
 
+// Endpoint is the address of a Management service instance
+type Endpoint struct {
+	Addr       string
+	TLSEnabled bool
+}
+
 type GrpcClient struct {
-	key        wgtypes.Key
+	key wgtypes.Key
+	ctx context.Context
+
+	// mu protects the connection to the current endpoint, it is replaced on failover
+	mu         sync.Mutex
+	endpoints  []Endpoint
+	current    int
 	realClient proto.ManagementServiceClient
-	ctx        context.Context
 	conn       *grpc.ClientConn
+	// serverKey is the public key pinned from the first endpoint the client has connected to. Endpoints presenting
+	// another key are rejected and messages encrypted with another key are not sent.
+	// Only the endpoint the key was pinned from can replace it, e.g. after a restart
+	serverKey     *wgtypes.Key
+	serverKeyFrom int
+
+	onEndpointChange func(endpoint Endpoint)
 }
 
 // NewClient creates a new client to Management service
 func NewClient(ctx context.Context, addr string, ourPrivateKey wgtypes.Key, tlsEnabled bool) (*GrpcClient, error) {
+	return NewFailoverClient(ctx, []Endpoint{{Addr: addr, TLSEnabled: tlsEnabled}}, ourPrivateKey)
+}
+
+// NewFailoverClient creates a new client to a Management service reachable at multiple endpoints.
+// It connects to the first healthy endpoint in order and fails over to the next one when the connection is lost
+func NewFailoverClient(ctx context.Context, endpoints []Endpoint, ourPrivateKey wgtypes.Key) (*GrpcClient, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no Management service endpoint provided")
+	}
+
+	c := &GrpcClient{
+		key:       ourPrivateKey,
+		ctx:       ctx,
+		endpoints: endpoints,
+		current:   -1,
+	}
+
+	if len(endpoints) == 1 {
+		err := c.connect(0)
+		if err != nil {
+			log.Errorf("failed creating connection to Management Service %v", err)
+			return nil, err
+		}
+		return c, nil
+	}
+
+	var errs []string
+	for i := range endpoints {
+		err := c.connect(i)
+		if err == nil {
+			return c, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", endpoints[i].Addr, err))
+	}
+
+	log.Errorf("failed creating connection to any Management Service endpoint: %s", strings.Join(errs, ", "))
+	return nil, fmt.Errorf("unable to connect to any Management service endpoint: %s", strings.Join(errs, ", "))
+}
+
+// connect dials an endpoint and makes it the current one.
+// With multiple endpoints the endpoint has to answer with its server key to be considered healthy
+func (c *GrpcClient) connect(i int) error {
+	endpoint := c.endpoints[i]
+
 	transportOption := grpc.WithTransportCredentials(insecure.NewCredentials())
 
-	if tlsEnabled {
+	if endpoint.TLSEnabled {
 		transportOption = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
 	}
 
-	mgmCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	mgmCtx, cancel := context.WithTimeout(c.ctx, 5*time.Second)
 	defer cancel()
 	conn, err := grpc.DialContext(
 		mgmCtx,
-		addr,
+		endpoint.Addr,
 		transportOption,
 		grpc.WithBlock(),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
@@ -49,23 +113,133 @@ func NewClient(ctx context.Context, addr string, ourPrivateKey wgtypes.Key, tlsE
 			Timeout: 10 * time.Second,
 		}))
 	if err != nil {
-		log.Errorf("failed creating connection to Management Service %v", err)
-		return nil, err
+		return err
 	}
 
 	realClient := proto.NewManagementServiceClient(conn)
 
-	return &GrpcClient{
-		key:        ourPrivateKey,
-		realClient: realClient,
-		ctx:        ctx,
-		conn:       conn,
-	}, nil
+	if len(c.endpoints) > 1 {
+		serverKey, err := requestServerKey(mgmCtx, realClient)
+		if err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("health check failed: %v", err)
+		}
+		err = c.pinServerKey(i, *serverKey)
+		if err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	oldConn := c.conn
+	changed := c.current != -1 && c.current != i
+	c.conn = conn
+	c.realClient = realClient
+	c.current = i
+	onEndpointChange := c.onEndpointChange
+	c.mu.Unlock()
+
+	if oldConn != nil {
+		_ = oldConn.Close()
+	}
+
+	if changed {
+		log.Infof("failed over to the Management service endpoint %s", endpoint.Addr)
+		if onEndpointChange != nil {
+			onEndpointChange(endpoint)
+		}
+	}
+
+	return nil
+}
+
+// failover connects to the next healthy endpoint after the current one.
+// The current endpoint is tried last, it returns an error if no endpoint is healthy
+func (c *GrpcClient) failover() error {
+	c.mu.Lock()
+	current := c.current
+	c.mu.Unlock()
+
+	var errs []string
+	for n := 1; n <= len(c.endpoints); n++ {
+		i := (current + n) % len(c.endpoints)
+		err := c.connect(i)
+		if err == nil {
+			return nil
+		}
+		log.Debugf("Management service endpoint %s is not healthy: %v", c.endpoints[i].Addr, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", c.endpoints[i].Addr, err))
+	}
+	return fmt.Errorf("no healthy Management service endpoint: %s", strings.Join(errs, ", "))
+}
+
+// failoverIfUnavailable fails over to another endpoint if err shows that the current endpoint is unreachable
+func (c *GrpcClient) failoverIfUnavailable(err error) {
+	if len(c.endpoints) < 2 {
+		return
+	}
+	if s, ok := gstatus.FromError(err); !ok || s.Code() != codes.Unavailable {
+		return
+	}
+
+	err = c.failover()
+	if err != nil {
+		log.Debug(err)
+	}
+}
+
+// Endpoint returns the endpoint the client is connected to
+func (c *GrpcClient) Endpoint() Endpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.endpoints[c.current]
+}
+
+// SetEndpointChangeListener sets a function called after the client has failed over to another endpoint
+func (c *GrpcClient) SetEndpointChangeListener(listener func(endpoint Endpoint)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEndpointChange = listener
+}
+
+// connection returns the gRPC client and connection of the current endpoint
+func (c *GrpcClient) connection() (proto.ManagementServiceClient, *grpc.ClientConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.realClient, c.conn
+}
+
+// pinServerKey pins the key presented by the endpoint i, or returns an error if it differs from the pinned key.
+// It prevents failing over to an endpoint that doesn't belong to the same Management service
+func (c *GrpcClient) pinServerKey(i int, serverKey wgtypes.Key) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.serverKey != nil && *c.serverKey != serverKey && c.serverKeyFrom != i {
+		return fmt.Errorf("Management service endpoint %s presents the server key %s instead of the pinned key %s",
+			c.endpoints[i].Addr, serverKey.String(), c.serverKey.String())
+	}
+	c.serverKey = &serverKey
+	c.serverKeyFrom = i
+	return nil
+}
+
+// verifyServerKey returns an error if the key is not the pinned one.
+// It prevents sending messages encrypted for one endpoint to another after a failover
+func (c *GrpcClient) verifyServerKey(serverKey wgtypes.Key) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.serverKey != nil && *c.serverKey != serverKey {
+		return fmt.Errorf("server key %s doesn't belong to the connected Management service endpoint %s",
+			serverKey.String(), c.endpoints[c.current].Addr)
+	}
+	return nil
 }
 
 // Close closes connection to the Management Service
 func (c *GrpcClient) Close() error {
-	return c.conn.Close()
+	_, conn := c.connection()
+	return conn.Close()
 }
 
 // defaultBackoff is a basic backoff mechanism for general issues
@@ -84,7 +258,8 @@ func defaultBackoff(ctx context.Context) backoff.BackOff {
 // ready indicates whether the client is okay and ready to be used
 // for now it just checks whether gRPC connection to the service is ready
 func (c *GrpcClient) ready() bool {
-	return c.conn.GetState() == connectivity.Ready || c.conn.GetState() == connectivity.Idle
+	_, conn := c.connection()
+	return conn.GetState() == connectivity.Ready || conn.GetState() == connectivity.Idle
 }
 
 // Sync wraps the real client's Sync endpoint call and takes care of retries and encryption/decryption of messages
@@ -93,19 +268,28 @@ func (c *GrpcClient) Sync(msgHandler func(msg *proto.SyncResponse) error) error
 	backOff := defaultBackoff(c.ctx)
 
 	operation := func() error {
-		log.Debugf("management connection state %v", c.conn.GetState())
+		_, conn := c.connection()
+		log.Debugf("management connection state %v", conn.GetState())
 
-		connState := c.conn.GetState()
+		connState := conn.GetState()
 		if connState == connectivity.Shutdown {
 			return backoff.Permanent(fmt.Errorf("connection to management has been shut down"))
 		} else if !(connState == connectivity.Ready || connState == connectivity.Idle) {
-			c.conn.WaitForStateChange(c.ctx, connState)
+			if len(c.endpoints) > 1 {
+				err := c.failover()
+				if err == nil {
+					return fmt.Errorf("connection to management was in %s state, failed over to %s", connState, c.Endpoint().Addr)
+				}
+				log.Debug(err)
+			}
+			conn.WaitForStateChange(c.ctx, connState)
 			return fmt.Errorf("connection to management is not ready and in %s state", connState)
 		}
 
 		serverPubKey, err := c.GetServerPublicKey()
 		if err != nil {
 			log.Debugf("failed getting Management Service public key: %s", err)
+			c.failoverIfUnavailable(err)
 			return err
 		}
 
@@ -117,6 +301,7 @@ func (c *GrpcClient) Sync(msgHandler func(msg *proto.SyncResponse) error) error
 			if s, ok := gstatus.FromError(err); ok && s.Code() == codes.PermissionDenied {
 				return backoff.Permanent(err) // unrecoverable error, propagate to the upper layer
 			}
+			c.failoverIfUnavailable(err)
 			return err
 		}
 
@@ -159,7 +344,8 @@ func (c *GrpcClient) connectToStream(ctx context.Context, serverPubKey wgtypes.K
 		return nil, err
 	}
 	syncReq := &proto.EncryptedMessage{WgPubKey: myPublicKey.String(), Body: encryptedReq}
-	sync, err := c.realClient.Sync(ctx, syncReq)
+	realClient, _ := c.connection()
+	sync, err := realClient.Sync(ctx, syncReq)
 	if err != nil {
 		return nil, err
 	}
@@ -202,7 +388,26 @@ func (c *GrpcClient) GetServerPublicKey() (*wgtypes.Key, error) {
 
 	mgmCtx, cancel := context.WithTimeout(c.ctx, 5*time.Second)
 	defer cancel()
-	resp, err := c.realClient.GetServerKey(mgmCtx, &proto.Empty{})
+	c.mu.Lock()
+	realClient, current := c.realClient, c.current
+	c.mu.Unlock()
+	serverKey, err := requestServerKey(mgmCtx, realClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.endpoints) > 1 {
+		err = c.pinServerKey(current, *serverKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return serverKey, nil
+}
+
+func requestServerKey(ctx context.Context, realClient proto.ManagementServiceClient) (*wgtypes.Key, error) {
+	resp, err := realClient.GetServerKey(ctx, &proto.Empty{})
 	if err != nil {
 		return nil, err
 	}
@@ -219,6 +424,10 @@ func (c *GrpcClient) login(serverKey wgtypes.Key, req *proto.LoginRequest) (*pro
 	if !c.ready() {
 		return nil, fmt.Errorf("no connection to management")
 	}
+	err := c.verifyServerKey(serverKey)
+	if err != nil {
+		return nil, err
+	}
 	loginReq, err := encryption.EncryptMessage(serverKey, c.key, req)
 	if err != nil {
 		log.Errorf("failed to encrypt message: %s", err)
@@ -226,7 +435,8 @@ func (c *GrpcClient) login(serverKey wgtypes.Key, req *proto.LoginRequest) (*pro
 	}
 	mgmCtx, cancel := context.WithTimeout(c.ctx, 5*time.Second)
 	defer cancel()
-	resp, err := c.realClient.Login(mgmCtx, &proto.EncryptedMessage{
+	realClient, _ := c.connection()
+	resp, err := realClient.Login(mgmCtx, &proto.EncryptedMessage{
 		WgPubKey: c.key.PublicKey().String(),
 		Body:     loginReq,
 	})
@@ -273,13 +483,19 @@ func (c *GrpcClient) GetDeviceAuthorizationFlow(serverKey wgtypes.Key) (*proto.D
 	mgmCtx, cancel := context.WithTimeout(c.ctx, time.Second*2)
 	defer cancel()
 
+	err := c.verifyServerKey(serverKey)
+	if err != nil {
+		return nil, err
+	}
+
 	message := &proto.DeviceAuthorizationFlowRequest{}
 	encryptedMSG, err := encryption.EncryptMessage(serverKey, c.key, message)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.realClient.GetDeviceAuthorizationFlow(mgmCtx, &proto.EncryptedMessage{
+	realClient, _ := c.connection()
+	resp, err := realClient.GetDeviceAuthorizationFlow(mgmCtx, &proto.EncryptedMessage{
 		WgPubKey: c.key.PublicKey().String(),
 		Body:     encryptedMSG},
 	)
@@ -298,6 +514,103 @@ func (c *GrpcClient) GetDeviceAuthorizationFlow(serverKey wgtypes.Key) (*proto.D
 	return flowInfoResp, nil
 }
 
+// GetPKCEAuthorizationFlow returns a PKCE authorization code flow information.
+// It also takes care of encrypting and decrypting messages.
+func (c *GrpcClient) GetPKCEAuthorizationFlow(serverKey wgtypes.Key) (*proto.PKCEAuthorizationFlow, error) {
+	if !c.ready() {
+		return nil, fmt.Errorf("no connection to management in order to get pkce authorization flow")
+	}
+	mgmCtx, cancel := context.WithTimeout(c.ctx, time.Second*2)
+	defer cancel()
+
+	err := c.verifyServerKey(serverKey)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &proto.PKCEAuthorizationFlowRequest{}
+	encryptedMSG, err := encryption.EncryptMessage(serverKey, c.key, message)
+	if err != nil {
+		return nil, err
+	}
+
+	realClient, _ := c.connection()
+	resp, err := realClient.GetPKCEAuthorizationFlow(mgmCtx, &proto.EncryptedMessage{
+		WgPubKey: c.key.PublicKey().String(),
+		Body:     encryptedMSG},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	flowInfoResp := &proto.PKCEAuthorizationFlow{}
+	err = encryption.DecryptMessage(serverKey, c.key, resp.Body, flowInfoResp)
+	if err != nil {
+		errWithMSG := fmt.Errorf("failed to decrypt pkce authorization flow message: %s", err)
+		log.Error(errWithMSG)
+		return nil, errWithMSG
+	}
+
+	return flowInfoResp, nil
+}
+
+// ReportRouteConflicts sends the current route conflicts of the peer to the Management Service.
+// An empty list clears the previously reported conflicts
+func (c *GrpcClient) ReportRouteConflicts(conflicts []*proto.RouteConflict) error {
+	if !c.ready() {
+		return fmt.Errorf("no connection to management in order to report route conflicts")
+	}
+
+	serverKey, err := c.GetServerPublicKey()
+	if err != nil {
+		return err
+	}
+
+	mgmCtx, cancel := context.WithTimeout(c.ctx, time.Second*2)
+	defer cancel()
+
+	message := &proto.RouteConflictsReport{Conflicts: conflicts}
+	encryptedMSG, err := encryption.EncryptMessage(*serverKey, c.key, message)
+	if err != nil {
+		return err
+	}
+
+	realClient, _ := c.connection()
+	_, err = realClient.ReportRouteConflicts(mgmCtx, &proto.EncryptedMessage{
+		WgPubKey: c.key.PublicKey().String(),
+		Body:     encryptedMSG},
+	)
+	return err
+}
+
+// ReportRouteTraffic sends the traffic forwarded by the peer for each of the routes it routes to the Management Service
+func (c *GrpcClient) ReportRouteTraffic(routesTraffic []*proto.RouteTraffic) error {
+	if !c.ready() {
+		return fmt.Errorf("no connection to management in order to report routes traffic")
+	}
+
+	serverKey, err := c.GetServerPublicKey()
+	if err != nil {
+		return err
+	}
+
+	mgmCtx, cancel := context.WithTimeout(c.ctx, time.Second*2)
+	defer cancel()
+
+	message := &proto.RouteTrafficReport{Routes: routesTraffic}
+	encryptedMSG, err := encryption.EncryptMessage(*serverKey, c.key, message)
+	if err != nil {
+		return err
+	}
+
+	realClient, _ := c.connection()
+	_, err = realClient.ReportRouteTraffic(mgmCtx, &proto.EncryptedMessage{
+		WgPubKey: c.key.PublicKey().String(),
+		Body:     encryptedMSG},
+	)
+	return err
+}
+
 func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 	if info == nil {
 		return nil
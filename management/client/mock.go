@@ -13,6 +13,9 @@ type MockClient struct {
 	RegisterFunc                   func(serverKey wgtypes.Key, setupKey string, jwtToken string, info *system.Info, sshKey []byte) (*proto.LoginResponse, error)
 	LoginFunc                      func(serverKey wgtypes.Key, info *system.Info, sshKey []byte) (*proto.LoginResponse, error)
 	GetDeviceAuthorizationFlowFunc func(serverKey wgtypes.Key) (*proto.DeviceAuthorizationFlow, error)
+	GetPKCEAuthorizationFlowFunc   func(serverKey wgtypes.Key) (*proto.PKCEAuthorizationFlow, error)
+	ReportRouteConflictsFunc       func(conflicts []*proto.RouteConflict) error
+	ReportRouteTrafficFunc         func(routesTraffic []*proto.RouteTraffic) error
 }
 
 func (m *MockClient) Close() error {
@@ -56,3 +59,24 @@ func (m *MockClient) GetDeviceAuthorizationFlow(serverKey wgtypes.Key) (*proto.D
 	}
 	return m.GetDeviceAuthorizationFlowFunc(serverKey)
 }
+
+func (m *MockClient) GetPKCEAuthorizationFlow(serverKey wgtypes.Key) (*proto.PKCEAuthorizationFlow, error) {
+	if m.GetPKCEAuthorizationFlowFunc == nil {
+		return nil, nil
+	}
+	return m.GetPKCEAuthorizationFlowFunc(serverKey)
+}
+
+func (m *MockClient) ReportRouteConflicts(conflicts []*proto.RouteConflict) error {
+	if m.ReportRouteConflictsFunc == nil {
+		return nil
+	}
+	return m.ReportRouteConflictsFunc(conflicts)
+}
+
+func (m *MockClient) ReportRouteTraffic(routesTraffic []*proto.RouteTraffic) error {
+	if m.ReportRouteTrafficFunc == nil {
+		return nil
+	}
+	return m.ReportRouteTrafficFunc(routesTraffic)
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -358,7 +357,7 @@ func loadMgmtConfig(mgmtConfigPath string) (*server.Config, error) {
 	if oidcEndpoint != "" {
 		// if OIDCConfigEndpoint is specified, we can load DeviceAuthEndpoint and TokenEndpoint automatically
 		log.Infof("loading OIDC configuration from the provided IDP configuration endpoint %s", oidcEndpoint)
-		oidcConfig, err := fetchOIDCConfig(oidcEndpoint)
+		oidcConfig, err := server.FetchOIDCConfig(oidcEndpoint)
 		if err != nil {
 			return nil, err
 		}
@@ -379,6 +378,8 @@ func loadMgmtConfig(mgmtConfigPath string) (*server.Config, error) {
 			log.Infof("overriding DeviceAuthorizationFlow.DeviceAuthEndpoint with a new value: %s, previously configured value: %s",
 				oidcConfig.DeviceAuthEndpoint, config.DeviceAuthorizationFlow.ProviderConfig.DeviceAuthEndpoint)
 			config.DeviceAuthorizationFlow.ProviderConfig.DeviceAuthEndpoint = oidcConfig.DeviceAuthEndpoint
+			config.DeviceAuthorizationFlow.ProviderConfig.Issuer = oidcConfig.Issuer
+			config.DeviceAuthorizationFlow.ProviderConfig.JwksURI = oidcConfig.JwksURI
 
 			u, err := url.Parse(oidcEndpoint)
 			if err != nil {
@@ -388,52 +389,35 @@ func loadMgmtConfig(mgmtConfigPath string) (*server.Config, error) {
 				u.Host, config.DeviceAuthorizationFlow.ProviderConfig.Domain)
 			config.DeviceAuthorizationFlow.ProviderConfig.Domain = u.Host
 		}
-	}
-
-	return config, err
-}
-
-// OIDCConfigResponse used for parsing OIDC config response
-type OIDCConfigResponse struct {
-	Issuer             string `json:"issuer"`
-	TokenEndpoint      string `json:"token_endpoint"`
-	DeviceAuthEndpoint string `json:"device_authorization_endpoint"`
-	JwksURI            string `json:"jwks_uri"`
-}
 
-// fetchOIDCConfig fetches OIDC configuration from the IDP
-func fetchOIDCConfig(oidcEndpoint string) (OIDCConfigResponse, error) {
-
-	res, err := http.Get(oidcEndpoint)
-	if err != nil {
-		return OIDCConfigResponse{}, fmt.Errorf("failed fetching OIDC configuration fro mendpoint %s %v", oidcEndpoint, err)
+		if config.PKCEAuthorizationFlow != nil {
+			log.Infof("overriding PKCEAuthorizationFlow.TokenEndpoint with a new value: %s, previously configured value: %s",
+				oidcConfig.TokenEndpoint, config.PKCEAuthorizationFlow.ProviderConfig.TokenEndpoint)
+			config.PKCEAuthorizationFlow.ProviderConfig.TokenEndpoint = oidcConfig.TokenEndpoint
+			log.Infof("overriding PKCEAuthorizationFlow.AuthorizationEndpoint with a new value: %s, previously configured value: %s",
+				oidcConfig.AuthorizationEndpoint, config.PKCEAuthorizationFlow.ProviderConfig.AuthorizationEndpoint)
+			config.PKCEAuthorizationFlow.ProviderConfig.AuthorizationEndpoint = oidcConfig.AuthorizationEndpoint
+			config.PKCEAuthorizationFlow.ProviderConfig.Issuer = oidcConfig.Issuer
+			config.PKCEAuthorizationFlow.ProviderConfig.JwksURI = oidcConfig.JwksURI
+		}
 	}
 
-	defer func() {
-		err := res.Body.Close()
+	// an unreachable identity provider doesn't prevent the start, the configured endpoints are used
+	if !(config.DeviceAuthorizationFlow == nil || strings.ToLower(config.DeviceAuthorizationFlow.Provider) == string(server.NONE)) {
+		err = config.DeviceAuthorizationFlow.ProviderConfig.ResolveOIDCEndpoints()
 		if err != nil {
-			log.Debugf("failed closing response body %v", err)
+			log.Errorf("failed resolving DeviceAuthorizationFlow endpoints, using the configured ones: %v", err)
 		}
-	}()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return OIDCConfigResponse{}, fmt.Errorf("failed reading OIDC configuration response body: %v", err)
-	}
-
-	if res.StatusCode != 200 {
-		return OIDCConfigResponse{}, fmt.Errorf("OIDC configuration request returned status %d with response: %s",
-			res.StatusCode, string(body))
 	}
 
-	config := OIDCConfigResponse{}
-	err = json.Unmarshal(body, &config)
-	if err != nil {
-		return OIDCConfigResponse{}, fmt.Errorf("failed unmarshaling OIDC configuration response: %v", err)
+	if config.PKCEAuthorizationFlow != nil {
+		err = config.PKCEAuthorizationFlow.ProviderConfig.ResolveOIDCEndpoints()
+		if err != nil {
+			log.Errorf("failed resolving PKCEAuthorizationFlow endpoints, using the configured ones: %v", err)
+		}
 	}
 
 	return config, nil
-
 }
 
 func loadTLSConfig(certFile string, certKey string) (*tls.Config, error) {
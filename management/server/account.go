@@ -66,6 +66,9 @@ type AccountManager interface {
 	AddPeer(setupKey string, userId string, peer *Peer) (*Peer, error)
 	UpdatePeerMeta(peerKey string, meta PeerSystemMeta) error
 	UpdatePeerSSHKey(peerKey string, sshKey string) error
+	UpdatePeerRouteConflicts(peerKey string, conflicts []RouteConflict) error
+	UpdatePeerRoutesTraffic(peerKey string, routesTraffic map[string]RouteTraffic) error
+	GetPeerRoutesTraffic(peerKey string) map[string]RouteTraffic
 	GetUsersFromAccount(accountId string) ([]*UserInfo, error)
 	GetGroup(accountId, groupID string) (*Group, error)
 	SaveGroup(accountId string, group *Group) error
@@ -86,6 +89,13 @@ type AccountManager interface {
 	UpdateRoute(accountID string, routeID string, operations []RouteUpdateOperation) (*route.Route, error)
 	DeleteRoute(accountID, routeID string) error
 	ListRoutes(accountID string) ([]*route.Route, error)
+	GetPortForward(accountID, portForwardID string) (*route.PortForward, error)
+	CreatePortForward(accountID string, peer, description, protocol string, externalPort int, targetPeer, targetIP string, targetPort int, enabled bool) (*route.PortForward, error)
+	SavePortForward(accountID string, portForward *route.PortForward) error
+	DeletePortForward(accountID, portForwardID string) error
+	ListPortForwards(accountID string) ([]*route.PortForward, error)
+	GetPeerPortForwards(peerKey string) ([]*route.PortForward, error)
+	GetPeerConnectionPolicies(peerKey string) (map[string]ConnectionPolicy, error)
 	GetNameServerGroup(accountID, nsGroupID string) (*nbdns.NameServerGroup, error)
 	CreateNameServerGroup(accountID string, name, description string, nameServerList []nbdns.NameServer, groups []string, primary bool, domains []string, enabled bool) (*nbdns.NameServerGroup, error)
 	SaveNameServerGroup(accountID string, nsGroupToSave *nbdns.NameServerGroup) error
@@ -113,6 +123,9 @@ type DefaultAccountManager struct {
 	singleAccountMode bool
 	// singleAccountModeDomain is a domain to use in singleAccountMode setup
 	singleAccountModeDomain string
+
+	// routesTraffic keeps the routes traffic reported by the routing peers until it is persisted
+	routesTraffic *routesTrafficCache
 }
 
 // Account represents a unique account of the system
@@ -130,6 +143,7 @@ type Account struct {
 	Groups                 map[string]*Group
 	Rules                  map[string]*Rule
 	Routes                 map[string]*route.Route
+	PortForwards           map[string]*route.PortForward
 	NameServerGroups       map[string]*nbdns.NameServerGroup
 }
 
@@ -173,6 +187,11 @@ func (a *Account) Copy() *Account {
 		routes[id] = route.Copy()
 	}
 
+	portForwards := map[string]*route.PortForward{}
+	for id, portForward := range a.PortForwards {
+		portForwards[id] = portForward.Copy()
+	}
+
 	nsGroups := map[string]*nbdns.NameServerGroup{}
 	for id, nsGroup := range a.NameServerGroups {
 		nsGroups[id] = nsGroup.Copy()
@@ -188,6 +207,7 @@ func (a *Account) Copy() *Account {
 		Groups:           groups,
 		Rules:            rules,
 		Routes:           routes,
+		PortForwards:     portForwards,
 		NameServerGroups: nsGroups,
 	}
 }
@@ -212,6 +232,7 @@ func BuildManager(store Store, peersUpdateManager *PeersUpdateManager, idpManage
 		ctx:                context.Background(),
 		cacheMux:           sync.Mutex{},
 		cacheLoading:       map[string]chan struct{}{},
+		routesTraffic:      newRoutesTrafficCache(),
 	}
 	allAccounts := store.GetAllAccounts()
 	// enable single account mode only if configured by user and number of existing accounts is not grater than 1
@@ -252,6 +273,8 @@ func BuildManager(store Store, peersUpdateManager *PeersUpdateManager, idpManage
 		}()
 	}
 
+	go am.persistRoutesTrafficPeriodically()
+
 	return am, nil
 
 }
@@ -765,6 +788,7 @@ func newAccountWithId(accountId, userId, domain string) *Account {
 	peers := make(map[string]*Peer)
 	users := make(map[string]*User)
 	routes := make(map[string]*route.Route)
+	portForwards := make(map[string]*route.PortForward)
 	nameServersGroups := make(map[string]*nbdns.NameServerGroup)
 	users[userId] = NewAdminUser(userId)
 	log.Debugf("created new account %s with setup key %s", accountId, defaultKey.Key)
@@ -778,6 +802,7 @@ func newAccountWithId(accountId, userId, domain string) *Account {
 		CreatedBy:        userId,
 		Domain:           domain,
 		Routes:           routes,
+		PortForwards:     portForwards,
 		NameServerGroups: nameServersGroups,
 	}
 
@@ -32,6 +32,8 @@ type Config struct {
 	IdpManagerConfig *idp.Config
 
 	DeviceAuthorizationFlow *DeviceAuthorizationFlow
+
+	PKCEAuthorizationFlow *PKCEAuthorizationFlow
 }
 
 // TURNConfig is a config of the TURNCredentialsManager
@@ -91,6 +93,23 @@ type ProviderConfig struct {
 	TokenEndpoint string
 	// DeviceAuthEndpoint is the endpoint of an IDP manager where clients can obtain device authorization code
 	DeviceAuthEndpoint string
+	// AuthorizationEndpoint is the endpoint of an IDP manager where the user authorizes the PKCE flow
+	AuthorizationEndpoint string
+	// Scope requested by the client
+	Scope string
+	// RedirectURLs are the loopback URLs registered with the IDP the client receives the authorization code on
+	RedirectURLs []string
+	// Issuer is the URL of the OIDC provider, endpoints that aren't configured are resolved from its discovery document
+	Issuer string
+	// JwksURI is the endpoint of the keys clients verify the signature of access tokens with
+	JwksURI string
+}
+
+// PKCEAuthorizationFlow represents Authorization Code Flow information
+// that can be used by the client to login initiate a Oauth 2.0 authorization code grant flow
+// with Proof Key for Code Exchange (PKCE). See https://datatracker.ietf.org/doc/html/rfc7636
+type PKCEAuthorizationFlow struct {
+	ProviderConfig ProviderConfig
 }
 
 // validateURL validates input http url
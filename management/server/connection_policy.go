@@ -0,0 +1,124 @@
+package server
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/management/proto"
+)
+
+// ConnectionPolicy restricts how peers are connected to each other
+type ConnectionPolicy string
+
+const (
+	// ConnectionPolicyAuto lets the peers use a direct connection when possible and a relayed one otherwise
+	ConnectionPolicyAuto ConnectionPolicy = "auto"
+	// ConnectionPolicyRelay connects the peers only through a TURN relay, the peers never contact each other directly
+	ConnectionPolicyRelay ConnectionPolicy = "relay"
+	// ConnectionPolicyDirect connects the peers only directly, TURN relays are never used
+	ConnectionPolicyDirect ConnectionPolicy = "direct"
+)
+
+// connectionPolicyConflict is the combination of a relay and a direct policy. No connection satisfies both, so
+// the peers are left out of each other's network map
+const connectionPolicyConflict ConnectionPolicy = "conflict"
+
+// ParseConnectionPolicy parses a connection policy, an empty value is ConnectionPolicyAuto
+func ParseConnectionPolicy(value string) (ConnectionPolicy, error) {
+	switch policy := ConnectionPolicy(value); policy {
+	case "":
+		return ConnectionPolicyAuto, nil
+	case ConnectionPolicyAuto, ConnectionPolicyRelay, ConnectionPolicyDirect:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid connection policy %q, must be one of %s, %s or %s", value,
+			ConnectionPolicyAuto, ConnectionPolicyRelay, ConnectionPolicyDirect)
+	}
+}
+
+// combineConnectionPolicies returns the policy satisfying all the policies.
+// A relay and a direct policy can't both be satisfied, their combination is connectionPolicyConflict
+func combineConnectionPolicies(policies ...ConnectionPolicy) ConnectionPolicy {
+	combined := ConnectionPolicyAuto
+	for _, policy := range policies {
+		switch {
+		case policy == connectionPolicyConflict:
+			return connectionPolicyConflict
+		case policy == ConnectionPolicyAuto || policy == "" || policy == combined:
+			// doesn't restrict the connection any further
+		case combined == ConnectionPolicyAuto:
+			combined = policy
+		default:
+			return connectionPolicyConflict
+		}
+	}
+	return combined
+}
+
+// peerConnectionPolicy returns the connection policy of a peer combined with the policies of its groups
+func peerConnectionPolicy(account *Account, peerKey string) ConnectionPolicy {
+	var policies []ConnectionPolicy
+	if peer, ok := account.Peers[peerKey]; ok {
+		policies = append(policies, peer.ConnectionPolicy)
+	}
+	for _, group := range account.Groups {
+		for _, groupPeer := range group.Peers {
+			if groupPeer == peerKey {
+				policies = append(policies, group.ConnectionPolicy)
+				break
+			}
+		}
+	}
+	return combineConnectionPolicies(policies...)
+}
+
+// getPeerConnectionPolicies returns the policies of the connections between a peer and the given remote peers,
+// indexed by the remote peer key. Both sides of a connection get the same policy, conflicting policies are logged
+func getPeerConnectionPolicies(account *Account, peerKey string, remotePeers []*Peer) map[string]ConnectionPolicy {
+	local := peerConnectionPolicy(account, peerKey)
+	policies := make(map[string]ConnectionPolicy, len(remotePeers))
+	for _, remotePeer := range remotePeers {
+		policy := combineConnectionPolicies(local, peerConnectionPolicy(account, remotePeer.Key))
+		if policy == connectionPolicyConflict {
+			log.Warnf("the connection policies of peers %s and %s require both a relayed and a direct connection, "+
+				"the peers won't be connected", peerKey, remotePeer.Key)
+		}
+		policies[remotePeer.Key] = policy
+	}
+	return policies
+}
+
+// GetPeerConnectionPolicies returns the policies of the connections between a peer and all the peers of its account,
+// indexed by the remote peer key
+func (am *DefaultAccountManager) GetPeerConnectionPolicies(peerKey string) (map[string]ConnectionPolicy, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	account, err := am.Store.GetPeerAccount(peerKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Invalid peer key %s", peerKey)
+	}
+
+	remotePeers := make([]*Peer, 0, len(account.Peers))
+	for _, peer := range account.Peers {
+		if peer.Key != peerKey {
+			remotePeers = append(remotePeers, peer)
+		}
+	}
+
+	return getPeerConnectionPolicies(account, peerKey, remotePeers), nil
+}
+
+func toProtocolConnectionPolicy(policy ConnectionPolicy) proto.RemotePeerConfig_ConnectionPolicy {
+	switch policy {
+	case ConnectionPolicyRelay:
+		return proto.RemotePeerConfig_RELAY
+	case ConnectionPolicyDirect:
+		return proto.RemotePeerConfig_DIRECT
+	default:
+		return proto.RemotePeerConfig_AUTO
+	}
+}
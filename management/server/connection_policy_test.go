@@ -0,0 +1,78 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/proto"
+)
+
+func TestParseConnectionPolicy(t *testing.T) {
+	policy, err := ParseConnectionPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, ConnectionPolicyAuto, policy, "an empty policy should be auto")
+
+	policy, err = ParseConnectionPolicy("relay")
+	require.NoError(t, err)
+	assert.Equal(t, ConnectionPolicyRelay, policy)
+
+	_, err = ParseConnectionPolicy("tunnel")
+	assert.Error(t, err)
+}
+
+func TestGetPeerConnectionPolicies(t *testing.T) {
+	account := &Account{
+		Peers: map[string]*Peer{
+			"office":   {Key: "office"},
+			"server":   {Key: "server"},
+			"hostile":  {Key: "hostile", ConnectionPolicy: ConnectionPolicyRelay},
+			"customer": {Key: "customer"},
+			"laptop":   {Key: "laptop", ConnectionPolicy: ConnectionPolicyDirect},
+			"kiosk":    {Key: "kiosk", ConnectionPolicy: ConnectionPolicyRelay},
+		},
+		Groups: map[string]*Group{
+			"all":        {ID: "all", Peers: []string{"office", "server", "hostile", "customer", "laptop", "kiosk"}},
+			"no-relays":  {ID: "no-relays", Peers: []string{"customer"}, ConnectionPolicy: ConnectionPolicyDirect},
+			"restricted": {ID: "restricted", Peers: []string{"laptop"}, ConnectionPolicy: ConnectionPolicyRelay},
+		},
+	}
+
+	var remotePeers []*Peer
+	for _, peer := range account.Peers {
+		remotePeers = append(remotePeers, peer)
+	}
+
+	testCases := []struct {
+		name     string
+		local    string
+		remote   string
+		expected ConnectionPolicy
+	}{
+		{name: "Peers Without Policies Connect Automatically", local: "office", remote: "server", expected: ConnectionPolicyAuto},
+		{name: "Peer Policy Applies To Both Sides", local: "office", remote: "hostile", expected: ConnectionPolicyRelay},
+		{name: "Peer Policy Applies To Both Sides Reversed", local: "hostile", remote: "office", expected: ConnectionPolicyRelay},
+		{name: "Group Policy Applies To Its Peers", local: "office", remote: "customer", expected: ConnectionPolicyDirect},
+		{name: "Relay And Direct Conflict", local: "customer", remote: "hostile", expected: connectionPolicyConflict},
+		{name: "Relay And Direct Conflict Reversed", local: "hostile", remote: "customer", expected: connectionPolicyConflict},
+		{name: "Group Relay Policy Conflicts With Peer Direct Policy", local: "office", remote: "laptop", expected: connectionPolicyConflict},
+		{name: "Equal Policies Don't Conflict", local: "hostile", remote: "kiosk", expected: ConnectionPolicyRelay},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policies := getPeerConnectionPolicies(account, testCase.local, remotePeers)
+			assert.Equal(t, testCase.expected, policies[testCase.remote])
+		})
+	}
+
+	remotePeerConfigs := toRemotePeerConfig([]*Peer{account.Peers["hostile"]}, getPeerConnectionPolicies(account, "office", remotePeers))
+	require.Len(t, remotePeerConfigs, 1)
+	assert.Equal(t, proto.RemotePeerConfig_RELAY, remotePeerConfigs[0].GetConnectionPolicy())
+
+	remotePeerConfigs = toRemotePeerConfig([]*Peer{account.Peers["office"], account.Peers["customer"]},
+		getPeerConnectionPolicies(account, "hostile", remotePeers))
+	require.Len(t, remotePeerConfigs, 1, "peers with conflicting policies shouldn't be connected")
+	assert.Equal(t, "office", remotePeerConfigs[0].GetWgPubKey())
+}
@@ -228,6 +228,17 @@ func (s *FileStore) DeletePeer(accountId string, peerKey string) (*Peer, error)
 		account.Routes[routeID].Peer = ""
 	}
 
+	for _, portForward := range account.PortForwards {
+		if portForward.Peer == peerKey {
+			portForward.Enabled = false
+			portForward.Peer = ""
+		}
+		if portForward.TargetPeer == peerKey {
+			portForward.Enabled = false
+			portForward.TargetPeer = ""
+		}
+	}
+
 	err = s.persist(s.storeFile)
 	if err != nil {
 		return nil, err
@@ -15,6 +15,9 @@ type Group struct {
 
 	// Peers list of the group
 	Peers []string
+
+	// ConnectionPolicy restricts how the other peers connect to the peers of the group
+	ConnectionPolicy ConnectionPolicy
 }
 
 const (
@@ -42,6 +45,8 @@ func (g *Group) Copy() *Group {
 		ID:    g.ID,
 		Name:  g.Name,
 		Peers: g.Peers[:],
+
+		ConnectionPolicy: g.ConnectionPolicy,
 	}
 }
 
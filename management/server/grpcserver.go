@@ -263,7 +263,15 @@ func (s *GRPCServer) registerPeer(peerKey wgtypes.Key, req *proto.LoginRequest)
 				peersToSend = append(peersToSend, p)
 			}
 		}
-		update := toSyncResponse(s.config, remotePeer, peersToSend, networkMap.Routes, nil, networkMap.Network.CurrentSerial(), networkMap.Network)
+		portForwards, err := s.accountManager.GetPeerPortForwards(remotePeer.Key)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to fetch port forwards of peer %s, error: %v", remotePeer.Key, err)
+		}
+		connectionPolicies, err := s.accountManager.GetPeerConnectionPolicies(remotePeer.Key)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to fetch connection policies of peer %s, error: %v", remotePeer.Key, err)
+		}
+		update := toSyncResponse(s.config, remotePeer, peersToSend, connectionPolicies, networkMap.Routes, portForwards, nil, networkMap.Network.CurrentSerial(), networkMap.Network)
 		err = s.peersUpdateManager.SendUpdate(remotePeer.Key, &UpdateMessage{Update: update})
 		if err != nil {
 			// todo rethink if we should keep this return
@@ -439,27 +447,35 @@ func toPeerConfig(peer *Peer, network *Network) *proto.PeerConfig {
 	}
 }
 
-func toRemotePeerConfig(peers []*Peer) []*proto.RemotePeerConfig {
+// toRemotePeerConfig converts the peers to remote peer configs, peers with conflicting connection policies are skipped
+func toRemotePeerConfig(peers []*Peer, connectionPolicies map[string]ConnectionPolicy) []*proto.RemotePeerConfig {
 	remotePeers := []*proto.RemotePeerConfig{}
 	for _, rPeer := range peers {
+		if connectionPolicies[rPeer.Key] == connectionPolicyConflict {
+			continue
+		}
 		remotePeers = append(remotePeers, &proto.RemotePeerConfig{
-			WgPubKey:   rPeer.Key,
-			AllowedIps: []string{fmt.Sprintf(AllowedIPsFormat, rPeer.IP)},
-			SshConfig:  &proto.SSHConfig{SshPubKey: []byte(rPeer.SSHKey)},
+			WgPubKey:         rPeer.Key,
+			AllowedIps:       []string{fmt.Sprintf(AllowedIPsFormat, rPeer.IP)},
+			SshConfig:        &proto.SSHConfig{SshPubKey: []byte(rPeer.SSHKey)},
+			ConnectionPolicy: toProtocolConnectionPolicy(connectionPolicies[rPeer.Key]),
+			Name:             rPeer.Name,
 		})
 	}
 	return remotePeers
 }
 
-func toSyncResponse(config *Config, peer *Peer, peers []*Peer, routes []*route.Route, turnCredentials *TURNCredentials, serial uint64, network *Network) *proto.SyncResponse {
+func toSyncResponse(config *Config, peer *Peer, peers []*Peer, connectionPolicies map[string]ConnectionPolicy, routes []*route.Route, portForwards []*route.PortForward, turnCredentials *TURNCredentials, serial uint64, network *Network) *proto.SyncResponse {
 	wtConfig := toWiretrusteeConfig(config, turnCredentials)
 
 	pConfig := toPeerConfig(peer, network)
 
-	remotePeers := toRemotePeerConfig(peers)
+	remotePeers := toRemotePeerConfig(peers, connectionPolicies)
 
 	routesUpdate := toProtocolRoutes(routes)
 
+	portForwardsUpdate := toProtocolPortForwards(portForwards)
+
 	return &proto.SyncResponse{
 		WiretrusteeConfig:  wtConfig,
 		PeerConfig:         pConfig,
@@ -471,6 +487,7 @@ func toSyncResponse(config *Config, peer *Peer, peers []*Peer, routes []*route.R
 			RemotePeers:        remotePeers,
 			RemotePeersIsEmpty: len(remotePeers) == 0,
 			Routes:             routesUpdate,
+			PortForwards:       portForwardsUpdate,
 		},
 	}
 }
@@ -496,7 +513,7 @@ func (s *GRPCServer) sendInitialSync(peerKey wgtypes.Key, peer *Peer, srv proto.
 	} else {
 		turnCredentials = nil
 	}
-	plainResp := toSyncResponse(s.config, peer, networkMap.Peers, networkMap.Routes, turnCredentials, networkMap.Network.CurrentSerial(), networkMap.Network)
+	plainResp := toSyncResponse(s.config, peer, networkMap.Peers, networkMap.ConnectionPolicies, networkMap.Routes, networkMap.PortForwards, turnCredentials, networkMap.Network.CurrentSerial(), networkMap.Network)
 
 	encryptedResp, err := encryption.EncryptMessage(peerKey, s.wgKey, plainResp)
 	if err != nil {
@@ -552,6 +569,9 @@ func (s *GRPCServer) GetDeviceAuthorizationFlow(ctx context.Context, req *proto.
 			Audience:           s.config.DeviceAuthorizationFlow.ProviderConfig.Audience,
 			DeviceAuthEndpoint: s.config.DeviceAuthorizationFlow.ProviderConfig.DeviceAuthEndpoint,
 			TokenEndpoint:      s.config.DeviceAuthorizationFlow.ProviderConfig.TokenEndpoint,
+			Scope:              s.config.DeviceAuthorizationFlow.ProviderConfig.Scope,
+			Issuer:             s.config.DeviceAuthorizationFlow.ProviderConfig.Issuer,
+			JwksURI:            s.config.DeviceAuthorizationFlow.ProviderConfig.JwksURI,
 		},
 	}
 
@@ -565,3 +585,125 @@ func (s *GRPCServer) GetDeviceAuthorizationFlow(ctx context.Context, req *proto.
 		Body:     encryptedResp,
 	}, nil
 }
+
+// GetPKCEAuthorizationFlow returns a PKCE authorization code flow information
+// This is used for initiating an Oauth 2 authorization code grant flow with PKCE
+// which can be used by our clients to Login
+func (s *GRPCServer) GetPKCEAuthorizationFlow(ctx context.Context, req *proto.EncryptedMessage) (*proto.EncryptedMessage, error) {
+	peerKey, err := wgtypes.ParseKey(req.GetWgPubKey())
+	if err != nil {
+		errMSG := fmt.Sprintf("error while parsing peer's Wireguard public key %s on GetPKCEAuthorizationFlow request.", req.WgPubKey)
+		log.Warn(errMSG)
+		return nil, status.Error(codes.InvalidArgument, errMSG)
+	}
+
+	err = encryption.DecryptMessage(peerKey, s.wgKey, req.Body, &proto.PKCEAuthorizationFlowRequest{})
+	if err != nil {
+		errMSG := fmt.Sprintf("error while decrypting peer's message with Wireguard public key %s.", req.WgPubKey)
+		log.Warn(errMSG)
+		return nil, status.Error(codes.InvalidArgument, errMSG)
+	}
+
+	if s.config.PKCEAuthorizationFlow == nil {
+		return nil, status.Error(codes.NotFound, "no pkce authorization flow information available")
+	}
+
+	flowInfoResp := &proto.PKCEAuthorizationFlow{
+		ProviderConfig: &proto.ProviderConfig{
+			ClientID:              s.config.PKCEAuthorizationFlow.ProviderConfig.ClientID,
+			ClientSecret:          s.config.PKCEAuthorizationFlow.ProviderConfig.ClientSecret,
+			Audience:              s.config.PKCEAuthorizationFlow.ProviderConfig.Audience,
+			TokenEndpoint:         s.config.PKCEAuthorizationFlow.ProviderConfig.TokenEndpoint,
+			AuthorizationEndpoint: s.config.PKCEAuthorizationFlow.ProviderConfig.AuthorizationEndpoint,
+			Scope:                 s.config.PKCEAuthorizationFlow.ProviderConfig.Scope,
+			RedirectURLs:          s.config.PKCEAuthorizationFlow.ProviderConfig.RedirectURLs,
+			Issuer:                s.config.PKCEAuthorizationFlow.ProviderConfig.Issuer,
+			JwksURI:               s.config.PKCEAuthorizationFlow.ProviderConfig.JwksURI,
+		},
+	}
+
+	encryptedResp, err := encryption.EncryptMessage(peerKey, s.wgKey, flowInfoResp)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to encrypt pkce authorization flow information")
+	}
+
+	return &proto.EncryptedMessage{
+		WgPubKey: s.wgKey.PublicKey().String(),
+		Body:     encryptedResp,
+	}, nil
+}
+
+// ReportRouteConflicts stores the route conflicts reported by the peer, replacing the previously reported ones
+func (s *GRPCServer) ReportRouteConflicts(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error) {
+	peerKey, err := wgtypes.ParseKey(req.GetWgPubKey())
+	if err != nil {
+		errMSG := fmt.Sprintf("error while parsing peer's Wireguard public key %s on ReportRouteConflicts request.", req.WgPubKey)
+		log.Warn(errMSG)
+		return nil, status.Error(codes.InvalidArgument, errMSG)
+	}
+
+	report := &proto.RouteConflictsReport{}
+	err = encryption.DecryptMessage(peerKey, s.wgKey, req.Body, report)
+	if err != nil {
+		errMSG := fmt.Sprintf("error while decrypting peer's message with Wireguard public key %s.", req.WgPubKey)
+		log.Warn(errMSG)
+		return nil, status.Error(codes.InvalidArgument, errMSG)
+	}
+
+	conflicts := make([]RouteConflict, 0, len(report.GetConflicts()))
+	for _, conflict := range report.GetConflicts() {
+		conflicts = append(conflicts, RouteConflict{
+			NetID:        conflict.GetNetID(),
+			Network:      conflict.GetNetwork(),
+			LocalNetwork: conflict.GetLocalNetwork(),
+			Interface:    conflict.GetInterface(),
+			Action:       conflict.GetAction(),
+		})
+	}
+
+	err = s.accountManager.UpdatePeerRouteConflicts(peerKey.String(), conflicts)
+	if err != nil {
+		log.Warnf("failed updating route conflicts of peer %s: %v", peerKey.String(), err)
+		return nil, status.Errorf(codes.Internal, "failed updating route conflicts")
+	}
+
+	return &proto.Empty{}, nil
+}
+
+// ReportRouteTraffic stores the routes traffic reported by a routing peer, replacing the previously reported one
+func (s *GRPCServer) ReportRouteTraffic(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error) {
+	peerKey, err := wgtypes.ParseKey(req.GetWgPubKey())
+	if err != nil {
+		errMSG := fmt.Sprintf("error while parsing peer's Wireguard public key %s on ReportRouteTraffic request.", req.WgPubKey)
+		log.Warn(errMSG)
+		return nil, status.Error(codes.InvalidArgument, errMSG)
+	}
+
+	report := &proto.RouteTrafficReport{}
+	err = encryption.DecryptMessage(peerKey, s.wgKey, req.Body, report)
+	if err != nil {
+		errMSG := fmt.Sprintf("error while decrypting peer's message with Wireguard public key %s.", req.WgPubKey)
+		log.Warn(errMSG)
+		return nil, status.Error(codes.InvalidArgument, errMSG)
+	}
+
+	now := time.Now()
+	routesTraffic := make(map[string]RouteTraffic, len(report.GetRoutes()))
+	for _, traffic := range report.GetRoutes() {
+		routesTraffic[traffic.GetID()] = RouteTraffic{
+			TxBytes:     traffic.GetTxBytes(),
+			TxPackets:   traffic.GetTxPackets(),
+			RxBytes:     traffic.GetRxBytes(),
+			RxPackets:   traffic.GetRxPackets(),
+			LastUpdated: now,
+		}
+	}
+
+	err = s.accountManager.UpdatePeerRoutesTraffic(peerKey.String(), routesTraffic)
+	if err != nil {
+		log.Warnf("failed updating routes traffic of peer %s: %v", peerKey.String(), err)
+		return nil, status.Errorf(codes.Internal, "failed updating routes traffic")
+	}
+
+	return &proto.Empty{}, nil
+}
@@ -11,6 +11,13 @@ const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for ConnectionPolicy.
+const (
+	ConnectionPolicyAuto   ConnectionPolicy = "auto"
+	ConnectionPolicyDirect ConnectionPolicy = "direct"
+	ConnectionPolicyRelay  ConnectionPolicy = "relay"
+)
+
 // Defines values for GroupPatchOperationOp.
 const (
 	GroupPatchOperationOpAdd     GroupPatchOperationOp = "add"
@@ -52,6 +59,24 @@ const (
 	PatchMinimumOpReplace PatchMinimumOp = "replace"
 )
 
+// Defines values for PortForwardProtocol.
+const (
+	PortForwardProtocolTcp PortForwardProtocol = "tcp"
+	PortForwardProtocolUdp PortForwardProtocol = "udp"
+)
+
+// Defines values for PortForwardRequestProtocol.
+const (
+	PortForwardRequestProtocolTcp PortForwardRequestProtocol = "tcp"
+	PortForwardRequestProtocolUdp PortForwardRequestProtocol = "udp"
+)
+
+// Defines values for RouteConflictAction.
+const (
+	RouteConflictActionApplied RouteConflictAction = "applied"
+	RouteConflictActionSkipped RouteConflictAction = "skipped"
+)
+
 // Defines values for RoutePatchOperationOp.
 const (
 	RoutePatchOperationOpAdd     RoutePatchOperationOp = "add"
@@ -94,8 +119,14 @@ const (
 	UserStatusInvited  UserStatus = "invited"
 )
 
+// ConnectionPolicy Restricts how the other peers connect to a peer, relay connects only through a TURN relay and direct never uses one. Peers requiring relay and direct connections from each other aren't connected
+type ConnectionPolicy string
+
 // Group defines model for Group.
 type Group struct {
+	// ConnectionPolicy Restricts how the other peers connect to a peer, relay connects only through a TURN relay and direct never uses one. Peers requiring relay and direct connections from each other aren't connected
+	ConnectionPolicy ConnectionPolicy `json:"connection_policy"`
+
 	// Id Group ID
 	Id string `json:"id"`
 
@@ -240,6 +271,9 @@ type Peer struct {
 	// Connected Peer to Management connection status
 	Connected bool `json:"connected"`
 
+	// ConnectionPolicy Restricts how the other peers connect to a peer, relay connects only through a TURN relay and direct never uses one. Peers requiring relay and direct connections from each other aren't connected
+	ConnectionPolicy ConnectionPolicy `json:"connection_policy"`
+
 	// Groups Groups that the peer belongs to
 	Groups []GroupMinimum `json:"groups"`
 
@@ -261,6 +295,9 @@ type Peer struct {
 	// Os Peer's operating system and version
 	Os string `json:"os"`
 
+	// RouteConflicts Routes that overlap with networks the peer is directly attached to, as reported by the peer
+	RouteConflicts *[]RouteConflict `json:"route_conflicts,omitempty"`
+
 	// SshEnabled Indicates whether SSH server is enabled on this peer
 	SshEnabled bool `json:"ssh_enabled"`
 
@@ -283,6 +320,69 @@ type PeerMinimum struct {
 	Name string `json:"name"`
 }
 
+// PortForward defines model for PortForward.
+type PortForward struct {
+	// Description Port forward description
+	Description string `json:"description"`
+
+	// Enabled Port forward status
+	Enabled bool `json:"enabled"`
+
+	// ExternalPort Port exposed by the routing peer
+	ExternalPort int `json:"external_port"`
+
+	// Id Port forward Id
+	Id string `json:"id"`
+
+	// Peer Peer Identifier of the routing peer exposing the port
+	Peer string `json:"peer"`
+
+	// Protocol Protocol of the forwarded traffic
+	Protocol PortForwardProtocol `json:"protocol"`
+
+	// TargetIp IP address the traffic is forwarded to. Mutually exclusive with target_peer
+	TargetIp *string `json:"target_ip,omitempty"`
+
+	// TargetPeer Peer Identifier the traffic is forwarded to. Mutually exclusive with target_ip
+	TargetPeer *string `json:"target_peer,omitempty"`
+
+	// TargetPort Port the traffic is forwarded to
+	TargetPort int `json:"target_port"`
+}
+
+// PortForwardProtocol Protocol of the forwarded traffic
+type PortForwardProtocol string
+
+// PortForwardRequest defines model for PortForwardRequest.
+type PortForwardRequest struct {
+	// Description Port forward description
+	Description string `json:"description"`
+
+	// Enabled Port forward status
+	Enabled bool `json:"enabled"`
+
+	// ExternalPort Port exposed by the routing peer
+	ExternalPort int `json:"external_port"`
+
+	// Peer Peer Identifier of the routing peer exposing the port
+	Peer string `json:"peer"`
+
+	// Protocol Protocol of the forwarded traffic
+	Protocol PortForwardRequestProtocol `json:"protocol"`
+
+	// TargetIp IP address the traffic is forwarded to. Mutually exclusive with target_peer
+	TargetIp *string `json:"target_ip,omitempty"`
+
+	// TargetPeer Peer Identifier the traffic is forwarded to. Mutually exclusive with target_ip
+	TargetPeer *string `json:"target_peer,omitempty"`
+
+	// TargetPort Port the traffic is forwarded to
+	TargetPort int `json:"target_port"`
+}
+
+// PortForwardRequestProtocol Protocol of the forwarded traffic
+type PortForwardRequestProtocol string
+
 // Route defines model for Route.
 type Route struct {
 	// Description Route description
@@ -311,8 +411,32 @@ type Route struct {
 
 	// Peer Peer Identifier associated with route
 	Peer string `json:"peer"`
+
+	// Traffic Traffic forwarded by the routing peer for the route since its forwarding rules were applied, as reported by the peer
+	Traffic *RouteTraffic `json:"traffic,omitempty"`
 }
 
+// RouteConflict defines model for RouteConflict.
+type RouteConflict struct {
+	// Action Action taken by the peer for the conflicting route
+	Action RouteConflictAction `json:"action"`
+
+	// Interface Local interface name the overlapping network is attached to
+	Interface string `json:"interface"`
+
+	// LocalNetwork Local network range in CIDR format that overlaps with the route
+	LocalNetwork string `json:"local_network"`
+
+	// Network Route network range in CIDR format
+	Network string `json:"network"`
+
+	// NetworkId Route network identifier
+	NetworkId string `json:"network_id"`
+}
+
+// RouteConflictAction Action taken by the peer for the conflicting route
+type RouteConflictAction string
+
 // RoutePatchOperation defines model for RoutePatchOperation.
 type RoutePatchOperation struct {
 	// Op Patch operation type
@@ -355,6 +479,24 @@ type RouteRequest struct {
 	Peer string `json:"peer"`
 }
 
+// RouteTraffic Traffic forwarded by the routing peer for the route since its forwarding rules were applied, as reported by the peer
+type RouteTraffic struct {
+	// LastUpdated Last time the routing peer reported the traffic counters
+	LastUpdated time.Time `json:"last_updated"`
+
+	// RxBytes Bytes forwarded from the route's network
+	RxBytes int64 `json:"rx_bytes"`
+
+	// RxPackets Packets forwarded from the route's network
+	RxPackets int64 `json:"rx_packets"`
+
+	// TxBytes Bytes forwarded to the route's network
+	TxBytes int64 `json:"tx_bytes"`
+
+	// TxPackets Packets forwarded to the route's network
+	TxPackets int64 `json:"tx_packets"`
+}
+
 // Rule defines model for Rule.
 type Rule struct {
 	// Description Rule friendly description
@@ -522,8 +664,10 @@ type PatchApiDnsNameserversIdJSONBody = []NameserverGroupPatchOperation
 
 // PostApiGroupsJSONBody defines parameters for PostApiGroups.
 type PostApiGroupsJSONBody struct {
-	Name  string    `json:"name"`
-	Peers *[]string `json:"peers,omitempty"`
+	// ConnectionPolicy Restricts how the other peers connect to a peer, relay connects only through a TURN relay and direct never uses one. Peers requiring relay and direct connections from each other aren't connected
+	ConnectionPolicy *ConnectionPolicy `json:"connection_policy,omitempty"`
+	Name             string            `json:"name"`
+	Peers            *[]string         `json:"peers,omitempty"`
 }
 
 // PatchApiGroupsIdJSONBody defines parameters for PatchApiGroupsId.
@@ -533,12 +677,17 @@ type PatchApiGroupsIdJSONBody = []GroupPatchOperation
 type PutApiGroupsIdJSONBody struct {
 	Name  *string   `json:"Name,omitempty"`
 	Peers *[]string `json:"Peers,omitempty"`
+
+	// ConnectionPolicy Restricts how the other peers connect to a peer, relay connects only through a TURN relay and direct never uses one. Peers requiring relay and direct connections from each other aren't connected
+	ConnectionPolicy *ConnectionPolicy `json:"connection_policy,omitempty"`
 }
 
 // PutApiPeersIdJSONBody defines parameters for PutApiPeersId.
 type PutApiPeersIdJSONBody struct {
-	Name       string `json:"name"`
-	SshEnabled bool   `json:"ssh_enabled"`
+	// ConnectionPolicy Restricts how the other peers connect to a peer, relay connects only through a TURN relay and direct never uses one. Peers requiring relay and direct connections from each other aren't connected
+	ConnectionPolicy *ConnectionPolicy `json:"connection_policy,omitempty"`
+	Name             string            `json:"name"`
+	SshEnabled       bool              `json:"ssh_enabled"`
 }
 
 // PatchApiRoutesIdJSONBody defines parameters for PatchApiRoutesId.
@@ -602,6 +751,12 @@ type PutApiGroupsIdJSONRequestBody PutApiGroupsIdJSONBody
 // PutApiPeersIdJSONRequestBody defines body for PutApiPeersId for application/json ContentType.
 type PutApiPeersIdJSONRequestBody PutApiPeersIdJSONBody
 
+// PostApiPortForwardsJSONRequestBody defines body for PostApiPortForwards for application/json ContentType.
+type PostApiPortForwardsJSONRequestBody = PortForwardRequest
+
+// PutApiPortForwardsIdJSONRequestBody defines body for PutApiPortForwardsId for application/json ContentType.
+type PutApiPortForwardsIdJSONRequestBody = PortForwardRequest
+
 // PostApiRoutesJSONRequestBody defines body for PostApiRoutes for application/json ContentType.
 type PostApiRoutesJSONRequestBody = RouteRequest
 
@@ -94,10 +94,17 @@ func (h *Groups) UpdateGroupHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	connectionPolicy, err := connectionPolicyFromRequest(req.ConnectionPolicy, account.Groups[groupID].ConnectionPolicy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
 	group := server.Group{
-		ID:    groupID,
-		Name:  *req.Name,
-		Peers: peerIPsToKeys(account, req.Peers),
+		ID:               groupID,
+		Name:             *req.Name,
+		Peers:            peerIPsToKeys(account, req.Peers),
+		ConnectionPolicy: connectionPolicy,
 	}
 
 	if err := h.accountManager.SaveGroup(account.Id, &group); err != nil {
@@ -243,10 +250,17 @@ func (h *Groups) CreateGroupHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	connectionPolicy, err := connectionPolicyFromRequest(req.ConnectionPolicy, server.ConnectionPolicyAuto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
 	group := server.Group{
-		ID:    xid.New().String(),
-		Name:  req.Name,
-		Peers: peerIPsToKeys(account, req.Peers),
+		ID:               xid.New().String(),
+		Name:             req.Name,
+		Peers:            peerIPsToKeys(account, req.Peers),
+		ConnectionPolicy: connectionPolicy,
 	}
 
 	if err := h.accountManager.SaveGroup(account.Id, &group); err != nil {
@@ -347,9 +361,10 @@ func peerIPsToKeys(account *server.Account, peerIPs *[]string) []string {
 func toGroupResponse(account *server.Account, group *server.Group) *api.Group {
 	cache := make(map[string]api.PeerMinimum)
 	gr := api.Group{
-		Id:         group.ID,
-		Name:       group.Name,
-		PeersCount: len(group.Peers),
+		Id:               group.ID,
+		Name:             group.Name,
+		PeersCount:       len(group.Peers),
+		ConnectionPolicy: toConnectionPolicyResponse(group.ConnectionPolicy),
 	}
 
 	for _, pid := range group.Peers {
@@ -369,3 +384,19 @@ func toGroupResponse(account *server.Account, group *server.Group) *api.Group {
 	}
 	return &gr
 }
+
+// connectionPolicyFromRequest parses the connection policy of a request, the current policy is kept when the request
+// has none
+func connectionPolicyFromRequest(requested *api.ConnectionPolicy, current server.ConnectionPolicy) (server.ConnectionPolicy, error) {
+	if requested == nil {
+		return current, nil
+	}
+	return server.ParseConnectionPolicy(string(*requested))
+}
+
+func toConnectionPolicyResponse(policy server.ConnectionPolicy) api.ConnectionPolicy {
+	if policy == "" {
+		return api.ConnectionPolicyAuto
+	}
+	return api.ConnectionPolicy(policy)
+}
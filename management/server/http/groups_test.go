@@ -179,10 +179,34 @@ func TestWriteGroup(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   true,
 			expectedGroup: &api.Group{
-				Id:   "id-was-set",
-				Name: "Default POSTed Group",
+				Id:               "id-was-set",
+				Name:             "Default POSTed Group",
+				ConnectionPolicy: api.ConnectionPolicyAuto,
 			},
 		},
+		{
+			name:        "Write Group POST Connection Policy OK",
+			requestType: http.MethodPost,
+			requestPath: "/api/groups",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{"name":"Relayed Group","connection_policy":"relay"}`)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedGroup: &api.Group{
+				Id:               "id-was-set",
+				Name:             "Relayed Group",
+				ConnectionPolicy: api.ConnectionPolicyRelay,
+			},
+		},
+		{
+			name:        "Write Group POST Invalid Connection Policy",
+			requestType: http.MethodPost,
+			requestPath: "/api/groups",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{"name":"Relayed Group","connection_policy":"tunnel"}`)),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   false,
+		},
 		{
 			name:        "Write Group POST Invalid Name",
 			requestType: http.MethodPost,
@@ -266,6 +290,7 @@ func TestWriteGroup(t *testing.T) {
 				Peers: []api.PeerMinimum{
 					{Id: "100.100.100.100"},
 					{Id: "200.200.200.200"}},
+				ConnectionPolicy: api.ConnectionPolicyAuto,
 			},
 		},
 	}
@@ -39,6 +39,7 @@ func APIHandler(accountManager s.AccountManager, authIssuer string, authAudience
 	keysHandler := NewSetupKeysHandler(accountManager, authAudience)
 	userHandler := NewUserHandler(accountManager, authAudience)
 	routesHandler := NewRoutes(accountManager, authAudience)
+	portForwardsHandler := NewPortForwards(accountManager, authAudience)
 	nameserversHandler := NewNameservers(accountManager, authAudience)
 
 	apiHandler.HandleFunc("/peers", peersHandler.GetPeers).Methods("GET", "OPTIONS")
@@ -74,6 +75,12 @@ func APIHandler(accountManager s.AccountManager, authIssuer string, authAudience
 	apiHandler.HandleFunc("/routes/{id}", routesHandler.GetRouteHandler).Methods("GET", "OPTIONS")
 	apiHandler.HandleFunc("/routes/{id}", routesHandler.DeleteRouteHandler).Methods("DELETE", "OPTIONS")
 
+	apiHandler.HandleFunc("/port-forwards", portForwardsHandler.GetAllPortForwardsHandler).Methods("GET", "OPTIONS")
+	apiHandler.HandleFunc("/port-forwards", portForwardsHandler.CreatePortForwardHandler).Methods("POST", "OPTIONS")
+	apiHandler.HandleFunc("/port-forwards/{id}", portForwardsHandler.UpdatePortForwardHandler).Methods("PUT", "OPTIONS")
+	apiHandler.HandleFunc("/port-forwards/{id}", portForwardsHandler.GetPortForwardHandler).Methods("GET", "OPTIONS")
+	apiHandler.HandleFunc("/port-forwards/{id}", portForwardsHandler.DeletePortForwardHandler).Methods("DELETE", "OPTIONS")
+
 	apiHandler.HandleFunc("/dns/nameservers", nameserversHandler.GetAllNameserversHandler).Methods("GET", "OPTIONS")
 	apiHandler.HandleFunc("/dns/nameservers", nameserversHandler.CreateNameserverGroupHandler).Methods("POST", "OPTIONS")
 	apiHandler.HandleFunc("/dns/nameservers/{id}", nameserversHandler.UpdateNameserverGroupHandler).Methods("PUT", "OPTIONS")
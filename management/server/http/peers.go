@@ -35,7 +35,13 @@ func (h *Peers) updatePeer(account *server.Account, peer *server.Peer, w http.Re
 		return
 	}
 
-	update := &server.Peer{Key: peer.Key, SSHEnabled: req.SshEnabled, Name: req.Name}
+	connectionPolicy, err := connectionPolicyFromRequest(req.ConnectionPolicy, peer.ConnectionPolicy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	update := &server.Peer{Key: peer.Key, SSHEnabled: req.SshEnabled, Name: req.Name, ConnectionPolicy: connectionPolicy}
 	peer, err = h.accountManager.UpdatePeer(account.Id, update)
 	if err != nil {
 		log.Errorf("failed updating peer %s under account %s %v", peerIp, account.Id, err)
@@ -134,18 +140,36 @@ func toPeerResponse(peer *server.Peer, account *server.Account) *api.Peer {
 			}
 		}
 	}
+	var routeConflicts *[]api.RouteConflict
+	if len(peer.RouteConflicts) > 0 {
+		conflicts := make([]api.RouteConflict, 0, len(peer.RouteConflicts))
+		for _, conflict := range peer.RouteConflicts {
+			conflicts = append(conflicts, api.RouteConflict{
+				NetworkId:    conflict.NetID,
+				Network:      conflict.Network,
+				LocalNetwork: conflict.LocalNetwork,
+				Interface:    conflict.Interface,
+				Action:       api.RouteConflictAction(conflict.Action),
+			})
+		}
+		routeConflicts = &conflicts
+	}
+
 	return &api.Peer{
-		Id:         peer.IP.String(),
-		Name:       peer.Name,
-		Ip:         peer.IP.String(),
-		Connected:  peer.Status.Connected,
-		LastSeen:   peer.Status.LastSeen,
-		Os:         fmt.Sprintf("%s %s", peer.Meta.OS, peer.Meta.Core),
-		Version:    peer.Meta.WtVersion,
-		Groups:     groupsInfo,
-		SshEnabled: peer.SSHEnabled,
-		Hostname:   peer.Meta.Hostname,
-		UserId:     &peer.UserID,
-		UiVersion:  &peer.Meta.UIVersion,
+		Id:             peer.IP.String(),
+		Name:           peer.Name,
+		Ip:             peer.IP.String(),
+		Connected:      peer.Status.Connected,
+		LastSeen:       peer.Status.LastSeen,
+		Os:             fmt.Sprintf("%s %s", peer.Meta.OS, peer.Meta.Core),
+		Version:        peer.Meta.WtVersion,
+		Groups:         groupsInfo,
+		SshEnabled:     peer.SSHEnabled,
+		Hostname:       peer.Meta.Hostname,
+		UserId:         &peer.UserID,
+		UiVersion:      &peer.Meta.UIVersion,
+		RouteConflicts: routeConflicts,
+
+		ConnectionPolicy: toConnectionPolicyResponse(peer.ConnectionPolicy),
 	}
 }
@@ -0,0 +1,284 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/jwtclaims"
+	"github.com/netbirdio/netbird/route"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/netip"
+)
+
+// PortForwards is the port forwards handler of the account
+type PortForwards struct {
+	jwtExtractor   jwtclaims.ClaimsExtractor
+	accountManager server.AccountManager
+	authAudience   string
+}
+
+// NewPortForwards returns a new instance of PortForwards handler
+func NewPortForwards(accountManager server.AccountManager, authAudience string) *PortForwards {
+	return &PortForwards{
+		accountManager: accountManager,
+		authAudience:   authAudience,
+		jwtExtractor:   *jwtclaims.NewClaimsExtractor(nil),
+	}
+}
+
+// GetAllPortForwardsHandler returns the list of port forwards for the account
+func (h *PortForwards) GetAllPortForwardsHandler(w http.ResponseWriter, r *http.Request) {
+	account, err := getJWTAccount(h.accountManager, h.jwtExtractor, h.authAudience, r)
+	if err != nil {
+		log.Error(err)
+		http.Redirect(w, r, "/", http.StatusInternalServerError)
+		return
+	}
+
+	portForwards, err := h.accountManager.ListPortForwards(account.Id)
+	if err != nil {
+		log.Error(err)
+		http.Redirect(w, r, "/", http.StatusInternalServerError)
+		return
+	}
+	apiPortForwards := make([]*api.PortForward, 0)
+	for _, p := range portForwards {
+		apiPortForwards = append(apiPortForwards, toPortForwardResponse(account, p))
+	}
+
+	writeJSONObject(w, apiPortForwards)
+}
+
+// CreatePortForwardHandler handles port forward creation request
+func (h *PortForwards) CreatePortForwardHandler(w http.ResponseWriter, r *http.Request) {
+	account, err := getJWTAccount(h.accountManager, h.jwtExtractor, h.authAudience, r)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusInternalServerError)
+		return
+	}
+
+	var req api.PostApiPortForwardsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	peerKey, targetPeerKey, targetIP, err := h.parsePortForwardRequest(account.Id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	targetIPString := ""
+	if targetIP.IsValid() {
+		targetIPString = targetIP.String()
+	}
+
+	newPortForward, err := h.accountManager.CreatePortForward(account.Id, peerKey, req.Description, string(req.Protocol),
+		req.ExternalPort, targetPeerKey, targetIPString, req.TargetPort, req.Enabled)
+	if err != nil {
+		writePortForwardError(w, r, err)
+		return
+	}
+
+	resp := toPortForwardResponse(account, newPortForward)
+
+	writeJSONObject(w, &resp)
+}
+
+// UpdatePortForwardHandler handles update to a port forward identified by a given ID
+func (h *PortForwards) UpdatePortForwardHandler(w http.ResponseWriter, r *http.Request) {
+	account, err := getJWTAccount(h.accountManager, h.jwtExtractor, h.authAudience, r)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusInternalServerError)
+		return
+	}
+
+	portForwardID := mux.Vars(r)["id"]
+	if len(portForwardID) == 0 {
+		http.Error(w, "invalid port forward ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.accountManager.GetPortForward(account.Id, portForwardID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("couldn't find port forward for ID %s", portForwardID), http.StatusNotFound)
+		return
+	}
+
+	var req api.PutApiPortForwardsIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	peerKey, targetPeerKey, targetIP, err := h.parsePortForwardRequest(account.Id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	newPortForward := &route.PortForward{
+		ID:           portForwardID,
+		Description:  req.Description,
+		Peer:         peerKey,
+		Protocol:     string(req.Protocol),
+		ExternalPort: req.ExternalPort,
+		TargetPeer:   targetPeerKey,
+		TargetIP:     targetIP,
+		TargetPort:   req.TargetPort,
+		Enabled:      req.Enabled,
+	}
+
+	err = h.accountManager.SavePortForward(account.Id, newPortForward)
+	if err != nil {
+		log.Errorf("failed updating port forward \"%s\" under account %s %v", portForwardID, account.Id, err)
+		writePortForwardError(w, r, err)
+		return
+	}
+
+	resp := toPortForwardResponse(account, newPortForward)
+
+	writeJSONObject(w, &resp)
+}
+
+// DeletePortForwardHandler handles port forward deletion request
+func (h *PortForwards) DeletePortForwardHandler(w http.ResponseWriter, r *http.Request) {
+	account, err := getJWTAccount(h.accountManager, h.jwtExtractor, h.authAudience, r)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusInternalServerError)
+		return
+	}
+
+	portForwardID := mux.Vars(r)["id"]
+	if len(portForwardID) == 0 {
+		http.Error(w, "invalid port forward ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.accountManager.DeletePortForward(account.Id, portForwardID)
+	if err != nil {
+		errStatus, ok := status.FromError(err)
+		if ok && errStatus.Code() == codes.NotFound {
+			http.Error(w, fmt.Sprintf("port forward %s not found under account %s", portForwardID, account.Id), http.StatusNotFound)
+			return
+		}
+		log.Errorf("failed delete port forward %s under account %s %v", portForwardID, account.Id, err)
+		http.Redirect(w, r, "/", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONObject(w, "")
+}
+
+// GetPortForwardHandler handles a port forward Get request identified by ID
+func (h *PortForwards) GetPortForwardHandler(w http.ResponseWriter, r *http.Request) {
+	account, err := getJWTAccount(h.accountManager, h.jwtExtractor, h.authAudience, r)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusInternalServerError)
+		return
+	}
+
+	portForwardID := mux.Vars(r)["id"]
+	if len(portForwardID) == 0 {
+		http.Error(w, "invalid port forward ID", http.StatusBadRequest)
+		return
+	}
+
+	foundPortForward, err := h.accountManager.GetPortForward(account.Id, portForwardID)
+	if err != nil {
+		http.Error(w, "port forward not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSONObject(w, toPortForwardResponse(account, foundPortForward))
+}
+
+// parsePortForwardRequest validates the request and resolves the routing and target peer IPs to peer keys
+func (h *PortForwards) parsePortForwardRequest(accountID string, req api.PortForwardRequest) (string, string, netip.Addr, error) {
+	err := route.ValidatePortForward(string(req.Protocol), req.ExternalPort, req.TargetPort)
+	if err != nil {
+		return "", "", netip.Addr{}, fmt.Errorf(status.Convert(err).Message())
+	}
+
+	peerKey := req.Peer
+	if req.Peer != "" {
+		peer, err := h.accountManager.GetPeerByIP(accountID, req.Peer)
+		if err != nil {
+			return "", "", netip.Addr{}, fmt.Errorf("couldn't find peer %s", req.Peer)
+		}
+		peerKey = peer.Key
+	}
+
+	hasTargetPeer := req.TargetPeer != nil && *req.TargetPeer != ""
+	hasTargetIP := req.TargetIp != nil && *req.TargetIp != ""
+
+	if hasTargetPeer == hasTargetIP {
+		return "", "", netip.Addr{}, fmt.Errorf("either target_peer or target_ip should be provided")
+	}
+
+	if hasTargetPeer {
+		targetPeer, err := h.accountManager.GetPeerByIP(accountID, *req.TargetPeer)
+		if err != nil {
+			return "", "", netip.Addr{}, fmt.Errorf("couldn't find target peer %s", *req.TargetPeer)
+		}
+		return peerKey, targetPeer.Key, netip.Addr{}, nil
+	}
+
+	targetIP, err := netip.ParseAddr(*req.TargetIp)
+	if err != nil {
+		return "", "", netip.Addr{}, fmt.Errorf("couldn't parse target IP %s", *req.TargetIp)
+	}
+
+	return peerKey, "", targetIP, nil
+}
+
+func writePortForwardError(w http.ResponseWriter, r *http.Request, err error) {
+	errStatus, ok := status.FromError(err)
+	if ok && (errStatus.Code() == codes.InvalidArgument || errStatus.Code() == codes.AlreadyExists) {
+		http.Error(w, errStatus.Message(), http.StatusUnprocessableEntity)
+		return
+	}
+	log.Error(err)
+	http.Redirect(w, r, "/", http.StatusInternalServerError)
+}
+
+func toPortForwardResponse(account *server.Account, portForward *route.PortForward) *api.PortForward {
+	var peerIP string
+	if portForward.Peer != "" {
+		peer, found := account.Peers[portForward.Peer]
+		if found {
+			peerIP = peer.IP.String()
+		}
+	}
+
+	resp := &api.PortForward{
+		Id:           portForward.ID,
+		Description:  portForward.Description,
+		Enabled:      portForward.Enabled,
+		Peer:         peerIP,
+		Protocol:     api.PortForwardProtocol(portForward.Protocol),
+		ExternalPort: portForward.ExternalPort,
+		TargetPort:   portForward.TargetPort,
+	}
+
+	if portForward.TargetPeer != "" {
+		targetPeer, found := account.Peers[portForward.TargetPeer]
+		if found {
+			targetPeerIP := targetPeer.IP.String()
+			resp.TargetPeer = &targetPeerIP
+		}
+	}
+
+	if portForward.TargetIP.IsValid() {
+		targetIP := portForward.TargetIP.String()
+		resp.TargetIp = &targetIP
+	}
+
+	return resp
+}
@@ -0,0 +1,276 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/route"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/magiconair/properties/assert"
+	"github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/jwtclaims"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+)
+
+const (
+	existingPortForwardID = "existingPortForwardID"
+	notFoundPortForwardID = "notFoundPortForwardID"
+)
+
+var baseExistingPortForward = &route.PortForward{
+	ID:           existingPortForwardID,
+	Description:  "base port forward",
+	Peer:         existingPeerKey,
+	Protocol:     route.PortForwardProtocolTCP,
+	ExternalPort: 8080,
+	TargetIP:     netip.MustParseAddr("10.0.0.5"),
+	TargetPort:   80,
+	Enabled:      true,
+}
+
+func initPortForwardsTestData() *PortForwards {
+	return &PortForwards{
+		accountManager: &mock_server.MockAccountManager{
+			GetPortForwardFunc: func(_, portForwardID string) (*route.PortForward, error) {
+				if portForwardID == existingPortForwardID {
+					return baseExistingPortForward, nil
+				}
+				return nil, status.Errorf(codes.NotFound, "port forward with ID %s not found", portForwardID)
+			},
+			CreatePortForwardFunc: func(_ string, peer, description, protocol string, externalPort int, targetPeer, targetIP string, targetPort int, enabled bool) (*route.PortForward, error) {
+				newPortForward := &route.PortForward{
+					ID:           existingPortForwardID,
+					Description:  description,
+					Peer:         peer,
+					Protocol:     protocol,
+					ExternalPort: externalPort,
+					TargetPeer:   targetPeer,
+					TargetPort:   targetPort,
+					Enabled:      enabled,
+				}
+				if targetIP != "" {
+					newPortForward.TargetIP = netip.MustParseAddr(targetIP)
+				}
+				return newPortForward, nil
+			},
+			SavePortForwardFunc: func(_ string, _ *route.PortForward) error {
+				return nil
+			},
+			DeletePortForwardFunc: func(_ string, portForwardID string) error {
+				if portForwardID != existingPortForwardID {
+					return status.Errorf(codes.NotFound, "port forward with ID %s not found", portForwardID)
+				}
+				return nil
+			},
+			GetPeerByIPFunc: func(_ string, peerIP string) (*server.Peer, error) {
+				if peerIP != existingPeerID {
+					return nil, status.Errorf(codes.NotFound, "Peer with ID %s not found", peerIP)
+				}
+				return &server.Peer{
+					Key: existingPeerKey,
+					IP:  netip.MustParseAddr(existingPeerID).AsSlice(),
+				}, nil
+			},
+			GetAccountFromTokenFunc: func(_ jwtclaims.AuthorizationClaims) (*server.Account, error) {
+				return testingAccount, nil
+			},
+		},
+		authAudience: "",
+		jwtExtractor: jwtclaims.ClaimsExtractor{
+			ExtractClaimsFromRequestContext: func(r *http.Request, authAudiance string) jwtclaims.AuthorizationClaims {
+				return jwtclaims.AuthorizationClaims{
+					UserId:    "test_user",
+					Domain:    "hotmail.com",
+					AccountId: testAccountID,
+				}
+			},
+		},
+	}
+}
+
+func TestPortForwardsHandlers(t *testing.T) {
+	targetIP := "10.0.0.5"
+	targetPeerIP := existingPeerID
+
+	tt := []struct {
+		name                string
+		expectedStatus      int
+		expectedBody        bool
+		expectedPortForward *api.PortForward
+		requestType         string
+		requestPath         string
+		requestBody         io.Reader
+	}{
+		{
+			name:                "Get Existing Port Forward",
+			requestType:         http.MethodGet,
+			requestPath:         "/api/port-forwards/" + existingPortForwardID,
+			expectedStatus:      http.StatusOK,
+			expectedBody:        true,
+			expectedPortForward: toPortForwardResponse(testingAccount, baseExistingPortForward),
+		},
+		{
+			name:           "Get Not Existing Port Forward",
+			requestType:    http.MethodGet,
+			requestPath:    "/api/port-forwards/" + notFoundPortForwardID,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Delete Existing Port Forward",
+			requestType:    http.MethodDelete,
+			requestPath:    "/api/port-forwards/" + existingPortForwardID,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Delete Not Existing Port Forward",
+			requestType:    http.MethodDelete,
+			requestPath:    "/api/port-forwards/" + notFoundPortForwardID,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "POST Target IP OK",
+			requestType: http.MethodPost,
+			requestPath: "/api/port-forwards",
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Post\",\"peer\":\"%s\",\"protocol\":\"tcp\",\"external_port\":8080,\"target_ip\":\"%s\",\"target_port\":80,\"enabled\":true}",
+				existingPeerID, targetIP)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedPortForward: &api.PortForward{
+				Id:           existingPortForwardID,
+				Description:  "Post",
+				Peer:         existingPeerID,
+				Protocol:     api.PortForwardProtocolTcp,
+				ExternalPort: 8080,
+				TargetIp:     &targetIP,
+				TargetPort:   80,
+				Enabled:      true,
+			},
+		},
+		{
+			name:        "POST Target Peer OK",
+			requestType: http.MethodPost,
+			requestPath: "/api/port-forwards",
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Post\",\"peer\":\"%s\",\"protocol\":\"udp\",\"external_port\":5353,\"target_peer\":\"%s\",\"target_port\":53,\"enabled\":true}",
+				existingPeerID, existingPeerID)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedPortForward: &api.PortForward{
+				Id:           existingPortForwardID,
+				Description:  "Post",
+				Peer:         existingPeerID,
+				Protocol:     api.PortForwardProtocolUdp,
+				ExternalPort: 5353,
+				TargetPeer:   &targetPeerIP,
+				TargetPort:   53,
+				Enabled:      true,
+			},
+		},
+		{
+			name:        "POST Both Targets",
+			requestType: http.MethodPost,
+			requestPath: "/api/port-forwards",
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Post\",\"peer\":\"%s\",\"protocol\":\"tcp\",\"external_port\":8080,\"target_peer\":\"%s\",\"target_ip\":\"%s\",\"target_port\":80}",
+				existingPeerID, existingPeerID, targetIP)),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "POST Invalid Protocol",
+			requestType: http.MethodPost,
+			requestPath: "/api/port-forwards",
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Post\",\"peer\":\"%s\",\"protocol\":\"icmp\",\"external_port\":8080,\"target_ip\":\"%s\",\"target_port\":80}",
+				existingPeerID, targetIP)),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "POST Invalid Port",
+			requestType: http.MethodPost,
+			requestPath: "/api/port-forwards",
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Post\",\"peer\":\"%s\",\"protocol\":\"tcp\",\"external_port\":70000,\"target_ip\":\"%s\",\"target_port\":80}",
+				existingPeerID, targetIP)),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "POST Not Found Peer",
+			requestType: http.MethodPost,
+			requestPath: "/api/port-forwards",
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Post\",\"peer\":\"%s\",\"protocol\":\"tcp\",\"external_port\":8080,\"target_ip\":\"%s\",\"target_port\":80}",
+				notFoundPeerID, targetIP)),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "PUT OK",
+			requestType: http.MethodPut,
+			requestPath: "/api/port-forwards/" + existingPortForwardID,
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Put\",\"peer\":\"%s\",\"protocol\":\"tcp\",\"external_port\":8443,\"target_ip\":\"%s\",\"target_port\":443}",
+				existingPeerID, targetIP)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedPortForward: &api.PortForward{
+				Id:           existingPortForwardID,
+				Description:  "Put",
+				Peer:         existingPeerID,
+				Protocol:     api.PortForwardProtocolTcp,
+				ExternalPort: 8443,
+				TargetIp:     &targetIP,
+				TargetPort:   443,
+			},
+		},
+		{
+			name:        "PUT Not Found Port Forward",
+			requestType: http.MethodPut,
+			requestPath: "/api/port-forwards/" + notFoundPortForwardID,
+			requestBody: bytes.NewBufferString(fmt.Sprintf("{\"description\":\"Put\",\"peer\":\"%s\",\"protocol\":\"tcp\",\"external_port\":8443,\"target_ip\":\"%s\",\"target_port\":443}",
+				existingPeerID, targetIP)),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	p := initPortForwardsTestData()
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.requestType, tc.requestPath, tc.requestBody)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/port-forwards/{id}", p.GetPortForwardHandler).Methods("GET")
+			router.HandleFunc("/api/port-forwards/{id}", p.DeletePortForwardHandler).Methods("DELETE")
+			router.HandleFunc("/api/port-forwards", p.CreatePortForwardHandler).Methods("POST")
+			router.HandleFunc("/api/port-forwards/{id}", p.UpdatePortForwardHandler).Methods("PUT")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			content, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("I don't know what I expected; %v", err)
+			}
+
+			if status := recorder.Code; status != tc.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v, content: %s",
+					status, tc.expectedStatus, string(content))
+				return
+			}
+
+			if !tc.expectedBody {
+				return
+			}
+
+			got := &api.PortForward{}
+			if err = json.Unmarshal(content, &got); err != nil {
+				t.Fatalf("Sent content is not in correct json format; %v", err)
+			}
+			assert.Equal(t, got, tc.expectedPortForward)
+		})
+	}
+}
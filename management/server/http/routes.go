@@ -48,7 +48,7 @@ func (h *Routes) GetAllRoutesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	apiRoutes := make([]*api.Route, 0)
 	for _, r := range routes {
-		apiRoutes = append(apiRoutes, toRouteResponse(account, r))
+		apiRoutes = append(apiRoutes, toRouteResponse(account, r, h.accountManager.GetPeerRoutesTraffic(r.Peer)))
 	}
 
 	writeJSONObject(w, apiRoutes)
@@ -97,7 +97,7 @@ func (h *Routes) CreateRouteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := toRouteResponse(account, newRoute)
+	resp := toRouteResponse(account, newRoute, h.accountManager.GetPeerRoutesTraffic(newRoute.Peer))
 
 	writeJSONObject(w, &resp)
 }
@@ -170,7 +170,7 @@ func (h *Routes) UpdateRouteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := toRouteResponse(account, newRoute)
+	resp := toRouteResponse(account, newRoute, h.accountManager.GetPeerRoutesTraffic(newRoute.Peer))
 
 	writeJSONObject(w, &resp)
 }
@@ -327,7 +327,7 @@ func (h *Routes) PatchRouteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := toRouteResponse(account, route)
+	resp := toRouteResponse(account, route, h.accountManager.GetPeerRoutesTraffic(route.Peer))
 
 	writeJSONObject(w, &resp)
 }
@@ -381,17 +381,29 @@ func (h *Routes) GetRouteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSONObject(w, toRouteResponse(account, foundRoute))
+	writeJSONObject(w, toRouteResponse(account, foundRoute, h.accountManager.GetPeerRoutesTraffic(foundRoute.Peer)))
 }
 
-func toRouteResponse(account *server.Account, serverRoute *route.Route) *api.Route {
+func toRouteResponse(account *server.Account, serverRoute *route.Route, peerRoutesTraffic map[string]server.RouteTraffic) *api.Route {
 	var peerIP string
+	var traffic *api.RouteTraffic
 	if serverRoute.Peer != "" {
 		peer, found := account.Peers[serverRoute.Peer]
 		if !found {
 			panic("peer ID not found")
 		}
 		peerIP = peer.IP.String()
+
+		routeTraffic, found := peerRoutesTraffic[serverRoute.ID]
+		if found {
+			traffic = &api.RouteTraffic{
+				TxBytes:     int64(routeTraffic.TxBytes),
+				TxPackets:   int64(routeTraffic.TxPackets),
+				RxBytes:     int64(routeTraffic.RxBytes),
+				RxPackets:   int64(routeTraffic.RxPackets),
+				LastUpdated: routeTraffic.LastUpdated,
+			}
+		}
 	}
 
 	return &api.Route{
@@ -404,5 +416,6 @@ func toRouteResponse(account *server.Account, serverRoute *route.Route) *api.Rou
 		NetworkType: serverRoute.NetworkType.String(),
 		Masquerade:  serverRoute.Masquerade,
 		Metric:      serverRoute.Metric,
+		Traffic:     traffic,
 	}
 }
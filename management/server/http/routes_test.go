@@ -153,7 +153,7 @@ func TestRoutesHandlers(t *testing.T) {
 			requestPath:    "/api/routes/" + existingRouteID,
 			expectedStatus: http.StatusOK,
 			expectedBody:   true,
-			expectedRoute:  toRouteResponse(testingAccount, baseExistingRoute),
+			expectedRoute:  toRouteResponse(testingAccount, baseExistingRoute, nil),
 		},
 		{
 			name:           "Get Not Existing Route",
@@ -392,6 +392,83 @@ func TestServer_GetDeviceAuthorizationFlow(t *testing.T) {
 	}
 }
 
+func TestServer_GetPKCEAuthorizationFlow(t *testing.T) {
+	testingServerKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err, "unable to generate server wg key for testing GetPKCEAuthorizationFlow")
+
+	testingClientKey, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err, "unable to generate client wg key for testing GetPKCEAuthorizationFlow")
+
+	testCases := []struct {
+		name            string
+		inputFlow       *PKCEAuthorizationFlow
+		expectedErrFunc require.ErrorAssertionFunc
+	}{
+		{
+			name:            "Testing No PKCE Flow Config",
+			inputFlow:       nil,
+			expectedErrFunc: require.Error,
+		},
+		{
+			name: "Testing Full PKCE Flow Config",
+			inputFlow: &PKCEAuthorizationFlow{
+				ProviderConfig: ProviderConfig{
+					ClientID:              "test",
+					Audience:              "netbird",
+					TokenEndpoint:         "https://idp.example.com/token",
+					AuthorizationEndpoint: "https://idp.example.com/authorize",
+					Scope:                 "openid profile email",
+					RedirectURLs:          []string{"http://localhost:53000/", "http://localhost:54000/"},
+					Issuer:                "https://idp.example.com/",
+					JwksURI:               "https://idp.example.com/.well-known/jwks.json",
+				},
+			},
+			expectedErrFunc: require.NoError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mgmtServer := &GRPCServer{
+				wgKey: testingServerKey,
+				config: &Config{
+					PKCEAuthorizationFlow: testCase.inputFlow,
+				},
+			}
+
+			message := &mgmtProto.PKCEAuthorizationFlowRequest{}
+
+			encryptedMSG, err := encryption.EncryptMessage(testingClientKey.PublicKey(), mgmtServer.wgKey, message)
+			require.NoError(t, err, "should be able to encrypt message")
+
+			resp, err := mgmtServer.GetPKCEAuthorizationFlow(
+				context.TODO(),
+				&mgmtProto.EncryptedMessage{
+					WgPubKey: testingClientKey.PublicKey().String(),
+					Body:     encryptedMSG,
+				},
+			)
+			testCase.expectedErrFunc(t, err)
+			if testCase.inputFlow == nil {
+				return
+			}
+
+			flowInfoResp := &mgmtProto.PKCEAuthorizationFlow{}
+			err = encryption.DecryptMessage(mgmtServer.wgKey.PublicKey(), testingClientKey, resp.Body, flowInfoResp)
+			require.NoError(t, err, "should be able to decrypt")
+
+			expected := testCase.inputFlow.ProviderConfig
+			require.Equal(t, expected.ClientID, flowInfoResp.GetProviderConfig().GetClientID())
+			require.Equal(t, expected.AuthorizationEndpoint, flowInfoResp.GetProviderConfig().GetAuthorizationEndpoint())
+			require.Equal(t, expected.TokenEndpoint, flowInfoResp.GetProviderConfig().GetTokenEndpoint())
+			require.Equal(t, expected.Scope, flowInfoResp.GetProviderConfig().GetScope())
+			require.Equal(t, expected.RedirectURLs, flowInfoResp.GetProviderConfig().GetRedirectURLs())
+			require.Equal(t, expected.Issuer, flowInfoResp.GetProviderConfig().GetIssuer())
+			require.Equal(t, expected.JwksURI, flowInfoResp.GetProviderConfig().GetJwksURI())
+		})
+	}
+}
+
 func startManagement(t *testing.T, port int, config *Config) (*grpc.Server, error) {
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
 	if err != nil {
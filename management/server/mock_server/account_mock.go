@@ -43,6 +43,9 @@ type MockAccountManager struct {
 	GetUsersFromAccountFunc         func(accountID string) ([]*server.UserInfo, error)
 	UpdatePeerMetaFunc              func(peerKey string, meta server.PeerSystemMeta) error
 	UpdatePeerSSHKeyFunc            func(peerKey string, sshKey string) error
+	UpdatePeerRouteConflictsFunc    func(peerKey string, conflicts []server.RouteConflict) error
+	UpdatePeerRoutesTrafficFunc     func(peerKey string, routesTraffic map[string]server.RouteTraffic) error
+	GetPeerRoutesTrafficFunc        func(peerKey string) map[string]server.RouteTraffic
 	UpdatePeerFunc                  func(accountID string, peer *server.Peer) (*server.Peer, error)
 	CreateRouteFunc                 func(accountID string, prefix, peer, description, netID string, masquerade bool, metric int, enabled bool) (*route.Route, error)
 	GetRouteFunc                    func(accountID, routeID string) (*route.Route, error)
@@ -50,6 +53,13 @@ type MockAccountManager struct {
 	UpdateRouteFunc                 func(accountID string, routeID string, operations []server.RouteUpdateOperation) (*route.Route, error)
 	DeleteRouteFunc                 func(accountID, routeID string) error
 	ListRoutesFunc                  func(accountID string) ([]*route.Route, error)
+	GetPortForwardFunc              func(accountID, portForwardID string) (*route.PortForward, error)
+	CreatePortForwardFunc           func(accountID string, peer, description, protocol string, externalPort int, targetPeer, targetIP string, targetPort int, enabled bool) (*route.PortForward, error)
+	SavePortForwardFunc             func(accountID string, portForward *route.PortForward) error
+	DeletePortForwardFunc           func(accountID, portForwardID string) error
+	ListPortForwardsFunc            func(accountID string) ([]*route.PortForward, error)
+	GetPeerPortForwardsFunc         func(peerKey string) ([]*route.PortForward, error)
+	GetPeerConnectionPoliciesFunc   func(peerKey string) (map[string]server.ConnectionPolicy, error)
 	SaveSetupKeyFunc                func(accountID string, key *server.SetupKey) (*server.SetupKey, error)
 	ListSetupKeysFunc               func(accountID string) ([]*server.SetupKey, error)
 	SaveUserFunc                    func(accountID string, user *server.User) (*server.UserInfo, error)
@@ -335,6 +345,30 @@ func (am *MockAccountManager) UpdatePeerSSHKey(peerKey string, sshKey string) er
 	return status.Errorf(codes.Unimplemented, "method UpdatePeerSSHKey is is not implemented")
 }
 
+// UpdatePeerRouteConflicts mocks UpdatePeerRouteConflicts function of the account manager
+func (am *MockAccountManager) UpdatePeerRouteConflicts(peerKey string, conflicts []server.RouteConflict) error {
+	if am.UpdatePeerRouteConflictsFunc != nil {
+		return am.UpdatePeerRouteConflictsFunc(peerKey, conflicts)
+	}
+	return status.Errorf(codes.Unimplemented, "method UpdatePeerRouteConflicts is not implemented")
+}
+
+// UpdatePeerRoutesTraffic mocks UpdatePeerRoutesTraffic function of the account manager
+func (am *MockAccountManager) UpdatePeerRoutesTraffic(peerKey string, routesTraffic map[string]server.RouteTraffic) error {
+	if am.UpdatePeerRoutesTrafficFunc != nil {
+		return am.UpdatePeerRoutesTrafficFunc(peerKey, routesTraffic)
+	}
+	return status.Errorf(codes.Unimplemented, "method UpdatePeerRoutesTraffic is not implemented")
+}
+
+// GetPeerRoutesTraffic mocks GetPeerRoutesTraffic function of the account manager
+func (am *MockAccountManager) GetPeerRoutesTraffic(peerKey string) map[string]server.RouteTraffic {
+	if am.GetPeerRoutesTrafficFunc != nil {
+		return am.GetPeerRoutesTrafficFunc(peerKey)
+	}
+	return nil
+}
+
 // UpdatePeer mocks UpdatePeerFunc function of the account manager
 func (am *MockAccountManager) UpdatePeer(accountID string, peer *server.Peer) (*server.Peer, error) {
 	if am.UpdatePeerFunc != nil {
@@ -391,6 +425,62 @@ func (am *MockAccountManager) ListRoutes(accountID string) ([]*route.Route, erro
 	return nil, status.Errorf(codes.Unimplemented, "method ListRoutes is not implemented")
 }
 
+// GetPortForward mock implementation of GetPortForward from server.AccountManager interface
+func (am *MockAccountManager) GetPortForward(accountID, portForwardID string) (*route.PortForward, error) {
+	if am.GetPortForwardFunc != nil {
+		return am.GetPortForwardFunc(accountID, portForwardID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortForward is not implemented")
+}
+
+// CreatePortForward mock implementation of CreatePortForward from server.AccountManager interface
+func (am *MockAccountManager) CreatePortForward(accountID string, peer, description, protocol string, externalPort int, targetPeer, targetIP string, targetPort int, enabled bool) (*route.PortForward, error) {
+	if am.CreatePortForwardFunc != nil {
+		return am.CreatePortForwardFunc(accountID, peer, description, protocol, externalPort, targetPeer, targetIP, targetPort, enabled)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePortForward is not implemented")
+}
+
+// SavePortForward mock implementation of SavePortForward from server.AccountManager interface
+func (am *MockAccountManager) SavePortForward(accountID string, portForward *route.PortForward) error {
+	if am.SavePortForwardFunc != nil {
+		return am.SavePortForwardFunc(accountID, portForward)
+	}
+	return status.Errorf(codes.Unimplemented, "method SavePortForward is not implemented")
+}
+
+// DeletePortForward mock implementation of DeletePortForward from server.AccountManager interface
+func (am *MockAccountManager) DeletePortForward(accountID, portForwardID string) error {
+	if am.DeletePortForwardFunc != nil {
+		return am.DeletePortForwardFunc(accountID, portForwardID)
+	}
+	return status.Errorf(codes.Unimplemented, "method DeletePortForward is not implemented")
+}
+
+// ListPortForwards mock implementation of ListPortForwards from server.AccountManager interface
+func (am *MockAccountManager) ListPortForwards(accountID string) ([]*route.PortForward, error) {
+	if am.ListPortForwardsFunc != nil {
+		return am.ListPortForwardsFunc(accountID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method ListPortForwards is not implemented")
+}
+
+// GetPeerPortForwards mock implementation of GetPeerPortForwards from server.AccountManager interface
+func (am *MockAccountManager) GetPeerPortForwards(peerKey string) ([]*route.PortForward, error) {
+	if am.GetPeerPortForwardsFunc != nil {
+		return am.GetPeerPortForwardsFunc(peerKey)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerPortForwards is not implemented")
+}
+
+// GetPeerConnectionPolicies mock implementation of GetPeerConnectionPolicies from server.AccountManager interface
+func (am *MockAccountManager) GetPeerConnectionPolicies(peerKey string) (map[string]server.ConnectionPolicy, error) {
+	if am.GetPeerConnectionPoliciesFunc != nil {
+		return am.GetPeerConnectionPoliciesFunc(peerKey)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerConnectionPolicies is not implemented")
+}
+
 // SaveSetupKey mocks SaveSetupKey of the AccountManager interface
 func (am *MockAccountManager) SaveSetupKey(accountID string, key *server.SetupKey) (*server.SetupKey, error) {
 	if am.SaveSetupKeyFunc != nil {
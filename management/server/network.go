@@ -23,9 +23,12 @@ const (
 )
 
 type NetworkMap struct {
-	Peers   []*Peer
-	Network *Network
-	Routes  []*route.Route
+	Peers        []*Peer
+	Network      *Network
+	Routes       []*route.Route
+	PortForwards []*route.PortForward
+	// ConnectionPolicies are the policies of the connections to the peers, indexed by peer key
+	ConnectionPolicies map[string]ConnectionPolicy
 }
 
 type Network struct {
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// oidcDiscoveryPath is the path of the OpenID Connect discovery document relative to the issuer URL
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+// OIDCConfigResponse used for parsing OIDC config response
+type OIDCConfigResponse struct {
+	Issuer                string `json:"issuer"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	DeviceAuthEndpoint    string `json:"device_authorization_endpoint"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// FetchOIDCConfig fetches OIDC configuration from the IDP
+func FetchOIDCConfig(oidcEndpoint string) (OIDCConfigResponse, error) {
+
+	res, err := http.Get(oidcEndpoint)
+	if err != nil {
+		return OIDCConfigResponse{}, fmt.Errorf("failed fetching OIDC configuration fro mendpoint %s %v", oidcEndpoint, err)
+	}
+
+	defer func() {
+		err := res.Body.Close()
+		if err != nil {
+			log.Debugf("failed closing response body %v", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return OIDCConfigResponse{}, fmt.Errorf("failed reading OIDC configuration response body: %v", err)
+	}
+
+	if res.StatusCode != 200 {
+		return OIDCConfigResponse{}, fmt.Errorf("OIDC configuration request returned status %d with response: %s",
+			res.StatusCode, string(body))
+	}
+
+	config := OIDCConfigResponse{}
+	err = json.Unmarshal(body, &config)
+	if err != nil {
+		return OIDCConfigResponse{}, fmt.Errorf("failed unmarshaling OIDC configuration response: %v", err)
+	}
+
+	return config, nil
+
+}
+
+// ResolveOIDCEndpoints fills the endpoints that aren't configured from the discovery document of the Issuer
+// and replaces the Issuer with the exact one of the document.
+// Nothing is fetched if no Issuer is configured or all endpoints are set
+func (c *ProviderConfig) ResolveOIDCEndpoints() error {
+	if c.Issuer == "" {
+		return nil
+	}
+	if c.TokenEndpoint != "" && c.DeviceAuthEndpoint != "" && c.AuthorizationEndpoint != "" && c.JwksURI != "" {
+		return nil
+	}
+
+	oidcConfig, err := FetchOIDCConfig(strings.TrimSuffix(c.Issuer, "/") + oidcDiscoveryPath)
+	if err != nil {
+		return err
+	}
+
+	// the issuer of the discovery document must be the configured one, see
+	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfigurationValidation
+	if strings.TrimSuffix(oidcConfig.Issuer, "/") != strings.TrimSuffix(c.Issuer, "/") {
+		return fmt.Errorf("OIDC configuration of issuer %s belongs to issuer %s", c.Issuer, oidcConfig.Issuer)
+	}
+	// clients verify the iss claim of the tokens exactly, it has to be the issuer of the discovery document
+	c.Issuer = oidcConfig.Issuer
+
+	if c.TokenEndpoint == "" {
+		c.TokenEndpoint = oidcConfig.TokenEndpoint
+	}
+	if c.DeviceAuthEndpoint == "" {
+		c.DeviceAuthEndpoint = oidcConfig.DeviceAuthEndpoint
+	}
+	if c.AuthorizationEndpoint == "" {
+		c.AuthorizationEndpoint = oidcConfig.AuthorizationEndpoint
+	}
+	if c.JwksURI == "" {
+		c.JwksURI = oidcConfig.JwksURI
+	}
+
+	return nil
+}
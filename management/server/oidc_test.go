@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newOIDCDiscoveryServer(t *testing.T, issuer func(serverURL string) string) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != oidcDiscoveryPath {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(OIDCConfigResponse{
+			Issuer:                issuer(server.URL),
+			TokenEndpoint:         server.URL + "/oauth/token",
+			DeviceAuthEndpoint:    server.URL + "/oauth/device/code",
+			AuthorizationEndpoint: server.URL + "/authorize",
+			JwksURI:               server.URL + "/.well-known/jwks.json",
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestProviderConfig_ResolveOIDCEndpoints(t *testing.T) {
+	server := newOIDCDiscoveryServer(t, func(serverURL string) string { return serverURL + "/" })
+
+	config := ProviderConfig{
+		Issuer:        server.URL + "/",
+		TokenEndpoint: "https://idp.example.com/token",
+	}
+	require.NoError(t, config.ResolveOIDCEndpoints())
+	require.Equal(t, "https://idp.example.com/token", config.TokenEndpoint, "configured endpoints should be kept")
+	require.Equal(t, server.URL+"/oauth/device/code", config.DeviceAuthEndpoint)
+	require.Equal(t, server.URL+"/authorize", config.AuthorizationEndpoint)
+	require.Equal(t, server.URL+"/.well-known/jwks.json", config.JwksURI)
+
+	withoutSlash := ProviderConfig{Issuer: server.URL}
+	require.NoError(t, withoutSlash.ResolveOIDCEndpoints())
+	require.Equal(t, server.URL+"/", withoutSlash.Issuer, "the issuer of the discovery document should be used")
+
+	withoutIssuer := ProviderConfig{TokenEndpoint: "https://idp.example.com/token"}
+	require.NoError(t, withoutIssuer.ResolveOIDCEndpoints())
+	require.Empty(t, withoutIssuer.JwksURI, "nothing should be resolved without an issuer")
+}
+
+func TestProviderConfig_ResolveOIDCEndpoints_IssuerMismatch(t *testing.T) {
+	server := newOIDCDiscoveryServer(t, func(string) string { return "https://attacker.example.com" })
+
+	config := ProviderConfig{Issuer: server.URL}
+	require.Error(t, config.ResolveOIDCEndpoints(), "a discovery document of another issuer should be rejected")
+
+	unreachable := ProviderConfig{Issuer: server.URL + "/unknown"}
+	require.Error(t, unreachable.ResolveOIDCEndpoints())
+}
@@ -3,11 +3,13 @@ package server
 import (
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/proto"
+	"github.com/netbirdio/netbird/route"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -31,6 +33,34 @@ type PeerStatus struct {
 	Connected bool
 }
 
+// RouteConflict represents a route that overlaps with a network the peer is directly attached to
+type RouteConflict struct {
+	// NetID is the network ID of the conflicting route
+	NetID string
+	// Network is the conflicting route network in CIDR format
+	Network string
+	// LocalNetwork is the local network in CIDR format that overlaps with the route
+	LocalNetwork string
+	// Interface is the local interface the overlapping network is attached to
+	Interface string
+	// Action is the action taken by the peer, e.g. skipped or applied
+	Action string
+}
+
+// RouteTraffic represents the traffic forwarded by a routing peer for one of its routes as reported by the peer
+type RouteTraffic struct {
+	// TxBytes is the number of bytes forwarded to the routed network
+	TxBytes uint64
+	// TxPackets is the number of packets forwarded to the routed network
+	TxPackets uint64
+	// RxBytes is the number of bytes forwarded from the routed network
+	RxBytes uint64
+	// RxPackets is the number of packets forwarded from the routed network
+	RxPackets uint64
+	// LastUpdated is the time the counters were last reported by the peer
+	LastUpdated time.Time
+}
+
 // Peer represents a machine connected to the network.
 // The Peer is a Wireguard peer identified by a public key
 type Peer struct {
@@ -51,20 +81,30 @@ type Peer struct {
 	SSHKey string
 	// SSHEnabled indicated whether SSH server is enabled on the peer
 	SSHEnabled bool
+	// RouteConflicts is a list of routes that overlap with the peer's local networks as reported by the peer
+	RouteConflicts []RouteConflict
+	// RoutesTraffic is the traffic forwarded by the peer for each of its routes, indexed by route ID
+	RoutesTraffic map[string]RouteTraffic
+	// ConnectionPolicy restricts how the other peers connect to the peer, it is combined with the policies of its groups
+	ConnectionPolicy ConnectionPolicy
 }
 
 // Copy copies Peer object
 func (p *Peer) Copy() *Peer {
 	return &Peer{
-		Key:        p.Key,
-		SetupKey:   p.SetupKey,
-		IP:         p.IP,
-		Meta:       p.Meta,
-		Name:       p.Name,
-		Status:     p.Status,
-		UserID:     p.UserID,
-		SSHKey:     p.SSHKey,
-		SSHEnabled: p.SSHEnabled,
+		Key:            p.Key,
+		SetupKey:       p.SetupKey,
+		IP:             p.IP,
+		Meta:           p.Meta,
+		Name:           p.Name,
+		Status:         p.Status,
+		UserID:         p.UserID,
+		SSHKey:         p.SSHKey,
+		SSHEnabled:     p.SSHEnabled,
+		RouteConflicts: p.RouteConflicts,
+		RoutesTraffic:  p.RoutesTraffic,
+
+		ConnectionPolicy: p.ConnectionPolicy,
 	}
 }
 
@@ -106,7 +146,7 @@ func (am *DefaultAccountManager) MarkPeerConnected(peerKey string, connected boo
 	return nil
 }
 
-// UpdatePeer updates peer. Only Peer.Name and Peer.SSHEnabled can be updated.
+// UpdatePeer updates peer. Only Peer.Name, Peer.SSHEnabled and Peer.ConnectionPolicy can be updated.
 func (am *DefaultAccountManager) UpdatePeer(accountID string, update *Peer) (*Peer, error) {
 	am.mux.Lock()
 	defer am.mux.Unlock()
@@ -126,6 +166,10 @@ func (am *DefaultAccountManager) UpdatePeer(accountID string, update *Peer) (*Pe
 		peerCopy.Name = update.Name
 	}
 	peerCopy.SSHEnabled = update.SSHEnabled
+	peerCopy.ConnectionPolicy, err = ParseConnectionPolicy(string(update.ConnectionPolicy))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	err = am.Store.SavePeer(accountID, peerCopy)
 	if err != nil {
@@ -255,12 +299,27 @@ func (am *DefaultAccountManager) GetNetworkMap(peerKey string) (*NetworkMap, err
 	routesUpdate := am.getPeersRoutes(append(aclPeers, account.Peers[peerKey]))
 
 	return &NetworkMap{
-		Peers:   aclPeers,
-		Network: account.Network.Copy(),
-		Routes:  routesUpdate,
+		Peers:              aclPeers,
+		Network:            account.Network.Copy(),
+		Routes:             routesUpdate,
+		PortForwards:       getPeerPortForwards(account, peerKey),
+		ConnectionPolicies: getPeerConnectionPolicies(account, peerKey, aclPeers),
 	}, err
 }
 
+// GetPeerPortForwards returns the port forwards applied by a given routing peer
+func (am *DefaultAccountManager) GetPeerPortForwards(peerKey string) ([]*route.PortForward, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	account, err := am.Store.GetPeerAccount(peerKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Invalid peer key %s", peerKey)
+	}
+
+	return getPeerPortForwards(account, peerKey), nil
+}
+
 // GetPeerNetwork returns the Network for a given peer
 func (am *DefaultAccountManager) GetPeerNetwork(peerKey string) (*Network, error) {
 	am.mux.Lock()
@@ -461,6 +520,152 @@ func (am *DefaultAccountManager) UpdatePeerMeta(peerKey string, meta PeerSystemM
 	return nil
 }
 
+// UpdatePeerRouteConflicts replaces the route conflicts reported by the peer
+func (am *DefaultAccountManager) UpdatePeerRouteConflicts(peerKey string, conflicts []RouteConflict) error {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	peer, err := am.Store.GetPeer(peerKey)
+	if err != nil {
+		return err
+	}
+
+	account, err := am.Store.GetPeerAccount(peerKey)
+	if err != nil {
+		return err
+	}
+
+	peerCopy := peer.Copy()
+	peerCopy.RouteConflicts = conflicts
+
+	err = am.Store.SavePeer(account.Id, peerCopy)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// routesTrafficPersistInterval is how often the routes traffic reported by the peers is written to the store
+const routesTrafficPersistInterval = 10 * time.Minute
+
+// routesTrafficCache keeps the routes traffic reported by the peers in memory, so frequent reports don't rewrite the
+// store. The traffic of the changed peers is persisted periodically
+type routesTrafficCache struct {
+	mux sync.Mutex
+	// traffic is the last reported traffic indexed by peer key and route ID
+	traffic map[string]map[string]RouteTraffic
+	// changed holds the keys of the peers with traffic that wasn't persisted yet
+	changed map[string]struct{}
+}
+
+func newRoutesTrafficCache() *routesTrafficCache {
+	return &routesTrafficCache{
+		traffic: make(map[string]map[string]RouteTraffic),
+		changed: make(map[string]struct{}),
+	}
+}
+
+// UpdatePeerRoutesTraffic replaces the routes traffic reported by the peer. Traffic of routes that don't exist
+// or aren't routed by the peer is ignored. The traffic is kept in memory and persisted periodically
+func (am *DefaultAccountManager) UpdatePeerRoutesTraffic(peerKey string, routesTraffic map[string]RouteTraffic) error {
+	am.mux.Lock()
+	account, err := am.Store.GetPeerAccount(peerKey)
+	am.mux.Unlock()
+	if err != nil {
+		return err
+	}
+
+	peerRoutesTraffic := make(map[string]RouteTraffic)
+	for routeID, traffic := range routesTraffic {
+		route, found := account.Routes[routeID]
+		if !found || route.Peer != peerKey {
+			log.Debugf("ignoring traffic reported by peer %s for route %s as it is not routed by the peer", peerKey, routeID)
+			continue
+		}
+		peerRoutesTraffic[routeID] = traffic
+	}
+
+	am.routesTraffic.mux.Lock()
+	defer am.routesTraffic.mux.Unlock()
+
+	am.routesTraffic.traffic[peerKey] = peerRoutesTraffic
+	am.routesTraffic.changed[peerKey] = struct{}{}
+	return nil
+}
+
+// GetPeerRoutesTraffic returns the last routes traffic reported by the peer, including the traffic that wasn't
+// persisted yet
+func (am *DefaultAccountManager) GetPeerRoutesTraffic(peerKey string) map[string]RouteTraffic {
+	am.routesTraffic.mux.Lock()
+	traffic, found := am.routesTraffic.traffic[peerKey]
+	am.routesTraffic.mux.Unlock()
+	if found {
+		return traffic
+	}
+
+	peer, err := am.Store.GetPeer(peerKey)
+	if err != nil {
+		return nil
+	}
+	return peer.RoutesTraffic
+}
+
+// persistRoutesTraffic saves the routes traffic of the peers that reported traffic since the last call
+func (am *DefaultAccountManager) persistRoutesTraffic() {
+	am.routesTraffic.mux.Lock()
+	changed := make(map[string]map[string]RouteTraffic, len(am.routesTraffic.changed))
+	for peerKey := range am.routesTraffic.changed {
+		changed[peerKey] = am.routesTraffic.traffic[peerKey]
+	}
+	am.routesTraffic.changed = make(map[string]struct{})
+	am.routesTraffic.mux.Unlock()
+
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	for peerKey, traffic := range changed {
+		err := am.savePeerRoutesTraffic(peerKey, traffic)
+		if err != nil {
+			log.Debugf("unable to persist routes traffic of peer %s: %v", peerKey, err)
+			am.routesTraffic.mux.Lock()
+			delete(am.routesTraffic.traffic, peerKey)
+			am.routesTraffic.mux.Unlock()
+		}
+	}
+}
+
+func (am *DefaultAccountManager) savePeerRoutesTraffic(peerKey string, traffic map[string]RouteTraffic) error {
+	peer, err := am.Store.GetPeer(peerKey)
+	if err != nil {
+		return err
+	}
+
+	account, err := am.Store.GetPeerAccount(peerKey)
+	if err != nil {
+		return err
+	}
+
+	peerCopy := peer.Copy()
+	peerCopy.RoutesTraffic = traffic
+
+	return am.Store.SavePeer(account.Id, peerCopy)
+}
+
+// persistRoutesTrafficPeriodically persists the reported routes traffic every routesTrafficPersistInterval
+func (am *DefaultAccountManager) persistRoutesTrafficPeriodically() {
+	ticker := time.NewTicker(routesTrafficPersistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-am.ctx.Done():
+			return
+		case <-ticker.C:
+			am.persistRoutesTraffic()
+		}
+	}
+}
+
 // getPeersByACL returns all peers that given peer has access to.
 func (am *DefaultAccountManager) getPeersByACL(account *Account, peerKey string) []*Peer {
 	var peers []*Peer
@@ -538,7 +743,7 @@ func (am *DefaultAccountManager) updateAccountPeers(account *Account) error {
 
 	for _, peer := range peers {
 		aclPeers := am.getPeersByACL(account, peer.Key)
-		peersUpdate := toRemotePeerConfig(aclPeers)
+		peersUpdate := toRemotePeerConfig(aclPeers, getPeerConnectionPolicies(account, peer.Key, aclPeers))
 		routesUpdate := toProtocolRoutes(am.getPeersRoutes(append(aclPeers, peer)))
 		err = am.peersUpdateManager.SendUpdate(peer.Key,
 			&UpdateMessage{
@@ -553,6 +758,7 @@ func (am *DefaultAccountManager) updateAccountPeers(account *Account) error {
 						RemotePeersIsEmpty: len(peersUpdate) == 0,
 						PeerConfig:         toPeerConfig(peer, network),
 						Routes:             routesUpdate,
+						PortForwards:       toProtocolPortForwards(getPeerPortForwards(account, peer.Key)),
 					},
 				},
 			})
@@ -319,3 +319,81 @@ func TestAccountManager_GetPeerNetwork(t *testing.T) {
 	}
 
 }
+
+func TestAccountManager_UpdatePeerRouteConflicts(t *testing.T) {
+	manager, err := createManager(t)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	account, err := createAccount(manager, "test_account", "account_creator", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var setupKey *SetupKey
+	for _, key := range account.SetupKeys {
+		if key.Type == SetupKeyReusable {
+			setupKey = key
+		}
+	}
+
+	peerKey, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	_, err = manager.AddPeer(setupKey.Key, "", &Peer{
+		Key:  peerKey.PublicKey().String(),
+		Meta: PeerSystemMeta{},
+		Name: "test-peer",
+	})
+	if err != nil {
+		t.Errorf("expecting peer to be added, got failure %v", err)
+		return
+	}
+
+	conflicts := []RouteConflict{
+		{
+			NetID:        "office",
+			Network:      "192.168.1.0/24",
+			LocalNetwork: "192.168.1.0/24",
+			Interface:    "eth0",
+			Action:       "skipped",
+		},
+	}
+
+	err = manager.UpdatePeerRouteConflicts(peerKey.PublicKey().String(), conflicts)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	peer, err := manager.GetPeer(peerKey.PublicKey().String())
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	if len(peer.RouteConflicts) != 1 || peer.RouteConflicts[0] != conflicts[0] {
+		t.Errorf("expecting peer route conflicts to be %v, got %v", conflicts, peer.RouteConflicts)
+	}
+
+	err = manager.UpdatePeerRouteConflicts(peerKey.PublicKey().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	peer, err = manager.GetPeer(peerKey.PublicKey().String())
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+
+	if len(peer.RouteConflicts) != 0 {
+		t.Errorf("expecting peer route conflicts to be cleared, got %v", peer.RouteConflicts)
+	}
+}
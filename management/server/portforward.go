@@ -0,0 +1,246 @@
+package server
+
+import (
+	"github.com/netbirdio/netbird/management/proto"
+	"github.com/netbirdio/netbird/route"
+	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/netip"
+	"sort"
+)
+
+// GetPortForward gets a port forward object from account and port forward IDs
+func (am *DefaultAccountManager) GetPortForward(accountID, portForwardID string) (*route.PortForward, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	account, err := am.Store.GetAccount(accountID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "account not found")
+	}
+
+	portForward, found := account.PortForwards[portForwardID]
+	if found {
+		return portForward, nil
+	}
+
+	return nil, status.Errorf(codes.NotFound, "port forward with ID %s not found", portForwardID)
+}
+
+// CreatePortForward creates and saves a new port forward
+func (am *DefaultAccountManager) CreatePortForward(accountID string, peer, description, protocol string, externalPort int, targetPeer, targetIP string, targetPort int, enabled bool) (*route.PortForward, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	account, err := am.Store.GetAccount(accountID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "account not found")
+	}
+
+	newPortForward := &route.PortForward{
+		ID:           xid.New().String(),
+		Description:  description,
+		Peer:         peer,
+		Protocol:     protocol,
+		ExternalPort: externalPort,
+		TargetPeer:   targetPeer,
+		TargetPort:   targetPort,
+		Enabled:      enabled,
+	}
+
+	if targetIP != "" {
+		newPortForward.TargetIP, err = netip.ParseAddr(targetIP)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to parse target IP %s", targetIP)
+		}
+	}
+
+	err = validatePortForward(account, newPortForward)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.PortForwards == nil {
+		account.PortForwards = make(map[string]*route.PortForward)
+	}
+
+	account.PortForwards[newPortForward.ID] = newPortForward
+
+	account.Network.IncSerial()
+	if err = am.Store.SaveAccount(account); err != nil {
+		return nil, err
+	}
+
+	err = am.updateAccountPeers(account)
+	if err != nil {
+		log.Error(err)
+		return newPortForward, status.Errorf(codes.Unavailable, "failed to update peers after create port forward %s", newPortForward.ID)
+	}
+	return newPortForward, nil
+}
+
+// SavePortForward saves port forward
+func (am *DefaultAccountManager) SavePortForward(accountID string, portForwardToSave *route.PortForward) error {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	if portForwardToSave == nil {
+		return status.Errorf(codes.InvalidArgument, "port forward provided is nil")
+	}
+
+	account, err := am.Store.GetAccount(accountID)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "account not found")
+	}
+
+	err = validatePortForward(account, portForwardToSave)
+	if err != nil {
+		return err
+	}
+
+	if account.PortForwards == nil {
+		account.PortForwards = make(map[string]*route.PortForward)
+	}
+
+	account.PortForwards[portForwardToSave.ID] = portForwardToSave
+
+	account.Network.IncSerial()
+	if err = am.Store.SaveAccount(account); err != nil {
+		return err
+	}
+
+	return am.updateAccountPeers(account)
+}
+
+// DeletePortForward deletes port forward with portForwardID
+func (am *DefaultAccountManager) DeletePortForward(accountID, portForwardID string) error {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	account, err := am.Store.GetAccount(accountID)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "account not found")
+	}
+
+	delete(account.PortForwards, portForwardID)
+
+	account.Network.IncSerial()
+	if err = am.Store.SaveAccount(account); err != nil {
+		return err
+	}
+
+	return am.updateAccountPeers(account)
+}
+
+// ListPortForwards returns a list of port forwards from account
+func (am *DefaultAccountManager) ListPortForwards(accountID string) ([]*route.PortForward, error) {
+	am.mux.Lock()
+	defer am.mux.Unlock()
+
+	account, err := am.Store.GetAccount(accountID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "account not found")
+	}
+
+	portForwards := make([]*route.PortForward, 0, len(account.PortForwards))
+	for _, item := range account.PortForwards {
+		portForwards = append(portForwards, item)
+	}
+
+	return portForwards, nil
+}
+
+// validatePortForward checks the port forward values and that the routing and target peers exist.
+// Only one target, a peer or an IP address, can be set and the external port and protocol can be used
+// only once per routing peer
+func validatePortForward(account *Account, portForward *route.PortForward) error {
+	err := route.ValidatePortForward(portForward.Protocol, portForward.ExternalPort, portForward.TargetPort)
+	if err != nil {
+		return err
+	}
+
+	if portForward.Peer != "" {
+		_, peerExist := account.Peers[portForward.Peer]
+		if !peerExist {
+			return status.Errorf(codes.InvalidArgument, "failed to find Peer %s", portForward.Peer)
+		}
+	}
+
+	if portForward.TargetPeer != "" && portForward.TargetIP.IsValid() {
+		return status.Errorf(codes.InvalidArgument, "only one of target peer or target IP should be provided")
+	}
+
+	if portForward.TargetPeer == "" && !portForward.TargetIP.IsValid() {
+		return status.Errorf(codes.InvalidArgument, "target peer or target IP should be provided")
+	}
+
+	if portForward.TargetPeer != "" {
+		_, peerExist := account.Peers[portForward.TargetPeer]
+		if !peerExist {
+			return status.Errorf(codes.InvalidArgument, "failed to find target Peer %s", portForward.TargetPeer)
+		}
+	}
+
+	if portForward.Peer == "" {
+		return nil
+	}
+
+	for _, existing := range account.PortForwards {
+		if existing.ID != portForward.ID && existing.Peer == portForward.Peer &&
+			existing.Protocol == portForward.Protocol && existing.ExternalPort == portForward.ExternalPort {
+			return status.Errorf(codes.AlreadyExists, "port forward with %s port %d already exists for peer %s",
+				portForward.Protocol, portForward.ExternalPort, portForward.Peer)
+		}
+	}
+
+	return nil
+}
+
+// getPeerPortForwards returns the enabled port forwards applied by the routing peer with the target peers resolved
+// to their IP addresses
+func getPeerPortForwards(account *Account, peerKey string) []*route.PortForward {
+	portForwards := make([]*route.PortForward, 0)
+	for _, portForward := range account.PortForwards {
+		if !portForward.Enabled || portForward.Peer != peerKey {
+			continue
+		}
+
+		resolved := portForward.Copy()
+		if resolved.TargetPeer != "" {
+			targetPeer, found := account.Peers[resolved.TargetPeer]
+			if !found {
+				log.Warnf("target peer %s of port forward %s not found", resolved.TargetPeer, resolved.ID)
+				continue
+			}
+			addr, ok := netip.AddrFromSlice(targetPeer.IP)
+			if !ok {
+				log.Warnf("target peer %s of port forward %s has an invalid IP", resolved.TargetPeer, resolved.ID)
+				continue
+			}
+			resolved.TargetIP = addr.Unmap()
+		}
+		portForwards = append(portForwards, resolved)
+	}
+
+	sort.Slice(portForwards, func(i, j int) bool {
+		return portForwards[i].ID < portForwards[j].ID
+	})
+
+	return portForwards
+}
+
+func toProtocolPortForwards(portForwards []*route.PortForward) []*proto.PortForward {
+	protoPortForwards := make([]*proto.PortForward, 0)
+	for _, p := range portForwards {
+		protoPortForwards = append(protoPortForwards, &proto.PortForward{
+			ID:           p.ID,
+			Protocol:     p.Protocol,
+			ExternalPort: int64(p.ExternalPort),
+			TargetIP:     p.TargetIP.String(),
+			TargetPort:   int64(p.TargetPort),
+		})
+	}
+	return protoPortForwards
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"github.com/netbirdio/netbird/route"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"testing"
+)
+
+func TestCreatePortForward(t *testing.T) {
+
+	type input struct {
+		peer         string
+		protocol     string
+		externalPort int
+		targetPeer   string
+		targetIP     string
+		targetPort   int
+	}
+
+	testCases := []struct {
+		name                string
+		inputArgs           input
+		shouldCreate        bool
+		errFunc             require.ErrorAssertionFunc
+		expectedPortForward *route.PortForward
+	}{
+		{
+			name: "Happy Path Target IP",
+			inputArgs: input{
+				peer:         peer1Key,
+				protocol:     route.PortForwardProtocolTCP,
+				externalPort: 8080,
+				targetIP:     "10.0.0.5",
+				targetPort:   80,
+			},
+			errFunc:      require.NoError,
+			shouldCreate: true,
+			expectedPortForward: &route.PortForward{
+				Peer:         peer1Key,
+				Protocol:     route.PortForwardProtocolTCP,
+				ExternalPort: 8080,
+				TargetIP:     netip.MustParseAddr("10.0.0.5"),
+				TargetPort:   80,
+				Enabled:      true,
+			},
+		},
+		{
+			name: "Happy Path Target Peer",
+			inputArgs: input{
+				peer:         peer1Key,
+				protocol:     route.PortForwardProtocolUDP,
+				externalPort: 5353,
+				targetPeer:   peer2Key,
+				targetPort:   53,
+			},
+			errFunc:      require.NoError,
+			shouldCreate: true,
+			expectedPortForward: &route.PortForward{
+				Peer:         peer1Key,
+				Protocol:     route.PortForwardProtocolUDP,
+				ExternalPort: 5353,
+				TargetPeer:   peer2Key,
+				TargetPort:   53,
+				Enabled:      true,
+			},
+		},
+		{
+			name: "Bad Protocol",
+			inputArgs: input{
+				peer:         peer1Key,
+				protocol:     "icmp",
+				externalPort: 8080,
+				targetIP:     "10.0.0.5",
+				targetPort:   80,
+			},
+			errFunc: require.Error,
+		},
+		{
+			name: "Bad Port",
+			inputArgs: input{
+				peer:         peer1Key,
+				protocol:     route.PortForwardProtocolTCP,
+				externalPort: 0,
+				targetIP:     "10.0.0.5",
+				targetPort:   80,
+			},
+			errFunc: require.Error,
+		},
+		{
+			name: "Bad Peer",
+			inputArgs: input{
+				peer:         "notExistingPeer",
+				protocol:     route.PortForwardProtocolTCP,
+				externalPort: 8080,
+				targetIP:     "10.0.0.5",
+				targetPort:   80,
+			},
+			errFunc: require.Error,
+		},
+		{
+			name: "Bad Target Peer",
+			inputArgs: input{
+				peer:         peer1Key,
+				protocol:     route.PortForwardProtocolTCP,
+				externalPort: 8080,
+				targetPeer:   "notExistingPeer",
+				targetPort:   80,
+			},
+			errFunc: require.Error,
+		},
+		{
+			name: "Both Targets",
+			inputArgs: input{
+				peer:         peer1Key,
+				protocol:     route.PortForwardProtocolTCP,
+				externalPort: 8080,
+				targetPeer:   peer2Key,
+				targetIP:     "10.0.0.5",
+				targetPort:   80,
+			},
+			errFunc: require.Error,
+		},
+		{
+			name: "No Target",
+			inputArgs: input{
+				peer:         peer1Key,
+				protocol:     route.PortForwardProtocolTCP,
+				externalPort: 8080,
+				targetPort:   80,
+			},
+			errFunc: require.Error,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			am, err := createRouterManager(t)
+			if err != nil {
+				t.Error("failed to create account manager")
+			}
+
+			account, err := initTestRouteAccount(t, am)
+			if err != nil {
+				t.Error("failed to init testing account")
+			}
+
+			outPortForward, err := am.CreatePortForward(
+				account.Id,
+				testCase.inputArgs.peer,
+				"",
+				testCase.inputArgs.protocol,
+				testCase.inputArgs.externalPort,
+				testCase.inputArgs.targetPeer,
+				testCase.inputArgs.targetIP,
+				testCase.inputArgs.targetPort,
+				true,
+			)
+
+			testCase.errFunc(t, err)
+
+			if !testCase.shouldCreate {
+				return
+			}
+
+			// assign generated ID
+			testCase.expectedPortForward.ID = outPortForward.ID
+
+			if !testCase.expectedPortForward.IsEqual(outPortForward) {
+				t.Errorf("new port forward didn't match expected port forward:\nGot %#v\nExpected:%#v\n", outPortForward, testCase.expectedPortForward)
+			}
+		})
+	}
+}
+
+func TestCreatePortForward_DuplicateExternalPort(t *testing.T) {
+	am, err := createRouterManager(t)
+	require.NoError(t, err, "failed to create account manager")
+
+	account, err := initTestRouteAccount(t, am)
+	require.NoError(t, err, "failed to init testing account")
+
+	_, err = am.CreatePortForward(account.Id, peer1Key, "", route.PortForwardProtocolTCP, 8080, "", "10.0.0.5", 80, true)
+	require.NoError(t, err)
+
+	_, err = am.CreatePortForward(account.Id, peer1Key, "", route.PortForwardProtocolTCP, 8080, "", "10.0.0.6", 80, true)
+	require.Error(t, err, "same external port and protocol should not be used twice on a routing peer")
+
+	_, err = am.CreatePortForward(account.Id, peer1Key, "", route.PortForwardProtocolUDP, 8080, "", "10.0.0.6", 80, true)
+	require.NoError(t, err, "same external port should be allowed with a different protocol")
+
+	_, err = am.CreatePortForward(account.Id, peer2Key, "", route.PortForwardProtocolTCP, 8080, "", "10.0.0.6", 80, true)
+	require.NoError(t, err, "same external port should be allowed on a different routing peer")
+}
+
+func TestGetNetworkMap_PortForwardSync(t *testing.T) {
+	am, err := createRouterManager(t)
+	require.NoError(t, err, "failed to create account manager")
+
+	account, err := initTestRouteAccount(t, am)
+	require.NoError(t, err, "failed to init testing account")
+
+	newNetworkMap, err := am.GetNetworkMap(peer1Key)
+	require.NoError(t, err)
+	require.Len(t, newNetworkMap.PortForwards, 0, "new accounts should have no port forwards")
+
+	createdPortForward, err := am.CreatePortForward(account.Id, peer1Key, "", route.PortForwardProtocolTCP, 8080, peer2Key, "", 80, false)
+	require.NoError(t, err)
+
+	noDisabled, err := am.GetNetworkMap(peer1Key)
+	require.NoError(t, err)
+	require.Len(t, noDisabled.PortForwards, 0, "no port forwards for disabled port forwards")
+
+	enabledPortForward := createdPortForward.Copy()
+	enabledPortForward.Enabled = true
+
+	err = am.SavePortForward(account.Id, enabledPortForward)
+	require.NoError(t, err)
+
+	peer1NetworkMap, err := am.GetNetworkMap(peer1Key)
+	require.NoError(t, err)
+	require.Len(t, peer1NetworkMap.PortForwards, 1, "routing peer should receive the port forward")
+
+	peer2, err := am.Store.GetPeer(peer2Key)
+	require.NoError(t, err)
+	peer2IP, _ := netip.AddrFromSlice(peer2.IP)
+	require.Equal(t, peer2IP.Unmap(), peer1NetworkMap.PortForwards[0].TargetIP, "target peer should be resolved to its IP")
+
+	peer2NetworkMap, err := am.GetNetworkMap(peer2Key)
+	require.NoError(t, err)
+	require.Len(t, peer2NetworkMap.PortForwards, 0, "only the routing peer should receive the port forward")
+
+	err = am.DeletePortForward(account.Id, enabledPortForward.ID)
+	require.NoError(t, err)
+
+	peer1Deleted, err := am.GetNetworkMap(peer1Key)
+	require.NoError(t, err)
+	require.Len(t, peer1Deleted.PortForwards, 0, "deleted port forwards should not be received")
+}
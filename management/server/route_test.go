@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"net/netip"
 	"testing"
+	"time"
 )
 
 const peer1Key = "BhRPtynAAYRDy08+q4HTMsos8fs4plTP4NOSh7C1ry8="
@@ -842,3 +843,60 @@ func initTestRouteAccount(t *testing.T, am *DefaultAccountManager) (*Account, er
 
 	return account, nil
 }
+
+func TestUpdatePeerRoutesTraffic(t *testing.T) {
+	am, err := createRouterManager(t)
+	require.NoError(t, err, "failed to create account manager")
+
+	account, err := initTestRouteAccount(t, am)
+	require.NoError(t, err, "failed to init testing account")
+
+	peer1Route, err := am.CreateRoute(account.Id, "192.168.0.0/16", peer1Key, "peer1 route", "superNet", false, 9999, true)
+	require.NoError(t, err)
+
+	peer2Route, err := am.CreateRoute(account.Id, "10.0.0.0/8", peer2Key, "peer2 route", "otherNet", false, 9999, true)
+	require.NoError(t, err)
+
+	traffic := RouteTraffic{
+		TxBytes:     1000,
+		TxPackets:   10,
+		RxBytes:     2000,
+		RxPackets:   20,
+		LastUpdated: time.Now().UTC(),
+	}
+
+	err = am.UpdatePeerRoutesTraffic(peer1Key, map[string]RouteTraffic{
+		peer1Route.ID:      traffic,
+		peer2Route.ID:      traffic,
+		"notExistingRoute": traffic,
+	})
+	require.NoError(t, err)
+
+	peerRoutesTraffic := am.GetPeerRoutesTraffic(peer1Key)
+	require.Len(t, peerRoutesTraffic, 1, "only traffic of routes routed by the peer should be kept")
+	require.Equal(t, traffic, peerRoutesTraffic[peer1Route.ID])
+
+	peer, err := am.GetPeer(peer1Key)
+	require.NoError(t, err)
+	require.Len(t, peer.RoutesTraffic, 0, "traffic should not be persisted on every report")
+
+	am.persistRoutesTraffic()
+
+	peer, err = am.GetPeer(peer1Key)
+	require.NoError(t, err)
+	require.Len(t, peer.RoutesTraffic, 1, "traffic should be persisted")
+	require.Equal(t, traffic, peer.RoutesTraffic[peer1Route.ID])
+
+	err = am.UpdatePeerRoutesTraffic(peer1Key, nil)
+	require.NoError(t, err)
+	require.Len(t, am.GetPeerRoutesTraffic(peer1Key), 0, "an empty report should clear the traffic")
+
+	am.persistRoutesTraffic()
+
+	peer, err = am.GetPeer(peer1Key)
+	require.NoError(t, err)
+	require.Len(t, peer.RoutesTraffic, 0, "an empty report should clear the stored traffic")
+
+	err = am.UpdatePeerRoutesTraffic("notExistingPeer", nil)
+	require.Error(t, err, "traffic of unknown peers should not be stored")
+}
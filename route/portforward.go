@@ -0,0 +1,78 @@
+package route
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/netip"
+)
+
+const (
+	// PortForwardProtocolTCP tcp port forward protocol
+	PortForwardProtocolTCP = "tcp"
+	// PortForwardProtocolUDP udp port forward protocol
+	PortForwardProtocolUDP = "udp"
+	// MinPort min port input
+	MinPort = 1
+	// MaxPort max port input
+	MaxPort = 65535
+)
+
+// PortForward represents a port forwarding (DNAT) rule applied by a routing peer.
+// Traffic received by the routing peer on ExternalPort is forwarded to TargetPort of either
+// the TargetPeer or, when no target peer is set, the TargetIP
+type PortForward struct {
+	ID           string
+	Description  string
+	Peer         string
+	Protocol     string
+	ExternalPort int
+	TargetPeer   string
+	TargetIP     netip.Addr
+	TargetPort   int
+	Enabled      bool
+}
+
+// Copy copies a port forward object
+func (p *PortForward) Copy() *PortForward {
+	return &PortForward{
+		ID:           p.ID,
+		Description:  p.Description,
+		Peer:         p.Peer,
+		Protocol:     p.Protocol,
+		ExternalPort: p.ExternalPort,
+		TargetPeer:   p.TargetPeer,
+		TargetIP:     p.TargetIP,
+		TargetPort:   p.TargetPort,
+		Enabled:      p.Enabled,
+	}
+}
+
+// IsEqual compares one port forward with the other
+func (p *PortForward) IsEqual(other *PortForward) bool {
+	return other.ID == p.ID &&
+		other.Description == p.Description &&
+		other.Peer == p.Peer &&
+		other.Protocol == p.Protocol &&
+		other.ExternalPort == p.ExternalPort &&
+		other.TargetPeer == p.TargetPeer &&
+		other.TargetIP == p.TargetIP &&
+		other.TargetPort == p.TargetPort &&
+		other.Enabled == p.Enabled
+}
+
+// ValidatePortForward checks the protocol and ports of a port forward
+func ValidatePortForward(protocol string, externalPort, targetPort int) error {
+	if protocol != PortForwardProtocolTCP && protocol != PortForwardProtocolUDP {
+		return status.Errorf(codes.InvalidArgument, "protocol should be %s or %s", PortForwardProtocolTCP, PortForwardProtocolUDP)
+	}
+
+	if externalPort < MinPort || externalPort > MaxPort {
+		return status.Errorf(codes.InvalidArgument, "external port should be between %d and %d", MinPort, MaxPort)
+	}
+
+	if targetPort < MinPort || targetPort > MaxPort {
+		return status.Errorf(codes.InvalidArgument, "target port should be between %d and %d", MinPort, MaxPort)
+	}
+
+	return nil
+}